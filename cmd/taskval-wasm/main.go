@@ -0,0 +1,70 @@
+//go:build js && wasm
+
+// Command taskval-wasm is taskval's validator compiled to WebAssembly for
+// browsers, so a plan editor can validate client-side with the same rules
+// as the CLI. Build it with
+//
+//	GOOS=js GOARCH=wasm go build -o taskval.wasm ./cmd/taskval-wasm
+//
+// and load it with the wasm_exec.js shipped in $(go env GOROOT)/lib/wasm.
+// It defines one global function:
+//
+//	taskval.validate(json, mode) -> string
+//
+// json is the document text and mode is "graph" (the default) or "task".
+// The result is the JSON that `taskval --output=json` prints for the same
+// document, under the default standard profile. A bad mode or an internal
+// error returns {"error": "..."} instead.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"syscall/js"
+
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+func main() {
+	js.Global().Set("taskval", js.ValueOf(map[string]any{
+		"validate": js.FuncOf(validate),
+	}))
+	select {} // Keep the exported function alive.
+}
+
+// validate is the JS binding of taskval.validate.
+func validate(_ js.Value, args []js.Value) any {
+	if len(args) == 0 || args[0].Type() != js.TypeString {
+		return errorJSON(fmt.Errorf("validate needs the document as a string"))
+	}
+	mode := validator.ModeTaskGraph
+	if len(args) > 1 && !args[1].IsUndefined() {
+		switch m := args[1].String(); m {
+		case "graph":
+		case "task":
+			mode = validator.ModeSingleTask
+		default:
+			return errorJSON(fmt.Errorf("invalid mode '%s'. Must be 'task' or 'graph'", m))
+		}
+	}
+
+	rules, err := validator.Profile("standard")
+	if err != nil {
+		return errorJSON(err)
+	}
+	result, err := validator.ValidateWithOptions([]byte(args[0].String()), mode, validator.Options{Rules: rules})
+	if err != nil {
+		return errorJSON(err)
+	}
+	out, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return errorJSON(err)
+	}
+	return string(out)
+}
+
+// errorJSON is the result for a call that could not validate.
+func errorJSON(err error) string {
+	out, _ := json.Marshal(map[string]string{"error": err.Error()})
+	return string(out)
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/nixlim/task_templating/internal/compare"
+)
+
+// runCompareRuns implements 'taskval compare-runs': diff two JSON
+// validation reports and list the findings head introduced and fixed.
+// It exits 1 when head has new findings, so CI can fail on regressions
+// only.
+func runCompareRuns(args []string) int {
+	fs := flag.NewFlagSet("compare-runs", flag.ContinueOnError)
+	output := fs.String("output", "text", "Output format: 'text' or 'json'")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *output != "text" && *output != "json" {
+		fmt.Fprintf(os.Stderr, "Error: invalid output format '%s'. Must be 'text' or 'json'.\n", *output)
+		return 2
+	}
+	if fs.NArg() != 2 {
+		fmt.Fprintf(os.Stderr, "Error: expected a base and a head report (taskval compare-runs base.json head.json), got %d argument(s).\n", fs.NArg())
+		return 2
+	}
+
+	var reports [2]*compare.Report
+	for i, path := range fs.Args() {
+		data, _, err := readInput([]string{path})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return 2
+		}
+		reports[i], err = compare.ParseReport(data)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s: %s\n", path, err)
+			return 2
+		}
+	}
+	diff := compare.Compare(reports[0], reports[1])
+
+	switch *output {
+	case "text":
+		outputComparison(os.Stdout, diff)
+	case "json":
+		if err := writeJSON("", diff); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return 2
+		}
+	}
+	if len(diff.New) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// outputComparison writes the text form of a report comparison, with
+// findings in the same layout as validation output.
+func outputComparison(w io.Writer, diff compare.Diff) {
+	if len(diff.New) > 0 {
+		fmt.Fprintln(w, "NEW FINDINGS")
+	} else {
+		fmt.Fprintln(w, "NO NEW FINDINGS")
+	}
+	fmt.Fprintf(w, "\nSummary: %d new, %d fixed, %d unchanged (%d error(s), %d warning(s), %d info(s))\n",
+		len(diff.New), len(diff.Fixed), diff.Unchanged.Total(),
+		diff.Unchanged.Errors, diff.Unchanged.Warnings, diff.Unchanged.Infos)
+
+	if len(diff.New) > 0 {
+		fmt.Fprintln(w, "\n--- NEW (introduced by head) ---")
+		for i, e := range diff.New {
+			printError(w, i+1, e)
+		}
+	}
+	if len(diff.Fixed) > 0 {
+		fmt.Fprintln(w, "\n--- FIXED (in base, gone from head) ---")
+		for i, e := range diff.Fixed {
+			printError(w, i+1, e)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/nixlim/task_templating/internal/beads"
+	"github.com/nixlim/task_templating/internal/schedule"
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// runCost implements 'taskval cost': estimate the spend of a graph per
+// milestone, from cost hints or agent-hours times a rate.
+func runCost(args []string) int {
+	fs := flag.NewFlagSet("cost", flag.ContinueOnError)
+	rate := fs.Float64("rate", 0, "Spend per agent-hour for tasks without a cost field; required unless every task has one")
+	currency := fs.String("currency", "", "Currency label for the text report (e.g. USD)")
+	format := fs.String("format", "text", "Report format: 'text' or 'csv'")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *format != "text" && *format != "csv" {
+		fmt.Fprintf(os.Stderr, "Error: invalid --format '%s'. Must be 'text' or 'csv'.\n", *format)
+		return 2
+	}
+	if *rate < 0 {
+		fmt.Fprintf(os.Stderr, "Error: --rate must not be negative.\n")
+		return 2
+	}
+
+	result, _, code := loadValidated(fs.Args(), validator.ModeTaskGraph)
+	if code != 0 {
+		return code
+	}
+
+	report := schedule.EstimateCost(result.Graph, schedule.CostOptions{
+		Rate:           *rate,
+		Minutes:        beads.MapEstimate,
+		UnknownMinutes: beads.MapEstimate("medium"),
+	})
+	if report.Unpriced > 0 && *rate == 0 {
+		fmt.Fprintf(os.Stderr, "Error: --rate is required: %d task(s) have no cost field.\n", report.Unpriced)
+		return 2
+	}
+
+	rows := append(slices.Clone(report.Milestones), report.Total)
+	if *format == "csv" {
+		w := csv.NewWriter(os.Stdout)
+		_ = w.Write([]string{"milestone", "tasks", "agent_hours", "cost"})
+		for _, g := range rows {
+			_ = w.Write([]string{g.Name, strconv.Itoa(g.Tasks), formatHours(g.Minutes), formatMoney(g.Cost)})
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return 2
+		}
+		return 0
+	}
+
+	unit := strings.TrimSpace(*currency + " per agent-hour")
+	if *rate > 0 {
+		fmt.Printf("COST ESTIMATE (%s %s)\n\n", formatMoney(*rate), unit)
+	} else {
+		fmt.Printf("COST ESTIMATE (cost fields only)\n\n")
+	}
+	width := len("Milestone")
+	for _, g := range report.Milestones {
+		width = max(width, len(g.Name))
+	}
+	fmt.Printf("  %-*s %6s %12s %14s\n", width, "Milestone", "Tasks", "Agent-hours", "Cost")
+	for i, g := range rows {
+		if i == len(rows)-1 {
+			fmt.Printf("  %s\n", strings.Repeat("-", width+35))
+		}
+		fmt.Printf("  %-*s %6d %12s %14s\n", width, g.Name, g.Tasks, formatHours(g.Minutes), formatMoney(g.Cost))
+	}
+	fmt.Println()
+	if hinted := report.Total.Tasks - report.Unpriced; hinted > 0 {
+		fmt.Printf("  %d task(s) priced by their cost field; the rest at the rate.\n", hinted)
+	}
+	fmt.Println("  (unknown estimates count as medium)")
+	return 0
+}
+
+// formatHours renders working minutes as decimal hours.
+func formatHours(m int) string {
+	return strconv.FormatFloat(float64(m)/60, 'f', 2, 64)
+}
+
+// formatMoney renders an amount with two decimals.
+func formatMoney(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
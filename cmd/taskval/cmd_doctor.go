@@ -0,0 +1,162 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/nixlim/task_templating/internal/beads"
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// Doctor check outcomes.
+const (
+	checkPass = "pass"
+	checkWarn = "warn"
+	checkFail = "fail"
+	checkSkip = "skip"
+)
+
+// doctorCheck is one line of the 'taskval doctor' report.
+type doctorCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	Fix    string `json:"fix,omitempty"`
+}
+
+// doctorReport is the JSON form of 'taskval doctor'.
+type doctorReport struct {
+	OK     bool          `json:"ok"`
+	Checks []doctorCheck `json:"checks"`
+}
+
+// runDoctor implements 'taskval doctor': check the environment taskval
+// runs in and say how to fix what is broken.
+func runDoctor(args []string) int {
+	fs := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	configFile := fs.String("config", "", "Also check this validation config file")
+	output := fs.String("output", "text", "Output format: 'text' or 'json'")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *output != "text" && *output != "json" {
+		fmt.Fprintf(os.Stderr, "Error: invalid --output '%s'. Must be 'text' or 'json'.\n", *output)
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "Error: doctor takes no arguments, got %d.\n", fs.NArg())
+		return 2
+	}
+
+	report := doctorReport{OK: true, Checks: doctorChecks(*configFile)}
+	for _, c := range report.Checks {
+		if c.Status == checkFail {
+			report.OK = false
+		}
+	}
+
+	if *output == "json" {
+		if err := writeJSON("-", report); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return 2
+		}
+	} else {
+		fmt.Println("TASKVAL DOCTOR")
+		fmt.Println()
+		for _, c := range report.Checks {
+			fmt.Printf("  [%s] %s", strings.ToUpper(c.Status), c.Name)
+			if c.Detail != "" {
+				fmt.Printf(": %s", c.Detail)
+			}
+			fmt.Println()
+			if c.Fix != "" {
+				fmt.Printf("         Fix: %s\n", c.Fix)
+			}
+		}
+		fmt.Println()
+		if report.OK {
+			fmt.Println("  No problems found.")
+		} else {
+			fmt.Println("  Problems found; see the fixes above.")
+		}
+	}
+	if !report.OK {
+		return 1
+	}
+	return 0
+}
+
+// doctorChecks runs every check in order. The bd checks after a missing
+// bd are skipped rather than failed again.
+func doctorChecks(configFile string) []doctorCheck {
+	var checks []doctorCheck
+	add := func(name, status, detail, fix string) {
+		checks = append(checks, doctorCheck{Name: name, Status: status, Detail: detail, Fix: fix})
+	}
+
+	bdPath, err := exec.LookPath("bd")
+	if err != nil {
+		add("bd on PATH", checkFail, "bd not found", "go install github.com/steveyegge/beads/cmd/bd@latest")
+		add("bd version", checkSkip, "bd not found", "")
+		add("beads initialized", checkSkip, "bd not found", "")
+	} else {
+		add("bd on PATH", checkPass, bdPath, "")
+		switch version, ok, err := beads.InstalledVersion(); {
+		case err != nil:
+			add("bd version", checkFail, err.Error(), "go install github.com/steveyegge/beads/cmd/bd@latest")
+		case !ok:
+			add("bd version", checkWarn, "'bd version' printed no release number (development build?); commands run unchanged", "")
+		default:
+			add("bd version", checkPass, fmt.Sprintf("%s (minimum %s)", version, beads.MinBdVersion), "")
+		}
+		if err := beads.PreFlightCheck(); err != nil {
+			add("beads initialized", checkFail, err.Error(), "Run 'bd init' in the project, or run taskval from a directory that has a beads database")
+		} else {
+			add("beads initialized", checkPass, "", "")
+		}
+	}
+
+	if _, err := validator.NewSchemaValidator(); err != nil {
+		add("schemas compile", checkFail, err.Error(), "Rebuild taskval; the embedded schemas are broken")
+	} else {
+		add("schemas compile", checkPass, "task_node, task_graph, design_metadata", "")
+	}
+
+	if configFile == "" {
+		add("config file", checkSkip, "no --config given", "")
+	} else if _, err := validator.LoadConfig(configFile); err != nil {
+		add("config file", checkFail, err.Error(), "Correct the file; the taskval command reference lists the keys --config accepts")
+	} else {
+		add("config file", checkPass, configFile, "")
+	}
+
+	// taskval keeps no state or cache directory of its own: it writes
+	// reports and generated files where asked, by default the current
+	// directory, and custom rules and bd may use the temp directory.
+	dirs := []struct{ name, path, fix string }{
+		{"current directory writable", ".", "Run taskval from a writable directory, or pass -o/--output-file paths elsewhere"},
+		{"temp directory writable", os.TempDir(), "Point TMPDIR at a writable directory"},
+	}
+	for _, d := range dirs {
+		if err := checkWritable(d.path); err != nil {
+			add(d.name, checkFail, err.Error(), d.fix)
+		} else {
+			add(d.name, checkPass, d.path, "")
+		}
+	}
+	return checks
+}
+
+// checkWritable creates and removes a file in dir.
+func checkWritable(dir string) error {
+	f, err := os.CreateTemp(dir, ".taskval-doctor-*")
+	if err != nil {
+		return err
+	}
+	name := f.Name()
+	f.Close()
+	return os.Remove(name)
+}
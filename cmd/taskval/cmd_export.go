@@ -0,0 +1,115 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nixlim/task_templating/internal/beads"
+	"github.com/nixlim/task_templating/internal/export"
+)
+
+// runExport implements 'taskval export': render a validated document with
+// one of the registered export targets.
+func runExport(args []string) int {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	target := fs.String("target", "", "Export target: "+strings.Join(export.Targets(), ", "))
+	mode := fs.String("mode", "graph", "Input mode: 'task' for a single task node, 'graph' for a full task graph")
+	out := fs.String("o", "", "Output file (single-file targets) or directory (multi-file targets); default stdout")
+	start := fs.String("start", "", "First working day of the plan, YYYY-MM-DD (calendar targets; default today)")
+	workers := fs.Int("workers", 1, "Number of tasks that can run in parallel (calendar targets)")
+	unit := fs.String("estimate-unit", "", "Show estimates converted to minutes, hours, pomodoros, or points[:N] (N minutes per point)")
+	acceptanceStyle := fs.String("acceptance-style", "", "Acceptance list style for checklist targets: 'bullets' or 'checkboxes' (default: the target's own)")
+	projectURL := fs.String("project-url", "", "Tracker project to export into, e.g. https://dev.azure.com/org/project (azure-devops target)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	exporter, err := export.Lookup(*target)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s.\n", err)
+		return 2
+	}
+	valMode, err := parseMode(*mode)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s.\n", err)
+		return 2
+	}
+	opts := export.Options{
+		Start:           time.Now(),
+		Workers:         *workers,
+		EstimateMinutes: beads.MapEstimate,
+		ProjectURL:      *projectURL,
+	}
+	if *unit != "" {
+		u, err := beads.ParseEstimateUnit(*unit)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s.\n", err)
+			return 2
+		}
+		opts.FormatEstimate = u.Format
+	}
+	if *acceptanceStyle != "" {
+		if opts.AcceptanceBullet, err = beads.AcceptanceBullet(*acceptanceStyle); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s.\n", err)
+			return 2
+		}
+	}
+	if *start != "" {
+		if opts.Start, err = time.Parse("2006-01-02", *start); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid --start '%s'. Use YYYY-MM-DD.\n", *start)
+			return 2
+		}
+	}
+
+	result, _, code := loadValidated(fs.Args(), valMode)
+	if code != 0 {
+		return code
+	}
+
+	files, err := exporter.Export(result.Graph, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+
+	if err := writeExportFiles(*out, files); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+	return 0
+}
+
+// writeExportFiles writes a single file to out (or stdout), or multiple files
+// into the directory out. An existing directory always receives files by name.
+func writeExportFiles(out string, files []export.File) error {
+	toDir := len(files) > 1
+	if info, err := os.Stat(out); out != "" && err == nil && info.IsDir() {
+		toDir = true
+	}
+
+	if !toDir {
+		if len(files) == 0 {
+			return nil
+		}
+		return writeOutput(out, files[0].Content)
+	}
+
+	if out == "" {
+		return fmt.Errorf("target produces %d files; use -o <dir> to choose where to write them", len(files))
+	}
+	if err := os.MkdirAll(out, 0o755); err != nil {
+		return fmt.Errorf("creating '%s': %w", out, err)
+	}
+	for _, f := range files {
+		path := filepath.Join(out, f.Name)
+		if err := writeOutput(path, f.Content); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Wrote %s\n", path)
+	}
+	return nil
+}
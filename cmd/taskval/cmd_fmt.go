@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// runFmt implements 'taskval fmt': rewrite a graph in canonical form
+// (field order and two-space indentation). When the canonical form differs
+// from the input, graph_revision (if present) gets a patch bump.
+func runFmt(args []string) int {
+	fs := flag.NewFlagSet("fmt", flag.ContinueOnError)
+	write := fs.Bool("w", false, "Write the result back to the input file instead of stdout")
+	check := fs.Bool("check", false, "Exit 1 and print the file name if it is not in canonical form; write nothing")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *write && *check {
+		fmt.Fprintf(os.Stderr, "Error: -w and --check are mutually exclusive.\n")
+		return 2
+	}
+
+	data, filename, err := readInput(fs.Args())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+	if *write && filename == "-" {
+		fmt.Fprintf(os.Stderr, "Error: -w needs a file argument, not stdin.\n")
+		return 2
+	}
+
+	if validator.UsesTaskTemplates(data) {
+		fmt.Fprintf(os.Stderr, "Error: fmt does not support graphs that use task_templates; formatting would fill instantiated tasks with empty fields.\n")
+		return 2
+	}
+
+	// Formatting must never drop a field. Unknown graph and task fields
+	// are carried through; anything else the model would lose is an error.
+	var graph validator.TaskGraph
+	if err := json.Unmarshal(data, &graph); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: parsing task graph: %s\n", err)
+		return 2
+	}
+	if lost, err := validator.DroppedFields(data, validator.ModeTaskGraph); err != nil || len(lost) > 0 {
+		if err == nil {
+			err = fmt.Errorf("formatting would drop %s", strings.Join(lost, ", "))
+		}
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+
+	formatted, err := encodeJSON(&graph)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Internal error: %s\n", err)
+		return 2
+	}
+	changed := !bytes.Equal(formatted, data)
+
+	if *check {
+		if changed {
+			fmt.Println(filename)
+			return 1
+		}
+		return 0
+	}
+
+	if changed {
+		prev := graph.GraphRevision
+		rev, err := graph.BumpRevision(validator.RevisionPatch)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return 2
+		}
+		if rev != "" {
+			if formatted, err = encodeJSON(&graph); err != nil {
+				fmt.Fprintf(os.Stderr, "Internal error: %s\n", err)
+				return 2
+			}
+			fmt.Fprintf(os.Stderr, "graph_revision: %s -> %s\n", prev, rev)
+		}
+	}
+
+	if !*write {
+		_, _ = os.Stdout.Write(formatted)
+		return 0
+	}
+	if !changed {
+		return 0
+	}
+	if err := writeOutput(filename, formatted); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+	return 0
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/nixlim/task_templating/internal/scaffold"
+)
+
+// runFromMarkdown implements 'taskval from-markdown': convert a markdown plan
+// into a draft task graph.
+func runFromMarkdown(args []string) int {
+	fs := flag.NewFlagSet("from-markdown", flag.ContinueOnError)
+	out := fs.String("o", "", "Write the draft graph to this file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	data, _, err := readInput(fs.Args())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+
+	graph, err := scaffold.FromMarkdown(bytes.NewReader(data))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+
+	rendered, err := scaffold.RenderGraph(graph)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Internal error: %s\n", err)
+		return 2
+	}
+
+	if err := writeOutput(*out, rendered); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+	return 0
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/nixlim/task_templating/internal/handoff"
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// runHandoff implements 'taskval handoff': write one markdown brief per task.
+func runHandoff(args []string) int {
+	fs := flag.NewFlagSet("handoff", flag.ContinueOnError)
+	tasks := fs.String("task", "", "Comma-separated task_ids to brief (default: all tasks)")
+	outDir := fs.String("o", "", "Directory to write <task_id>.md briefs into (default: print to stdout)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	result, _, code := loadValidated(fs.Args(), validator.ModeTaskGraph)
+	if code != 0 {
+		return code
+	}
+	graph := result.Graph
+
+	var ids []string
+	if *tasks != "" {
+		ids = splitList(*tasks)
+	} else {
+		for _, t := range graph.Tasks {
+			ids = append(ids, t.TaskID)
+		}
+	}
+
+	if *outDir != "" {
+		if err := os.MkdirAll(*outDir, 0o755); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: creating '%s': %s\n", *outDir, err)
+			return 2
+		}
+	}
+
+	for i, id := range ids {
+		brief, err := handoff.Brief(graph, id)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return 2
+		}
+
+		if *outDir == "" {
+			if i > 0 {
+				fmt.Print("\n---\n\n")
+			}
+			fmt.Print(brief)
+			continue
+		}
+
+		path := filepath.Join(*outDir, id+".md")
+		if err := writeOutput(path, []byte(brief)); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return 2
+		}
+		fmt.Fprintf(os.Stderr, "Wrote %s\n", path)
+	}
+	return 0
+}
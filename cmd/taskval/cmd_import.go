@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/nixlim/task_templating/internal/scaffold"
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// runImport implements 'taskval import': convert work tracked elsewhere
+// into a draft task graph.
+func runImport(args []string) int {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	from := fs.String("from", "", "Source to import from: 'github', or 'csv' for a spreadsheet file given as the argument")
+	repo := fs.String("repo", "", "GitHub repository to read, as owner/name (token from GITHUB_TOKEN or GH_TOKEN)")
+	milestone := fs.String("milestone", "", "Import only the issues in this milestone, grouped under it")
+	input := fs.String("input", "", "Read issues from this JSON file ('-' for stdin) instead of the API, e.g. saved gh issue list --json output")
+	out := fs.String("o", "", "Write the draft graph to this file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	switch *from {
+	case "csv":
+		if *repo != "" || *milestone != "" || *input != "" {
+			fmt.Fprintf(os.Stderr, "Error: --repo, --milestone, and --input apply to --from=github; give the CSV file as the argument.\n")
+			return 2
+		}
+		return importCSV(fs.Args(), *out)
+	case "github":
+	default:
+		fmt.Fprintf(os.Stderr, "Error: invalid --from '%s'. Must be 'github' or 'csv'.\n", *from)
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "Error: unexpected argument '%s'.\n", fs.Arg(0))
+		return 2
+	}
+	if (*repo == "") == (*input == "") {
+		fmt.Fprintf(os.Stderr, "Error: give either --repo to read from GitHub or --input to read a saved issue list.\n")
+		return 2
+	}
+
+	var issues []scaffold.GitHubIssue
+	if *input != "" {
+		data, _, err := readInput([]string{*input})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return 2
+		}
+		if err := json.Unmarshal(data, &issues); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: parsing '%s': expected a JSON array of issues: %s\n", *input, err)
+			return 2
+		}
+	} else {
+		token := os.Getenv("GITHUB_TOKEN")
+		if token == "" {
+			token = os.Getenv("GH_TOKEN")
+		}
+		var err error
+		issues, err = scaffold.FetchGitHubIssues(context.Background(), scaffold.GitHubAPI, *repo, *milestone, token)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return 2
+		}
+	}
+
+	graph, err := scaffold.FromGitHubIssues(issues, *milestone)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+	return writeDraft(graph, *out)
+}
+
+// importCSV converts the spreadsheet plan named by args (see
+// scaffold.FromCSV) and writes the draft graph.
+func importCSV(args []string, out string) int {
+	data, _, err := readInput(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+	graph, err := scaffold.FromCSV(bytes.NewReader(data))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+	return writeDraft(graph, out)
+}
+
+// writeDraft renders an imported graph to out, or stdout.
+func writeDraft(graph *validator.TaskGraph, out string) int {
+	rendered, err := scaffold.RenderGraph(graph)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Internal error: %s\n", err)
+		return 2
+	}
+	if err := writeOutput(out, rendered); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+	return 0
+}
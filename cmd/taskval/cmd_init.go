@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/nixlim/task_templating/internal/scaffold"
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// runInit implements 'taskval init': print a skeleton document for the
+// chosen mode, or with --from, instantiate a plan template.
+func runInit(args []string) int {
+	fs := flag.NewFlagSet("init", flag.ContinueOnError)
+	mode := fs.String("mode", "graph", "Skeleton to generate: 'task' for a single task node, 'graph' for a full task graph")
+	out := fs.String("o", "", "Write the skeleton to this file instead of stdout")
+	from := fs.String("from", "", "Instantiate the plan template at REPO:NAME (a git repository), an http(s) URL, or a file")
+	vars := make(templateVars)
+	fs.Var(vars, "var", "Template variable as name=value (repeatable); variables not given are prompted for on a terminal")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "Error: unexpected argument '%s'.\n", fs.Arg(0))
+		return 2
+	}
+	if *from != "" {
+		modeSet := false
+		fs.Visit(func(f *flag.Flag) { modeSet = modeSet || f.Name == "mode" })
+		if modeSet {
+			fmt.Fprintf(os.Stderr, "Error: --mode does not apply to --from; templates are task graphs.\n")
+			return 2
+		}
+		return initFromTemplate(*from, vars, *out)
+	}
+	if len(vars) > 0 {
+		fmt.Fprintf(os.Stderr, "Error: --var requires --from.\n")
+		return 2
+	}
+
+	valMode, err := parseMode(*mode)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s.\n", err)
+		return 2
+	}
+
+	data, err := scaffold.Render(valMode)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Internal error: %s\n", err)
+		return 2
+	}
+
+	if err := writeOutput(*out, data); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+	return 0
+}
+
+// templateVars collects repeated --var name=value flags.
+type templateVars map[string]string
+
+func (v templateVars) String() string { return "" }
+
+func (v templateVars) Set(s string) error {
+	name, value, ok := strings.Cut(s, "=")
+	if !ok || name == "" {
+		return fmt.Errorf("expected name=value, got '%s'", s)
+	}
+	v[name] = value
+	return nil
+}
+
+// initFromTemplate fetches the template at source, fills in its variables,
+// and writes the plan if it validates. Nothing is written otherwise.
+func initFromTemplate(source string, vars templateVars, out string) int {
+	data, err := scaffold.FetchTemplate(context.Background(), source)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+	tmpl, err := scaffold.ParseTemplate(data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s: %s\n", source, err)
+		return 2
+	}
+	if stat, err := os.Stdin.Stat(); err == nil && stat.Mode()&os.ModeCharDevice != 0 {
+		if err := promptVariables(os.Stdin, os.Stderr, tmpl, vars); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return 2
+		}
+	}
+	plan, err := tmpl.Instantiate(vars)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s; pass --var name=value.\n", err)
+		return 2
+	}
+
+	result, err := validator.Validate(plan, validator.ModeTaskGraph)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Internal error: %s\n", err)
+		return 2
+	}
+	if !result.Valid {
+		outputText(os.Stderr, result)
+		fmt.Fprintf(os.Stderr, "Error: the plan instantiated from '%s' does not validate; nothing was written.\n", source)
+		return 1
+	}
+	if err := writeOutput(out, plan); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+	return 0
+}
+
+// promptVariables asks on w for each template variable not already in
+// vars, reading answers from r. An empty answer takes the default, or is
+// asked again for a required variable.
+func promptVariables(r io.Reader, w io.Writer, tmpl *scaffold.Template, vars templateVars) error {
+	in := bufio.NewReader(r)
+	for _, v := range tmpl.Variables {
+		if _, ok := vars[v.Name]; ok {
+			continue
+		}
+		prompt := v.Name
+		if v.Description != "" {
+			prompt += " (" + v.Description + ")"
+		}
+		if v.Default != nil {
+			prompt += " [" + *v.Default + "]"
+		}
+		for {
+			fmt.Fprintf(w, "%s: ", prompt)
+			line, err := in.ReadString('\n')
+			if err != nil && line == "" {
+				// Out of input: leave the rest to defaults, and any
+				// required ones to Instantiate's error.
+				fmt.Fprintln(w)
+				return nil
+			}
+			if line = strings.TrimSpace(line); line != "" {
+				vars[v.Name] = line
+				break
+			}
+			if v.Default != nil {
+				break
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// runLintIDs implements 'taskval lint-ids': check only the task_id naming
+// convention (IDS) from a config file, without the other rules.
+func runLintIDs(args []string) int {
+	fs := flag.NewFlagSet("lint-ids", flag.ContinueOnError)
+	configFile := fs.String("config", "", "Validation config file whose task_ids section holds the convention (required)")
+	output := fs.String("output", "text", "Output format: 'text' or 'json'")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *output != "text" && *output != "json" {
+		fmt.Fprintf(os.Stderr, "Error: invalid --output '%s'. Must be 'text' or 'json'.\n", *output)
+		return 2
+	}
+	if *configFile == "" {
+		fmt.Fprintf(os.Stderr, "Error: --config is required.\n")
+		return 2
+	}
+	cfg, err := validator.LoadConfig(*configFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+	if cfg.TaskIDs == nil {
+		fmt.Fprintf(os.Stderr, "Error: config '%s' has no task_ids section.\n", *configFile)
+		return 2
+	}
+
+	data, _, err := readInput(fs.Args())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+	if data, err = validator.ExpandTaskTemplates(data); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: expanding task templates: %s\n", err)
+		return 2
+	}
+	var graph validator.TaskGraph
+	if err := json.Unmarshal(data, &graph); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: parsing task graph: %s\n", err)
+		return 2
+	}
+
+	result := &validator.ValidationResult{Valid: true}
+	result.Stats.TotalTasks = len(graph.Tasks)
+	for _, e := range validator.LintIDs(&graph, *cfg.TaskIDs) {
+		result.AddError(e)
+	}
+	result = cfg.RuleConfig.Apply(result)
+
+	if *output == "json" {
+		if err := writeJSON("-", result); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return 2
+		}
+	} else {
+		outputText(os.Stdout, result)
+	}
+	if !result.Valid {
+		return 1
+	}
+	return 0
+}
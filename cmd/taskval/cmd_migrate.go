@@ -0,0 +1,68 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/nixlim/task_templating/internal/migrate"
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// runMigrate implements 'taskval migrate': upgrade a graph to a newer spec
+// version, write it back, and print the change report.
+func runMigrate(args []string) int {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	latest := validator.SpecVersions[len(validator.SpecVersions)-1]
+	to := fs.String("to", latest, "Target spec version (e.g. 0.2)")
+	out := fs.String("o", "", "Write the migrated graph here instead of overwriting the input ('-' for stdout)")
+	reportPath := fs.String("report", "", "Also write the change report as JSON to this file")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	data, filename, err := readInput(fs.Args())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+
+	graph, report, err := migrate.Migrate(data, *to)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+
+	dest := *out
+	if dest == "" {
+		dest = filename // In place; "-" for stdin input means stdout.
+	}
+	if len(report.Changes) > 0 || dest != filename {
+		if err := writeJSON(dest, graph); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return 2
+		}
+	}
+
+	if *reportPath != "" {
+		if err := writeJSON(*reportPath, report); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return 2
+		}
+	}
+
+	if len(report.Unknown) > 0 {
+		fmt.Fprintf(os.Stderr, "Kept %d field(s) spec %s does not define: %s\n", len(report.Unknown), report.To, strings.Join(report.Unknown, ", "))
+	}
+	if len(report.Changes) == 0 {
+		fmt.Fprintf(os.Stderr, "%s is already at spec %s; nothing to migrate.\n", filename, report.To)
+		return 0
+	}
+	fmt.Fprintf(os.Stderr, "Migrated %s from spec %s to %s (%d change(s)):\n", filename, report.From, report.To, len(report.Changes))
+	for _, c := range report.Changes {
+		fmt.Fprintf(os.Stderr, "  %s: %s\n", c.Path, c.Description)
+	}
+	fmt.Fprintf(os.Stderr, "Review N/A placeholders marked TODO, then run taskval to validate.\n")
+	return 0
+}
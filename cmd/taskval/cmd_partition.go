@@ -0,0 +1,64 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/nixlim/task_templating/internal/analysis"
+	"github.com/nixlim/task_templating/internal/beads"
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// runPartition implements 'taskval partition': split a graph into one
+// sub-graph per agent and write a coordination summary.
+func runPartition(args []string) int {
+	fs := flag.NewFlagSet("partition", flag.ContinueOnError)
+	agents := fs.Int("agents", 2, "Number of agents to split the graph between")
+	outDir := fs.String("o", "partitions", "Directory for the agent-N.json sub-graphs and COORDINATION.md")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *agents < 1 {
+		fmt.Fprintf(os.Stderr, "Error: --agents must be at least 1.\n")
+		return 2
+	}
+
+	result, _, code := loadValidated(fs.Args(), validator.ModeTaskGraph)
+	if code != 0 {
+		return code
+	}
+
+	plan, err := analysis.PartitionGraph(result.Graph, analysis.PartitionOptions{
+		Agents:         *agents,
+		Minutes:        beads.MapEstimate,
+		UnknownMinutes: beads.MapEstimate("medium"),
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+
+	if err := os.MkdirAll(*outDir, 0o755); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: creating '%s': %s\n", *outDir, err)
+		return 2
+	}
+	for _, part := range plan.Parts {
+		path := filepath.Join(*outDir, analysis.PartitionFile(part.Agent))
+		if err := writeJSON(path, part.Graph); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return 2
+		}
+		fmt.Fprintf(os.Stderr, "Wrote %s\n", path)
+	}
+	summary := plan.Summary()
+	path := filepath.Join(*outDir, "COORDINATION.md")
+	if err := writeOutput(path, []byte(summary)); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+	fmt.Fprintf(os.Stderr, "Wrote %s\n", path)
+	fmt.Print(summary)
+	return 0
+}
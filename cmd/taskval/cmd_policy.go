@@ -0,0 +1,61 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/nixlim/task_templating/internal/policy"
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// runPolicy implements 'taskval policy': print the sandbox policy derived
+// from one task's effects and files_scope.
+func runPolicy(args []string) int {
+	fs := flag.NewFlagSet("policy", flag.ContinueOnError)
+	taskID := fs.String("task", "", "task_id to build the policy for (may be omitted when the graph has one task)")
+	format := fs.String("format", "json", "Policy format: 'json' or 'yaml'")
+	out := fs.String("o", "-", "Write the policy to this file ('-' for stdout)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *format != "json" && *format != "yaml" {
+		fmt.Fprintf(os.Stderr, "Error: invalid --format '%s'. Must be 'json' or 'yaml'.\n", *format)
+		return 2
+	}
+
+	result, _, code := loadValidated(fs.Args(), validator.ModeTaskGraph)
+	if code != 0 {
+		return code
+	}
+	graph := result.Graph
+
+	id := *taskID
+	if id == "" {
+		if len(graph.Tasks) != 1 {
+			fmt.Fprintf(os.Stderr, "Error: --task is required when the graph has more than one task.\n")
+			return 2
+		}
+		id = graph.Tasks[0].TaskID
+	}
+
+	p, err := policy.ForTask(graph, id)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+	if !p.EffectsDeclared {
+		fmt.Fprintf(os.Stderr, "Warning: task '%s' declares no effects; the policy grants no access beyond its files_scope.\n", id)
+	}
+
+	if *format == "yaml" {
+		err = writeOutput(*out, p.YAML())
+	} else {
+		err = writeJSON(*out, p)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+	return 0
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/nixlim/task_templating/internal/query"
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// runQuery implements 'taskval query': evaluate a selector against a graph,
+// optionally narrowed by convenience filters, and print the result.
+func runQuery(args []string) int {
+	fs := flag.NewFlagSet("query", flag.ContinueOnError)
+	sel := fs.String("select", "tasks[*].task_id", "JMESPath-style selector, e.g. 'tasks[?priority==critical].task_id'")
+	milestone := fs.String("milestone", "", "Only consider tasks in the milestone with this name")
+	dependsOn := fs.String("depends-on", "", "Only consider tasks that directly depend on this task_id")
+	label := fs.String("label", "", "Only consider tasks carrying this label")
+	noFilesScope := fs.Bool("no-files-scope", false, "Only consider tasks whose files_scope is absent, empty, or N/A")
+	format := fs.String("format", "text", "Output format: 'text' (one value per line) or 'json'")
+	files, err := parseInterspersed(fs, args)
+	if err != nil {
+		return 2
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(os.Stderr, "Error: invalid format '%s'. Must be 'text' or 'json'.\n", *format)
+		return 2
+	}
+
+	expr, err := query.Compile(*sel)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+
+	data, _, err := readInput(files)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+
+	if data, err = validator.ExpandTaskTemplates(data); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: expanding task templates: %s\n", err)
+		return 2
+	}
+
+	// Filters need the typed graph; without them the raw document is
+	// queried so fields are seen exactly as written.
+	filter := query.Filter{Milestone: *milestone, DependsOn: *dependsOn, NoFilesScope: *noFilesScope, Label: *label}
+	if !filter.IsZero() {
+		var graph validator.TaskGraph
+		if err := json.Unmarshal(data, &graph); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: parsing task graph: %s\n", err)
+			return 2
+		}
+		if data, err = json.Marshal(filter.Apply(&graph)); err != nil {
+			fmt.Fprintf(os.Stderr, "Internal error: %s\n", err)
+			return 2
+		}
+	}
+
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: parsing JSON: %s\n", err)
+		return 2
+	}
+	value := expr.Eval(doc)
+
+	if *format == "json" {
+		if err := writeJSON("", value); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return 2
+		}
+		return 0
+	}
+
+	values, ok := value.([]any)
+	if !ok {
+		values = []any{value}
+	}
+	for _, v := range values {
+		switch x := v.(type) {
+		case nil:
+		case string:
+			fmt.Println(x)
+		default:
+			line, _ := json.Marshal(x)
+			fmt.Println(string(line))
+		}
+	}
+	return 0
+}
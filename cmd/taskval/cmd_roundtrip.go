@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// runRoundtrip implements 'taskval roundtrip': check that decoding a
+// document into taskval's model and encoding it again loses nothing, the
+// guarantee rewrite commands such as fmt and migrate rely on.
+func runRoundtrip(args []string) int {
+	fs := flag.NewFlagSet("roundtrip", flag.ContinueOnError)
+	mode := fs.String("mode", "graph", "Input mode: 'task' for a single task node, 'graph' for a full task graph")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	valMode, err := parseMode(*mode)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s.\n", err)
+		return 2
+	}
+	data, filename, err := readInput(fs.Args())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+
+	diffs, err := validator.RoundTrip(data, valMode)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+	if len(diffs) == 0 {
+		fmt.Printf("ROUNDTRIP OK: %s re-encodes without loss\n", filename)
+		return 0
+	}
+
+	fmt.Printf("ROUNDTRIP FAILED: %s changes in %d place(s) when re-encoded\n", filename, len(diffs))
+	for _, d := range diffs {
+		path := d.Path
+		if path == "" {
+			path = "(document)"
+		}
+		switch d.Kind {
+		case validator.DiffDropped:
+			fmt.Printf("  dropped  %s: %s\n", path, d.Before)
+		case validator.DiffAdded:
+			fmt.Printf("  added    %s: %s\n", path, d.After)
+		default:
+			fmt.Printf("  changed  %s: %s -> %s\n", path, d.Before, d.After)
+		}
+	}
+	return 1
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/nixlim/task_templating/internal/scaffold"
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// runScaffold implements 'taskval scaffold': generate a test harness for one task.
+func runScaffold(args []string) int {
+	fs := flag.NewFlagSet("scaffold", flag.ContinueOnError)
+	lang := fs.String("lang", "go", "Language of the generated test skeleton (supported: go)")
+	taskID := fs.String("task", "", "task_id to scaffold (required in graph mode)")
+	mode := fs.String("mode", "graph", "Input mode: 'task' for a single task node, 'graph' for a full task graph")
+	pkg := fs.String("package", "", "Package name for the generated file (default: derived from files_scope)")
+	out := fs.String("o", "", "Write the test file here instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if *lang != "go" {
+		fmt.Fprintf(os.Stderr, "Error: unsupported language '%s'. Supported: go.\n", *lang)
+		return 2
+	}
+	valMode, err := parseMode(*mode)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s.\n", err)
+		return 2
+	}
+	if valMode == validator.ModeTaskGraph && *taskID == "" {
+		fmt.Fprintf(os.Stderr, "Error: --task is required in graph mode.\n")
+		return 2
+	}
+
+	result, _, code := loadValidated(fs.Args(), valMode)
+	if code != 0 {
+		return code
+	}
+	if *taskID == "" {
+		*taskID = result.Graph.Tasks[0].TaskID
+	}
+
+	task, err := result.Graph.ResolvedTask(*taskID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+
+	src, err := scaffold.GoTestStub(task, *pkg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Internal error: %s\n", err)
+		return 2
+	}
+	if err := writeOutput(*out, src); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+	return 0
+}
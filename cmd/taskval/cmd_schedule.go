@@ -0,0 +1,78 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/nixlim/task_templating/internal/beads"
+	"github.com/nixlim/task_templating/internal/schedule"
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// runSchedule implements 'taskval schedule': print the estimated execution
+// plan and critical path for a given number of parallel workers.
+func runSchedule(args []string) int {
+	fs := flag.NewFlagSet("schedule", flag.ContinueOnError)
+	workers := fs.Int("workers", 1, "Number of tasks that can run in parallel")
+	unit := fs.String("estimate-unit", "", "Report times in minutes, hours, pomodoros, or points[:N] (N minutes per point); default hours and minutes")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	format := formatMinutes
+	if *unit != "" {
+		u, err := beads.ParseEstimateUnit(*unit)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s.\n", err)
+			return 2
+		}
+		format = u.Format
+	}
+
+	result, _, code := loadValidated(fs.Args(), validator.ModeTaskGraph)
+	if code != 0 {
+		return code
+	}
+
+	sched, err := schedule.Compute(result.Graph, schedule.Options{
+		Workers:        *workers,
+		Minutes:        beads.MapEstimate,
+		UnknownMinutes: beads.MapEstimate("medium"),
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+
+	fmt.Printf("SCHEDULE (%d worker(s))\n\n", max(*workers, 1))
+	for _, s := range sched.Slots {
+		marker := " "
+		if s.Critical {
+			marker = "*"
+		}
+		fmt.Printf("  %s w%-2d %8s -> %-8s %s\n", marker, s.Worker, format(s.Start), format(s.End), s.TaskID)
+	}
+	fmt.Printf("\n  Makespan:      %s\n", format(sched.Makespan))
+	fmt.Printf("  Critical path: %s (%s)\n", strings.Join(sched.CriticalPath, " -> "), format(sched.CriticalMinutes))
+	fmt.Println("  (* marks critical-path tasks; unknown estimates count as medium)")
+	if risky := sched.CriticalRisks(result.Graph); len(risky) > 0 {
+		fmt.Println("\n  High-risk tasks on the critical path:")
+		for _, t := range risky {
+			mitigation := t.Risk.Mitigation
+			if mitigation == "" {
+				mitigation = "no mitigation"
+			}
+			fmt.Printf("    %s: %s\n", t.TaskID, mitigation)
+		}
+	}
+	return 0
+}
+
+// formatMinutes renders working minutes as hours and minutes.
+func formatMinutes(m int) string {
+	if m%60 == 0 {
+		return fmt.Sprintf("%dh", m/60)
+	}
+	return fmt.Sprintf("%dh%02dm", m/60, m%60)
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/nixlim/task_templating/internal/analysis"
+	"github.com/nixlim/task_templating/internal/beads"
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// runScore implements 'taskval score': rank the tasks that are ready to
+// start and print the dispatch queue as JSON.
+func runScore(args []string) int {
+	fs := flag.NewFlagSet("score", flag.ContinueOnError)
+	done := fs.String("done", "", "Comma-separated task_ids that are already finished")
+	weights := fs.String("weights", "", "Score weights as name=value pairs, e.g. priority=2,unblocks=1,estimate=0.5 (each defaults to 1)")
+	out := fs.String("o", "-", "Write the queue to this file ('-' for stdout)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	w, err := analysis.ParseScoreWeights(*weights)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid --weights: %s.\n", err)
+		return 2
+	}
+
+	result, _, code := loadValidated(fs.Args(), validator.ModeTaskGraph)
+	if code != 0 {
+		return code
+	}
+
+	var doneIDs []string
+	if *done != "" {
+		doneIDs = splitList(*done)
+	}
+	queue, err := analysis.ScoreReady(result.Graph, analysis.ScoreOptions{
+		Weights:        w,
+		Done:           doneIDs,
+		Minutes:        beads.MapEstimate,
+		UnknownMinutes: beads.MapEstimate("medium"),
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+	if err := writeJSON(*out, queue); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+	return 0
+}
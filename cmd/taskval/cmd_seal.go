@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// runSeal implements 'taskval seal': validate a graph, then embed the
+// SHA-256 of its canonical form so later edits fail --verify-seal.
+func runSeal(args []string) int {
+	fs := flag.NewFlagSet("seal", flag.ContinueOnError)
+	outPath := fs.String("o", "", "Write the sealed graph to this path instead of rewriting the input ('-' for stdout)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	data, filename, err := readInput(fs.Args())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+	dest := *outPath
+	if dest == "" {
+		dest = filename
+	}
+
+	// Only a valid graph is worth sealing.
+	result, err := validator.Validate(data, validator.ModeTaskGraph)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Internal error: %s\n", err)
+		return 2
+	}
+	if !result.Valid {
+		outputText(os.Stderr, result)
+		fmt.Fprintf(os.Stderr, "\nError: refusing to seal a graph that fails validation.\n")
+		return 1
+	}
+
+	// Task templates are expanded in the sealed graph, so the seal
+	// approves the concrete tasks; that must not replace the source.
+	if validator.UsesTaskTemplates(data) && dest == filename {
+		fmt.Fprintf(os.Stderr, "Error: %s uses task_templates, and the sealed graph has them expanded; write it elsewhere with -o.\n", filename)
+		return 2
+	}
+
+	// The graph passed the schema, so it has no field the model would
+	// drop from the output and leave out of the digest.
+	graph := result.Graph
+	if err := graph.ApplySeal(); err != nil {
+		fmt.Fprintf(os.Stderr, "Internal error: %s\n", err)
+		return 2
+	}
+	if err := writeJSON(dest, graph); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+	fmt.Fprintf(os.Stderr, "Sealed %s (%s:%s)\n", filename, graph.Seal.Algorithm, graph.Seal.Digest)
+	return 0
+}
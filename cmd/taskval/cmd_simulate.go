@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/nixlim/task_templating/internal/beads"
+	"github.com/nixlim/task_templating/internal/schedule"
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// runSimulate implements 'taskval simulate': a Monte Carlo forecast of when
+// the graph and each milestone complete, as P50/P80/P95 working times.
+func runSimulate(args []string) int {
+	fs := flag.NewFlagSet("simulate", flag.ContinueOnError)
+	iterations := fs.Int("iterations", 1000, "Number of sampled schedules")
+	workers := fs.Int("workers", 1, "Number of tasks that can run in parallel")
+	dist := fs.String("distribution", schedule.Triangular, "Task duration distribution around the estimate: "+strings.Join(schedule.Distributions, ", "))
+	seed := fs.Uint64("seed", 1, "Random seed; the same seed and input give the same forecast")
+	unit := fs.String("estimate-unit", "", "Report times in minutes, hours, pomodoros, or points[:N] (N minutes per point); default hours and minutes")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	format := formatMinutes
+	if *unit != "" {
+		u, err := beads.ParseEstimateUnit(*unit)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s.\n", err)
+			return 2
+		}
+		format = u.Format
+	}
+
+	result, _, code := loadValidated(fs.Args(), validator.ModeTaskGraph)
+	if code != 0 {
+		return code
+	}
+
+	forecast, err := schedule.Simulate(result.Graph, schedule.Options{
+		Workers:        *workers,
+		Minutes:        beads.MapEstimate,
+		UnknownMinutes: beads.MapEstimate("medium"),
+	}, schedule.SimulateOptions{Iterations: *iterations, Distribution: *dist, Seed: *seed})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+
+	fmt.Printf("FORECAST (%d iterations, %d worker(s), %s)\n\n", forecast.Iterations, max(*workers, 1), *dist)
+	width := len("Graph")
+	for _, m := range forecast.Milestones {
+		width = max(width, len(m.Name))
+	}
+	row := func(name string, p schedule.Percentiles) {
+		fmt.Printf("  %-*s  %9s  %9s  %9s\n", width, name, format(p.P50), format(p.P80), format(p.P95))
+	}
+	fmt.Printf("  %-*s  %9s  %9s  %9s\n", width, "", "P50", "P80", "P95")
+	row("Graph", forecast.Graph)
+	for _, m := range forecast.Milestones {
+		row(m.Name, m.Percentiles)
+	}
+	fmt.Println("\n  (working time from the start of the plan; unknown estimates count as medium)")
+	return 0
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/nixlim/task_templating/internal/analysis"
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// runSuggestMilestones implements 'taskval suggest-milestones': propose a
+// milestones block for a graph from its dependency structure.
+func runSuggestMilestones(args []string) int {
+	fs := flag.NewFlagSet("suggest-milestones", flag.ContinueOnError)
+	strategy := fs.String("strategy", analysis.StrategyLayers, "Grouping: 'layers' (by dependency depth) or 'components' (one per independent workstream)")
+	maxTasks := fs.Int("max-tasks", 8, "Largest number of tasks in one milestone")
+	minTasks := fs.Int("min-tasks", 2, "Milestones smaller than this absorb the next dependency layer")
+	output := fs.String("o", "", "Write the milestones block to this file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	result, _, code := loadValidated(fs.Args(), validator.ModeTaskGraph)
+	if code != 0 {
+		return code
+	}
+
+	milestones, err := analysis.SuggestMilestones(result.Graph, analysis.MilestoneOptions{Strategy: *strategy, MaxTasks: *maxTasks, MinTasks: *minTasks})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+	if n := len(result.Graph.Milestones); n > 0 {
+		fmt.Fprintf(os.Stderr, "Note: the graph already defines %d milestone(s); the proposal ignores them.\n", n)
+	}
+
+	block := struct {
+		Milestones []validator.Milestone `json:"milestones"`
+	}{milestones}
+	if err := writeJSON(*output, block); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+	return 0
+}
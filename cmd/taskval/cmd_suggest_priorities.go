@@ -0,0 +1,73 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/nixlim/task_templating/internal/analysis"
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// runSuggestPriorities implements 'taskval suggest-priorities': report
+// tasks whose priority is low for how much of the graph they block, and
+// with --apply, raise them.
+func runSuggestPriorities(args []string) int {
+	fs := flag.NewFlagSet("suggest-priorities", flag.ContinueOnError)
+	apply := fs.Bool("apply", false, "Raise the flagged priorities and write the graph back to the input file (stdout for stdin)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	result, filename, code := loadValidated(fs.Args(), validator.ModeTaskGraph)
+	if code != 0 {
+		return code
+	}
+	graph := result.Graph
+
+	suggestions, err := analysis.SuggestPriorities(graph)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+
+	// With --apply the graph may go to stdout, so the report goes to stderr.
+	report := os.Stdout
+	if *apply {
+		report = os.Stderr
+	}
+	if len(suggestions) == 0 {
+		fmt.Fprintln(report, "No priority changes suggested.")
+	} else {
+		fmt.Fprintf(report, "PRIORITY SUGGESTIONS (%d)\n\n", len(suggestions))
+		for _, s := range suggestions {
+			fmt.Fprintf(report, "  %-30s blocks %2d task(s)  %s -> %s\n", s.TaskID, s.Blocked, priorityLabel(s.Current), s.Suggested)
+		}
+	}
+	if !*apply || len(suggestions) == 0 {
+		return 0
+	}
+
+	analysis.ApplyPriorities(graph, suggestions)
+	if prev := graph.GraphRevision; prev != "" {
+		rev, err := graph.BumpRevision(validator.RevisionPatch)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return 2
+		}
+		fmt.Fprintf(os.Stderr, "graph_revision: %s -> %s\n", prev, rev)
+	}
+	if err := writeJSON(filename, graph); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+	return 0
+}
+
+// priorityLabel renders a priority for reports, showing unset as "(unset)".
+func priorityLabel(l validator.Level) string {
+	if l == "" {
+		return "(unset)"
+	}
+	return string(l)
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// runValidateDesign implements 'taskval validate-design': check a _template
+// metadata blob, as stored in a bd issue's design field, on its own.
+func runValidateDesign(args []string) int {
+	fs := flag.NewFlagSet("validate-design", flag.ContinueOnError)
+	output := fs.String("output", "text", "Output format: 'text' or 'json'")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *output != "text" && *output != "json" {
+		fmt.Fprintf(os.Stderr, "Error: invalid output format '%s'. Must be 'text' or 'json'.\n", *output)
+		return 2
+	}
+
+	data, _, err := readInput(fs.Args())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+	result, err := validator.ValidateDesign(data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+
+	switch *output {
+	case "text":
+		outputText(os.Stdout, result)
+	case "json":
+		outputJSON(os.Stdout, result, nil, nil, nil, nil)
+	}
+	if !result.Valid {
+		return 1
+	}
+	return 0
+}
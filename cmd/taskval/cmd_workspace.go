@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/nixlim/task_templating/internal/workspace"
+)
+
+// runWorkspace implements 'taskval workspace': validate every graph file
+// under a directory as one project and optionally write the merged graph.
+func runWorkspace(args []string) int {
+	fs := flag.NewFlagSet("workspace", flag.ContinueOnError)
+	output := fs.String("output", "text", "Output format: 'text' or 'json'")
+	merged := fs.String("o", "", "On success, write the merged task graph to this file ('-' for stdout)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *output != "text" && *output != "json" {
+		fmt.Fprintf(os.Stderr, "Error: invalid output format '%s'. Must be 'text' or 'json'.\n", *output)
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintf(os.Stderr, "Error: expected exactly one workspace directory, got %d\n", fs.NArg())
+		return 2
+	}
+	if *merged == "-" && *output == "json" {
+		fmt.Fprintf(os.Stderr, "Error: -o - cannot be combined with --output=json.\n")
+		return 2
+	}
+
+	res, err := workspace.Validate(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+
+	// The report goes to stderr when stdout carries the merged graph.
+	reportTo := os.Stdout
+	if *merged == "-" {
+		reportTo = os.Stderr
+	}
+	switch *output {
+	case "text":
+		fmt.Fprintf(reportTo, "Workspace: %s (%d graph file(s): %s)\n\n", fs.Arg(0), len(res.Files), strings.Join(res.Files, ", "))
+		outputText(reportTo, res.Report)
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(res)
+	}
+
+	if !res.Report.Valid {
+		return 1
+	}
+	if *merged != "" {
+		if err := writeJSON(*merged, res.Graph); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return 2
+		}
+	}
+	return 0
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// runWrap implements 'taskval wrap': promote a standalone task into a one-task graph.
+func runWrap(args []string) int {
+	fs := flag.NewFlagSet("wrap", flag.ContinueOnError)
+	out := fs.String("o", "", "Write the graph to this file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	result, _, code := loadValidated(fs.Args(), validator.ModeSingleTask)
+	if code != 0 {
+		return code
+	}
+
+	if err := writeJSON(*out, validator.WrapTask(result.Graph.Tasks[0])); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+	return 0
+}
+
+// runExtract implements 'taskval extract': pull one task out of a graph with
+// the graph defaults merged in, ready to hand to an agent or validate alone.
+func runExtract(args []string) int {
+	fs := flag.NewFlagSet("extract", flag.ContinueOnError)
+	taskID := fs.String("task", "", "task_id of the task to extract (required)")
+	out := fs.String("o", "", "Write the task to this file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *taskID == "" {
+		fmt.Fprintf(os.Stderr, "Error: --task is required.\n")
+		return 2
+	}
+
+	result, _, code := loadValidated(fs.Args(), validator.ModeTaskGraph)
+	if code != 0 {
+		return code
+	}
+
+	task, err := result.Graph.ResolvedTask(*taskID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+
+	if err := writeJSON(*out, task); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+	return 0
+}
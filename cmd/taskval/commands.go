@@ -0,0 +1,243 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// command is a taskval subcommand dispatched by name from the first argument.
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) int
+}
+
+// commands returns all registered subcommands in display order.
+func commands() []command {
+	return []command{
+		{"init", "Print a schema-conforming skeleton task or task graph", runInit},
+		{"from-markdown", "Convert a markdown plan into a draft task graph", runFromMarkdown},
+		{"import", "Convert tracker issues or a CSV plan into a draft task graph (--from)", runImport},
+		{"wrap", "Wrap a single task into a one-task graph", runWrap},
+		{"extract", "Extract one task from a graph with defaults resolved", runExtract},
+		{"handoff", "Write a self-contained markdown brief per task for agent handoff", runHandoff},
+		{"policy", "Derive an agent sandbox policy from a task's effects and files_scope", runPolicy},
+		{"export", "Render a validated graph for another tool (--target)", runExport},
+		{"schedule", "Estimate start/end times and the critical path for N workers", runSchedule},
+		{"cost", "Estimate spend per milestone from cost fields or agent-hours times --rate", runCost},
+		{"simulate", "Forecast P50/P80/P95 completion times by Monte Carlo sampling", runSimulate},
+		{"score", "Rank ready tasks by a weighted score into a JSON dispatch queue", runScore},
+		{"suggest-priorities", "Flag low-priority tasks that block much of the graph (--apply raises them)", runSuggestPriorities},
+		{"suggest-milestones", "Propose milestones from the graph's dependency layers or workstreams", runSuggestMilestones},
+		{"partition", "Split a graph into weakly coupled sub-graphs, one per agent", runPartition},
+		{"lint-ids", "Check task_ids against the naming convention in a config file", runLintIDs},
+		{"validate-design", "Check a _template metadata blob from a bd issue's design field", runValidateDesign},
+		{"scaffold", "Generate a test skeleton with one test per acceptance criterion", runScaffold},
+		{"fmt", "Rewrite a graph in canonical form, bumping graph_revision", runFmt},
+		{"roundtrip", "Check that a document survives decoding and re-encoding without loss", runRoundtrip},
+		{"seal", "Embed a SHA-256 of a validated graph's canonical form (see --verify-seal)", runSeal},
+		{"migrate", "Upgrade a graph to a newer spec version with a change report", runMigrate},
+		{"query", "Select values from a graph with a JMESPath-style expression", runQuery},
+		{"workspace", "Validate all graph files in a directory tree as one project", runWorkspace},
+		{"doctor", "Check bd, beads, schemas, a config file, and write access, with fixes", runDoctor},
+		{"compare-runs", "Diff two JSON validation reports: new, fixed, and unchanged findings", runCompareRuns},
+		{"serve", "Serve the TaskVal gRPC service (Validate, PlanBeads, ApplyPlan)", runServe},
+	}
+}
+
+// lookupCommand returns the subcommand with the given name, or nil.
+func lookupCommand(name string) *command {
+	for _, c := range commands() {
+		if c.name == name {
+			return &c
+		}
+	}
+	return nil
+}
+
+// printCommands writes the subcommand summary table used in usage output.
+func printCommands(w io.Writer) {
+	for _, c := range commands() {
+		fmt.Fprintf(w, "  %-18s %s\n", c.name, c.summary)
+	}
+}
+
+// parseMode converts a --mode flag value into a validator mode.
+func parseMode(mode string) (validator.Mode, error) {
+	switch mode {
+	case "task":
+		return validator.ModeSingleTask, nil
+	case "graph":
+		return validator.ModeTaskGraph, nil
+	default:
+		return 0, fmt.Errorf("invalid mode '%s'. Must be 'task' or 'graph'", mode)
+	}
+}
+
+// parseInterspersed parses args with fs, accepting flags after positional
+// arguments as well as before them, so 'query plan.json --select x' works
+// like 'query --select x plan.json'. Everything after "--" is positional.
+// It returns the positional arguments in order.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		rest := fs.Args()
+		if len(rest) == 0 {
+			return positional, nil
+		}
+		if consumed := len(args) - len(rest); consumed > 0 && args[consumed-1] == "--" {
+			return append(positional, rest...), nil
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+// writeOutput writes data to the named file, or to stdout when path is empty or "-".
+func writeOutput(path string, data []byte) error {
+	if path == "" || path == "-" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("writing '%s': %w", path, err)
+	}
+	return nil
+}
+
+// loadValidated reads and validates the input named by args. On failure it
+// prints the reason (or the validation report) to stderr and returns a
+// non-zero exit code; callers return that code unchanged.
+func loadValidated(args []string, mode validator.Mode) (*validator.ValidationResult, string, int) {
+	data, filename, err := readInput(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return nil, filename, 2
+	}
+
+	result, err := validator.Validate(data, mode)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Internal error: %s\n", err)
+		return nil, filename, 2
+	}
+	if !result.Valid {
+		outputText(os.Stderr, result)
+		return nil, filename, 1
+	}
+	return result, filename, 0
+}
+
+// writeJSON writes v as indented JSON to the named file or stdout. HTML
+// escaping is disabled so constraint expressions like "x > 0" stay readable.
+func writeJSON(path string, v any) error {
+	data, err := encodeJSON(v)
+	if err != nil {
+		return err
+	}
+	return writeOutput(path, data)
+}
+
+// encodeJSON returns v as indented JSON with a trailing newline, the
+// canonical on-disk form used by commands that write graphs.
+func encodeJSON(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return nil, fmt.Errorf("marshaling output: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// readIDList reads task_ids from a file, one per line. Blank lines and
+// lines starting with '#' are ignored.
+func readIDList(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading file '%s': %w", path, err)
+	}
+	defer f.Close()
+
+	var ids []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		ids = append(ids, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading file '%s': %w", path, err)
+	}
+	return ids, nil
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries.
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// byteUnits are the size suffixes parseByteSize accepts, largest first.
+var byteUnits = []struct {
+	suffix string
+	size   int64
+}{
+	{"GB", 1 << 30},
+	{"MB", 1 << 20},
+	{"KB", 1 << 10},
+	{"B", 1},
+}
+
+// parseByteSize parses a size such as "50MB", "512KB", or "1048576". The
+// units are powers of 1024 and case-insensitive.
+func parseByteSize(s string) (int64, error) {
+	num, unit := strings.TrimSpace(s), int64(1)
+	for _, u := range byteUnits {
+		if rest, ok := strings.CutSuffix(strings.ToUpper(num), u.suffix); ok {
+			num, unit = strings.TrimSpace(rest), u.size
+			break
+		}
+	}
+	n, err := strconv.ParseInt(num, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("'%s' is not a size; use bytes or a number with KB, MB, or GB", s)
+	}
+	if n > math.MaxInt64/unit {
+		return 0, fmt.Errorf("'%s' is too large", s)
+	}
+	return n * unit, nil
+}
+
+// formatByteSize prints n in the largest unit that divides it evenly.
+func formatByteSize(n int64) string {
+	for _, u := range byteUnits {
+		if n >= u.size && n%u.size == 0 {
+			if u.size == 1 {
+				return strconv.FormatInt(n, 10)
+			}
+			return strconv.FormatInt(n/u.size, 10) + u.suffix
+		}
+	}
+	return strconv.FormatInt(n, 10)
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseInterspersed(t *testing.T) {
+	for _, tc := range []struct {
+		args      []string
+		positions []string
+		sel       string
+	}{
+		{[]string{"--select", "x", "plan.json"}, []string{"plan.json"}, "x"},
+		{[]string{"plan.json", "--select", "x"}, []string{"plan.json"}, "x"},
+		{[]string{"a.json", "--select=x", "b.json"}, []string{"a.json", "b.json"}, "x"},
+		{[]string{"--", "--select", "x"}, []string{"--select", "x"}, ""},
+		{[]string{"-"}, []string{"-"}, ""},
+	} {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		sel := fs.String("select", "", "")
+		got, err := parseInterspersed(fs, tc.args)
+		if err != nil || !slices.Equal(got, tc.positions) || *sel != tc.sel {
+			t.Errorf("parseInterspersed(%q) = %q, select %q, %v; want %q, select %q", tc.args, got, *sel, err, tc.positions, tc.sel)
+		}
+	}
+}
+
+// TestQueryFlagsAfterFile runs the documented 'taskval query plan.json
+// --select ...' form.
+func TestQueryFlagsAfterFile(t *testing.T) {
+	plan := filepath.Join(t.TempDir(), "plan.json")
+	doc := `{"version": "0.1.0", "tasks": [{"task_id": "a", "task_name": "Task A"}, {"task_id": "b", "task_name": "Task B"}]}`
+	if err := os.WriteFile(plan, []byte(doc), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout = w
+	code := runQuery([]string{plan, "--select", "tasks[*].task_name"})
+	w.Close()
+	os.Stdout = stdout
+	out, _ := io.ReadAll(r)
+
+	if code != 0 || strings.TrimSpace(string(out)) != "Task A\nTask B" {
+		t.Errorf("runQuery = %d, output %q; want 0 and the task names", code, out)
+	}
+}
@@ -6,34 +6,112 @@
 //	taskval --mode=task <single_task.json>
 //	taskval --mode=graph <task_graph.json>
 //	cat task.json | taskval --mode=task -
+//	cat plan.json | taskval --filename=plan.json -
+//	taskval <command> [flags] [args]
+//
+// Commands:
+//
+//	init           Print a schema-conforming skeleton task or task graph
+//	from-markdown  Convert a markdown plan into a draft task graph
+//	import         Convert tracker issues or a CSV plan into a draft task graph (--from)
+//	wrap           Wrap a single task into a one-task graph
+//	extract        Extract one task from a graph with defaults resolved
+//	handoff        Write a self-contained markdown brief per task for agent handoff
+//	policy         Derive an agent sandbox policy from a task's effects and files_scope
+//	export         Render a validated graph for another tool (--target)
+//	schedule       Estimate start/end times and the critical path for N workers
+//	cost           Estimate spend per milestone from cost fields or agent-hours times --rate
+//	simulate       Forecast P50/P80/P95 completion times by Monte Carlo sampling
+//	score          Rank ready tasks by a weighted score into a JSON dispatch queue
+//	suggest-priorities  Flag low-priority tasks that block much of the graph (--apply raises them)
+//	suggest-milestones  Propose milestones from the graph's dependency layers or workstreams
+//	partition      Split a graph into weakly coupled sub-graphs, one per agent
+//	lint-ids       Check task_ids against the naming convention in a config file
+//	validate-design  Check a _template metadata blob from a bd issue's design field
+//	scaffold       Generate a test skeleton with one test per acceptance criterion
+//	fmt            Rewrite a graph in canonical form, bumping graph_revision
+//	roundtrip      Check that a document survives decoding and re-encoding without loss
+//	seal           Embed a SHA-256 of a validated graph's canonical form
+//	migrate        Upgrade a graph to a newer spec version with a change report
+//	query          Select values from a graph with a JMESPath-style expression
+//	workspace      Validate all graph files in a directory tree as one project
+//	doctor         Check bd, beads, schemas, a config file, and write access, with fixes
+//	compare-runs   Diff two JSON validation reports: new, fixed, and unchanged findings
 //
 // Output format:
 //
 //	--output=text   Human/LLM-readable text (default)
 //	--output=json   Machine-readable JSON
+//	--output=html   Self-contained HTML report with an interactive dependency graph
+//	--output=proto  Binary Report message of proto/taskval.proto
+//	--output=slack  Compact Slack-flavored markdown summary for chat-ops posting
+//	--progress=jsonl  Write one JSON event per step (rules, bd commands) to stderr
+//	--output-file   Also write the results to a file, in --report-format (json, text, html)
+//	--max-input-size  Refuse larger input (default 50MB; 0 for no limit)
 //
 // Beads integration:
 //
 //	--create-beads  On validation success, create Beads issues via bd CLI
 //	--dry-run       Show bd commands that would be executed (requires --create-beads)
+//	--dry-run-full  --dry-run including design metadata updates and report comments
 //	--epic-title    Override the auto-generated epic title (graph mode only)
+//	--epic-estimate Epic estimate from its tasks: sum, critical-path, or none (default)
+//	--milestone-label-prefix  Label tasks with their milestones, e.g. milestone:m1 (empty to turn off)
+//	--parent-epic   Parent the tasks under this existing, open epic instead of creating one
+//	--skip-tasks    Leave these task_ids (comma-separated) out of creation; links to them are dropped
+//	--only-tasks    Create only these task_ids (comma-separated); links to the rest are dropped
+//	--acceptance-style      bullets (default) or checkboxes for the issue acceptance list
+//	--description-template  Go text/template file for issue descriptions
+//	--attach-report Comment each created issue with that task's warnings and infos
+//	--design-extensions     Copy each task's x_ fields into its design metadata
+//	--on-duplicate  When a task already has an open issue: skip, update, or error
+//	--notify-webhook        POST the creation result as JSON to this URL when done
+//
+// Validation options:
+//
+//	--schema-only   Run only Tier 1 (JSON Schema) checks
+//	--semantic-only Run only Tier 2 checks on input assumed to be schema-valid
+//	--profile       Rule profile: minimal, standard (default), or strict
+//	--repo-root     Check files_scope entries against a checked-out repository
+//	--config        JSON file of rule adjustments and custom rules (external commands)
+//	--llm-review    Add LLM findings from an OpenAI-compatible model (opt-in; see --llm-endpoint, --llm-model)
+//	--codeowners    Report each task's owning teams from a CODEOWNERS file
+//	--verify-seal   Fail unless the graph matches the seal written by 'taskval seal'
+//	--require-verification  Fail when an acceptance criterion has no verification entry
+//	--task          Validate only these task_ids (comma-separated); marks the result partial
+//	--external-deps File of task_ids defined in other graphs that depends_on may reference
+//	--filename      Name to report for stdin input (derived epic title, HTML report title)
+//	--parse-always  Include the parsed graph in JSON output even when validation fails
+//	--lang          Language of finding messages and suggestions: en (default) or de
+//	--fail-on       Findings that fail the run: error (default), warning, or never
+//	--warnings-exit-code  Exit code for a pass with warnings (e.g. 3; default 0)
+//	--max-warnings  Fail when there are more than N warnings, even without errors
 //
 // Exit codes:
 //
 //	0   Validation passed (no errors; warnings may be present)
-//	1   Validation failed (one or more errors)
+//	1   Validation failed (one or more errors; with --fail-on=warning, or warnings;
+//	    with --max-warnings, more warnings than allowed)
 //	2   Usage error, internal error, or bd command failure
+//	3   Validation passed with warnings, when --warnings-exit-code=3
 package main
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"io"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/nixlim/task_templating/internal/beads"
+	"github.com/nixlim/task_templating/internal/i18n"
+	"github.com/nixlim/task_templating/internal/llmreview"
+	"github.com/nixlim/task_templating/internal/protoreport"
+	"github.com/nixlim/task_templating/internal/report"
 	"github.com/nixlim/task_templating/internal/validator"
 )
 
@@ -42,40 +120,207 @@ func main() {
 }
 
 func run() int {
+	// Subcommands take precedence over the default validate behavior.
+	if len(os.Args) > 1 {
+		if cmd := lookupCommand(os.Args[1]); cmd != nil {
+			return cmd.run(os.Args[2:])
+		}
+	}
+
 	mode := flag.String("mode", "graph", "Validation mode: 'task' for a single task node, 'graph' for a full task graph")
-	output := flag.String("output", "text", "Output format: 'text' for human/LLM-readable, 'json' for machine-readable")
+	output := flag.String("output", "text", "Output format: 'text' for human/LLM-readable, 'json' for machine-readable, 'html' for a self-contained report, 'proto' for a binary Report message (proto/taskval.proto), 'slack' for a chat summary")
+	progressFormat := flag.String("progress", "", "Write progress events to stderr: 'jsonl' for one JSON object per line (validation start and end, each rule, each bd command); default none")
+	maxInput := flag.String("max-input-size", formatByteSize(defaultMaxInputSize), "Refuse input larger than this: bytes, or a number with KB, MB, or GB (powers of 1024); 0 for no limit")
+	outputFile := flag.String("output-file", "", "Also write the results to this file in --report-format, whatever --output prints to the console")
+	reportFormat := flag.String("report-format", "json", "Format of --output-file: 'json', 'text', or 'html'")
 	createBeads := flag.Bool("create-beads", false, "On validation success, create Beads issues via bd CLI")
 	dryRun := flag.Bool("dry-run", false, "Show bd commands that would be executed (requires --create-beads)")
+	dryRunFull := flag.Bool("dry-run-full", false, "Like --dry-run, but also list the design metadata updates (pretty-printed) and report comments")
 	epicTitle := flag.String("epic-title", "", "Override the auto-generated epic title (graph mode only)")
+	epicEstimate := flag.String("epic-estimate", beads.EpicEstimateNone, "Set the created epic's estimate from its tasks: 'sum' of their estimates, 'critical-path' length, or 'none' (graph mode only)")
+	milestoneLabelPrefix := flag.String("milestone-label-prefix", beads.DefaultMilestoneLabelPrefix, "Label each created task with this prefix and the kebab-case name of each milestone listing it (graph mode); empty for no milestone labels")
+	parentEpic := flag.String("parent-epic", "", "bd ID of an existing, open epic to parent the tasks under instead of creating one (e.g. bd-123)")
+	skipTasks := flag.String("skip-tasks", "", "Leave these task_ids (comma-separated) out of beads creation; dependency links to or from them are dropped and reported (graph mode only)")
+	onlyTasks := flag.String("only-tasks", "", "Create issues for only these task_ids (comma-separated); dependency links to or from the rest are dropped and reported (graph mode only)")
+	acceptanceStyle := flag.String("acceptance-style", beads.AcceptanceBullets, "Issue acceptance list style: 'bullets' (- item) or 'checkboxes' (- [ ] item)")
+	descTemplate := flag.String("description-template", "", "Go text/template file rendering each issue description, executed with the task node")
+	attachReport := flag.Bool("attach-report", false, "Post each task's validation findings (warnings, infos) as a comment on its created issue")
+	designExtensions := flag.Bool("design-extensions", false, "Copy each task's x_ extension fields into its bd design metadata (_template.extensions)")
+	notifyWebhook := flag.String("notify-webhook", "", "After creating issues (or failing part way), POST the result as JSON to this http(s) URL")
+	onDuplicate := flag.String("on-duplicate", "", "Check for open issues matching each task (by _template.task_id or title) and 'skip', 'update', or 'error'; default creates without checking")
+	profile := flag.String("profile", "standard", "Rule profile: 'minimal' (schema and references only), 'standard', or 'strict' (opt-in rules on, warnings become errors)")
+	repoRoot := flag.String("repo-root", "", "Check files_scope entries against the repository at this directory (REPO rule)")
+	configFile := flag.String("config", "", "Validation config file (JSON): rule adjustments (disabled, enabled, severity, limits, warnings_as_errors) layered on --profile, and custom_rules run as external commands")
+	codeOwners := flag.String("codeowners", "", "Resolve files_scope against this CODEOWNERS file and report each task's owning teams (OWNERS rule)")
+	llmReview := flag.Bool("llm-review", false, "Ask an OpenAI-compatible model to critique goals and acceptance criteria (LLM rule); off by default, nothing is sent without it")
+	llmEndpoint := flag.String("llm-endpoint", "", "API base URL for --llm-review, e.g. http://localhost:11434/v1 (default $"+llmreview.EnvEndpoint+"; key from $"+llmreview.EnvAPIKey+")")
+	llmModel := flag.String("llm-model", "", "Model name for --llm-review (default $"+llmreview.EnvModel+")")
+	schemaOnly := flag.Bool("schema-only", false, "Run only Tier 1 (JSON Schema) checks")
+	semanticOnly := flag.Bool("semantic-only", false, "Run only Tier 2 (semantic) checks; assumes the input is schema-valid")
+	requireVerification := flag.Bool("require-verification", false, "Fail (VERIFY rule) when an acceptance criterion has no verification entry")
+	verifySeal := flag.Bool("verify-seal", false, "Fail (SEAL rule) unless the graph is sealed and unchanged since 'taskval seal' (graph mode only)")
+	taskScope := flag.String("task", "", "Validate only these task_ids (comma-separated) within the graph; graph-wide checks run on the subset")
+	filenameHint := flag.String("filename", "", "Name to report for stdin input ('-'), e.g. the plan's path; used in the derived epic title and the HTML report title")
+	externalDeps := flag.String("external-deps", "", "File listing task_ids defined outside the input (one per line), accepted as depends_on targets")
+	failOn := flag.String("fail-on", failOnError, "Findings that fail the run (exit 1): 'error', 'warning' (errors or warnings), or 'never' (always exit 0)")
+	warningsExitCode := flag.Int("warnings-exit-code", 0, "Exit code when validation passes with warnings, e.g. 3, so pipelines can branch on warnings without parsing output")
+	maxWarnings := flag.Int("max-warnings", -1, "Fail the run (exit 1) when there are more than N warnings, even without errors; -1 means no limit")
+	lang := flag.String("lang", i18n.English, "Language of finding messages and suggestions: "+strings.Join(i18n.Languages(), ", ")+"; text without a translation stays in English")
+	parseAlways := flag.Bool("parse-always", false, "Include the parsed graph in JSON output (\"graph\") whenever the input decodes, even when validation fails")
 
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "taskval — Structured Task Template Spec validator\n\n")
 		fmt.Fprintf(os.Stderr, "Usage:\n")
 		fmt.Fprintf(os.Stderr, "  taskval [flags] <file.json>\n")
-		fmt.Fprintf(os.Stderr, "  taskval [flags] -          (read from stdin)\n\n")
-		fmt.Fprintf(os.Stderr, "Flags:\n")
+		fmt.Fprintf(os.Stderr, "  taskval [flags] -          (read from stdin)\n")
+		fmt.Fprintf(os.Stderr, "  taskval <command> [flags] [args]\n\n")
+		fmt.Fprintf(os.Stderr, "Commands:\n")
+		printCommands(os.Stderr)
+		fmt.Fprintf(os.Stderr, "\nFlags:\n")
 		flag.PrintDefaults()
 		fmt.Fprintf(os.Stderr, "\nExit codes:\n")
-		fmt.Fprintf(os.Stderr, "  0  Validation passed (no errors)\n")
-		fmt.Fprintf(os.Stderr, "  1  Validation failed (errors found)\n")
+		fmt.Fprintf(os.Stderr, "  0  Validation passed (no errors; warnings may be present)\n")
+		fmt.Fprintf(os.Stderr, "  1  Validation failed (errors found; with --fail-on=warning, warnings;\n")
+		fmt.Fprintf(os.Stderr, "     with --max-warnings, more warnings than allowed)\n")
 		fmt.Fprintf(os.Stderr, "  2  Usage, internal, or bd error\n")
+		fmt.Fprintf(os.Stderr, "  3  Validation passed with warnings, when --warnings-exit-code=3\n")
 	}
 	flag.Parse()
 
 	// Validate flags.
-	var valMode validator.Mode
-	switch *mode {
-	case "task":
-		valMode = validator.ModeSingleTask
-	case "graph":
-		valMode = validator.ModeTaskGraph
+	valMode, err := parseMode(*mode)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s.\n", err)
+		return 2
+	}
+
+	if *output != "text" && *output != "json" && *output != "html" && *output != "proto" && *output != "slack" {
+		fmt.Fprintf(os.Stderr, "Error: invalid output format '%s'. Must be 'text', 'json', 'html', 'proto', or 'slack'.\n", *output)
+		return 2
+	}
+
+	if *reportFormat != "text" && *reportFormat != "json" && *reportFormat != "html" {
+		fmt.Fprintf(os.Stderr, "Error: invalid report format '%s'. Must be 'text', 'json', or 'html'.\n", *reportFormat)
+		return 2
+	}
+
+	if *output == "html" && *createBeads {
+		fmt.Fprintf(os.Stderr, "Error: --output=html cannot be combined with --create-beads.\n")
+		return 2
+	}
+
+	if *schemaOnly && *semanticOnly {
+		fmt.Fprintf(os.Stderr, "Error: --schema-only and --semantic-only are mutually exclusive.\n")
+		return 2
+	}
+
+	if *taskScope != "" && valMode != validator.ModeTaskGraph {
+		fmt.Fprintf(os.Stderr, "Error: --task requires --mode=graph.\n")
+		return 2
+	}
+
+	if *verifySeal && (valMode != validator.ModeTaskGraph || *taskScope != "") {
+		fmt.Fprintf(os.Stderr, "Error: --verify-seal requires --mode=graph and cannot be combined with --task.\n")
+		return 2
+	}
+
+	if *taskScope != "" && *createBeads {
+		fmt.Fprintf(os.Stderr, "Error: --task cannot be combined with --create-beads; partial validation does not cover the whole graph.\n")
+		return 2
+	}
+
+	if *skipTasks != "" || *onlyTasks != "" {
+		switch {
+		case !*createBeads:
+			fmt.Fprintf(os.Stderr, "Error: --skip-tasks and --only-tasks require --create-beads.\n")
+			return 2
+		case *skipTasks != "" && *onlyTasks != "":
+			fmt.Fprintf(os.Stderr, "Error: --skip-tasks and --only-tasks are mutually exclusive.\n")
+			return 2
+		case valMode != validator.ModeTaskGraph:
+			fmt.Fprintf(os.Stderr, "Error: --skip-tasks and --only-tasks require --mode=graph.\n")
+			return 2
+		}
+	}
+
+	switch *onDuplicate {
+	case "", beads.OnDuplicateSkip, beads.OnDuplicateUpdate, beads.OnDuplicateError:
 	default:
-		fmt.Fprintf(os.Stderr, "Error: invalid mode '%s'. Must be 'task' or 'graph'.\n", *mode)
+		fmt.Fprintf(os.Stderr, "Error: invalid --on-duplicate '%s'. Must be 'skip', 'update', or 'error'.\n", *onDuplicate)
+		return 2
+	}
+
+	acceptanceBullet, err := beads.AcceptanceBullet(*acceptanceStyle)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s.\n", err)
+		return 2
+	}
+
+	if *descTemplate != "" && !*createBeads {
+		fmt.Fprintf(os.Stderr, "Error: --description-template requires --create-beads.\n")
+		return 2
+	}
+
+	if *attachReport && !*createBeads {
+		fmt.Fprintf(os.Stderr, "Error: --attach-report requires --create-beads.\n")
+		return 2
+	}
+
+	if *designExtensions && !*createBeads {
+		fmt.Fprintf(os.Stderr, "Error: --design-extensions requires --create-beads.\n")
+		return 2
+	}
+
+	if *onDuplicate != "" && !*createBeads {
+		fmt.Fprintf(os.Stderr, "Error: --on-duplicate requires --create-beads.\n")
+		return 2
+	}
+
+	if *notifyWebhook != "" {
+		if !*createBeads {
+			fmt.Fprintf(os.Stderr, "Error: --notify-webhook requires --create-beads.\n")
+			return 2
+		}
+		if err := beads.CheckWebhookURL(*notifyWebhook); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s.\n", err)
+			return 2
+		}
+	}
+
+	if *parentEpic != "" {
+		if !*createBeads {
+			fmt.Fprintf(os.Stderr, "Error: --parent-epic requires --create-beads.\n")
+			return 2
+		}
+		if *epicTitle != "" {
+			fmt.Fprintf(os.Stderr, "Error: --epic-title cannot be combined with --parent-epic; no epic is created.\n")
+			return 2
+		}
+		if *epicEstimate != beads.EpicEstimateNone {
+			fmt.Fprintf(os.Stderr, "Error: --epic-estimate cannot be combined with --parent-epic; no epic is created.\n")
+			return 2
+		}
+	}
+
+	if strings.ContainsAny(*milestoneLabelPrefix, ", ") {
+		fmt.Fprintf(os.Stderr, "Error: --milestone-label-prefix must not contain commas or spaces, got '%s'.\n", *milestoneLabelPrefix)
 		return 2
 	}
 
-	if *output != "text" && *output != "json" {
-		fmt.Fprintf(os.Stderr, "Error: invalid output format '%s'. Must be 'text' or 'json'.\n", *output)
+	if *progressFormat != "" && *progressFormat != "jsonl" {
+		fmt.Fprintf(os.Stderr, "Error: invalid --progress '%s'. Must be 'jsonl'.\n", *progressFormat)
+		return 2
+	}
+	prog := newProgress(*progressFormat, os.Stderr)
+
+	switch *epicEstimate {
+	case beads.EpicEstimateNone, beads.EpicEstimateSum, beads.EpicEstimateCriticalPath:
+	default:
+		fmt.Fprintf(os.Stderr, "Error: invalid --epic-estimate '%s'. Must be 'sum', 'critical-path', or 'none'.\n", *epicEstimate)
+		return 2
+	}
+	if *epicEstimate != beads.EpicEstimateNone && !*createBeads {
+		fmt.Fprintf(os.Stderr, "Error: --epic-estimate requires --create-beads.\n")
 		return 2
 	}
 
@@ -84,71 +329,347 @@ func run() int {
 		return 2
 	}
 
+	if *dryRunFull {
+		if !*createBeads {
+			fmt.Fprintf(os.Stderr, "Error: --dry-run-full requires --create-beads.\n")
+			return 2
+		}
+		*dryRun = true
+	}
+
+	var reviewer *llmreview.Reviewer
+	if *llmReview {
+		var err error
+		reviewer, err = llmreview.New(llmreview.ConfigFromEnv(llmreview.Config{Endpoint: *llmEndpoint, Model: *llmModel}))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s.\n", err)
+			return 2
+		}
+	}
+
+	if !slices.Contains(i18n.Languages(), *lang) {
+		fmt.Fprintf(os.Stderr, "Error: unsupported --lang '%s'. Must be one of: %s.\n", *lang, strings.Join(i18n.Languages(), ", "))
+		return 2
+	}
+
+	switch *failOn {
+	case failOnError, failOnWarning, failOnNever:
+	default:
+		fmt.Fprintf(os.Stderr, "Error: invalid --fail-on '%s'. Must be '%s', '%s', or '%s'.\n", *failOn, failOnError, failOnWarning, failOnNever)
+		return 2
+	}
+	if *warningsExitCode != 0 && (*warningsExitCode < 3 || *warningsExitCode > 125) {
+		fmt.Fprintf(os.Stderr, "Error: --warnings-exit-code must be 0 or between 3 and 125 (1 and 2 already mean failure and usage error), got %d.\n", *warningsExitCode)
+		return 2
+	}
+
+	if *parseAlways {
+		if *output != "json" && (*outputFile == "" || *reportFormat != "json") {
+			fmt.Fprintf(os.Stderr, "Error: --parse-always adds the graph to JSON output; use it with --output=json or a JSON --output-file.\n")
+			return 2
+		}
+		if *createBeads {
+			fmt.Fprintf(os.Stderr, "Error: --parse-always cannot be combined with --create-beads.\n")
+			return 2
+		}
+	}
+
+	if *filenameHint != "" && flag.Arg(0) != "-" {
+		fmt.Fprintf(os.Stderr, "Error: --filename names stdin input; use it with '-', not a file argument.\n")
+		return 2
+	}
+
+	limit, err := parseByteSize(*maxInput)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid --max-input-size: %s\n", err)
+		return 2
+	}
+	maxInputSize = limit
+
 	// Read input.
 	data, filename, err := readInput(flag.Args())
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
 		return 2
 	}
+	if *filenameHint != "" {
+		filename = *filenameHint
+	}
+	report := &reportFile{path: *outputFile, format: *reportFormat, data: data, mode: valMode, filename: filename, dryRunFull: *dryRunFull, withGraph: *parseAlways}
+
+	rules, err := validator.Profile(*profile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s.\n", err)
+		return 2
+	}
+	opts := validator.Options{Tasks: splitList(*taskScope), Rules: rules, RepoRoot: *repoRoot, VerifySeal: *verifySeal, RequireVerification: *requireVerification, ParseAlways: *parseAlways}
+	switch {
+	case *schemaOnly:
+		opts.Tiers = validator.SchemaTier
+	case *semanticOnly:
+		opts.Tiers = validator.SemanticTier
+	}
+	if *externalDeps != "" {
+		opts.ExternalTasks, err = readIDList(*externalDeps)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return 2
+		}
+	}
+	if *configFile != "" {
+		cfg, err := validator.LoadConfig(*configFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return 2
+		}
+		opts.Rules = opts.Rules.Merge(cfg.RuleConfig)
+		opts.CustomRules = cfg.CustomRules
+		opts.IDConvention = cfg.TaskIDs
+	}
+	if *codeOwners != "" {
+		opts.CodeOwners, err = validator.LoadCodeOwners(*codeOwners)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return 2
+		}
+	}
 
 	// Run validation.
-	result, err := validator.Validate(data, valMode)
+	if prog != nil {
+		opts.RuleDone = prog.ruleFinished
+	}
+	prog.validationStarted(filename, valMode)
+	result, err := validator.ValidateWithOptions(data, valMode, opts)
+	if err != nil {
+		if *semanticOnly {
+			fmt.Fprintf(os.Stderr, "Error: %s (--semantic-only assumes schema-valid input; run --schema-only first)\n", err)
+			return 2
+		}
+		fmt.Fprintf(os.Stderr, "Internal error: %s\n", err)
+		return 2
+	}
+
+	// The model only reviews documents that passed. Its findings go through
+	// the same rule config as the built-in rules; a failed review is not a
+	// validation failure.
+	if reviewer != nil && result.Valid && result.Graph != nil {
+		graph := result.Graph
+		findings, err := reviewer.Review(context.Background(), result.Graph)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %s; continuing without it.\n", err)
+		}
+		for _, f := range findings {
+			result.AddError(f)
+		}
+		result = opts.Rules.Apply(result)
+		if *parseAlways {
+			result.Graph = graph
+		}
+	}
+
+	prog.validationFinished(result)
+
+	// Translate last, so the rule config and the reviewer see the
+	// English findings.
+	result, err = i18n.Translate(result, *lang)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Internal error: %s\n", err)
 		return 2
 	}
 
 	// Output validation results.
-	if *output == "text" {
-		outputText(result)
+	switch *output {
+	case "text":
+		outputText(os.Stdout, result)
+	case "html":
+		if err := outputHTML(os.Stdout, result, data, valMode, filename); err != nil {
+			fmt.Fprintf(os.Stderr, "Internal error: %s\n", err)
+			return 2
+		}
 	}
 
-	if !result.Valid {
-		if *output == "json" {
-			outputJSON(result, nil)
+	var shownGraph *validator.TaskGraph
+	if *parseAlways {
+		shownGraph = result.Graph
+	}
+	// With --fail-on=warning a warning fails the run like an error, so
+	// no issues are created either.
+	status := exitStatus(result, *failOn, *warningsExitCode)
+	if overWarningBudget(result, *maxWarnings) && *failOn != failOnNever {
+		fmt.Fprintf(os.Stderr, "Error: %d warning(s) exceed --max-warnings=%d.\n", result.Stats.WarningCount, *maxWarnings)
+		status = 1
+	}
+	if !result.Valid || status == 1 {
+		switch *output {
+		case "json":
+			outputJSON(os.Stdout, result, shownGraph, nil, nil, nil)
+		case "proto":
+			outputProto(os.Stdout, result, nil, nil, nil)
+		case "slack":
+			outputSlack(os.Stdout, filename, result, nil, nil, nil)
 		}
-		return 1
+		if err := report.write(result, nil, nil, nil); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return 2
+		}
+		return status
 	}
 
 	// If --create-beads, proceed to beads creation.
 	if *createBeads {
-		exitCode := runBeadsCreation(result, valMode, *dryRun, *epicTitle, filename, *output)
+		exitCode := runBeadsCreation(result, beadsRunOptions{
+			Backend:              beads.NewCLIBackend(),
+			Mode:                 valMode,
+			Filename:             filename,
+			Output:               *output,
+			OnDuplicate:          *onDuplicate,
+			AttachReport:         *attachReport,
+			DesignExtensions:     *designExtensions,
+			DescTemplate:         *descTemplate,
+			AcceptanceBullet:     acceptanceBullet,
+			DryRun:               *dryRun,
+			DryRunFull:           *dryRunFull,
+			EpicTitle:            *epicTitle,
+			EpicEstimate:         *epicEstimate,
+			MilestoneLabelPrefix: *milestoneLabelPrefix,
+			ParentEpic:           *parentEpic,
+			NotifyWebhook:        *notifyWebhook,
+			SkipTasks:            splitList(*skipTasks),
+			OnlyTasks:            splitList(*onlyTasks),
+			Progress:             prog,
+			Report:               report,
+		})
 		if exitCode != 0 {
 			return exitCode
 		}
-	} else if *output == "json" {
-		outputJSON(result, nil)
+	} else {
+		switch *output {
+		case "json":
+			outputJSON(os.Stdout, result, shownGraph, nil, nil, nil)
+		case "proto":
+			outputProto(os.Stdout, result, nil, nil, nil)
+		case "slack":
+			outputSlack(os.Stdout, filename, result, nil, nil, nil)
+		}
+		if err := report.write(result, nil, nil, nil); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return 2
+		}
 	}
 
+	return status
+}
+
+// --fail-on values: which findings make validation exit 1.
+const (
+	failOnError   = "error"
+	failOnWarning = "warning"
+	failOnNever   = "never"
+)
+
+// exitStatus maps a validation result to the process exit code under the
+// --fail-on policy: 1 when the run fails, warningsExitCode when it passes
+// with warnings, 0 otherwise.
+func exitStatus(result *validator.ValidationResult, failOn string, warningsExitCode int) int {
+	switch {
+	case failOn == failOnNever:
+		return 0
+	case !result.Valid:
+		return 1
+	case result.Stats.WarningCount > 0 && failOn == failOnWarning:
+		return 1
+	case result.Stats.WarningCount > 0:
+		return warningsExitCode
+	}
 	return 0
 }
 
-// runBeadsCreation handles the beads creation pipeline after successful validation.
-func runBeadsCreation(result *validator.ValidationResult, mode validator.Mode, dryRun bool, epicTitle, filename, output string) int {
+// overWarningBudget reports whether the result has more warnings than
+// --max-warnings allows. A negative limit allows any number.
+func overWarningBudget(result *validator.ValidationResult, maxWarnings int) bool {
+	return maxWarnings >= 0 && result.Stats.WarningCount > maxWarnings
+}
+
+// beadsRunOptions carries the validate flags that shape beads creation.
+type beadsRunOptions struct {
+	Backend  beads.Backend
+	Mode     validator.Mode
+	Filename string
+	Output   string
+
+	OnDuplicate          string
+	AttachReport         bool
+	DesignExtensions     bool
+	DescTemplate         string
+	AcceptanceBullet     string
+	DryRun               bool
+	DryRunFull           bool
+	EpicTitle            string
+	EpicEstimate         string
+	MilestoneLabelPrefix string
+	ParentEpic           string
+	NotifyWebhook        string
+	SkipTasks            []string
+	OnlyTasks            []string
+
+	Progress *progress
+	Report   *reportFile
+}
+
+// runBeadsCreation handles the beads creation pipeline after successful
+// validation. The report file, if any, gets the creation result too.
+func runBeadsCreation(result *validator.ValidationResult, opts beadsRunOptions) int {
+	backend, output, filename := opts.Backend, opts.Output, opts.Filename
+	prog, report := opts.Progress, opts.Report
 	if result.Graph == nil {
 		fmt.Fprintf(os.Stderr, "Internal error: validation passed but no parsed graph available\n")
 		return 2
 	}
 
-	// Pre-flight check (skip for dry-run since we don't execute commands).
-	if !dryRun {
-		if err := beads.PreFlightCheck(); err != nil {
+	// Pre-flight check. Dry-run skips it since no commands execute, unless
+	// duplicate detection or the parent epic needs to query bd.
+	if !opts.DryRun || opts.OnDuplicate != "" || opts.ParentEpic != "" {
+		if err := backend.Check(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return 2
+		}
+	}
+	if opts.ParentEpic != "" {
+		if err := beads.CheckParentEpic(backend, opts.ParentEpic); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
 			return 2
 		}
 	}
 
 	creator := &beads.Creator{
-		DryRun:    dryRun,
-		EpicTitle: epicTitle,
-		Filename:  filename,
+		DryRun:               opts.DryRun,
+		EpicTitle:            opts.EpicTitle,
+		EpicEstimate:         opts.EpicEstimate,
+		ParentEpic:           opts.ParentEpic,
+		MilestoneLabelPrefix: opts.MilestoneLabelPrefix,
+		SkipTasks:            opts.SkipTasks,
+		OnlyTasks:            opts.OnlyTasks,
+		Filename:             filename,
+		AcceptanceBullet:     opts.AcceptanceBullet,
+		DesignExtensions:     opts.DesignExtensions,
+	}
+	if opts.AttachReport {
+		creator.Report = result
+	}
+	if opts.DescTemplate != "" {
+		tmpl, err := beads.LoadDescriptionTemplate(opts.DescTemplate)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return 2
+		}
+		creator.DescriptionTemplate = tmpl
 	}
 
 	// Build commands.
 	var cmds []beads.BdCommand
 	var err error
 
-	switch mode {
+	switch opts.Mode {
 	case validator.ModeSingleTask:
 		if len(result.Graph.Tasks) == 0 {
 			fmt.Fprintf(os.Stderr, "Internal error: graph has no tasks\n")
@@ -163,21 +684,85 @@ func runBeadsCreation(result *validator.ValidationResult, mode validator.Mode, d
 		return 2
 	}
 
-	// Dry-run: print commands and exit.
-	if dryRun {
-		fmt.Print(beads.FormatDryRunOutput(cmds))
-		if output == "json" {
-			outputJSON(result, nil)
+	// Match tasks against open issues and apply the duplicate policy.
+	if opts.OnDuplicate != "" {
+		existing, err := beads.ListOpenIssues(backend)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return 2
+		}
+		cmds, err = beads.ResolveDuplicates(cmds, beads.FindDuplicates(cmds, existing), opts.OnDuplicate)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return 2
+		}
+	}
+
+	// Catch a command left with an unbound placeholder ID before anything
+	// runs or is previewed.
+	if err := beads.CheckPlaceholders(cmds); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
+	}
+
+	// Dry-run: print commands and exit. JSON output carries the commands
+	// instead of the text listing, so stdout stays parseable.
+	if opts.DryRun {
+		switch {
+		case output == "text" && opts.DryRunFull:
+			fmt.Print(beads.FormatDryRunOutputFull(cmds))
+		case output == "text":
+			fmt.Print(beads.FormatDryRunOutput(cmds))
+		case output == "json":
+			outputJSON(os.Stdout, result, nil, nil, beads.FormatDryRunJSON(cmds), nil)
+		case output == "proto":
+			outputProto(os.Stdout, result, nil, beads.FormatDryRunJSON(cmds), nil)
+		case output == "slack":
+			outputSlack(os.Stdout, filename, result, nil, beads.FormatDryRunJSON(cmds), nil)
+		}
+		if err := report.write(result, nil, cmds, nil); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return 2
 		}
 		return 0
 	}
 
 	// Execute commands.
-	creationResult, err := beads.ExecuteCommands(cmds)
+	var observe func(beads.CommandEvent)
+	if prog != nil {
+		observe = prog.bdCommand
+	}
+	creationResult, err := beads.ExecuteObserved(backend, cmds, observe)
+	prog.creationFinished(creationResult, err)
+	if opts.NotifyWebhook != "" {
+		// The issues exist whether or not the webhook hears of them, so a
+		// failed delivery does not fail the run.
+		if err := beads.Notify(context.Background(), opts.NotifyWebhook, beads.NewNotification(filename, creationResult, err)); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", err)
+		}
+	}
 	if err != nil {
+		// JSON, proto, and Slack output describe the failure and the
+		// partial result on stdout, so automation need not parse stderr.
 		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
-		if creationResult != nil && output == "text" {
-			fmt.Print(beads.FormatTextOutput(creationResult))
+		var partial *beads.BeadsJSON
+		if creationResult != nil {
+			partial = beads.FormatJSONOutput(creationResult)
+		}
+		switch output {
+		case "text":
+			if creationResult != nil {
+				fmt.Print(beads.FormatTextOutput(creationResult))
+			}
+		case "json":
+			outputJSON(os.Stdout, result, nil, partial, nil, beads.FormatErrorJSON(err))
+		case "proto":
+			outputProto(os.Stdout, result, partial, nil, beads.FormatErrorJSON(err))
+		case "slack":
+			outputSlack(os.Stdout, filename, result, partial, nil, beads.FormatErrorJSON(err))
+		}
+		if err := report.write(result, creationResult, nil, err); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
 		}
 		return 2
 	}
@@ -187,7 +772,15 @@ func runBeadsCreation(result *validator.ValidationResult, mode validator.Mode, d
 	case "text":
 		fmt.Print(beads.FormatTextOutput(creationResult))
 	case "json":
-		outputJSON(result, beads.FormatJSONOutput(creationResult))
+		outputJSON(os.Stdout, result, nil, beads.FormatJSONOutput(creationResult), nil, nil)
+	case "proto":
+		outputProto(os.Stdout, result, beads.FormatJSONOutput(creationResult), nil, nil)
+	case "slack":
+		outputSlack(os.Stdout, filename, result, beads.FormatJSONOutput(creationResult), nil, nil)
+	}
+	if err := report.write(result, creationResult, nil, nil); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 2
 	}
 
 	return 0
@@ -204,55 +797,209 @@ func readInput(args []string) ([]byte, string, error) {
 
 	filename := args[0]
 	if filename == "-" {
-		data, err := io.ReadAll(os.Stdin)
+		data, err := readLimited(os.Stdin)
 		if err != nil {
 			return nil, "-", fmt.Errorf("reading stdin: %w", err)
 		}
 		return data, "-", nil
 	}
 
-	data, err := os.ReadFile(filename)
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, filename, fmt.Errorf("reading file '%s': %w", filename, err)
+	}
+	defer f.Close()
+	data, err := readLimited(f)
 	if err != nil {
 		return nil, filename, fmt.Errorf("reading file '%s': %w", filename, err)
 	}
 	return data, filename, nil
 }
 
+// maxInputSize caps how much readInput reads, so a huge or endless input
+// fails cleanly instead of exhausting memory; 0 means no limit. The main
+// command sets it from --max-input-size.
+var maxInputSize int64 = defaultMaxInputSize
+
+const defaultMaxInputSize = 50 << 20
+
+// readLimited reads r to the end, failing once it passes maxInputSize.
+func readLimited(r io.Reader) ([]byte, error) {
+	if maxInputSize <= 0 {
+		return io.ReadAll(r)
+	}
+	data, err := io.ReadAll(io.LimitReader(r, maxInputSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxInputSize {
+		return nil, fmt.Errorf("input is larger than %s. Raise --max-input-size if it really is that big", formatByteSize(maxInputSize))
+	}
+	return data, nil
+}
+
 // combinedOutput holds validation result plus optional beads creation result for JSON output.
 type combinedOutput struct {
-	Valid  bool                        `json:"valid"`
-	Errors []validator.ValidationError `json:"errors,omitempty"`
-	Stats  validator.ValidationStats   `json:"stats"`
-	Beads  *beads.BeadsJSON            `json:"beads,omitempty"`
+	Valid   bool                        `json:"valid"`
+	Partial bool                        `json:"partial,omitempty"`
+	Errors  []validator.ValidationError `json:"errors,omitempty"`
+	Stats   validator.ValidationStats   `json:"stats"`
+	Graph   *validator.TaskGraph        `json:"graph,omitempty"` // Only with --parse-always
+	Beads   *beads.BeadsJSON            `json:"beads,omitempty"`
+	DryRun  *beads.DryRunJSON           `json:"dry_run,omitempty"`
+
+	// BeadsError is set when creation failed; Beads then holds what was
+	// done before the failure.
+	BeadsError *beads.ErrorJSON `json:"beads_error,omitempty"`
 }
 
-func outputJSON(result *validator.ValidationResult, beadsResult *beads.BeadsJSON) {
+func outputJSON(w io.Writer, result *validator.ValidationResult, graph *validator.TaskGraph, beadsResult *beads.BeadsJSON, plan *beads.DryRunJSON, failure *beads.ErrorJSON) {
 	out := combinedOutput{
-		Valid:  result.Valid,
-		Errors: result.Errors,
-		Stats:  result.Stats,
-		Beads:  beadsResult,
+		Valid:      result.Valid,
+		Partial:    result.Partial,
+		Errors:     result.Errors,
+		Stats:      result.Stats,
+		Graph:      graph,
+		Beads:      beadsResult,
+		DryRun:     plan,
+		BeadsError: failure,
 	}
-	enc := json.NewEncoder(os.Stdout)
+	enc := json.NewEncoder(w)
 	enc.SetIndent("", "  ")
 	_ = enc.Encode(out)
 }
 
-func outputText(result *validator.ValidationResult) {
+// outputProto writes the same content as outputJSON, less the graph, as a
+// binary Report message (proto/taskval.proto).
+func outputProto(w io.Writer, result *validator.ValidationResult, beadsResult *beads.BeadsJSON, plan *beads.DryRunJSON, failure *beads.ErrorJSON) {
+	_, _ = w.Write(protoreport.Marshal(result, beadsResult, plan, failure))
+}
+
+// outputHTML writes the HTML report. When validation failed the graph is
+// parsed best-effort so task cards and the dependency graph still render.
+func outputHTML(w io.Writer, result *validator.ValidationResult, data []byte, mode validator.Mode, filename string) error {
+	graph := result.Graph
+	if graph == nil {
+		switch mode {
+		case validator.ModeSingleTask:
+			var task validator.TaskNode
+			if json.Unmarshal(data, &task) == nil {
+				graph = validator.WrapTask(task)
+			}
+		case validator.ModeTaskGraph:
+			var g validator.TaskGraph
+			if json.Unmarshal(data, &g) == nil {
+				graph = &g
+			}
+		}
+	}
+
+	page, err := report.HTML("taskval report: "+reportTitle(filename), result, graph)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(page)
+	return err
+}
+
+// outputSlack writes the Slack summary of the results and, for a creation
+// run, of the issues created, the commands planned, or the failure.
+func outputSlack(w io.Writer, filename string, result *validator.ValidationResult, created *beads.BeadsJSON, plan *beads.DryRunJSON, failure *beads.ErrorJSON) {
+	fmt.Fprint(w, report.Slack("taskval: "+reportTitle(filename), result, created, plan, failure))
+}
+
+// reportTitle names the input in report headings.
+func reportTitle(filename string) string {
+	if filename == "-" {
+		return "(stdin)"
+	}
+	return filename
+}
+
+// reportFile is the --output-file destination: a copy of the results in
+// --report-format, written whatever --output prints, so a machine-readable
+// report can sit beside human-readable console output.
+type reportFile struct {
+	path     string
+	format   string
+	data     []byte
+	mode     validator.Mode
+	filename string
+
+	// dryRunFull selects the full dry-run listing for the text format.
+	dryRunFull bool
+
+	// withGraph adds the parsed graph to the JSON format (--parse-always).
+	withGraph bool
+}
+
+// write saves the validation result and, when issues were created, the
+// beads result and any creation failure, or for a dry run the planned
+// commands. It does nothing when no --output-file was given.
+func (r *reportFile) write(result *validator.ValidationResult, creation *beads.CreationResult, plan []beads.BdCommand, failure error) error {
+	if r.path == "" {
+		return nil
+	}
+	var buf bytes.Buffer
+	switch r.format {
+	case "json":
+		var beadsResult *beads.BeadsJSON
+		if creation != nil {
+			beadsResult = beads.FormatJSONOutput(creation)
+		}
+		var dryRun *beads.DryRunJSON
+		if plan != nil {
+			dryRun = beads.FormatDryRunJSON(plan)
+		}
+		var graph *validator.TaskGraph
+		if r.withGraph {
+			graph = result.Graph
+		}
+		var beadsErr *beads.ErrorJSON
+		if failure != nil {
+			beadsErr = beads.FormatErrorJSON(failure)
+		}
+		outputJSON(&buf, result, graph, beadsResult, dryRun, beadsErr)
+	case "text":
+		outputText(&buf, result)
+		if creation != nil {
+			buf.WriteString(beads.FormatTextOutput(creation))
+		}
+		if failure != nil {
+			fmt.Fprintf(&buf, "\nError: %s\n", failure)
+		}
+		switch {
+		case plan != nil && r.dryRunFull:
+			buf.WriteString(beads.FormatDryRunOutputFull(plan))
+		case plan != nil:
+			buf.WriteString(beads.FormatDryRunOutput(plan))
+		}
+	case "html":
+		if err := outputHTML(&buf, result, r.data, r.mode, r.filename); err != nil {
+			return err
+		}
+	}
+	if err := os.WriteFile(r.path, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("writing report '%s': %w", r.path, err)
+	}
+	return nil
+}
+
+func outputText(w io.Writer, result *validator.ValidationResult) {
 	if result.Valid && result.Stats.WarningCount == 0 && result.Stats.InfoCount == 0 {
-		fmt.Println("VALIDATION PASSED")
-		fmt.Printf("  Tasks validated: %d\n", result.Stats.TotalTasks)
-		fmt.Println("  No errors or warnings.")
+		fmt.Fprintln(w, "VALIDATION PASSED")
+		fmt.Fprintf(w, "  Tasks validated: %d\n", result.Stats.TotalTasks)
+		fmt.Fprintln(w, "  No errors or warnings.")
 		return
 	}
 
 	if result.Valid {
-		fmt.Println("VALIDATION PASSED (with warnings)")
+		fmt.Fprintln(w, "VALIDATION PASSED (with warnings)")
 	} else {
-		fmt.Println("VALIDATION FAILED")
+		fmt.Fprintln(w, "VALIDATION FAILED")
 	}
 
-	fmt.Printf("\nSummary: %d error(s), %d warning(s), %d info(s) across %d task(s)\n",
+	fmt.Fprintf(w, "\nSummary: %d error(s), %d warning(s), %d info(s) across %d task(s)\n",
 		result.Stats.ErrorCount,
 		result.Stats.WarningCount,
 		result.Stats.InfoCount,
@@ -261,49 +1008,53 @@ func outputText(result *validator.ValidationResult) {
 
 	// Group errors by severity for readability.
 	if result.Stats.ErrorCount > 0 {
-		fmt.Println("\n--- ERRORS (must fix) ---")
+		fmt.Fprintln(w, "\n--- ERRORS (must fix) ---")
 		for i, e := range result.Errors {
 			if e.Severity != validator.SeverityError {
 				continue
 			}
-			printError(i+1, e)
+			printError(w, i+1, e)
 		}
 	}
 
 	if result.Stats.WarningCount > 0 {
-		fmt.Println("\n--- WARNINGS (should fix) ---")
+		fmt.Fprintln(w, "\n--- WARNINGS (should fix) ---")
 		for i, e := range result.Errors {
 			if e.Severity != validator.SeverityWarning {
 				continue
 			}
-			printError(i+1, e)
+			printError(w, i+1, e)
 		}
 	}
 
 	if result.Stats.InfoCount > 0 {
-		fmt.Println("\n--- INFO ---")
+		fmt.Fprintln(w, "\n--- INFO ---")
 		for i, e := range result.Errors {
 			if e.Severity != validator.SeverityInfo {
 				continue
 			}
-			printError(i+1, e)
+			printError(w, i+1, e)
 		}
 	}
 }
 
-func printError(num int, e validator.ValidationError) {
-	fmt.Printf("\n  %d. [%s] Rule %s\n", num, e.Severity, e.Rule)
-	fmt.Printf("     Path:    %s\n", e.Path)
-	fmt.Printf("     Problem: %s\n", wrapText(e.Message, 14, 80))
+func printError(w io.Writer, num int, e validator.ValidationError) {
+	if e.Provisional {
+		fmt.Fprintf(w, "\n  %d. [%s] Rule %s (provisional: the document has schema errors)\n", num, e.Severity, e.Rule)
+	} else {
+		fmt.Fprintf(w, "\n  %d. [%s] Rule %s\n", num, e.Severity, e.Rule)
+	}
+	fmt.Fprintf(w, "     Path:    %s\n", e.Path)
+	fmt.Fprintf(w, "     Problem: %s\n", wrapText(e.Message, 14, 80))
 	if e.Suggestion != "" {
-		fmt.Printf("     Fix:     %s\n", wrapText(e.Suggestion, 14, 80))
+		fmt.Fprintf(w, "     Fix:     %s\n", wrapText(e.Suggestion, 14, 80))
 	}
 	if e.Context != "" {
 		ctx := e.Context
 		if len(ctx) > 120 {
 			ctx = ctx[:117] + "..."
 		}
-		fmt.Printf("     Value:   %q\n", ctx)
+		fmt.Fprintf(w, "     Value:   %q\n", ctx)
 	}
 }
 
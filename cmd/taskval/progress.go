@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/nixlim/task_templating/internal/beads"
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// progress writes --progress=jsonl events, one JSON object per line. Every
+// event has "event" and "time" (RFC 3339 with nanoseconds); durations are
+// "duration_ms". A nil *progress writes nothing, so callers need not check.
+type progress struct {
+	enc   *json.Encoder
+	start time.Time
+}
+
+// newProgress returns the event writer for a --progress value: nil for
+// none, or a JSON lines writer to w.
+func newProgress(format string, w io.Writer) *progress {
+	if format != "jsonl" {
+		return nil
+	}
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return &progress{enc: enc}
+}
+
+func (p *progress) emit(event string, fields map[string]any) {
+	if p == nil {
+		return
+	}
+	fields["event"] = event
+	fields["time"] = time.Now().UTC().Format(time.RFC3339Nano)
+	_ = p.enc.Encode(fields)
+}
+
+func durationMS(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// validationStarted marks the start of a run over source.
+func (p *progress) validationStarted(source string, mode validator.Mode) {
+	if p == nil {
+		return
+	}
+	p.start = time.Now()
+	name := "graph"
+	if mode == validator.ModeSingleTask {
+		name = "task"
+	}
+	p.emit("validation_started", map[string]any{"source": source, "mode": name})
+}
+
+// ruleFinished is a validator.Options.RuleDone callback.
+func (p *progress) ruleFinished(rule string, findings int, elapsed time.Duration) {
+	p.emit("rule_finished", map[string]any{"rule": rule, "findings": findings, "duration_ms": durationMS(elapsed)})
+}
+
+// validationFinished reports the final counts, after the rule config.
+func (p *progress) validationFinished(result *validator.ValidationResult) {
+	if p == nil {
+		return
+	}
+	p.emit("validation_finished", map[string]any{
+		"valid":       result.Valid,
+		"errors":      result.Stats.ErrorCount,
+		"warnings":    result.Stats.WarningCount,
+		"infos":       result.Stats.InfoCount,
+		"duration_ms": durationMS(time.Since(p.start)),
+	})
+}
+
+// bdCommand is a beads.ExecuteObserved callback.
+func (p *progress) bdCommand(ev beads.CommandEvent) {
+	if p == nil {
+		return
+	}
+	fields := map[string]any{"type": ev.Type, "duration_ms": durationMS(ev.Elapsed)}
+	if ev.TaskID != "" {
+		fields["task_id"] = ev.TaskID
+	}
+	if ev.IssueID != "" {
+		fields["issue_id"] = ev.IssueID
+	}
+	if ev.Err != nil {
+		fields["error"] = ev.Err.Error()
+	}
+	p.emit("bd_command", fields)
+}
+
+// creationFinished reports the outcome of issue creation.
+func (p *progress) creationFinished(result *beads.CreationResult, err error) {
+	if p == nil {
+		return
+	}
+	fields := map[string]any{"created": 0, "dependencies": 0}
+	if result != nil {
+		fields["epic_id"] = result.EpicID
+		fields["created"] = result.Created
+		fields["dependencies"] = result.Deps
+	}
+	if err != nil {
+		fields["error"] = err.Error()
+	}
+	p.emit("creation_finished", fields)
+}
@@ -0,0 +1,121 @@
+// Package analysis derives planning advice from a validated task graph's
+// structure: which tasks block the most work, how the graph could be split
+// into milestones, and how it could be divided between agents.
+package analysis
+
+import (
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// depGraph is the dependency structure of a graph by task index. Edges to
+// tasks outside the graph (external or dangling) are dropped.
+type depGraph struct {
+	tasks      []validator.TaskNode
+	deps       [][]int // deps[i] are the tasks i depends on
+	dependents [][]int // dependents[i] are the tasks that depend on i
+}
+
+func newDepGraph(graph *validator.TaskGraph) (*depGraph, error) {
+	n := len(graph.Tasks)
+	index := make(map[string]int, n)
+	for i, t := range graph.Tasks {
+		index[t.TaskID] = i
+	}
+	g := &depGraph{tasks: graph.Tasks, deps: make([][]int, n), dependents: make([][]int, n)}
+	for i, t := range graph.Tasks {
+		ids, _, err := t.ParseDependsOn()
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			if j, ok := index[id]; ok {
+				g.deps[i] = append(g.deps[i], j)
+				g.dependents[j] = append(g.dependents[j], i)
+			}
+		}
+	}
+	return g, nil
+}
+
+// blocked returns how many tasks transitively depend on task i.
+func (g *depGraph) blocked(i int) int {
+	seen := make([]bool, len(g.tasks))
+	stack := append([]int(nil), g.dependents[i]...)
+	count := 0
+	for len(stack) > 0 {
+		j := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if seen[j] {
+			continue
+		}
+		seen[j] = true
+		count++
+		stack = append(stack, g.dependents[j]...)
+	}
+	return count
+}
+
+// priorityRank orders priorities from 0 (critical) down; unset is medium.
+func priorityRank(p validator.Level) int {
+	if n, ok := p.Number(); ok {
+		return n
+	}
+	switch p {
+	case "critical":
+		return 0
+	case "high":
+		return 1
+	case "low":
+		return 3
+	default:
+		return 2
+	}
+}
+
+// PrioritySuggestion recommends raising a task's priority because much of
+// the graph waits on it.
+type PrioritySuggestion struct {
+	TaskID    string
+	Blocked   int // tasks that transitively depend on this one
+	Current   validator.Level
+	Suggested validator.Level
+}
+
+// SuggestPriorities flags tasks whose priority is below what their
+// blocking weight warrants: a task that half or more of the other tasks
+// wait on (at least three) should be critical, and one that a quarter or
+// more wait on (at least two) should be high. Suggestions only ever raise
+// a priority and are returned in graph order.
+func SuggestPriorities(graph *validator.TaskGraph) ([]PrioritySuggestion, error) {
+	g, err := newDepGraph(graph)
+	if err != nil {
+		return nil, err
+	}
+	others := len(g.tasks) - 1
+	var out []PrioritySuggestion
+	for i, t := range g.tasks {
+		b := g.blocked(i)
+		var want validator.Level
+		switch {
+		case b >= 3 && 2*b >= others:
+			want = "critical"
+		case b >= 2 && 4*b >= others:
+			want = "high"
+		default:
+			continue
+		}
+		if priorityRank(want) < priorityRank(t.Priority) {
+			out = append(out, PrioritySuggestion{TaskID: t.TaskID, Blocked: b, Current: t.Priority, Suggested: want})
+		}
+	}
+	return out, nil
+}
+
+// ApplyPriorities sets each suggested priority on graph.
+func ApplyPriorities(graph *validator.TaskGraph, suggestions []PrioritySuggestion) {
+	for _, s := range suggestions {
+		if t := graph.FindTask(s.TaskID); t != nil {
+			t.Priority = s.Suggested
+		}
+	}
+}
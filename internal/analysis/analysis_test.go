@@ -0,0 +1,248 @@
+package analysis
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// chainGraph returns root <- a <- b <- c plus an independent task d:
+// root blocks three of the four other tasks.
+func chainGraph() *validator.TaskGraph {
+	return &validator.TaskGraph{
+		Version: "0.1.0",
+		Tasks: []validator.TaskNode{
+			{TaskID: "root", Priority: "low"},
+			{TaskID: "a", DependsOn: json.RawMessage(`["root"]`)},
+			{TaskID: "b", Priority: "high", DependsOn: json.RawMessage(`["a"]`)},
+			{TaskID: "c", DependsOn: json.RawMessage(`["b", "external-x"]`)},
+			{TaskID: "d"},
+		},
+	}
+}
+
+func TestSuggestPriorities(t *testing.T) {
+	graph := chainGraph()
+	got, err := SuggestPriorities(graph)
+	if err != nil {
+		t.Fatalf("SuggestPriorities error: %v", err)
+	}
+	// root blocks 3 of 4 (critical); a blocks 2 of 4 (high, up from unset);
+	// b blocks 1 and is already high.
+	want := []PrioritySuggestion{
+		{TaskID: "root", Blocked: 3, Current: "low", Suggested: "critical"},
+		{TaskID: "a", Blocked: 2, Current: "", Suggested: "high"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("suggestion %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	ApplyPriorities(graph, got)
+	if graph.Tasks[0].Priority != "critical" || graph.Tasks[1].Priority != "high" {
+		t.Errorf("priorities after apply = %q, %q", graph.Tasks[0].Priority, graph.Tasks[1].Priority)
+	}
+	if again, _ := SuggestPriorities(graph); len(again) != 0 {
+		t.Errorf("applied graph should need no changes, got %+v", again)
+	}
+}
+
+func TestSuggestPrioritiesNeverLowers(t *testing.T) {
+	graph := chainGraph()
+	graph.Tasks[0].Priority = "0"
+	graph.Tasks[1].Priority = "critical"
+	got, _ := SuggestPriorities(graph)
+	if len(got) != 0 {
+		t.Errorf("suggestions must only raise priorities, got %+v", got)
+	}
+}
+
+func TestSuggestMilestonesLayers(t *testing.T) {
+	graph := chainGraph()
+	got, err := SuggestMilestones(graph, MilestoneOptions{})
+	if err != nil {
+		t.Fatalf("SuggestMilestones error: %v", err)
+	}
+	// Levels: {root, d}, {a}, {b}, {c}; single-task layers merge pairwise.
+	want := []validator.Milestone{
+		{Name: "M1", TaskIDs: []string{"root", "d"}},
+		{Name: "M2", DependsOnMilestones: []string{"M1"}, TaskIDs: []string{"a", "b"}},
+		{Name: "M3", DependsOnMilestones: []string{"M2"}, TaskIDs: []string{"c"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v\nwant %+v", got, want)
+	}
+
+	graph.Milestones = got
+	data, err := json.Marshal(graph)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := validator.ValidateWithOptions(data, validator.ModeTaskGraph, validator.Options{Tiers: validator.SemanticTier})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range result.Errors {
+		if e.Rule == "MILESTONE" {
+			t.Errorf("suggested milestones are inconsistent: %s", e.Message)
+		}
+	}
+}
+
+func TestSuggestMilestonesComponents(t *testing.T) {
+	got, err := SuggestMilestones(chainGraph(), MilestoneOptions{Strategy: StrategyComponents, MaxTasks: 2})
+	if err != nil {
+		t.Fatalf("SuggestMilestones error: %v", err)
+	}
+	var names [][]string
+	for _, m := range got {
+		names = append(names, m.TaskIDs)
+	}
+	// The chain is split at MaxTasks; d is its own workstream.
+	want := [][]string{{"root", "a"}, {"b", "c"}, {"d"}}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("groups = %v, want %v", names, want)
+	}
+	if _, err := SuggestMilestones(chainGraph(), MilestoneOptions{Strategy: "random"}); err == nil {
+		t.Error("expected error for unknown strategy")
+	}
+}
+
+func unitMinutes(string) int { return 60 }
+
+func TestPartitionGraph(t *testing.T) {
+	graph := &validator.TaskGraph{
+		Version:    "0.1.0",
+		Milestones: []validator.Milestone{{Name: "M1", TaskIDs: []string{"a1", "b1"}}, {Name: "M2", DependsOnMilestones: []string{"M1"}, TaskIDs: []string{"a2", "b2"}}},
+		Tasks: []validator.TaskNode{
+			{TaskID: "a1", FilesScope: json.RawMessage(`["api/"]`)},
+			{TaskID: "b1", FilesScope: json.RawMessage(`["web/app.ts"]`)},
+			{TaskID: "a2", DependsOn: json.RawMessage(`["a1"]`), FilesScope: json.RawMessage(`["api/handler.go"]`)},
+			{TaskID: "b2", DependsOn: json.RawMessage(`["b1"]`), FilesScope: json.RawMessage(`["web/*.ts"]`)},
+		},
+	}
+	plan, err := PartitionGraph(graph, PartitionOptions{Agents: 2, Minutes: unitMinutes})
+	if err != nil {
+		t.Fatalf("PartitionGraph error: %v", err)
+	}
+	if len(plan.Parts) != 2 || len(plan.CrossDeps) != 0 || len(plan.SharedScopes) != 0 {
+		t.Fatalf("independent workstreams should split cleanly, got %+v", plan)
+	}
+	for _, part := range plan.Parts {
+		if len(part.Graph.Tasks) != 2 || part.Minutes != 120 {
+			t.Errorf("agent %d: %d tasks, %d minutes; want 2, 120", part.Agent, len(part.Graph.Tasks), part.Minutes)
+		}
+		if len(part.Graph.Milestones) != 2 || len(part.Graph.Milestones[1].TaskIDs) != 1 {
+			t.Errorf("agent %d milestones = %+v", part.Agent, part.Graph.Milestones)
+		}
+	}
+
+	// With one chain forced across agents, the split edge is reported and
+	// the dependency becomes an external task of the dependent's sub-graph.
+	plan, err = PartitionGraph(chainGraph(), PartitionOptions{Agents: 2, Minutes: unitMinutes})
+	if err != nil {
+		t.Fatalf("PartitionGraph error: %v", err)
+	}
+	if len(plan.CrossDeps) == 0 {
+		t.Fatal("expected a cross-agent dependency")
+	}
+	d := plan.CrossDeps[0]
+	sub := plan.Parts[d.Agent-1].Graph
+	if !slices.Contains(sub.ExternalTasks, d.DependsOn) {
+		t.Errorf("external_tasks %v should list %s", sub.ExternalTasks, d.DependsOn)
+	}
+	if !strings.Contains(plan.Summary(), fmt.Sprintf("Agent %d `%s` waits for agent %d `%s`", d.Agent, d.TaskID, d.DepAgent, d.DependsOn)) {
+		t.Errorf("summary missing cross dependency:\n%s", plan.Summary())
+	}
+}
+
+func TestScopesOverlap(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"api/", "api/handler.go", true},
+		{"web/*.ts", "web/app.ts", true},
+		{"a.go", "a.go", true},
+		{"a.go", "a.go.bak", false},
+		{"api/", "web/", false},
+	}
+	for _, tt := range tests {
+		if got := scopesOverlap([]string{tt.a}, []string{tt.b}); got != tt.want {
+			t.Errorf("scopesOverlap(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestScoreReady(t *testing.T) {
+	graph := &validator.TaskGraph{
+		Version: "0.1.0",
+		Tasks: []validator.TaskNode{
+			{TaskID: "a", Priority: "low", Estimate: "small"},
+			{TaskID: "b", Priority: "critical", Estimate: "large"},
+			{TaskID: "c", Estimate: "small", DependsOn: json.RawMessage(`["a"]`)},
+			{TaskID: "d", DependsOn: json.RawMessage(`["a"]`)},
+			{TaskID: "e", DependsOn: json.RawMessage(`["a", "b"]`)},
+			{TaskID: "f", Estimate: "small"},
+		},
+	}
+	minutes := map[string]int{"small": 60, "large": 480}
+	opts := ScoreOptions{
+		Weights:        DefaultScoreWeights,
+		Minutes:        func(e string) int { return minutes[e] },
+		UnknownMinutes: 240,
+	}
+
+	q, err := ScoreReady(graph, opts)
+	if err != nil {
+		t.Fatalf("ScoreReady error: %v", err)
+	}
+	// a: 0.25 + 2/2 + 1 = 2.25; b: 1 + 0 + 0.125; f: 0.5 + 0 + 1.
+	var got []string
+	for _, e := range q.Queue {
+		got = append(got, fmt.Sprintf("%d:%s:%g:%d", e.Rank, e.TaskID, e.Score, e.Unblocks))
+	}
+	if want := []string{"1:a:2.25:2", "2:f:1.5:0", "3:b:1.125:0"}; !slices.Equal(got, want) {
+		t.Errorf("queue = %v, want %v", got, want)
+	}
+	if !slices.Equal(q.Waiting, []string{"c", "d", "e"}) {
+		t.Errorf("Waiting = %v", q.Waiting)
+	}
+
+	// With a done, e waits only on b, which now unblocks it.
+	opts.Done = []string{"a"}
+	opts.Weights = ScoreWeights{Unblocks: 1}
+	q, err = ScoreReady(graph, opts)
+	if err != nil {
+		t.Fatalf("ScoreReady error: %v", err)
+	}
+	if q.Queue[0].TaskID != "b" || q.Queue[0].Unblocks != 1 || len(q.Queue) != 4 || len(q.Waiting) != 1 {
+		t.Errorf("after a is done: %+v", q)
+	}
+
+	opts.Done = []string{"missing"}
+	if _, err := ScoreReady(graph, opts); err == nil {
+		t.Error("expected an error for an unknown done task")
+	}
+}
+
+func TestParseScoreWeights(t *testing.T) {
+	w, err := ParseScoreWeights("priority=2, estimate=0.5")
+	if err != nil || w != (ScoreWeights{Priority: 2, Unblocks: 1, Estimate: 0.5}) {
+		t.Errorf("ParseScoreWeights = %+v, %v", w, err)
+	}
+	for _, bad := range []string{"priority", "speed=1", "estimate=-1", "unblocks=x"} {
+		if _, err := ParseScoreWeights(bad); err == nil {
+			t.Errorf("%q: expected an error", bad)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package analysis
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// Milestone grouping strategies accepted by SuggestMilestones.
+const (
+	// StrategyLayers groups tasks by dependency depth: tasks with no
+	// dependencies first, then the tasks they unblock, and so on.
+	StrategyLayers = "layers"
+
+	// StrategyComponents makes one milestone per independent workstream
+	// (weakly connected component), layering any that are too large.
+	StrategyComponents = "components"
+)
+
+// MilestoneOptions controls SuggestMilestones.
+type MilestoneOptions struct {
+	// Strategy is StrategyLayers (default) or StrategyComponents.
+	Strategy string
+
+	// MaxTasks caps the size of a milestone. Values below one mean 8.
+	MaxTasks int
+
+	// MinTasks is the size below which a milestone absorbs the next
+	// dependency layer. Values below one mean 2.
+	MinTasks int
+}
+
+// SuggestMilestones proposes milestones for graph. Milestones are named
+// M1, M2, ... in execution order, and each lists the milestones holding
+// its tasks' dependencies in depends_on_milestones. Existing milestones
+// are ignored.
+func SuggestMilestones(graph *validator.TaskGraph, opts MilestoneOptions) ([]validator.Milestone, error) {
+	g, err := newDepGraph(graph)
+	if err != nil {
+		return nil, err
+	}
+	maxTasks := opts.MaxTasks
+	if maxTasks < 1 {
+		maxTasks = 8
+	}
+	minTasks := opts.MinTasks
+	if minTasks < 1 {
+		minTasks = 2
+	}
+	level, err := g.levels()
+	if err != nil {
+		return nil, err
+	}
+
+	var groups [][]int
+	switch opts.Strategy {
+	case StrategyLayers, "":
+		all := make([]int, len(g.tasks))
+		for i := range all {
+			all[i] = i
+		}
+		groups = layerGroups(all, level, minTasks, maxTasks)
+	case StrategyComponents:
+		for _, comp := range g.components() {
+			groups = append(groups, layerGroups(comp, level, minTasks, maxTasks)...)
+		}
+	default:
+		return nil, fmt.Errorf("unknown strategy '%s'. Must be '%s' or '%s'", opts.Strategy, StrategyLayers, StrategyComponents)
+	}
+
+	groupOf := make([]int, len(g.tasks))
+	for k, grp := range groups {
+		for _, i := range grp {
+			groupOf[i] = k
+		}
+	}
+	milestones := make([]validator.Milestone, len(groups))
+	for k, grp := range groups {
+		m := validator.Milestone{Name: fmt.Sprintf("M%d", k+1)}
+		var after []int
+		for _, i := range grp {
+			m.TaskIDs = append(m.TaskIDs, g.tasks[i].TaskID)
+			for _, d := range g.deps[i] {
+				if dk := groupOf[d]; dk != k && !slices.Contains(after, dk) {
+					after = append(after, dk)
+				}
+			}
+		}
+		slices.Sort(after)
+		for _, dk := range after {
+			m.DependsOnMilestones = append(m.DependsOnMilestones, fmt.Sprintf("M%d", dk+1))
+		}
+		milestones[k] = m
+	}
+	return milestones, nil
+}
+
+// layerGroups orders tasks by level (then graph order) and makes each level
+// a group. A group smaller than minTasks takes in the next level when the
+// result fits in maxTasks; a level larger than maxTasks is split.
+func layerGroups(tasks []int, level []int, minTasks, maxTasks int) [][]int {
+	byLevel := map[int][]int{}
+	var levels []int
+	for _, i := range tasks {
+		if _, ok := byLevel[level[i]]; !ok {
+			levels = append(levels, level[i])
+		}
+		byLevel[level[i]] = append(byLevel[level[i]], i)
+	}
+	slices.Sort(levels)
+
+	var groups [][]int
+	var cur []int
+	for _, l := range levels {
+		layer := byLevel[l]
+		if len(cur) > 0 && (len(cur) >= minTasks || len(cur)+len(layer) > maxTasks) {
+			groups = append(groups, cur)
+			cur = nil
+		}
+		for len(layer) > maxTasks {
+			groups = append(groups, layer[:maxTasks])
+			layer = layer[maxTasks:]
+		}
+		cur = append(cur, layer...)
+	}
+	if len(cur) > 0 {
+		groups = append(groups, cur)
+	}
+	return groups
+}
+
+// levels returns each task's depth: 0 for tasks without dependencies in
+// the graph, otherwise one more than its deepest dependency.
+func (g *depGraph) levels() ([]int, error) {
+	n := len(g.tasks)
+	level := make([]int, n)
+	remaining := make([]int, n)
+	var queue []int
+	for i := range n {
+		remaining[i] = len(g.deps[i])
+		if remaining[i] == 0 {
+			queue = append(queue, i)
+		}
+	}
+	done := 0
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+		done++
+		for _, d := range g.dependents[i] {
+			level[d] = max(level[d], level[i]+1)
+			if remaining[d]--; remaining[d] == 0 {
+				queue = append(queue, d)
+			}
+		}
+	}
+	if done != n {
+		return nil, fmt.Errorf("dependency graph has a cycle")
+	}
+	return level, nil
+}
+
+// components returns the weakly connected components of the graph, each
+// in graph order, ordered by their first task.
+func (g *depGraph) components() [][]int {
+	comp := make([]int, len(g.tasks))
+	for i := range comp {
+		comp[i] = -1
+	}
+	var out [][]int
+	for i := range g.tasks {
+		if comp[i] >= 0 {
+			continue
+		}
+		id := len(out)
+		var members []int
+		stack := []int{i}
+		comp[i] = id
+		for len(stack) > 0 {
+			j := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			members = append(members, j)
+			for _, k := range append(slices.Clone(g.deps[j]), g.dependents[j]...) {
+				if comp[k] < 0 {
+					comp[k] = id
+					stack = append(stack, k)
+				}
+			}
+		}
+		slices.Sort(members)
+		out = append(out, members)
+	}
+	return out
+}
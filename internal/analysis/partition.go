@@ -0,0 +1,367 @@
+package analysis
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// PartitionOptions controls PartitionGraph.
+type PartitionOptions struct {
+	// Agents is the number of partitions. Values below one mean one.
+	Agents int
+
+	// Minutes converts a task estimate into working minutes, used to
+	// balance the partitions. Required.
+	Minutes func(estimate string) int
+
+	// UnknownMinutes is used for tasks whose estimate converts to zero.
+	UnknownMinutes int
+}
+
+// Partition is the share of the graph assigned to one agent.
+type Partition struct {
+	// Agent numbers partitions from 1.
+	Agent int
+
+	// Graph holds the agent's tasks. Dependencies on other agents' tasks
+	// are listed in external_tasks so the sub-graph validates on its own.
+	Graph *validator.TaskGraph
+
+	// Minutes is the total estimated work.
+	Minutes int
+}
+
+// CrossDependency is a dependency edge between two agents' tasks: Agent's
+// TaskID waits for DepAgent's DependsOn.
+type CrossDependency struct {
+	TaskID    string
+	Agent     int
+	DependsOn string
+	DepAgent  int
+}
+
+// SharedScope is a files_scope entry that tasks of several agents touch.
+type SharedScope struct {
+	Path   string
+	Agents []int
+}
+
+// PartitionPlan is the result of PartitionGraph.
+type PartitionPlan struct {
+	Parts        []Partition
+	CrossDeps    []CrossDependency
+	SharedScopes []SharedScope
+}
+
+// Affinity weights: splitting a dependency edge costs more than splitting
+// two tasks that touch the same files.
+const (
+	dependencyAffinity = 2
+	scopeAffinity      = 1
+)
+
+// PartitionGraph splits graph into opts.Agents sub-graphs with balanced
+// estimated work, keeping dependent tasks and tasks with overlapping
+// files_scope together where it can. Independent workstreams are placed
+// whole when they fit; larger ones are split along dependency order.
+func PartitionGraph(graph *validator.TaskGraph, opts PartitionOptions) (*PartitionPlan, error) {
+	if opts.Minutes == nil {
+		return nil, fmt.Errorf("partition: Minutes conversion is required")
+	}
+	g, err := newDepGraph(graph)
+	if err != nil {
+		return nil, err
+	}
+	level, err := g.levels()
+	if err != nil {
+		return nil, err
+	}
+	agents := max(opts.Agents, 1)
+	n := len(g.tasks)
+
+	weight := make([]int, n)
+	total := 0
+	for i, t := range g.tasks {
+		weight[i] = opts.Minutes(string(t.Estimate))
+		if weight[i] == 0 {
+			weight[i] = opts.UnknownMinutes
+		}
+		weight[i] = max(weight[i], 1)
+		total += weight[i]
+	}
+	// Allow 10% over an even split so affinity can win over exact balance.
+	capacity := (total*11/10 + agents - 1) / agents
+
+	scopes := make([][]string, n)
+	for i := range g.tasks {
+		scopes[i], _, _ = g.tasks[i].ParseFilesScope()
+		for k, f := range scopes[i] {
+			scopes[i][k] = validator.NormalizeScopePath(f)
+		}
+	}
+	affinity := make([]map[int]int, n)
+	for i := range affinity {
+		affinity[i] = map[int]int{}
+	}
+	for i := range n {
+		for _, d := range g.deps[i] {
+			affinity[i][d] += dependencyAffinity
+			affinity[d][i] += dependencyAffinity
+		}
+		for j := i + 1; j < n; j++ {
+			if scopesOverlap(scopes[i], scopes[j]) {
+				affinity[i][j] += scopeAffinity
+				affinity[j][i] += scopeAffinity
+			}
+		}
+	}
+
+	// Visit the largest workstreams first, each in dependency order.
+	comps := g.components()
+	compWeight := func(c []int) int {
+		w := 0
+		for _, i := range c {
+			w += weight[i]
+		}
+		return w
+	}
+	slices.SortStableFunc(comps, func(a, b []int) int { return compWeight(b) - compWeight(a) })
+
+	part := make([]int, n)
+	for i := range part {
+		part[i] = -1
+	}
+	load := make([]int, agents)
+	lightest := func() int {
+		p := 0
+		for k := range load {
+			if load[k] < load[p] {
+				p = k
+			}
+		}
+		return p
+	}
+	gain := func(i, p int) int {
+		s := 0
+		for j, w := range affinity[i] {
+			if part[j] == p {
+				s += w
+			}
+		}
+		return s
+	}
+
+	for _, comp := range comps {
+		if w := compWeight(comp); w <= capacity-load[lightest()] {
+			p := lightest()
+			for _, i := range comp {
+				part[i] = p
+			}
+			load[p] += w
+			continue
+		}
+		order := slices.Clone(comp)
+		slices.SortStableFunc(order, func(a, b int) int { return level[a] - level[b] })
+		for _, i := range order {
+			best, bestGain := -1, -1
+			for p := range agents {
+				if load[p]+weight[i] > capacity {
+					continue
+				}
+				if gn := gain(i, p); gn > bestGain || (gn == bestGain && load[p] < load[best]) {
+					best, bestGain = p, gn
+				}
+			}
+			if best < 0 {
+				best = lightest()
+			}
+			part[i] = best
+			load[best] += weight[i]
+		}
+	}
+
+	// Refine: move single tasks to the partition they are most tied to
+	// while capacity allows, until nothing improves.
+	for range n {
+		moved := false
+		for i := range n {
+			cur := part[i]
+			for p := range agents {
+				if p == cur || load[p]+weight[i] > capacity {
+					continue
+				}
+				if gain(i, p) > gain(i, cur) {
+					load[cur] -= weight[i]
+					load[p] += weight[i]
+					part[i] = p
+					cur = p
+					moved = true
+				}
+			}
+		}
+		if !moved {
+			break
+		}
+	}
+
+	return buildPlan(graph, g, part, weight, scopes, agents), nil
+}
+
+// buildPlan turns a task-to-partition assignment into sub-graphs and the
+// coordination lists.
+func buildPlan(graph *validator.TaskGraph, g *depGraph, part, weight []int, scopes [][]string, agents int) *PartitionPlan {
+	plan := &PartitionPlan{}
+	for p := range agents {
+		sub := &validator.TaskGraph{
+			Version:       graph.Version,
+			GeneratedBy:   graph.GeneratedBy,
+			GeneratedAt:   graph.GeneratedAt,
+			GraphRevision: graph.GraphRevision,
+			Types:         graph.Types,
+			Defaults:      graph.Defaults,
+			Tasks:         []validator.TaskNode{},
+			Extra:         graph.Extra,
+		}
+		external := slices.Clone(graph.ExternalTasks)
+		keep := map[string]bool{}
+		minutes := 0
+		for i, t := range g.tasks {
+			if part[i] != p {
+				continue
+			}
+			sub.Tasks = append(sub.Tasks, t)
+			keep[t.TaskID] = true
+			minutes += weight[i]
+			for _, d := range g.deps[i] {
+				if part[d] != p {
+					if id := g.tasks[d].TaskID; !slices.Contains(external, id) {
+						external = append(external, id)
+					}
+					plan.CrossDeps = append(plan.CrossDeps, CrossDependency{
+						TaskID: t.TaskID, Agent: p + 1, DependsOn: g.tasks[d].TaskID, DepAgent: part[d] + 1,
+					})
+				}
+			}
+		}
+		sub.ExternalTasks = external
+		sub.Milestones = subMilestones(graph.Milestones, keep)
+		plan.Parts = append(plan.Parts, Partition{Agent: p + 1, Graph: sub, Minutes: minutes})
+	}
+
+	byPath := map[string][]int{}
+	var paths []string
+	for i := range g.tasks {
+		for _, s := range scopes[i] {
+			if _, ok := byPath[s]; !ok {
+				paths = append(paths, s)
+			}
+			if a := part[i] + 1; !slices.Contains(byPath[s], a) {
+				byPath[s] = append(byPath[s], a)
+			}
+		}
+	}
+	for _, s := range paths {
+		if a := byPath[s]; len(a) > 1 {
+			slices.Sort(a)
+			plan.SharedScopes = append(plan.SharedScopes, SharedScope{Path: s, Agents: a})
+		}
+	}
+	return plan
+}
+
+// subMilestones keeps the milestones that still hold tasks, restricted to
+// those tasks, and drops references to milestones that were removed.
+func subMilestones(milestones []validator.Milestone, keep map[string]bool) []validator.Milestone {
+	var out []validator.Milestone
+	kept := map[string]bool{}
+	for _, m := range milestones {
+		var ids []string
+		for _, id := range m.TaskIDs {
+			if keep[id] {
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) > 0 {
+			m.TaskIDs = ids
+			out = append(out, m)
+			kept[m.Name] = true
+		}
+	}
+	for k := range out {
+		var deps []string
+		for _, d := range out[k].DependsOnMilestones {
+			if kept[d] {
+				deps = append(deps, d)
+			}
+		}
+		out[k].DependsOnMilestones = deps
+	}
+	return out
+}
+
+// scopesOverlap reports whether any entries of a and b may touch the same
+// files: equal entries, or one is a directory or glob prefix of the other.
+func scopesOverlap(a, b []string) bool {
+	for _, x := range a {
+		for _, y := range b {
+			px, py := scopePrefix(x), scopePrefix(y)
+			if x == y || strings.HasPrefix(py, px) || strings.HasPrefix(px, py) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// scopePrefix returns the fixed part of a files_scope entry that may cover
+// other paths: a directory ("internal/") or the text before a glob's first
+// wildcard. A plain file returns itself with a NUL appended so it only
+// matches itself.
+func scopePrefix(s string) string {
+	if i := strings.IndexAny(s, "*?["); i >= 0 {
+		return s[:i]
+	}
+	if strings.HasSuffix(s, "/") {
+		return s
+	}
+	return s + "\x00"
+}
+
+// Summary renders the plan as a markdown coordination brief.
+func (p *PartitionPlan) Summary() string {
+	var sb strings.Builder
+	sb.WriteString("# Coordination\n\n")
+	sb.WriteString("| Agent | File | Tasks | Estimated minutes |\n|---|---|---|---|\n")
+	for _, part := range p.Parts {
+		fmt.Fprintf(&sb, "| %d | `%s` | %d | %d |\n", part.Agent, PartitionFile(part.Agent), len(part.Graph.Tasks), part.Minutes)
+	}
+
+	sb.WriteString("\n## Cross-agent dependencies\n\n")
+	if len(p.CrossDeps) == 0 {
+		sb.WriteString("None: every agent can work independently.\n")
+	}
+	for _, d := range p.CrossDeps {
+		fmt.Fprintf(&sb, "- Agent %d `%s` waits for agent %d `%s`\n", d.Agent, d.TaskID, d.DepAgent, d.DependsOn)
+	}
+
+	sb.WriteString("\n## Shared files\n\n")
+	if len(p.SharedScopes) == 0 {
+		sb.WriteString("None: no files_scope entry is used by more than one agent.\n")
+	}
+	for _, s := range p.SharedScopes {
+		agents := make([]string, len(s.Agents))
+		for i, a := range s.Agents {
+			agents[i] = fmt.Sprint(a)
+		}
+		fmt.Fprintf(&sb, "- `%s`: agents %s\n", s.Path, strings.Join(agents, ", "))
+	}
+	return sb.String()
+}
+
+// PartitionFile is the file name of an agent's sub-graph.
+func PartitionFile(agent int) string {
+	return fmt.Sprintf("agent-%d.json", agent)
+}
@@ -0,0 +1,180 @@
+package analysis
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// ScoreWeights weigh the parts of a readiness score. Each part is scaled
+// to 0..1 before weighting, so the weights are directly comparable.
+type ScoreWeights struct {
+	// Priority favors urgent tasks: critical (or 0) scores 1, bd priority
+	// 4 scores 0, and an unset priority counts as medium.
+	Priority float64 `json:"priority"`
+
+	// Unblocks favors tasks whose completion makes the most waiting tasks
+	// ready, relative to the best ready task.
+	Unblocks float64 `json:"unblocks"`
+
+	// Estimate favors short tasks: the shortest ready task scores 1 and
+	// one twice as long 0.5.
+	Estimate float64 `json:"estimate"`
+}
+
+// DefaultScoreWeights counts each part equally.
+var DefaultScoreWeights = ScoreWeights{Priority: 1, Unblocks: 1, Estimate: 1}
+
+// ParseScoreWeights parses "priority=2,unblocks=1,estimate=0.5". Parts
+// that are not named keep their default weight.
+func ParseScoreWeights(s string) (ScoreWeights, error) {
+	w := DefaultScoreWeights
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(item, "=")
+		if !ok {
+			return w, fmt.Errorf("weight '%s' must be name=value", item)
+		}
+		v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || v < 0 {
+			return w, fmt.Errorf("weight '%s' must be a non-negative number", item)
+		}
+		switch strings.TrimSpace(name) {
+		case "priority":
+			w.Priority = v
+		case "unblocks":
+			w.Unblocks = v
+		case "estimate":
+			w.Estimate = v
+		default:
+			return w, fmt.Errorf("unknown weight '%s'. Must be 'priority', 'unblocks', or 'estimate'", name)
+		}
+	}
+	return w, nil
+}
+
+// ScoreOptions controls readiness scoring.
+type ScoreOptions struct {
+	Weights ScoreWeights
+
+	// Done lists the task_ids that are already finished.
+	Done []string
+
+	// Minutes converts a task estimate into working minutes. Required.
+	Minutes func(estimate string) int
+
+	// UnknownMinutes is used for tasks whose estimate converts to zero.
+	UnknownMinutes int
+}
+
+// QueueEntry is one ready task in the dispatch queue.
+type QueueEntry struct {
+	Rank            int             `json:"rank"`
+	TaskID          string          `json:"task_id"`
+	TaskName        string          `json:"task_name"`
+	Score           float64         `json:"score"`
+	Priority        validator.Level `json:"priority,omitempty"`
+	Unblocks        int             `json:"unblocks"`
+	EstimateMinutes int             `json:"estimate_minutes"`
+}
+
+// DispatchQueue orders the ready tasks of a graph for handing to agents.
+type DispatchQueue struct {
+	Weights ScoreWeights `json:"weights"`
+	Done    []string     `json:"done"`
+
+	// Queue lists the ready tasks, best first.
+	Queue []QueueEntry `json:"queue"`
+
+	// Waiting lists the unfinished tasks that still wait on another
+	// unfinished task, in graph order.
+	Waiting []string `json:"waiting"`
+}
+
+// ScoreReady ranks the tasks that are not done and whose dependencies in
+// the graph all are. Ties keep graph order. A Done entry that names no
+// task is an error.
+func ScoreReady(graph *validator.TaskGraph, opts ScoreOptions) (*DispatchQueue, error) {
+	g, err := newDepGraph(graph)
+	if err != nil {
+		return nil, err
+	}
+	done := make([]bool, len(g.tasks))
+	for _, id := range opts.Done {
+		i := slices.IndexFunc(g.tasks, func(t validator.TaskNode) bool { return t.TaskID == id })
+		if i < 0 {
+			return nil, fmt.Errorf("done task '%s' is not in the graph", id)
+		}
+		done[i] = true
+	}
+	waitsOn := func(i, except int) bool {
+		for _, d := range g.deps[i] {
+			if d != except && !done[d] {
+				return true
+			}
+		}
+		return false
+	}
+
+	q := &DispatchQueue{Weights: opts.Weights, Done: opts.Done, Queue: []QueueEntry{}, Waiting: []string{}}
+	if q.Done == nil {
+		q.Done = []string{}
+	}
+	var ready []int
+	for i, t := range g.tasks {
+		switch {
+		case done[i]:
+		case waitsOn(i, -1):
+			q.Waiting = append(q.Waiting, t.TaskID)
+		default:
+			ready = append(ready, i)
+		}
+	}
+
+	entries := make([]QueueEntry, len(ready))
+	maxUnblocks, minMinutes := 0, 0
+	for k, i := range ready {
+		t := g.tasks[i]
+		e := QueueEntry{TaskID: t.TaskID, TaskName: t.TaskName, Priority: t.Priority}
+		for _, d := range g.dependents[i] {
+			if !done[d] && !waitsOn(d, i) {
+				e.Unblocks++
+			}
+		}
+		e.EstimateMinutes = opts.Minutes(string(t.Estimate))
+		if e.EstimateMinutes <= 0 {
+			e.EstimateMinutes = opts.UnknownMinutes
+		}
+		maxUnblocks = max(maxUnblocks, e.Unblocks)
+		if k == 0 || e.EstimateMinutes < minMinutes {
+			minMinutes = e.EstimateMinutes
+		}
+		entries[k] = e
+	}
+
+	for k := range entries {
+		e := &entries[k]
+		score := opts.Weights.Priority * float64(4-min(max(priorityRank(e.Priority), 0), 4)) / 4
+		if maxUnblocks > 0 {
+			score += opts.Weights.Unblocks * float64(e.Unblocks) / float64(maxUnblocks)
+		}
+		if e.EstimateMinutes > 0 {
+			score += opts.Weights.Estimate * float64(minMinutes) / float64(e.EstimateMinutes)
+		}
+		e.Score = math.Round(score*1000) / 1000
+	}
+	sort.SliceStable(entries, func(a, b int) bool { return entries[a].Score > entries[b].Score })
+	for k := range entries {
+		entries[k].Rank = k + 1
+	}
+	q.Queue = append(q.Queue, entries...)
+	return q, nil
+}
@@ -0,0 +1,136 @@
+package beads
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// maxArgLen is the longest single argument passed to bd. Linux refuses to
+// exec a command with any argument over MAX_ARG_STRLEN, 128 KiB counting
+// the terminating NUL, which a long description or design JSON can reach.
+var maxArgLen = 128*1024 - 1
+
+// truncatedMarker ends an argument cut to fit maxArgLen.
+const truncatedMarker = "\n\n[truncated by taskval: too long for a command-line argument]"
+
+// fileFlags lists, per bd subcommand, the content flags that have a
+// variant reading the value from a file. The "" flag stands for the
+// positional text of 'comments add'.
+var fileFlags = map[string]map[string]string{
+	"create":       {"--description": "--body-file"},
+	"update":       {"--description": "--body-file", "--design": "--design-file"},
+	"comments add": {"": "--file"},
+}
+
+// flagProber is implemented by backends that can tell whether the bd
+// they run accepts a flag. Backends without it get no file fallback.
+type flagProber interface {
+	supportsFlag(subcommand, flag string) bool
+}
+
+// supportsFlag reports whether 'bd <subcommand> --help' lists flag. The
+// help text is read once per subcommand; any failure counts as unsupported.
+func (b *cliBackend) supportsFlag(subcommand, flag string) bool {
+	if b.help == nil {
+		b.help = make(map[string]string)
+	}
+	help, ok := b.help[subcommand]
+	if !ok {
+		out, err := exec.Command("bd", append(strings.Fields(subcommand), "--help")...).CombinedOutput()
+		if err == nil {
+			help = string(out)
+		}
+		b.help[subcommand] = help
+	}
+	return helpListsFlag(help, flag)
+}
+
+// helpListsFlag reports whether help text has a line declaring flag, as in
+// "      --body-file string" or "  -f, --file string". A mention inside
+// another flag's description, or a longer flag it prefixes, does not count.
+func helpListsFlag(help, flag string) bool {
+	pattern := regexp.MustCompile(`(?m)^\s*(-\w, )?` + regexp.QuoteMeta(flag) + `([\s=]|$)`)
+	return pattern.MatchString(help)
+}
+
+// subcommand returns the bd subcommand args run, as keyed in fileFlags.
+func subcommand(args []string) string {
+	if len(args) > 1 && args[0] == "comments" {
+		return "comments " + args[1]
+	}
+	if len(args) > 0 {
+		return args[0]
+	}
+	return ""
+}
+
+// fitArgs returns args with every argument over maxArgLen moved to a temp
+// file, when bd has a file flag for it, or else truncated. It returns a
+// warning for each truncation and a cleanup func that removes the files,
+// to be called once the command has run.
+func fitArgs(backend Backend, args []string) ([]string, []string, func(), error) {
+	var files []string
+	cleanup := func() {
+		for _, f := range files {
+			os.Remove(f)
+		}
+	}
+	sub := subcommand(args)
+	prober, _ := backend.(flagProber)
+
+	out := make([]string, 0, len(args)+1)
+	var warnings []string
+	for i, a := range args {
+		if len(a) <= maxArgLen {
+			out = append(out, a)
+			continue
+		}
+		flag := ""
+		if i > 0 && strings.HasPrefix(args[i-1], "--") {
+			flag = args[i-1]
+		}
+		if fileFlag, ok := fileFlags[sub][flag]; ok && prober != nil && prober.supportsFlag(sub, fileFlag) {
+			path, err := writeArgFile(a)
+			if err != nil {
+				cleanup()
+				return nil, nil, nil, err
+			}
+			files = append(files, path)
+			if flag == "" {
+				out = append(out, fileFlag, path)
+			} else {
+				out[len(out)-1] = fileFlag
+				out = append(out, path)
+			}
+			continue
+		}
+		name := flag
+		if name == "" {
+			name = "text"
+		}
+		warnings = append(warnings, fmt.Sprintf("bd %s %s cut from %d to %d bytes: longer than the command-line argument limit, and bd has no file flag for it", sub, name, len(a), maxArgLen))
+		out = append(out, truncate(a, maxArgLen-len(truncatedMarker))+truncatedMarker)
+	}
+	return out, warnings, cleanup, nil
+}
+
+// writeArgFile writes an argument's value to a new temp file.
+func writeArgFile(value string) (string, error) {
+	f, err := os.CreateTemp("", "taskval-arg-*")
+	if err != nil {
+		return "", fmt.Errorf("writing long argument to a temp file: %w", err)
+	}
+	if _, err := f.WriteString(value); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return "", fmt.Errorf("writing long argument to a temp file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("writing long argument to a temp file: %w", err)
+	}
+	return f.Name(), nil
+}
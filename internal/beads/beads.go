@@ -1,12 +1,30 @@
 package beads
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"regexp"
+	"slices"
 	"strings"
+	"text/template"
+	"unicode/utf8"
 
+	"github.com/nixlim/task_templating/internal/schedule"
 	"github.com/nixlim/task_templating/internal/validator"
 )
 
+// DefaultMilestoneLabelPrefix is the --milestone-label-prefix default.
+const DefaultMilestoneLabelPrefix = "milestone:"
+
+// Epic estimate modes accepted by Creator.EpicEstimate.
+const (
+	EpicEstimateNone         = "none"
+	EpicEstimateSum          = "sum"
+	EpicEstimateCriticalPath = "critical-path"
+)
+
 // Creator orchestrates the creation of Beads issues from validated task templates.
 type Creator struct {
 	// DryRun when true prints commands without executing them.
@@ -15,8 +33,45 @@ type Creator struct {
 	// EpicTitle overrides the auto-generated epic title (graph mode only).
 	EpicTitle string
 
+	// EpicEstimate sets the created epic's estimate from its tasks: the
+	// sum of their estimates, or the length of the critical path, in
+	// minutes. Empty or EpicEstimateNone leaves the epic without one.
+	// Tasks without an estimate count as zero, as they do in bd.
+	EpicEstimate string
+
+	// MilestoneLabelPrefix, when set, labels each task in graph mode with
+	// the prefix followed by each milestone listing it, the name in
+	// kebab-case: "milestone:m1-core-infrastructure".
+	MilestoneLabelPrefix string
+
+	// ParentEpic is the bd ID of an existing epic to parent the tasks
+	// under instead of creating one; see CheckParentEpic.
+	ParentEpic string
+
+	// SkipTasks and OnlyTasks leave tasks out of graph creation: the
+	// listed task_ids, or all but the listed ones. Dependency links to a
+	// left-out task are dropped and reported (graph mode only).
+	SkipTasks []string
+	OnlyTasks []string
+
 	// Filename is the input file name, used for epic title derivation.
 	Filename string
+
+	// AcceptanceBullet is the list marker for acceptance criteria; see
+	// AcceptanceBullet. Empty means plain "- " bullets.
+	AcceptanceBullet string
+
+	// DescriptionTemplate, when set, replaces the default issue description
+	// layout; see LoadDescriptionTemplate.
+	DescriptionTemplate *template.Template
+
+	// Report, when set, is the validation result whose findings for each
+	// task are posted as a comment on that task's issue (--attach-report).
+	Report *validator.ValidationResult
+
+	// DesignExtensions copies each task's x_ extension fields into its
+	// design metadata, under _template.extensions.
+	DesignExtensions bool
 }
 
 // CreationResult holds the outcome of a beads creation operation.
@@ -27,9 +82,17 @@ type CreationResult struct {
 	// EpicTitle is the title used for the epic.
 	EpicTitle string
 
+	// EpicExisting is true when EpicID is an existing epic (--parent-epic)
+	// rather than one created by this run.
+	EpicExisting bool
+
 	// TaskIDs maps template task_id to bd issue ID.
 	TaskIDs map[string]string
 
+	// TaskOrder lists the task_ids of TaskIDs in the order their issues
+	// were created, reused, or updated: the graph's topological order.
+	TaskOrder []string
+
 	// TaskTitles maps template task_id to the task_name used as title.
 	TaskTitles map[string]string
 
@@ -42,14 +105,35 @@ type CreationResult struct {
 	// Deps is the number of dependencies linked.
 	Deps int
 
+	// DepsExisting is the number of dependencies that were already linked,
+	// from an earlier run, and were left as they are.
+	DepsExisting int
+
 	// DepsDetail holds dependency info for output formatting.
 	DepsDetail []DepLink
+
+	// Skipped and Updated list the task_ids matched to existing issues
+	// by --on-duplicate, which were reused or updated instead of created.
+	Skipped []string
+	Updated []string
+
+	// Excluded lists the task_ids left out by SkipTasks or OnlyTasks, and
+	// PrunedDeps the dependency links dropped with them.
+	Excluded   []string
+	PrunedDeps []PrunedDep
+
+	// Warnings records arguments truncated to fit the command-line
+	// argument limit, one per argument, prefixed with the task_id.
+	Warnings []string
 }
 
 // DepLink represents a dependency relationship between two beads issues.
 type DepLink struct {
 	TaskBdID string
 	DepBdID  string
+
+	// Existing is true when the link was already there.
+	Existing bool
 }
 
 // BdCommand represents a bd CLI command to be executed.
@@ -60,10 +144,19 @@ type BdCommand struct {
 	// TaskID is the template task_id this command relates to (for ID mapping).
 	TaskID string
 
-	// Type indicates the purpose: "create-epic", "create-task", "dep-add", "update-design".
+	// Type indicates the purpose: "create-epic", "create-task", "dep-add",
+	// "update-design", "add-comment", or, for tasks that already have an issue,
+	// "existing-task" (no command runs) and "update-task". "existing-epic"
+	// stands in for create-epic when tasks go under an existing epic.
+	// "excluded-task" and "pruned-dep" record a task left out and a link
+	// dropped with it; no command runs for them either.
 	Type string
 
-	// DepTaskID and DepOnID are set for dep-add commands.
+	// ExistingID is the issue reused by existing-epic, existing-task, and
+	// update-task.
+	ExistingID string
+
+	// DepTaskID and DepOnID are set for dep-add and pruned-dep commands.
 	DepTaskID string
 	DepOnID   string
 }
@@ -72,8 +165,16 @@ type BdCommand struct {
 func (c *Creator) BuildSingleTaskCommands(task *validator.TaskNode) ([]BdCommand, error) {
 	var cmds []BdCommand
 
-	// Step 1: Create the task issue.
-	createArgs := c.buildTaskCreateArgs(task, "")
+	// Step 1: Create the task issue, under the parent epic if one is given.
+	parentID := ""
+	if c.ParentEpic != "" {
+		cmds = append(cmds, c.existingEpicCommand())
+		parentID = "<epic-id>"
+	}
+	createArgs, err := c.buildTaskCreateArgs(task, parentID, nil)
+	if err != nil {
+		return nil, err
+	}
 	cmds = append(cmds, BdCommand{
 		Args:   createArgs,
 		TaskID: task.TaskID,
@@ -81,7 +182,7 @@ func (c *Creator) BuildSingleTaskCommands(task *validator.TaskNode) ([]BdCommand
 	})
 
 	// Step 2: Update with template metadata.
-	designJSON, err := BuildTemplateMetadata(task)
+	designJSON, err := BuildTemplateMetadataWith(task, c.DesignExtensions)
 	if err != nil {
 		return nil, fmt.Errorf("building template metadata for '%s': %w", task.TaskID, err)
 	}
@@ -91,6 +192,7 @@ func (c *Creator) BuildSingleTaskCommands(task *validator.TaskNode) ([]BdCommand
 		Type:   "update-design",
 	})
 
+	cmds = append(cmds, c.reportCommands(task, 0)...)
 	return cmds, nil
 }
 
@@ -98,27 +200,38 @@ func (c *Creator) BuildSingleTaskCommands(task *validator.TaskNode) ([]BdCommand
 func (c *Creator) BuildGraphCommands(graph *validator.TaskGraph) ([]BdCommand, error) {
 	var cmds []BdCommand
 
-	// Step 1: Create the epic.
-	epicTitle := c.resolveEpicTitle(graph)
-	epicPriority := c.resolveGraphPriority(graph)
-	epicArgs := []string{
-		"create",
-		"--title", epicTitle,
-		"--type", "epic",
-		"--priority", fmt.Sprintf("%d", epicPriority),
-		"--labels", "taskval-managed",
-		"--silent",
+	excluded, err := c.excludedTasks(graph)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 1: Create the epic, unless the tasks go under an existing one.
+	if c.ParentEpic != "" {
+		cmds = append(cmds, c.existingEpicCommand())
+	} else {
+		epic, err := c.epicCommand(graph, excluded)
+		if err != nil {
+			return nil, err
+		}
+		cmds = append(cmds, epic)
 	}
-	cmds = append(cmds, BdCommand{
-		Args: epicArgs,
-		Type: "create-epic",
-	})
 
-	// Step 2: Create tasks in topological order.
-	ordered := topologicalSort(graph)
+	// Step 2: Create tasks in topological order, leaving out excluded ones.
+	all := topologicalSort(graph)
+	var ordered []*validator.TaskNode
+	for _, task := range all {
+		if excluded[task.TaskID] {
+			cmds = append(cmds, BdCommand{TaskID: task.TaskID, Type: "excluded-task"})
+			continue
+		}
+		ordered = append(ordered, task)
+	}
 
 	for _, task := range ordered {
-		createArgs := c.buildTaskCreateArgs(task, "<epic-id>")
+		createArgs, err := c.buildTaskCreateArgs(task, "<epic-id>", c.milestoneLabels(graph, task.TaskID))
+		if err != nil {
+			return nil, err
+		}
 		cmds = append(cmds, BdCommand{
 			Args:   createArgs,
 			TaskID: task.TaskID,
@@ -126,13 +239,22 @@ func (c *Creator) BuildGraphCommands(graph *validator.TaskGraph) ([]BdCommand, e
 		})
 	}
 
-	// Step 3: Add dependency links.
-	for _, task := range ordered {
+	// Step 3: Add dependency links. External tasks have no issue created
+	// here, so links to them are left for the owning graph's import;
+	// links to or from excluded tasks are dropped and recorded.
+	for _, task := range all {
 		deps, _, err := task.ParseDependsOn()
 		if err != nil {
 			continue
 		}
 		for _, dep := range deps {
+			if graph.FindTask(dep) == nil {
+				continue
+			}
+			if excluded[task.TaskID] || excluded[dep] {
+				cmds = append(cmds, BdCommand{Type: "pruned-dep", DepTaskID: task.TaskID, DepOnID: dep})
+				continue
+			}
 			cmds = append(cmds, BdCommand{
 				Args:      []string{"dep", "add", "<" + task.TaskID + "-id>", "<" + dep + "-id>"},
 				Type:      "dep-add",
@@ -144,7 +266,7 @@ func (c *Creator) BuildGraphCommands(graph *validator.TaskGraph) ([]BdCommand, e
 
 	// Step 4: Update template metadata for each task.
 	for _, task := range ordered {
-		designJSON, err := BuildTemplateMetadata(task)
+		designJSON, err := BuildTemplateMetadataWith(task, c.DesignExtensions)
 		if err != nil {
 			return nil, fmt.Errorf("building template metadata for '%s': %w", task.TaskID, err)
 		}
@@ -155,26 +277,158 @@ func (c *Creator) BuildGraphCommands(graph *validator.TaskGraph) ([]BdCommand, e
 		})
 	}
 
+	// Step 5: Attach each task's validation findings.
+	for i := range graph.Tasks {
+		if !excluded[graph.Tasks[i].TaskID] {
+			cmds = append(cmds, c.reportCommands(&graph.Tasks[i], i)...)
+		}
+	}
+
 	return cmds, nil
 }
 
-// buildTaskCreateArgs constructs the arguments for a bd create command for a single task.
-func (c *Creator) buildTaskCreateArgs(task *validator.TaskNode, parentID string) []string {
+// epicCommand returns the command creating the graph's epic, with the
+// estimate of the tasks that are not excluded.
+func (c *Creator) epicCommand(graph *validator.TaskGraph, excluded map[string]bool) (BdCommand, error) {
+	args := []string{
+		"create",
+		"--title", c.resolveEpicTitle(graph),
+		"--type", "epic",
+		"--priority", fmt.Sprintf("%d", c.resolveGraphPriority(graph)),
+	}
+	est, err := c.resolveEpicEstimate(graph, excluded)
+	if err != nil {
+		return BdCommand{}, err
+	}
+	if est > 0 {
+		args = append(args, "--estimate", fmt.Sprintf("%d", est))
+	}
+	args = append(args, "--labels", "taskval-managed", "--silent")
+	return BdCommand{Args: args, Type: "create-epic"}, nil
+}
+
+// resolveEpicEstimate returns the epic's estimate in minutes under
+// EpicEstimate, or 0 for none.
+func (c *Creator) resolveEpicEstimate(graph *validator.TaskGraph, excluded map[string]bool) (int, error) {
+	switch c.EpicEstimate {
+	case "", EpicEstimateNone:
+		return 0, nil
+	case EpicEstimateSum, EpicEstimateCriticalPath:
+	default:
+		return 0, fmt.Errorf("unknown epic estimate '%s'. Must be 'sum', 'critical-path', or 'none'", c.EpicEstimate)
+	}
+
+	included := *graph
+	included.Tasks = slices.DeleteFunc(slices.Clone(graph.Tasks), func(t validator.TaskNode) bool { return excluded[t.TaskID] })
+	if c.EpicEstimate == EpicEstimateSum {
+		total := 0
+		for _, t := range included.Tasks {
+			total += MapEstimate(string(t.Estimate))
+		}
+		return total, nil
+	}
+	sched, err := schedule.Compute(&included, schedule.Options{Workers: len(included.Tasks), Minutes: MapEstimate})
+	if err != nil {
+		return 0, fmt.Errorf("computing the epic estimate: %w", err)
+	}
+	return sched.CriticalMinutes, nil
+}
+
+// existingEpicCommand returns the placeholder binding <epic-id> to
+// ParentEpic. No bd command runs for it.
+func (c *Creator) existingEpicCommand() BdCommand {
+	return BdCommand{Type: "existing-epic", ExistingID: c.ParentEpic}
+}
+
+// milestoneLabels returns the MilestoneLabelPrefix labels of a task, one
+// per milestone listing it, in milestone order.
+func (c *Creator) milestoneLabels(graph *validator.TaskGraph, taskID string) []string {
+	if c.MilestoneLabelPrefix == "" {
+		return nil
+	}
+	var labels []string
+	for _, m := range graph.Milestones {
+		if !slices.Contains(m.TaskIDs, taskID) {
+			continue
+		}
+		label := c.MilestoneLabelPrefix + milestoneSlug(m.Name)
+		if !slices.Contains(labels, label) {
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
+var nonSlug = regexp.MustCompile(`[^a-z0-9]+`)
+
+// milestoneSlug renders a milestone name in kebab-case, as task labels
+// are written; a comma would split the label in bd's --labels list.
+func milestoneSlug(name string) string {
+	slug := strings.Trim(nonSlug.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if slug == "" {
+		return "unnamed"
+	}
+	return slug
+}
+
+// reportCommands returns a comment command carrying the findings for the
+// task at index in the validated graph, or nothing if it has none.
+func (c *Creator) reportCommands(task *validator.TaskNode, index int) []BdCommand {
+	if c.Report == nil {
+		return nil
+	}
+	findings := c.Report.ForTask(index)
+	if len(findings) == 0 {
+		return nil
+	}
+	return []BdCommand{{
+		Args:   []string{"comments", "add", "<" + task.TaskID + "-id>", FormatTaskReport(task.TaskID, findings)},
+		TaskID: task.TaskID,
+		Type:   "add-comment",
+	}}
+}
+
+// FormatTaskReport renders one task's validation findings as a markdown
+// comment for its issue.
+func FormatTaskReport(taskID string, findings []validator.ValidationError) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("taskval validation report for `%s`: %d finding(s) still open.\n", taskID, len(findings)))
+	for _, f := range findings {
+		sb.WriteString(fmt.Sprintf("\n- **%s %s** at `%s`: %s", f.Severity, f.Rule, f.Path, f.Message))
+		if f.Suggestion != "" {
+			sb.WriteString(fmt.Sprintf("\n  Fix: %s", f.Suggestion))
+		}
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// buildTaskCreateArgs constructs the arguments for a bd create command for a
+// single task. extraLabels follow the task's own labels.
+func (c *Creator) buildTaskCreateArgs(task *validator.TaskNode, parentID string, extraLabels []string) ([]string, error) {
+	desc, err := RenderDescription(c.DescriptionTemplate, task)
+	if err != nil {
+		return nil, err
+	}
 	args := []string{
 		"create",
 		"--title", truncate(task.TaskName, 500),
 		"--type", "task",
-		"--description", ComposeDescription(task),
+		"--description", desc,
 	}
 
-	acceptance := FormatAcceptance(task.Acceptance)
+	bullet := c.AcceptanceBullet
+	if bullet == "" {
+		bullet = "- "
+	}
+	acceptance := FormatAcceptanceWith(task.Acceptance, bullet)
 	if acceptance != "" {
 		args = append(args, "--acceptance", acceptance)
 	}
 
-	args = append(args, "--priority", fmt.Sprintf("%d", MapPriority(task.Priority)))
+	args = append(args, "--priority", fmt.Sprintf("%d", MapPriority(string(task.Priority))))
 
-	est := MapEstimate(task.Estimate)
+	est := MapEstimate(string(task.Estimate))
 	if est > 0 {
 		args = append(args, "--estimate", fmt.Sprintf("%d", est))
 	}
@@ -187,8 +441,10 @@ func (c *Creator) buildTaskCreateArgs(task *validator.TaskNode, parentID string)
 		args = append(args, "--parent", parentID)
 	}
 
-	args = append(args, "--labels", "taskval-managed", "--silent")
-	return args
+	labels := append([]string{"taskval-managed"}, task.Labels...)
+	labels = append(labels, extraLabels...)
+	args = append(args, "--labels", strings.Join(labels, ","), "--silent")
+	return args, nil
 }
 
 // resolveEpicTitle determines the epic title using the resolution order from the spec.
@@ -216,7 +472,7 @@ func (c *Creator) resolveEpicTitle(graph *validator.TaskGraph) string {
 func (c *Creator) resolveGraphPriority(graph *validator.TaskGraph) int {
 	best := 2 // default medium
 	for _, t := range graph.Tasks {
-		p := MapPriority(t.Priority)
+		p := MapPriority(string(t.Priority))
 		if p < best {
 			best = p
 		}
@@ -229,35 +485,98 @@ func FormatTextOutput(result *CreationResult) string {
 	var sb strings.Builder
 	sb.WriteString("\nBEADS CREATION\n")
 
-	if result.EpicID != "" {
+	switch {
+	case result.EpicExisting:
+		sb.WriteString(fmt.Sprintf("  Epic exists:  %s, tasks parented under it\n", result.EpicID))
+	case result.EpicID != "":
 		sb.WriteString(fmt.Sprintf("  Epic created: %s %q\n", result.EpicID, result.EpicTitle))
 	}
 
-	for taskID, bdID := range result.TaskIDs {
+	for _, taskID := range orderedTaskIDs(result) {
+		bdID := result.TaskIDs[taskID]
 		title := result.TaskTitles[taskID]
-		sb.WriteString(fmt.Sprintf("  Task created: %s %q (%s)\n", bdID, title, taskID))
+		switch {
+		case slices.Contains(result.Skipped, taskID):
+			sb.WriteString(fmt.Sprintf("  Task exists:  %s (%s), skipped\n", bdID, taskID))
+		case slices.Contains(result.Updated, taskID):
+			sb.WriteString(fmt.Sprintf("  Task updated: %s %q (%s)\n", bdID, title, taskID))
+		default:
+			sb.WriteString(fmt.Sprintf("  Task created: %s %q (%s)\n", bdID, title, taskID))
+		}
 	}
 
 	for _, dep := range result.DepsDetail {
+		if dep.Existing {
+			sb.WriteString(fmt.Sprintf("  Dependency:   %s blocked-by %s, already linked\n", dep.TaskBdID, dep.DepBdID))
+			continue
+		}
 		sb.WriteString(fmt.Sprintf("  Dependency:   %s blocked-by %s\n", dep.TaskBdID, dep.DepBdID))
 	}
 
+	for _, id := range result.Excluded {
+		sb.WriteString(fmt.Sprintf("  Left out:     %s\n", id))
+	}
+	for _, dep := range result.PrunedDeps {
+		sb.WriteString(fmt.Sprintf("  Link dropped: %s blocked-by %s\n", dep.TaskID, dep.DependsOn))
+	}
+
+	for _, w := range result.Warnings {
+		sb.WriteString(fmt.Sprintf("  Warning:      %s\n", w))
+	}
+
 	epicCount := 0
-	if result.EpicID != "" {
+	if result.EpicID != "" && !result.EpicExisting {
 		epicCount = 1
 	}
-	sb.WriteString(fmt.Sprintf("\n  Summary: %d epic + %d tasks created, %d dependencies linked.\n",
+	sb.WriteString(fmt.Sprintf("\n  Summary: %d epic + %d tasks created, %d dependencies linked",
 		epicCount, result.Created-epicCount, result.Deps))
+	if result.DepsExisting > 0 {
+		sb.WriteString(fmt.Sprintf(" (%d already linked)", result.DepsExisting))
+	}
+	if len(result.Skipped) > 0 || len(result.Updated) > 0 {
+		sb.WriteString(fmt.Sprintf("; %d existing skipped, %d updated", len(result.Skipped), len(result.Updated)))
+	}
+	if len(result.Excluded) > 0 {
+		sb.WriteString(fmt.Sprintf("; %d left out, %d dependencies dropped", len(result.Excluded), len(result.PrunedDeps)))
+	}
+	sb.WriteString(".\n")
 
 	return sb.String()
 }
 
+// orderedTaskIDs returns the task_ids of result.TaskIDs in TaskOrder, then
+// any not listed there in sorted order, so output never depends on map
+// iteration.
+func orderedTaskIDs(result *CreationResult) []string {
+	ids := make([]string, 0, len(result.TaskIDs))
+	for _, id := range result.TaskOrder {
+		if _, ok := result.TaskIDs[id]; ok && !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	var rest []string
+	for id := range result.TaskIDs {
+		if !slices.Contains(ids, id) {
+			rest = append(rest, id)
+		}
+	}
+	slices.Sort(rest)
+	return append(ids, rest...)
+}
+
 // BeadsJSON is the JSON output structure for beads creation results.
 type BeadsJSON struct {
 	EpicID       string            `json:"epic_id,omitempty"`
 	Tasks        map[string]string `json:"tasks"`
+	TaskOrder    []string          `json:"task_order"`
 	DepsLinked   int               `json:"dependencies_linked"`
+	DepsExisting int               `json:"dependencies_existing,omitempty"`
 	TotalCreated int               `json:"total_created"`
+	Skipped      []string          `json:"skipped,omitempty"`
+	Updated      []string          `json:"updated,omitempty"`
+	Excluded     []string          `json:"excluded,omitempty"`
+	PrunedDeps   []PrunedDep       `json:"pruned_dependencies,omitempty"`
+	Warnings     []string          `json:"warnings,omitempty"`
 }
 
 // FormatJSONOutput creates the BeadsJSON structure from a CreationResult.
@@ -265,19 +584,103 @@ func FormatJSONOutput(result *CreationResult) *BeadsJSON {
 	return &BeadsJSON{
 		EpicID:       result.EpicID,
 		Tasks:        result.TaskIDs,
+		TaskOrder:    orderedTaskIDs(result),
 		DepsLinked:   result.Deps,
+		DepsExisting: result.DepsExisting,
 		TotalCreated: result.Created,
+		Skipped:      result.Skipped,
+		Updated:      result.Updated,
+		Excluded:     result.Excluded,
+		PrunedDeps:   result.PrunedDeps,
+		Warnings:     result.Warnings,
+	}
+}
+
+// ErrorJSON describes a failed creation run in JSON output, beside the
+// BeadsJSON of what was done before the failure.
+type ErrorJSON struct {
+	Message string   `json:"message"`
+	Type    string   `json:"type,omitempty"`
+	TaskID  string   `json:"task_id,omitempty"`
+	Command []string `json:"command,omitempty"`
+	Stderr  string   `json:"stderr,omitempty"`
+}
+
+// FormatErrorJSON creates the ErrorJSON for an error from ExecuteWith. The
+// command fields are set only when a bd command failed; an error raised
+// before any ran, such as an unbound placeholder, has just the message.
+func FormatErrorJSON(err error) *ErrorJSON {
+	out := &ErrorJSON{Message: err.Error()}
+	var cmdErr *CommandError
+	if errors.As(err, &cmdErr) {
+		out.Type = cmdErr.Type
+		out.TaskID = cmdErr.TaskID
+		out.Command = cmdErr.Args
+		out.Stderr = cmdErr.Err.Error()
+	}
+	return out
+}
+
+// DryRunJSON is the JSON output structure for a dry run: every command
+// that would run, in execution order, with placeholder IDs unresolved.
+type DryRunJSON struct {
+	Commands []PlannedCommand `json:"commands"`
+}
+
+// PlannedCommand is one BdCommand in DryRunJSON. Args omits the leading
+// "bd"; existing-epic, existing-task, excluded-task, and pruned-dep
+// entries have no args because nothing runs for them.
+type PlannedCommand struct {
+	Type       string   `json:"type"`
+	TaskID     string   `json:"task_id,omitempty"`
+	Args       []string `json:"args,omitempty"`
+	ExistingID string   `json:"existing_id,omitempty"`
+	DependsOn  string   `json:"depends_on,omitempty"`
+}
+
+// FormatDryRunJSON creates the DryRunJSON structure from the planned
+// commands. Unlike the text form it includes design updates and comments.
+func FormatDryRunJSON(cmds []BdCommand) *DryRunJSON {
+	out := &DryRunJSON{Commands: make([]PlannedCommand, 0, len(cmds))}
+	for _, cmd := range cmds {
+		pc := PlannedCommand{
+			Type:       cmd.Type,
+			TaskID:     cmd.TaskID,
+			ExistingID: cmd.ExistingID,
+			DependsOn:  cmd.DepOnID,
+		}
+		if cmd.Type == "dep-add" || cmd.Type == "pruned-dep" {
+			pc.TaskID = cmd.DepTaskID
+		}
+		pc.Args = cmd.Args
+		out.Commands = append(out.Commands, pc)
 	}
+	return out
 }
 
 // FormatDryRunOutput formats the dry-run output showing commands that would be executed.
 func FormatDryRunOutput(cmds []BdCommand) string {
+	return formatDryRun(cmds, false)
+}
+
+// FormatDryRunOutputFull is FormatDryRunOutput with nothing left out: design
+// updates are listed with their metadata pretty-printed below the command,
+// and report comments are listed too.
+func FormatDryRunOutputFull(cmds []BdCommand) string {
+	return formatDryRun(cmds, true)
+}
+
+func formatDryRun(cmds []BdCommand, full bool) string {
 	var sb strings.Builder
 	sb.WriteString("\nBEADS CREATION (DRY RUN)\n")
 
 	epicCount := 0
 	taskCount := 0
 	depCount := 0
+	reused := 0
+	comments := 0
+	excluded := 0
+	pruned := 0
 
 	for _, cmd := range cmds {
 		switch cmd.Type {
@@ -287,20 +690,72 @@ func FormatDryRunOutput(cmds []BdCommand) string {
 			taskCount++
 		case "dep-add":
 			depCount++
+		case "existing-epic":
+			sb.WriteString(fmt.Sprintf("  [DRY-RUN] (parent tasks under existing epic %s)\n", cmd.ExistingID))
+			continue
+		case "excluded-task":
+			excluded++
+			sb.WriteString(fmt.Sprintf("  [DRY-RUN] (leave out %s)\n", cmd.TaskID))
+			continue
+		case "pruned-dep":
+			pruned++
+			sb.WriteString(fmt.Sprintf("  [DRY-RUN] (drop link %s blocked-by %s)\n", cmd.DepTaskID, cmd.DepOnID))
+			continue
+		case "existing-task":
+			reused++
+			sb.WriteString(fmt.Sprintf("  [DRY-RUN] (reuse %s for %s)\n", cmd.ExistingID, cmd.TaskID))
+			continue
+		case "update-task":
+			reused++
 		}
-		// Skip update-design in dry-run output for brevity.
+		// Skip update-design and report comments in dry-run output for
+		// brevity, unless the full listing was asked for.
 		if cmd.Type == "update-design" {
+			if full {
+				sb.WriteString(formatDesignUpdate(cmd.Args))
+			}
 			continue
 		}
+		if cmd.Type == "add-comment" {
+			comments++
+			if !full {
+				continue
+			}
+		}
 		sb.WriteString(fmt.Sprintf("  [DRY-RUN] bd %s\n", formatArgs(cmd.Args)))
 	}
 
-	sb.WriteString(fmt.Sprintf("\n  Summary: Would create %d epic + %d tasks, link %d dependencies.\n",
+	sb.WriteString(fmt.Sprintf("\n  Summary: Would create %d epic + %d tasks, link %d dependencies",
 		epicCount, taskCount, depCount))
+	if reused > 0 {
+		sb.WriteString(fmt.Sprintf("; reuse %d existing issue(s)", reused))
+	}
+	if comments > 0 {
+		sb.WriteString(fmt.Sprintf("; attach %d validation report comment(s)", comments))
+	}
+	if excluded > 0 {
+		sb.WriteString(fmt.Sprintf("; leave out %d task(s) and drop %d dependencies", excluded, pruned))
+	}
+	sb.WriteString(".\n")
 
 	return sb.String()
 }
 
+// formatDesignUpdate renders an update-design command with its --design
+// JSON indented on the lines below it.
+func formatDesignUpdate(args []string) string {
+	i := slices.Index(args, "--design")
+	if i < 0 || i+1 >= len(args) {
+		return fmt.Sprintf("  [DRY-RUN] bd %s\n", formatArgs(args))
+	}
+	var design bytes.Buffer
+	if err := json.Indent(&design, []byte(args[i+1]), "      ", "  "); err != nil {
+		return fmt.Sprintf("  [DRY-RUN] bd %s\n", formatArgs(args))
+	}
+	rest := append(slices.Clone(args[:i+1]), args[i+2:]...)
+	return fmt.Sprintf("  [DRY-RUN] bd %s\n      %s\n", formatArgs(rest), design.String())
+}
+
 // topologicalSort returns tasks in dependency order (dependencies before dependents).
 func topologicalSort(graph *validator.TaskGraph) []*validator.TaskNode {
 	taskIndex := make(map[string]int, len(graph.Tasks))
@@ -354,11 +809,15 @@ func topologicalSort(graph *validator.TaskGraph) []*validator.TaskNode {
 	return ordered
 }
 
-// truncate shortens a string to maxLen if needed.
+// truncate shortens a string to at most maxLen bytes if needed, without
+// splitting a UTF-8 sequence.
 func truncate(s string, maxLen int) string {
 	if len(s) <= maxLen {
 		return s
 	}
+	for maxLen > 0 && !utf8.RuneStart(s[maxLen]) {
+		maxLen--
+	}
 	return s[:maxLen]
 }
 
@@ -1,9 +1,17 @@
 package beads
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
+	"unicode/utf8"
 
 	"github.com/nixlim/task_templating/internal/validator"
 )
@@ -127,6 +135,47 @@ func TestComposeDescription_GoalOnly(t *testing.T) {
 	}
 }
 
+func TestComposeDescription_Schedule(t *testing.T) {
+	task := &validator.TaskNode{
+		Goal:      "Ship the release.",
+		Outputs:   []validator.OutputSpec{{Name: "tag", Type: "string", Constraints: "semver", Destination: "git"}},
+		Due:       "2026-03-06",
+		NotBefore: "2026-03-02",
+	}
+	desc := ComposeDescription(task)
+	if want := "## Schedule\n- Not before: 2026-03-02\n- Due: 2026-03-06\n"; !strings.HasSuffix(desc, want) {
+		t.Errorf("got %q, want %q", desc, want)
+	}
+}
+
+func TestComposeDescription_Risk(t *testing.T) {
+	task := &validator.TaskNode{
+		Goal:    "Migrate the orders table.",
+		Outputs: []validator.OutputSpec{{Name: "rows", Type: "int", Constraints: "rows >= 0", Destination: "log"}},
+		Risk:    &validator.Risk{Level: validator.RiskHigh, Mitigation: "Dry run against a snapshot first"},
+	}
+	desc := ComposeDescription(task)
+	if want := "## Risk\n- Level: high\n- Mitigation: Dry run against a snapshot first\n"; !strings.HasSuffix(desc, want) {
+		t.Errorf("description should end with the risk section, got %q", desc)
+	}
+}
+
+func TestComposeDescription_Verification(t *testing.T) {
+	task := &validator.TaskNode{
+		Goal:       "Parse() rejects empty input.",
+		Outputs:    []validator.OutputSpec{{Name: "err", Type: "error", Constraints: "non-nil", Destination: "return"}},
+		Acceptance: []string{"Given an empty file, Parse returns ErrEmpty"},
+		Verification: []validator.VerificationSpec{
+			{Criterion: 0, Command: "go test ./config -run TestParseEmpty", TestFile: "config/parse_test.go"},
+		},
+	}
+	desc := ComposeDescription(task)
+	want := "## Verification\n- Given an empty file, Parse returns ErrEmpty\n  - Run: `go test ./config -run TestParseEmpty`\n  - Test: `config/parse_test.go`\n"
+	if !strings.HasSuffix(desc, want) {
+		t.Errorf("description should end with the verification section, got %q", desc)
+	}
+}
+
 func TestComposeDescription_NAFieldsOmitted(t *testing.T) {
 	task := &validator.TaskNode{
 		Goal:        "Task with N/A fields.",
@@ -196,6 +245,50 @@ func TestBuildTemplateMetadata(t *testing.T) {
 	if !ok || len(fs) != 2 {
 		t.Errorf("files_scope = %v, want 2-element array", tmpl["files_scope"])
 	}
+
+	// The metadata must pass 'taskval validate-design'.
+	result, err := validator.ValidateDesign([]byte(jsonStr))
+	if err != nil {
+		t.Fatalf("ValidateDesign error: %v", err)
+	}
+	if !result.Valid {
+		t.Errorf("metadata does not validate: %+v", result.Errors)
+	}
+}
+
+func TestBuildTemplateMetadataExtensions(t *testing.T) {
+	task := &validator.TaskNode{
+		TaskID:  "test-task",
+		Inputs:  []validator.InputSpec{},
+		Outputs: []validator.OutputSpec{},
+		Extra: map[string]json.RawMessage{
+			"x_jira": json.RawMessage(`{"key": "PAY-1"}`),
+			"owner":  json.RawMessage(`"ana"`),
+		},
+	}
+
+	plain, err := BuildTemplateMetadata(task)
+	if err != nil {
+		t.Fatalf("BuildTemplateMetadata error: %v", err)
+	}
+	if strings.Contains(plain, "extensions") {
+		t.Errorf("extensions should be opt-in, got %s", plain)
+	}
+
+	withExt, err := BuildTemplateMetadataWith(task, true)
+	if err != nil {
+		t.Fatalf("BuildTemplateMetadataWith error: %v", err)
+	}
+	if !strings.Contains(withExt, `"extensions":{"x_jira":{"key":"PAY-1"}}`) {
+		t.Errorf("metadata = %s, want only the x_ field under extensions", withExt)
+	}
+	result, err := validator.ValidateDesign([]byte(withExt))
+	if err != nil {
+		t.Fatalf("ValidateDesign error: %v", err)
+	}
+	if !result.Valid {
+		t.Errorf("metadata does not validate: %+v", result.Errors)
+	}
 }
 
 // --- Task .15: Tests for command construction ---
@@ -398,6 +491,48 @@ func TestFormatDryRunOutput(t *testing.T) {
 	}
 }
 
+func TestFormatDryRunOutputFull(t *testing.T) {
+	cmds := []BdCommand{
+		{Args: []string{"create", "--title", "Task 1", "--type", "task"}, Type: "create-task"},
+		{Args: []string{"update", "<task-1-id>", "--design", `{"_template":{"version":"0.2.0"}}`}, Type: "update-design"},
+		{Args: []string{"comments", "add", "<task-1-id>", "report"}, Type: "add-comment"},
+	}
+
+	output := FormatDryRunOutputFull(cmds)
+	want := "  [DRY-RUN] bd update <task-1-id> --design\n      {\n        \"_template\": {\n          \"version\": \"0.2.0\"\n        }\n      }\n"
+	if !strings.Contains(output, want) {
+		t.Errorf("design update not pretty-printed, got:\n%s", output)
+	}
+	if !strings.Contains(output, "[DRY-RUN] bd comments add") {
+		t.Errorf("full output should list report comments, got:\n%s", output)
+	}
+	if strings.Contains(FormatDryRunOutput(cmds), "--design") {
+		t.Error("default dry-run output should still hide design updates")
+	}
+}
+
+func TestFormatDryRunJSON(t *testing.T) {
+	cmds := []BdCommand{
+		{Args: []string{"create", "--title", "Task 1", "--type", "task"}, Type: "create-task", TaskID: "task-1"},
+		{Type: "existing-task", TaskID: "task-2", ExistingID: "bd-7"},
+		{Args: []string{"dep", "add", "<task-1-id>", "<task-2-id>"}, Type: "dep-add", DepTaskID: "task-1", DepOnID: "task-2"},
+		{Args: []string{"update", "<task-1-id>", "--design", "{}"}, Type: "update-design", TaskID: "task-1"},
+	}
+
+	data, err := json.Marshal(FormatDryRunJSON(cmds))
+	if err != nil {
+		t.Fatalf("marshaling: %v", err)
+	}
+	want := `{"commands":[` +
+		`{"type":"create-task","task_id":"task-1","args":["create","--title","Task 1","--type","task"]},` +
+		`{"type":"existing-task","task_id":"task-2","existing_id":"bd-7"},` +
+		`{"type":"dep-add","task_id":"task-1","args":["dep","add","\u003ctask-1-id\u003e","\u003ctask-2-id\u003e"],"depends_on":"task-2"},` +
+		`{"type":"update-design","task_id":"task-1","args":["update","\u003ctask-1-id\u003e","--design","{}"]}]}`
+	if string(data) != want {
+		t.Errorf("FormatDryRunJSON =\n%s\nwant\n%s", data, want)
+	}
+}
+
 func TestFormatTextOutput(t *testing.T) {
 	result := &CreationResult{
 		EpicID:     "bd-abc",
@@ -445,3 +580,905 @@ func TestFormatJSONOutput(t *testing.T) {
 		t.Errorf("TotalCreated = %d, want 3", out.TotalCreated)
 	}
 }
+
+func TestTaskOrder(t *testing.T) {
+	graph := &validator.TaskGraph{
+		Version: "0.1.0",
+		Tasks: []validator.TaskNode{
+			{TaskID: "zeta", TaskName: "Zeta", Goal: "Do Z.", Acceptance: []string{"Z is done"}},
+			{TaskID: "alpha", TaskName: "Alpha", Goal: "Do A.", Acceptance: []string{"A is done"}, DependsOn: json.RawMessage(`["zeta"]`)},
+			{TaskID: "mid", TaskName: "Mid", Goal: "Do M.", Acceptance: []string{"M is done"}, DependsOn: json.RawMessage(`["alpha"]`)},
+		},
+	}
+	cmds, err := (&Creator{}).BuildGraphCommands(graph)
+	if err != nil {
+		t.Fatalf("BuildGraphCommands error: %v", err)
+	}
+	result, err := ExecuteWith(&fakeBackend{}, cmds)
+	if err != nil {
+		t.Fatalf("ExecuteWith error: %v", err)
+	}
+	want := []string{"zeta", "alpha", "mid"}
+	if got := FormatJSONOutput(result).TaskOrder; !slices.Equal(got, want) {
+		t.Errorf("TaskOrder = %v, want %v", got, want)
+	}
+	out := FormatTextOutput(result)
+	if z, a, m := strings.Index(out, "(zeta)"), strings.Index(out, "(alpha)"), strings.Index(out, "(mid)"); z < 0 || z > a || a > m {
+		t.Errorf("task lines not in creation order:\n%s", out)
+	}
+
+	// A result built without TaskOrder still lists every task, sorted.
+	partial := &CreationResult{TaskIDs: map[string]string{"b": "bd-2", "a": "bd-1", "c": "bd-3"}, TaskOrder: []string{"c"}}
+	if got := orderedTaskIDs(partial); !slices.Equal(got, []string{"c", "a", "b"}) {
+		t.Errorf("orderedTaskIDs = %v, want [c a b]", got)
+	}
+}
+
+// fakeBackend records commands and queries and hands out sequential issue
+// IDs. Queries are answered with output, or an empty list.
+type fakeBackend struct {
+	calls   [][]string
+	queries [][]string
+	output  string
+}
+
+func (f *fakeBackend) Check() error { return nil }
+
+func (f *fakeBackend) Output(args []string) (string, error) {
+	f.queries = append(f.queries, args)
+	if f.output == "" {
+		return "[]", nil
+	}
+	return f.output, nil
+}
+
+func (f *fakeBackend) Run(args []string) (string, error) {
+	f.calls = append(f.calls, args)
+	if args[0] == "create" {
+		return "bd-" + string(rune('0'+len(f.calls))), nil
+	}
+	return "", nil
+}
+
+func TestExecuteWithBackend(t *testing.T) {
+	graph := &validator.TaskGraph{
+		Version: "0.1.0",
+		Tasks: []validator.TaskNode{
+			{TaskID: "task-a", TaskName: "Task A", Goal: "Do A.", Acceptance: []string{"A is done"}},
+			{TaskID: "task-b", TaskName: "Task B", Goal: "Do B.", Acceptance: []string{"B is done"}, DependsOn: json.RawMessage(`["task-a"]`)},
+		},
+	}
+	cmds, err := (&Creator{Filename: "test.json"}).BuildGraphCommands(graph)
+	if err != nil {
+		t.Fatalf("BuildGraphCommands error: %v", err)
+	}
+
+	backend := &fakeBackend{}
+	result, err := ExecuteWith(backend, cmds)
+	if err != nil {
+		t.Fatalf("ExecuteWith error: %v", err)
+	}
+	if len(backend.calls) != len(cmds) {
+		t.Errorf("backend ran %d commands, want %d", len(backend.calls), len(cmds))
+	}
+	if result.Created != 3 || result.Deps != 1 {
+		t.Errorf("Created=%d Deps=%d, want 3 and 1", result.Created, result.Deps)
+	}
+	for _, call := range backend.calls {
+		for _, a := range call {
+			if strings.Contains(a, "-id>") {
+				t.Errorf("placeholder not replaced in %v", call)
+			}
+		}
+	}
+}
+
+func TestExecuteObserved(t *testing.T) {
+	graph := &validator.TaskGraph{
+		Version: "0.1.0",
+		Tasks: []validator.TaskNode{
+			{TaskID: "task-a", TaskName: "Task A", Goal: "Do A.", Acceptance: []string{"A is done"}},
+			{TaskID: "task-b", TaskName: "Task B", Goal: "Do B.", Acceptance: []string{"B is done"}, DependsOn: json.RawMessage(`["task-a"]`)},
+		},
+	}
+	cmds, err := (&Creator{}).BuildGraphCommands(graph)
+	if err != nil {
+		t.Fatalf("BuildGraphCommands error: %v", err)
+	}
+	var events []CommandEvent
+	if _, err := ExecuteObserved(&fakeBackend{}, cmds, func(ev CommandEvent) { events = append(events, ev) }); err != nil {
+		t.Fatalf("ExecuteObserved error: %v", err)
+	}
+	if len(events) != len(cmds) {
+		t.Fatalf("got %d events, want one per command (%d)", len(events), len(cmds))
+	}
+	if ev := events[1]; ev.Type != "create-task" || ev.TaskID != "task-a" || ev.IssueID != "bd-2" {
+		t.Errorf("second event = %+v, want create-task task-a bd-2", ev)
+	}
+	if ev := events[3]; ev.Type != "dep-add" || ev.TaskID != "task-b" || strings.Join(ev.Args, " ") != "dep add bd-3 bd-2" {
+		t.Errorf("dep event = %+v, want dep-add for task-b with resolved IDs", ev)
+	}
+}
+
+func TestParentEpic(t *testing.T) {
+	graph := &validator.TaskGraph{
+		Version: "0.1.0",
+		Tasks: []validator.TaskNode{
+			{TaskID: "task-a", TaskName: "Task A", Goal: "Do A.", Acceptance: []string{"A is done"}},
+			{TaskID: "task-b", TaskName: "Task B", Goal: "Do B.", Acceptance: []string{"B is done"}, DependsOn: json.RawMessage(`["task-a"]`)},
+		},
+	}
+	cmds, err := (&Creator{ParentEpic: "bd-123"}).BuildGraphCommands(graph)
+	if err != nil {
+		t.Fatalf("BuildGraphCommands error: %v", err)
+	}
+	if cmds[0].Type != "existing-epic" || cmds[0].ExistingID != "bd-123" {
+		t.Fatalf("first command = %+v, want existing-epic bd-123", cmds[0])
+	}
+	for _, cmd := range cmds {
+		if cmd.Type == "create-epic" {
+			t.Errorf("no epic should be created: %v", cmd.Args)
+		}
+	}
+	if out := FormatDryRunOutput(cmds); !strings.Contains(out, "existing epic bd-123") || !strings.Contains(out, "Would create 0 epic + 2 tasks") {
+		t.Errorf("dry run should name the existing epic:\n%s", out)
+	}
+
+	backend := &fakeBackend{}
+	result, err := ExecuteWith(backend, cmds)
+	if err != nil {
+		t.Fatalf("ExecuteWith error: %v", err)
+	}
+	if result.EpicID != "bd-123" || !result.EpicExisting || result.Created != 2 {
+		t.Errorf("EpicID=%q EpicExisting=%v Created=%d, want bd-123, true, 2", result.EpicID, result.EpicExisting, result.Created)
+	}
+	if got := argValue(backend.calls[0], "--parent"); got != "bd-123" {
+		t.Errorf("task parent = %q, want bd-123", got)
+	}
+	if out := FormatTextOutput(result); !strings.Contains(out, "Epic exists:  bd-123") || !strings.Contains(out, "0 epic + 2 tasks") {
+		t.Errorf("text output should report the existing epic:\n%s", out)
+	}
+
+	single, err := (&Creator{ParentEpic: "bd-123"}).BuildSingleTaskCommands(&graph.Tasks[0])
+	if err != nil {
+		t.Fatalf("BuildSingleTaskCommands error: %v", err)
+	}
+	if single[0].Type != "existing-epic" || argValue(single[1].Args, "--parent") != "<epic-id>" {
+		t.Errorf("single task should be parented under the epic: %+v", single[:2])
+	}
+}
+
+func TestSkipAndOnlyTasks(t *testing.T) {
+	graph := &validator.TaskGraph{
+		Version: "0.1.0",
+		Tasks: []validator.TaskNode{
+			{TaskID: "task-a", TaskName: "Task A", Goal: "Do A.", Acceptance: []string{"A is done"}},
+			{TaskID: "task-b", TaskName: "Task B", Goal: "Do B.", Acceptance: []string{"B is done"}, DependsOn: json.RawMessage(`["task-a"]`)},
+			{TaskID: "task-c", TaskName: "Task C", Goal: "Do C.", Acceptance: []string{"C is done"}, DependsOn: json.RawMessage(`["task-b"]`)},
+		},
+	}
+
+	cmds, err := (&Creator{SkipTasks: []string{"task-b"}}).BuildGraphCommands(graph)
+	if err != nil {
+		t.Fatalf("BuildGraphCommands error: %v", err)
+	}
+	result, err := ExecuteWith(&fakeBackend{}, cmds)
+	if err != nil {
+		t.Fatalf("ExecuteWith error: %v", err)
+	}
+	if result.Created != 3 || result.Deps != 0 {
+		t.Errorf("Created=%d Deps=%d, want epic + 2 tasks and no links", result.Created, result.Deps)
+	}
+	if _, ok := result.TaskIDs["task-b"]; ok {
+		t.Error("skipped task-b should not be created")
+	}
+	wantPruned := []PrunedDep{{TaskID: "task-b", DependsOn: "task-a"}, {TaskID: "task-c", DependsOn: "task-b"}}
+	if !slices.Equal(result.Excluded, []string{"task-b"}) || !slices.Equal(result.PrunedDeps, wantPruned) {
+		t.Errorf("Excluded=%v PrunedDeps=%v, want [task-b] and %v", result.Excluded, result.PrunedDeps, wantPruned)
+	}
+	if out := FormatDryRunOutput(cmds); !strings.Contains(out, "(leave out task-b)") || !strings.Contains(out, "(drop link task-c blocked-by task-b)") ||
+		!strings.Contains(out, "leave out 1 task(s) and drop 2 dependencies") {
+		t.Errorf("dry run should report the left-out task and dropped links:\n%s", out)
+	}
+
+	cmds, err = (&Creator{OnlyTasks: []string{"task-a", "task-b"}}).BuildGraphCommands(graph)
+	if err != nil {
+		t.Fatalf("BuildGraphCommands error: %v", err)
+	}
+	result, err = ExecuteWith(&fakeBackend{}, cmds)
+	if err != nil {
+		t.Fatalf("ExecuteWith error: %v", err)
+	}
+	if result.Deps != 1 || !slices.Equal(result.Excluded, []string{"task-c"}) || len(result.PrunedDeps) != 1 {
+		t.Errorf("only: Deps=%d Excluded=%v PrunedDeps=%v, want 1 link, [task-c], 1 dropped", result.Deps, result.Excluded, result.PrunedDeps)
+	}
+
+	if _, err := (&Creator{SkipTasks: []string{"task-z"}}).BuildGraphCommands(graph); err == nil || !strings.Contains(err.Error(), "task-z") {
+		t.Errorf("unknown task: error = %v, want one naming task-z", err)
+	}
+	if _, err := (&Creator{SkipTasks: []string{"task-a", "task-b", "task-c"}}).BuildGraphCommands(graph); err == nil || !strings.Contains(err.Error(), "nothing to create") {
+		t.Errorf("all skipped: error = %v, want 'nothing to create'", err)
+	}
+}
+
+func TestEpicEstimate(t *testing.T) {
+	graph := &validator.TaskGraph{
+		Version: "0.1.0",
+		Tasks: []validator.TaskNode{
+			{TaskID: "task-a", TaskName: "Task A", Goal: "Do A.", Acceptance: []string{"A is done"}, Estimate: "small"},
+			{TaskID: "task-b", TaskName: "Task B", Goal: "Do B.", Acceptance: []string{"B is done"}, Estimate: "medium", DependsOn: json.RawMessage(`["task-a"]`)},
+			{TaskID: "task-c", TaskName: "Task C", Goal: "Do C.", Acceptance: []string{"C is done"}, Estimate: "large"},
+			{TaskID: "task-d", TaskName: "Task D", Goal: "Do D.", Acceptance: []string{"D is done"}},
+		},
+	}
+	tests := []struct {
+		mode string
+		skip []string
+		want string
+	}{
+		{"", nil, ""},
+		{EpicEstimateNone, nil, ""},
+		{EpicEstimateSum, nil, "780"},
+		{EpicEstimateCriticalPath, nil, "480"},
+		{EpicEstimateCriticalPath, []string{"task-c"}, "300"},
+		{EpicEstimateSum, []string{"task-a", "task-b", "task-c"}, ""},
+	}
+	for _, tt := range tests {
+		cmds, err := (&Creator{EpicEstimate: tt.mode, SkipTasks: tt.skip}).BuildGraphCommands(graph)
+		if err != nil {
+			t.Fatalf("%s: BuildGraphCommands error: %v", tt.mode, err)
+		}
+		if got := argValue(cmds[0].Args, "--estimate"); got != tt.want {
+			t.Errorf("%q skipping %v: epic --estimate = %q, want %q", tt.mode, tt.skip, got, tt.want)
+		}
+	}
+
+	if _, err := (&Creator{EpicEstimate: "max"}).BuildGraphCommands(graph); err == nil {
+		t.Error("expected an error for an unknown epic estimate mode")
+	}
+}
+
+// showBackend answers 'bd show' with a canned reply.
+type showBackend struct {
+	fakeBackend
+	out string
+	err error
+}
+
+func (b *showBackend) Output(args []string) (string, error) { return b.out, b.err }
+
+func TestCheckParentEpic(t *testing.T) {
+	tests := []struct {
+		name    string
+		backend *showBackend
+		wantErr string
+	}{
+		{"open epic", &showBackend{out: `[{"id":"bd-123","issue_type":"epic","status":"open"}]`}, ""},
+		{"object reply", &showBackend{out: `{"id":"bd-123","issue_type":"epic","status":"in_progress"}`}, ""},
+		{"missing", &showBackend{err: errors.New("issue not found")}, "not found"},
+		{"empty list", &showBackend{out: `[]`}, "not found"},
+		{"closed", &showBackend{out: `[{"id":"bd-123","issue_type":"epic","status":"closed"}]`}, "is closed"},
+		{"not an epic", &showBackend{out: `[{"id":"bd-123","issue_type":"task","status":"open"}]`}, "not an epic"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckParentEpic(tt.backend, "bd-123")
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// depBackend answers 'bd show' for bd-x2 with one existing link, and fails
+// 'bd dep add' for bd-x3 as a bd version that rejects duplicates would.
+type depBackend struct {
+	fakeBackend
+	shown []string
+}
+
+func (b *depBackend) Output(args []string) (string, error) {
+	b.shown = append(b.shown, args[1])
+	if args[1] == "bd-x2" {
+		return `[{"id":"bd-x2","dependencies":[{"id":"bd-x1","dependency_type":"blocks"},{"id":"bd-x9","dependency_type":"parent-child"}]}]`, nil
+	}
+	return `[{"id":"` + args[1] + `"}]`, nil
+}
+
+func (b *depBackend) Run(args []string) (string, error) {
+	if args[0] == "dep" && args[2] == "bd-x3" {
+		return "", errors.New("dependency bd-x3 -> bd-x1 already exists")
+	}
+	return b.fakeBackend.Run(args)
+}
+
+func TestExistingDeps(t *testing.T) {
+	dep := func(task, on string) BdCommand {
+		return BdCommand{Type: "dep-add", Args: []string{"dep", "add", "<" + task + "-id>", "<" + on + "-id>"}, DepTaskID: task, DepOnID: on}
+	}
+	cmds := []BdCommand{
+		{Type: "existing-task", TaskID: "task-a", ExistingID: "bd-x1"},
+		{Type: "existing-task", TaskID: "task-b", ExistingID: "bd-x2"},
+		{Type: "existing-task", TaskID: "task-c", ExistingID: "bd-x3"},
+		{Type: "create-task", TaskID: "task-d", Args: []string{"create", "--title", "Task D", "--silent"}},
+		dep("task-b", "task-a"),
+		dep("task-c", "task-a"),
+		dep("task-b", "task-c"),
+		dep("task-d", "task-a"),
+	}
+	backend := &depBackend{}
+	result, err := ExecuteWith(backend, cmds)
+	if err != nil {
+		t.Fatalf("ExecuteWith error: %v", err)
+	}
+	if result.Deps != 2 || result.DepsExisting != 2 {
+		t.Errorf("Deps = %d, DepsExisting = %d, want 2 and 2", result.Deps, result.DepsExisting)
+	}
+	// bd-x2 is looked up once; the link from the new task needs no lookup.
+	if strings.Join(backend.shown, " ") != "bd-x2 bd-x3" {
+		t.Errorf("bd show ran for %v, want bd-x2 and bd-x3", backend.shown)
+	}
+	for _, call := range backend.calls {
+		if strings.Join(call, " ") == "dep add bd-x2 bd-x1" {
+			t.Error("existing link bd-x2 -> bd-x1 was added again")
+		}
+	}
+	out := FormatTextOutput(result)
+	if !strings.Contains(out, "bd-x2 blocked-by bd-x1, already linked") || !strings.Contains(out, "(2 already linked)") {
+		t.Errorf("text output does not show the existing links:\n%s", out)
+	}
+	if FormatJSONOutput(result).DepsExisting != 2 {
+		t.Error("JSON output does not count the existing links")
+	}
+}
+
+func TestCheckPlaceholders(t *testing.T) {
+	graph := &validator.TaskGraph{
+		Version: "0.1.0",
+		Tasks: []validator.TaskNode{
+			{TaskID: "task-a", TaskName: "Task A", Goal: "Do A.", Acceptance: []string{"A is done"}},
+			{TaskID: "task-b", TaskName: "Task B", Goal: "Mention <task-z-id> in passing.", Acceptance: []string{"B is done"}, DependsOn: json.RawMessage(`["task-a"]`)},
+		},
+	}
+	cmds, err := (&Creator{}).BuildGraphCommands(graph)
+	if err != nil {
+		t.Fatalf("BuildGraphCommands error: %v", err)
+	}
+	if err := CheckPlaceholders(cmds); err != nil {
+		t.Fatalf("built commands: unexpected error: %v", err)
+	}
+
+	// Drop the create for task-a: its dep-add and design update are left
+	// with <task-a-id> unbound, and nothing may run.
+	var broken []BdCommand
+	for _, cmd := range cmds {
+		if cmd.Type == "create-task" && cmd.TaskID == "task-a" {
+			continue
+		}
+		broken = append(broken, cmd)
+	}
+	err = CheckPlaceholders(broken)
+	if err == nil {
+		t.Fatal("expected an error for the unbound <task-a-id>")
+	}
+	for _, want := range []string{"<task-a-id>", "dep-add (task-b)", "update-design (task-a)"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %s", err, want)
+		}
+	}
+	if strings.Contains(err.Error(), "task-z") {
+		t.Errorf("error %q reports a placeholder mentioned in description text", err)
+	}
+	backend := &fakeBackend{}
+	if _, err := ExecuteWith(backend, broken); err == nil || len(backend.calls) > 0 {
+		t.Errorf("ExecuteWith ran %d commands, error %v; want none run and an error", len(backend.calls), err)
+	}
+}
+
+// fileFlagBackend accepts the file flags listed in flags, and records the
+// content of the file passed to each command.
+type fileFlagBackend struct {
+	fakeBackend
+	flags []string
+	files []string
+}
+
+func (b *fileFlagBackend) supportsFlag(subcommand, flag string) bool {
+	return slices.Contains(b.flags, flag)
+}
+
+func (b *fileFlagBackend) Run(args []string) (string, error) {
+	for i, a := range args {
+		if strings.HasSuffix(a, "-file") || a == "--file" {
+			data, err := os.ReadFile(args[i+1])
+			if err != nil {
+				return "", err
+			}
+			b.files = append(b.files, string(data))
+		}
+	}
+	return b.fakeBackend.Run(args)
+}
+
+func TestLongArguments(t *testing.T) {
+	defer func(n int) { maxArgLen = n }(maxArgLen)
+	maxArgLen = 100
+
+	long := strings.Repeat("é", 80)
+	cmds := []BdCommand{
+		{Type: "create-task", TaskID: "task-a", Args: []string{"create", "--title", "A", "--description", long, "--acceptance", long, "--silent"}},
+		{Type: "add-comment", TaskID: "task-a", Args: []string{"comments", "add", "<task-a-id>", long}},
+	}
+	backend := &fileFlagBackend{flags: []string{"--body-file"}}
+	result, err := ExecuteWith(backend, cmds)
+	if err != nil {
+		t.Fatalf("ExecuteWith error: %v", err)
+	}
+
+	create := backend.calls[0]
+	if argValue(create, "--description") != "" || len(backend.files) != 1 || backend.files[0] != long {
+		t.Errorf("description was not passed through --body-file: %q", create)
+	}
+	acceptance := argValue(create, "--acceptance")
+	if len(acceptance) > maxArgLen || !strings.HasSuffix(acceptance, truncatedMarker) || !utf8.ValidString(acceptance) {
+		t.Errorf("acceptance not cut to fit: %d bytes, %q", len(acceptance), acceptance)
+	}
+	// No --file support: the comment text is cut too.
+	if comment := backend.calls[1][3]; len(comment) > maxArgLen {
+		t.Errorf("comment text is %d bytes, want at most %d", len(comment), maxArgLen)
+	}
+	if len(result.Warnings) != 2 || !strings.HasPrefix(result.Warnings[0], "task-a: bd create --acceptance cut") {
+		t.Errorf("Warnings = %q, want the acceptance and comment truncations", result.Warnings)
+	}
+	if !strings.Contains(FormatTextOutput(result), "Warning:      task-a: bd comments add text cut") {
+		t.Errorf("text output does not show the warnings:\n%s", FormatTextOutput(result))
+	}
+	if matches, _ := filepath.Glob(filepath.Join(os.TempDir(), "taskval-arg-*")); len(matches) > 0 {
+		t.Errorf("temp files left behind: %v", matches)
+	}
+}
+
+// failingBackend fails every 'bd dep add' with a canned stderr.
+type failingBackend struct {
+	fakeBackend
+}
+
+func (b *failingBackend) Run(args []string) (string, error) {
+	if args[0] == "dep" {
+		return "", errors.New("Error: issue bd-9 not found")
+	}
+	return b.fakeBackend.Run(args)
+}
+
+func TestHelpListsFlag(t *testing.T) {
+	help := `Create a new issue
+
+Flags:
+      --body-file-format string   Format of the file read by the body flag
+  -d, --description string        Issue description
+  -f, --file string               Create issues from a markdown file
+      --json                      Output JSON; see also --design-file
+`
+	for flag, want := range map[string]bool{
+		"--description": true,
+		"--file":        true,
+		"--json":        true,
+		"--body-file":   false, // only a prefix of --body-file-format
+		"--design-file": false, // only mentioned in a description
+	} {
+		if got := helpListsFlag(help, flag); got != want {
+			t.Errorf("helpListsFlag(%s) = %v, want %v", flag, got, want)
+		}
+	}
+
+	// The help of a bd create without --json can still contain the string,
+	// in a longer flag or a description.
+	older := `Flags:
+      --json-schema string   Validate against a schema; use 'bd list --json' to inspect
+`
+	if helpListsFlag(older, "--json") {
+		t.Error("helpListsFlag(--json) matched a mention and a longer flag")
+	}
+}
+
+func TestFormatErrorJSON(t *testing.T) {
+	graph := &validator.TaskGraph{
+		Version: "0.1.0",
+		Tasks: []validator.TaskNode{
+			{TaskID: "task-a", TaskName: "Task A", Goal: "Do A.", Acceptance: []string{"A is done"}},
+			{TaskID: "task-b", TaskName: "Task B", Goal: "Do B.", Acceptance: []string{"B is done"}, DependsOn: json.RawMessage(`["task-a"]`)},
+		},
+	}
+	cmds, err := (&Creator{}).BuildGraphCommands(graph)
+	if err != nil {
+		t.Fatalf("BuildGraphCommands error: %v", err)
+	}
+	result, err := ExecuteWith(&failingBackend{}, cmds)
+	var cmdErr *CommandError
+	if !errors.As(err, &cmdErr) {
+		t.Fatalf("error = %v, want a *CommandError", err)
+	}
+	if cmdErr.Created != 3 || result.Created != 3 {
+		t.Errorf("Created = %d (result %d), want the epic and both tasks", cmdErr.Created, result.Created)
+	}
+	if !strings.Contains(err.Error(), "bd command failed: bd dep add bd-3 bd-2") {
+		t.Errorf("error text = %q", err)
+	}
+
+	got := FormatErrorJSON(err)
+	want := ErrorJSON{Message: err.Error(), Type: "dep-add", TaskID: "task-b", Command: []string{"dep", "add", "bd-3", "bd-2"}, Stderr: "Error: issue bd-9 not found"}
+	if got.Message != want.Message || got.Type != want.Type || got.TaskID != want.TaskID || !slices.Equal(got.Command, want.Command) || got.Stderr != want.Stderr {
+		t.Errorf("FormatErrorJSON = %+v, want %+v", got, want)
+	}
+
+	// A failure before any command ran has only the message.
+	if got := FormatErrorJSON(errors.New("unresolved placeholder IDs")); got.Type != "" || got.Command != nil {
+		t.Errorf("FormatErrorJSON = %+v, want just the message", got)
+	}
+}
+
+func TestParseCreateJSON(t *testing.T) {
+	tests := []struct {
+		name, out, want string
+		wantErr         bool
+	}{
+		{"object", `{"id": "bd-12", "title": "Task A"}`, "bd-12", false},
+		{"list", `[{"id": "bd-7"}]`, "bd-7", false},
+		{"leading notice", "Note: a newer bd is available\n{\"id\": \"bd-3\"}\n", "bd-3", false},
+		{"no json", "bd-3\n", "", true},
+		{"no id", `{"title": "Task A"}`, "", true},
+	}
+	for _, tc := range tests {
+		got, err := parseCreateJSON(tc.out)
+		if (err != nil) != tc.wantErr || got != tc.want {
+			t.Errorf("%s: got (%q, %v), want %q (error %v)", tc.name, got, err, tc.want, tc.wantErr)
+		}
+	}
+}
+
+func TestBdVersionCompatibility(t *testing.T) {
+	v, ok := parseBdVersion("bd version 0.21.4 (dev)\n")
+	if !ok || v != (bdVersion{0, 21, 4}) {
+		t.Fatalf("parseBdVersion = %v, %v", v, ok)
+	}
+	if _, ok := parseBdVersion("bd version dev"); ok {
+		t.Error("expected no version in 'bd version dev'")
+	}
+
+	if err := checkBdVersion(bdVersion{0, 8, 9}); err == nil || !strings.Contains(err.Error(), MinBdVersion) {
+		t.Errorf("expected minimum-version error, got %v", err)
+	}
+	if err := checkBdVersion(v); err != nil {
+		t.Errorf("unexpected error for %s: %v", v, err)
+	}
+
+	args := []string{"create", "--title", "T", "--estimate", "60", "--silent"}
+	if got := strings.Join(compatArgs(args, bdVersion{0, 10, 0}), " "); got != "create --title T --silent" {
+		t.Errorf("old bd: got %q", got)
+	}
+	if got := compatArgs(args, v); len(got) != len(args) {
+		t.Errorf("new bd: flags dropped: %v", got)
+	}
+}
+
+func TestResolveDuplicates(t *testing.T) {
+	graph := &validator.TaskGraph{
+		Version: "0.1.0",
+		Tasks: []validator.TaskNode{
+			{TaskID: "task-a", TaskName: "Task A", Goal: "Do A.", Acceptance: []string{"A is done"}},
+			{TaskID: "task-b", TaskName: "Task B", Goal: "Do B.", Acceptance: []string{"B is done"}, DependsOn: json.RawMessage(`["task-a"]`)},
+		},
+	}
+	cmds, err := (&Creator{Filename: "test.json"}).BuildGraphCommands(graph)
+	if err != nil {
+		t.Fatalf("BuildGraphCommands error: %v", err)
+	}
+	// bd answers with every status; only the closed issue is left out. Had
+	// it been kept, task-b would match it by task_id instead of bd-41.
+	lister := &fakeBackend{output: `[
+		{"id": "bd-39", "title": "Old B", "status": "closed", "design": "{\"_template\":{\"task_id\":\"task-b\"}}"},
+		{"id": "bd-40", "title": "Renamed", "status": "open", "design": "{\"_template\":{\"task_id\":\"task-a\"}}"},
+		{"id": "bd-41", "title": "Task B", "status": "in_progress"},
+		{"id": "bd-42", "title": "Task C", "status": "blocked"}
+	]`}
+	existing, err := ListOpenIssues(lister)
+	if err != nil {
+		t.Fatalf("ListOpenIssues error: %v", err)
+	}
+	if got := strings.Join(lister.queries[0], " "); got != "list --label taskval-managed --limit 0 --json" {
+		t.Errorf("ListOpenIssues ran 'bd %s', want every taskval-managed issue, of any status", got)
+	}
+	var ids []string
+	for _, is := range existing {
+		ids = append(ids, is.ID)
+	}
+	if !slices.Equal(ids, []string{"bd-40", "bd-41", "bd-42"}) {
+		t.Errorf("ListOpenIssues = %v, want the open, in-progress, and blocked issues", ids)
+	}
+
+	dups := FindDuplicates(cmds, existing)
+	if len(dups) != 2 || dups[0].MatchedBy != "task_id" || dups[1].MatchedBy != "title" {
+		t.Fatalf("FindDuplicates = %+v", dups)
+	}
+
+	if _, err := ResolveDuplicates(cmds, dups, OnDuplicateError); err == nil || !strings.Contains(err.Error(), "bd-40") {
+		t.Errorf("error policy: got %v", err)
+	}
+
+	skipped, err := ResolveDuplicates(cmds, dups, OnDuplicateSkip)
+	if err != nil {
+		t.Fatalf("skip policy: %v", err)
+	}
+	backend := &fakeBackend{}
+	result, err := ExecuteWith(backend, skipped)
+	if err != nil {
+		t.Fatalf("ExecuteWith error: %v", err)
+	}
+	if result.Created != 1 || len(result.Skipped) != 2 {
+		t.Errorf("skip: Created=%d Skipped=%v, want epic only and 2 skipped", result.Created, result.Skipped)
+	}
+	if last := backend.calls[len(backend.calls)-1]; strings.Join(last, " ") != "dep add bd-41 bd-40" {
+		t.Errorf("dependency should link the existing issues, got %v", last)
+	}
+
+	updated, err := ResolveDuplicates(cmds, dups, OnDuplicateUpdate)
+	if err != nil {
+		t.Fatalf("update policy: %v", err)
+	}
+	for _, cmd := range updated {
+		if cmd.Type == "update-task" {
+			args := strings.Join(cmd.Args, " ")
+			if !strings.HasPrefix(args, "update "+cmd.ExistingID) || strings.Contains(args, "--parent") || strings.Contains(args, "--silent") {
+				t.Errorf("update-task args = %q", args)
+			}
+		}
+	}
+}
+
+func TestAttachReport(t *testing.T) {
+	graph := &validator.TaskGraph{
+		Version: "0.1.0",
+		Tasks: []validator.TaskNode{
+			{TaskID: "task-a", TaskName: "Task A", Goal: "Do A.", Acceptance: []string{"A is done"}},
+			{TaskID: "task-b", TaskName: "Task B", Goal: "Do B.", Acceptance: []string{"B is done"}},
+		},
+	}
+	report := &validator.ValidationResult{Valid: true}
+	report.AddError(validator.ValidationError{
+		Rule: "V7", Severity: validator.SeverityWarning, Path: "tasks[1].acceptance[0]",
+		Message: "Acceptance criterion is vague.", Suggestion: "State an observable outcome.",
+	})
+
+	cmds, err := (&Creator{Filename: "test.json", Report: report}).BuildGraphCommands(graph)
+	if err != nil {
+		t.Fatalf("BuildGraphCommands error: %v", err)
+	}
+	var comments []BdCommand
+	for _, cmd := range cmds {
+		if cmd.Type == "add-comment" {
+			comments = append(comments, cmd)
+		}
+	}
+	if len(comments) != 1 || comments[0].TaskID != "task-b" {
+		t.Fatalf("expected one comment for task-b, got %+v", comments)
+	}
+	body := comments[0].Args[3]
+	if !strings.Contains(body, "WARNING V7") || !strings.Contains(body, "Fix: State an observable outcome.") {
+		t.Errorf("comment body missing finding: %q", body)
+	}
+}
+
+func TestDescriptionTemplate(t *testing.T) {
+	task := &validator.TaskNode{
+		TaskID:      "task-a",
+		Goal:        "G.",
+		Inputs:      []validator.InputSpec{{Name: "a", Type: "int", Constraints: "c1", Source: "s1"}},
+		Outputs:     []validator.OutputSpec{{Name: "b", Type: "int", Constraints: "c2", Destination: "d2"}},
+		Constraints: json.RawMessage(`["C"]`),
+		NonGoals:    []string{"N"},
+		ErrorCases:  []validator.ErrorSpec{{Condition: "x", Behavior: "y", Output: "z"}},
+	}
+
+	// The default template reproduces the built-in layout exactly.
+	want := "G.\n\n## Inputs\n- **a** (`int`): c1 -- Source: s1\n" +
+		"\n## Outputs\n- **b** (`int`): c2 -- Dest: d2\n" +
+		"\n## Constraints\n- C\n" +
+		"\n## Non-Goals\n- N\n" +
+		"\n## Error Cases\n- **x**: y -> z\n"
+	if got := ComposeDescription(task); got != want {
+		t.Errorf("default layout:\ngot  %q\nwant %q", got, want)
+	}
+
+	path := filepath.Join(t.TempDir(), "short.tmpl")
+	if err := os.WriteFile(path, []byte("{{.Goal}} [{{join (stringList .Constraints) \", \"}}]"), 0o644); err == nil {
+		if _, err := LoadDescriptionTemplate(path); err == nil {
+			t.Error("expected parse error for an undefined function")
+		}
+	}
+	if err := os.WriteFile(path, []byte("{{.Goal}}{{range stringList .Constraints}} [{{.}}]{{end}}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := LoadDescriptionTemplate(path)
+	if err != nil {
+		t.Fatalf("LoadDescriptionTemplate: %v", err)
+	}
+	cmds, err := (&Creator{DescriptionTemplate: tmpl}).BuildSingleTaskCommands(task)
+	if err != nil {
+		t.Fatalf("BuildSingleTaskCommands: %v", err)
+	}
+	if got := argValue(cmds[0].Args, "--description"); got != "G. [C]" {
+		t.Errorf("custom template description = %q", got)
+	}
+}
+
+func TestAcceptanceStyles(t *testing.T) {
+	criteria := []string{"A is done", "B is done"}
+	checkbox, err := AcceptanceBullet(AcceptanceCheckboxes)
+	if err != nil {
+		t.Fatalf("AcceptanceBullet: %v", err)
+	}
+	if got := FormatAcceptanceWith(criteria, checkbox); got != "- [ ] A is done\n- [ ] B is done" {
+		t.Errorf("checkboxes = %q", got)
+	}
+	if _, err := AcceptanceBullet("stars"); err == nil {
+		t.Error("expected error for unknown style")
+	}
+
+	task := &validator.TaskNode{TaskID: "t", TaskName: "T", Goal: "G.", Acceptance: criteria}
+	cmds, err := (&Creator{AcceptanceBullet: checkbox}).BuildSingleTaskCommands(task)
+	if err != nil {
+		t.Fatalf("BuildSingleTaskCommands: %v", err)
+	}
+	if got := argValue(cmds[0].Args, "--acceptance"); !strings.HasPrefix(got, "- [ ] ") {
+		t.Errorf("--acceptance = %q, want checkboxes", got)
+	}
+}
+
+func TestEstimateUnits(t *testing.T) {
+	tests := []struct {
+		unit    string
+		minutes int
+		want    string
+	}{
+		{"minutes", 240, "240m"},
+		{"hours", 90, "1.5h"},
+		{"pomodoros", 60, "2.4 pomodoros"},
+		{"points", 60, "1 point"},
+		{"points:120", 480, "4 points"},
+	}
+	for _, tc := range tests {
+		u, err := ParseEstimateUnit(tc.unit)
+		if err != nil {
+			t.Fatalf("ParseEstimateUnit(%q): %v", tc.unit, err)
+		}
+		if got := u.Format(tc.minutes); got != tc.want {
+			t.Errorf("%s: Format(%d) = %q, want %q", tc.unit, tc.minutes, got, tc.want)
+		}
+	}
+	for _, bad := range []string{"days", "hours:2", "points:0", "points:x"} {
+		if _, err := ParseEstimateUnit(bad); err == nil {
+			t.Errorf("ParseEstimateUnit(%q): expected error", bad)
+		}
+	}
+}
+
+func TestTaskLabels(t *testing.T) {
+	task := &validator.TaskNode{TaskID: "t", TaskName: "Route me", Goal: "Done.", Labels: []string{"backend", "security"}}
+	cmds, err := (&Creator{}).BuildSingleTaskCommands(task)
+	if err != nil {
+		t.Fatalf("BuildSingleTaskCommands error: %v", err)
+	}
+	if got := argValue(cmds[0].Args, "--labels"); got != "taskval-managed,backend,security" {
+		t.Errorf("--labels = %q, want taskval-managed,backend,security", got)
+	}
+}
+
+func TestMilestoneLabels(t *testing.T) {
+	graph := &validator.TaskGraph{
+		Version: "0.1.0",
+		Tasks: []validator.TaskNode{
+			{TaskID: "task-a", TaskName: "Task A", Goal: "Do A.", Acceptance: []string{"A is done"}, Labels: []string{"backend"}},
+			{TaskID: "task-b", TaskName: "Task B", Goal: "Do B.", Acceptance: []string{"B is done"}},
+			{TaskID: "task-c", TaskName: "Task C", Goal: "Do C.", Acceptance: []string{"C is done"}},
+		},
+		Milestones: []validator.Milestone{
+			{Name: "Phase 1: Core, API", TaskIDs: []string{"task-a", "task-b"}},
+			{Name: "Phase 2", TaskIDs: []string{"task-b"}},
+		},
+	}
+	want := map[string]string{
+		"task-a": "taskval-managed,backend,milestone:phase-1-core-api",
+		"task-b": "taskval-managed,milestone:phase-1-core-api,milestone:phase-2",
+		"task-c": "taskval-managed",
+	}
+
+	cmds, err := (&Creator{MilestoneLabelPrefix: DefaultMilestoneLabelPrefix}).BuildGraphCommands(graph)
+	if err != nil {
+		t.Fatalf("BuildGraphCommands error: %v", err)
+	}
+	for _, cmd := range cmds {
+		if cmd.Type == "create-task" {
+			if got := argValue(cmd.Args, "--labels"); got != want[cmd.TaskID] {
+				t.Errorf("%s: --labels = %q, want %q", cmd.TaskID, got, want[cmd.TaskID])
+			}
+		}
+	}
+
+	cmds, err = (&Creator{}).BuildGraphCommands(graph)
+	if err != nil {
+		t.Fatalf("BuildGraphCommands error: %v", err)
+	}
+	for _, cmd := range cmds {
+		if cmd.Type == "create-task" && strings.Contains(argValue(cmd.Args, "--labels"), "milestone") {
+			t.Errorf("%s: no milestone labels without a prefix, got %v", cmd.TaskID, cmd.Args)
+		}
+	}
+}
+
+func TestNumericLevelsPassThrough(t *testing.T) {
+	if got := MapPriority("4"); got != 4 {
+		t.Errorf("MapPriority(4) = %d", got)
+	}
+	if got := MapEstimate("90"); got != 90 {
+		t.Errorf("MapEstimate(90) = %d", got)
+	}
+}
+
+func TestNotify(t *testing.T) {
+	var got Notification
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("got %s with Content-Type %q, want a JSON POST", r.Method, r.Header.Get("Content-Type"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decoding body: %v", err)
+		}
+		if r.URL.Path == "/down" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	partial := &CreationResult{EpicID: "proj-e1", EpicTitle: "Plan", TaskIDs: map[string]string{"a": "proj-e1.1"}, Created: 2}
+	n := NewNotification("plan.json", partial, errors.New("bd create failed"))
+	if err := Notify(context.Background(), srv.URL, n); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if got.Status != "failed" || got.Error != "bd create failed" || got.Source != "plan.json" {
+		t.Errorf("got %+v, want a failed notification for plan.json", got)
+	}
+	if got.BeadsJSON == nil || got.EpicID != "proj-e1" || got.Tasks["a"] != "proj-e1.1" || got.TotalCreated != 2 {
+		t.Errorf("got %+v, want the partial creation result", got.BeadsJSON)
+	}
+
+	// A run that failed before creating anything still has a task map.
+	if n := NewNotification("-", nil, errors.New("boom")); n.Tasks == nil || n.Status != "failed" {
+		t.Errorf("NewNotification(nil) = %+v, want failed with empty tasks", n)
+	}
+	if n := NewNotification("-", partial, nil); n.Status != "created" {
+		t.Errorf("Status = %q, want created", n.Status)
+	}
+
+	if err := Notify(context.Background(), srv.URL+"/down", n); err == nil || !strings.Contains(err.Error(), "503") {
+		t.Errorf("Notify to a failing webhook = %v, want the 503 status", err)
+	}
+}
+
+func TestCheckWebhookURL(t *testing.T) {
+	for _, u := range []string{"https://hooks.example.com/x", "http://localhost:8080/hook"} {
+		if err := CheckWebhookURL(u); err != nil {
+			t.Errorf("CheckWebhookURL(%q) = %v, want nil", u, err)
+		}
+	}
+	for _, u := range []string{"hooks.example.com/x", "ftp://example.com", "https://", "://x"} {
+		if err := CheckWebhookURL(u); err == nil {
+			t.Errorf("CheckWebhookURL(%q) = nil, want an error", u)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package beads
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Duplicate policies accepted by ResolveDuplicates.
+const (
+	OnDuplicateSkip   = "skip"
+	OnDuplicateUpdate = "update"
+	OnDuplicateError  = "error"
+)
+
+// Issue is the subset of a bd issue used for duplicate detection.
+type Issue struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Status string `json:"status"`
+	Design string `json:"design"`
+}
+
+// Duplicate pairs a task about to be created with an open issue that
+// already represents it.
+type Duplicate struct {
+	TaskID  string
+	IssueID string
+
+	// MatchedBy is "task_id" when the issue's design metadata names the
+	// task, or "title" when only the title matched.
+	MatchedBy string
+}
+
+// ListOpenIssues returns the taskval-managed issues known to bd that are
+// not closed: open, in progress, or blocked. The listing is unlimited,
+// since bd otherwise returns only its default page and duplicates beyond
+// it would go unnoticed.
+func ListOpenIssues(backend Backend) ([]Issue, error) {
+	out, err := backend.Output([]string{"list", "--label", "taskval-managed", "--limit", "0", "--json"})
+	if err != nil {
+		return nil, fmt.Errorf("listing existing issues: %w", err)
+	}
+	start := strings.IndexByte(out, '[')
+	if start < 0 {
+		return nil, nil // bd prints nothing (or a notice) when there are no issues.
+	}
+	var issues []Issue
+	if err := json.NewDecoder(strings.NewReader(out[start:])).Decode(&issues); err != nil {
+		return nil, fmt.Errorf("parsing bd list --json output: %w", err)
+	}
+	open := issues[:0]
+	for _, is := range issues {
+		if is.Status != "closed" {
+			open = append(open, is)
+		}
+	}
+	return open, nil
+}
+
+// FindDuplicates matches each create-task command against existing issues,
+// first by the _template.task_id in the issue's design metadata, then by
+// exact title.
+func FindDuplicates(cmds []BdCommand, existing []Issue) []Duplicate {
+	byTaskID := make(map[string]string)
+	byTitle := make(map[string]string)
+	for _, is := range existing {
+		var meta templateMetadata
+		if json.Unmarshal([]byte(is.Design), &meta) == nil && meta.Template.TaskID != "" {
+			if _, seen := byTaskID[meta.Template.TaskID]; !seen {
+				byTaskID[meta.Template.TaskID] = is.ID
+			}
+		}
+		if _, seen := byTitle[is.Title]; !seen {
+			byTitle[is.Title] = is.ID
+		}
+	}
+
+	var dups []Duplicate
+	for _, cmd := range cmds {
+		if cmd.Type != "create-task" {
+			continue
+		}
+		if id, ok := byTaskID[cmd.TaskID]; ok {
+			dups = append(dups, Duplicate{TaskID: cmd.TaskID, IssueID: id, MatchedBy: "task_id"})
+		} else if id, ok := byTitle[argValue(cmd.Args, "--title")]; ok {
+			dups = append(dups, Duplicate{TaskID: cmd.TaskID, IssueID: id, MatchedBy: "title"})
+		}
+	}
+	return dups
+}
+
+// ResolveDuplicates rewrites cmds so duplicated tasks are handled per
+// policy. "skip" reuses the existing issue untouched, "update" rewrites
+// its fields and design metadata, and "error" fails listing every
+// duplicate. Dependency links are kept so the graph is still wired up.
+func ResolveDuplicates(cmds []BdCommand, dups []Duplicate, policy string) ([]BdCommand, error) {
+	if len(dups) == 0 {
+		return cmds, nil
+	}
+	existing := make(map[string]string, len(dups))
+	for _, d := range dups {
+		existing[d.TaskID] = d.IssueID
+	}
+
+	switch policy {
+	case OnDuplicateError:
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "%d task(s) already have open issues:", len(dups))
+		for _, d := range dups {
+			fmt.Fprintf(&sb, "\n  %s -> %s (matched by %s)", d.TaskID, d.IssueID, d.MatchedBy)
+		}
+		sb.WriteString("\nUse --on-duplicate=skip or --on-duplicate=update to reuse them")
+		return nil, fmt.Errorf("%s", sb.String())
+	case OnDuplicateSkip, OnDuplicateUpdate:
+	default:
+		return nil, fmt.Errorf("unknown duplicate policy '%s'. Must be 'skip', 'update', or 'error'", policy)
+	}
+
+	out := make([]BdCommand, 0, len(cmds))
+	for _, cmd := range cmds {
+		id, dup := existing[cmd.TaskID]
+		if !dup {
+			out = append(out, cmd)
+			continue
+		}
+		switch {
+		case cmd.Type == "create-task" && policy == OnDuplicateSkip:
+			out = append(out, BdCommand{TaskID: cmd.TaskID, Type: "existing-task", ExistingID: id})
+		case cmd.Type == "create-task":
+			out = append(out, BdCommand{Args: updateArgs(cmd.Args, id), TaskID: cmd.TaskID, Type: "update-task", ExistingID: id})
+		case cmd.Type == "update-design" && policy == OnDuplicateSkip:
+			// Leave the existing issue's metadata as it is.
+		default:
+			out = append(out, cmd)
+		}
+	}
+	return out, nil
+}
+
+// updateArgs turns bd create arguments into bd update arguments for id,
+// dropping flags that only apply when an issue is created.
+func updateArgs(create []string, id string) []string {
+	args := []string{"update", id}
+	for i := 1; i < len(create); i++ {
+		switch create[i] {
+		case "--type", "--parent", "--labels":
+			i++
+		case "--silent", "--json":
+		default:
+			args = append(args, create[i])
+		}
+	}
+	return args
+}
+
+// argValue returns the value following flag in args, or "".
+func argValue(args []string, flag string) string {
+	for i, a := range args {
+		if a == flag && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
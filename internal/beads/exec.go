@@ -2,11 +2,86 @@ package beads
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"os/exec"
+	"slices"
 	"strings"
+	"time"
 )
 
+// Backend executes bd operations. The CLI backend forks bd once per
+// command; other backends may talk to beads without a subprocess.
+type Backend interface {
+	// Check verifies the backend is usable before any command runs.
+	Check() error
+
+	// Run executes one bd command and returns the issue ID it printed, if any.
+	Run(args []string) (string, error)
+
+	// Output executes a read-only bd command and returns its raw stdout.
+	Output(args []string) (string, error)
+}
+
+// NewCLIBackend returns the backend that runs bd as a subprocess. Other
+// ways of reaching beads implement Backend.
+func NewCLIBackend() Backend {
+	return &cliBackend{}
+}
+
+// cliBackend runs each command as a bd subprocess. Check turns on JSON
+// output when the installed bd supports it; until then, and on older bd
+// versions, create commands use --silent and the ID is read from stdout.
+// Check also records the bd version so Run can drop flags it predates.
+type cliBackend struct {
+	jsonOutput bool
+	version    *bdVersion
+
+	// help caches 'bd <subcommand> --help' output for supportsFlag.
+	help map[string]string
+}
+
+func (b *cliBackend) Check() error {
+	if err := PreFlightCheck(); err != nil {
+		return err
+	}
+	v, ok, err := detectBdVersion()
+	if err != nil {
+		return err
+	}
+	if ok {
+		if err := checkBdVersion(v); err != nil {
+			return err
+		}
+		b.version = &v
+	}
+	b.jsonOutput = b.supportsFlag("create", "--json")
+	return nil
+}
+
+func (b *cliBackend) Output(args []string) (string, error) { return runBd(args) }
+
+func (b *cliBackend) Run(args []string) (string, error) {
+	if b.version != nil {
+		args = compatArgs(args, *b.version)
+	}
+	if !b.jsonOutput || !slices.Contains(args, "--silent") {
+		return runBdCommand(args)
+	}
+	jsonArgs := make([]string, len(args))
+	for i, a := range args {
+		if a == "--silent" {
+			a = "--json"
+		}
+		jsonArgs[i] = a
+	}
+	out, err := runBd(jsonArgs)
+	if err != nil {
+		return "", err
+	}
+	return parseCreateJSON(out)
+}
+
 // PreFlightCheck verifies that bd is available and beads is initialized.
 // Returns a user-friendly error message if either check fails.
 func PreFlightCheck() error {
@@ -31,28 +106,190 @@ func PreFlightCheck() error {
 	return nil
 }
 
-// ExecuteCommands runs the bd commands and builds the CreationResult.
+// CheckParentEpic verifies that id names an open epic, so tasks can be
+// parented under it.
+func CheckParentEpic(backend Backend, id string) error {
+	out, err := backend.Output([]string{"show", id, "--json"})
+	if err != nil {
+		return fmt.Errorf("parent epic '%s' not found: %w", id, err)
+	}
+	start := strings.IndexAny(out, "{[")
+	if start < 0 {
+		return fmt.Errorf("parent epic '%s' not found: bd show printed no JSON", id)
+	}
+	var v any
+	if err := json.NewDecoder(strings.NewReader(out[start:])).Decode(&v); err != nil {
+		return fmt.Errorf("parsing bd show --json output: %w", err)
+	}
+	if list, ok := v.([]any); ok {
+		if len(list) == 0 {
+			return fmt.Errorf("parent epic '%s' not found", id)
+		}
+		v = list[0]
+	}
+	obj, _ := v.(map[string]any)
+	if obj == nil || obj["id"] == nil {
+		return fmt.Errorf("parent epic '%s' not found", id)
+	}
+	if t, ok := obj["issue_type"].(string); ok && t != "epic" {
+		return fmt.Errorf("parent epic '%s' is a %s, not an epic", id, t)
+	}
+	if status, _ := obj["status"].(string); status == "closed" {
+		return fmt.Errorf("parent epic '%s' is closed. Reopen it or leave out --parent-epic to create a new epic", id)
+	}
+	return nil
+}
+
+// ExecuteCommands runs the bd commands through the bd CLI; see ExecuteWith.
+func ExecuteCommands(cmds []BdCommand) (*CreationResult, error) {
+	return ExecuteWith(&cliBackend{}, cmds)
+}
+
+// CommandEvent describes one bd command ExecuteObserved ran.
+type CommandEvent struct {
+	// Type and TaskID are those of the BdCommand; for dep-add, TaskID is
+	// the dependent task.
+	Type   string
+	TaskID string
+
+	// Args are the arguments run, with placeholder IDs replaced.
+	Args []string
+
+	// IssueID is the ID bd printed, if any.
+	IssueID string
+
+	Elapsed time.Duration
+	Err     error
+}
+
+// CommandError is the error ExecuteObserved returns when a bd command
+// fails. The CreationResult returned with it holds what was done before.
+type CommandError struct {
+	// Type and TaskID are those of the failed BdCommand; for dep-add,
+	// TaskID is the dependent task.
+	Type   string
+	TaskID string
+
+	// Args are the arguments run, with placeholder IDs replaced.
+	Args []string
+
+	// Err is the backend's error: for the CLI backend, bd's stderr.
+	Err error
+
+	// Created is the number of issues created before the failure.
+	Created int
+}
+
+func (e *CommandError) Error() string {
+	return fmt.Sprintf("bd command failed: bd %s\n  Error: %s\n  %d issues created before failure",
+		strings.Join(e.Args, " "), e.Err, e.Created)
+}
+
+func (e *CommandError) Unwrap() error { return e.Err }
+
+// ExecuteWith runs the bd commands on backend and builds the CreationResult.
 // Commands are executed sequentially. Placeholder IDs in later commands
 // are replaced with actual IDs from earlier create commands.
-func ExecuteCommands(cmds []BdCommand) (*CreationResult, error) {
+func ExecuteWith(backend Backend, cmds []BdCommand) (*CreationResult, error) {
+	return ExecuteObserved(backend, cmds, nil)
+}
+
+// ExecuteObserved is ExecuteWith calling observe, if set, after each bd
+// command runs, including one that fails.
+// Nothing runs when a placeholder ID is unbound; see CheckPlaceholders.
+func ExecuteObserved(backend Backend, cmds []BdCommand, observe func(CommandEvent)) (*CreationResult, error) {
 	result := &CreationResult{
 		TaskIDs:    make(map[string]string),
 		TaskTitles: make(map[string]string),
 	}
+	if err := CheckPlaceholders(cmds); err != nil {
+		return result, err
+	}
 
 	// ID replacement map: placeholder -> actual bd ID.
 	idMap := make(map[string]string)
 
+	// Issues this run did not create, which may already have some of the
+	// dependency links, and the links found on them so far.
+	reused := make(map[string]bool)
+	linked := make(map[string]map[string]bool)
+
 	for _, cmd := range cmds {
+		// An existing epic and tasks matched to an existing issue reuse
+		// its ID without running bd.
+		if cmd.Type == "existing-epic" {
+			idMap["<epic-id>"] = cmd.ExistingID
+			result.EpicID = cmd.ExistingID
+			result.EpicExisting = true
+			reused[cmd.ExistingID] = true
+			continue
+		}
+		if cmd.Type == "excluded-task" {
+			result.Excluded = append(result.Excluded, cmd.TaskID)
+			continue
+		}
+		if cmd.Type == "pruned-dep" {
+			result.PrunedDeps = append(result.PrunedDeps, PrunedDep{TaskID: cmd.DepTaskID, DependsOn: cmd.DepOnID})
+			continue
+		}
+		if cmd.Type == "existing-task" {
+			idMap["<"+cmd.TaskID+"-id>"] = cmd.ExistingID
+			result.TaskIDs[cmd.TaskID] = cmd.ExistingID
+			result.TaskOrder = append(result.TaskOrder, cmd.TaskID)
+			result.Skipped = append(result.Skipped, cmd.TaskID)
+			reused[cmd.ExistingID] = true
+			continue
+		}
+
 		// Replace placeholder IDs with actual IDs.
 		args := replaceIDs(cmd.Args, idMap)
 
+		// A link between two reused issues may be there from an earlier
+		// run. Adding it again fails or duplicates it, depending on the bd
+		// version, so it is left as it is.
+		if cmd.Type == "dep-add" {
+			taskBdID, depBdID := idMap["<"+cmd.DepTaskID+"-id>"], idMap["<"+cmd.DepOnID+"-id>"]
+			if reused[taskBdID] && reused[depBdID] {
+				if _, ok := linked[taskBdID]; !ok {
+					// A failed lookup finds nothing; bd's own "already
+					// exists" error below still catches the link.
+					linked[taskBdID], _ = existingDeps(backend, taskBdID)
+				}
+				if linked[taskBdID][depBdID] {
+					recordExistingDep(result, taskBdID, depBdID)
+					continue
+				}
+			}
+		}
+
+		// Move arguments too long for the command line to files, or cut
+		// them down.
+		args, warnings, cleanup, err := fitArgs(backend, args)
+		if err != nil {
+			return result, fmt.Errorf("preparing bd %s: %w", subcommand(cmd.Args), err)
+		}
+		for _, w := range warnings {
+			owner := commandTaskID(cmd)
+			if owner == "" {
+				owner = "epic"
+			}
+			result.Warnings = append(result.Warnings, owner+": "+w)
+		}
+
 		// Execute the command.
-		bdID, err := runBdCommand(args)
+		start := time.Now()
+		bdID, err := backend.Run(args)
+		cleanup()
+		alreadyLinked := err != nil && cmd.Type == "dep-add" && isAlreadyLinked(err)
+		if alreadyLinked {
+			err = nil
+		}
+		if observe != nil {
+			observe(CommandEvent{Type: cmd.Type, TaskID: commandTaskID(cmd), Args: args, IssueID: bdID, Elapsed: time.Since(start), Err: err})
+		}
 		if err != nil {
 			// Report partial results.
-			return result, fmt.Errorf("bd command failed: bd %s\n  Error: %w\n  %d issues created before failure",
-				strings.Join(args, " "), err, result.Created)
+			return result, &CommandError{Type: cmd.Type, TaskID: commandTaskID(cmd), Args: args, Err: err, Created: result.Created}
 		}
 
 		// Record results based on command type.
@@ -71,6 +308,7 @@ func ExecuteCommands(cmds []BdCommand) (*CreationResult, error) {
 
 		case "create-task":
 			result.TaskIDs[cmd.TaskID] = bdID
+			result.TaskOrder = append(result.TaskOrder, cmd.TaskID)
 			// Extract title from args.
 			for i, a := range cmd.Args {
 				if a == "--title" && i+1 < len(cmd.Args) {
@@ -81,7 +319,19 @@ func ExecuteCommands(cmds []BdCommand) (*CreationResult, error) {
 			idMap["<"+cmd.TaskID+"-id>"] = bdID
 			result.Created++
 
+		case "update-task":
+			idMap["<"+cmd.TaskID+"-id>"] = cmd.ExistingID
+			result.TaskIDs[cmd.TaskID] = cmd.ExistingID
+			result.TaskOrder = append(result.TaskOrder, cmd.TaskID)
+			result.TaskTitles[cmd.TaskID] = argValue(cmd.Args, "--title")
+			result.Updated = append(result.Updated, cmd.TaskID)
+			reused[cmd.ExistingID] = true
+
 		case "dep-add":
+			if alreadyLinked {
+				recordExistingDep(result, idMap["<"+cmd.DepTaskID+"-id>"], idMap["<"+cmd.DepOnID+"-id>"])
+				break
+			}
 			result.Deps++
 			result.DepsDetail = append(result.DepsDetail, DepLink{
 				TaskBdID: idMap["<"+cmd.DepTaskID+"-id>"],
@@ -98,8 +348,149 @@ func ExecuteCommands(cmds []BdCommand) (*CreationResult, error) {
 	return result, nil
 }
 
-// runBdCommand executes a single bd command and returns the issue ID (from --silent output).
+// commandTaskID returns the task_id a command belongs to: the dependent
+// task for dep-add, else TaskID, which is empty for the epic.
+func commandTaskID(cmd BdCommand) string {
+	if cmd.Type == "dep-add" {
+		return cmd.DepTaskID
+	}
+	return cmd.TaskID
+}
+
+// CheckPlaceholders verifies that every placeholder ID in cmds, such as
+// <epic-id> or <task-a-id>, is bound by an earlier command. replaceIDs
+// leaves an unbound placeholder as it is, and bd would be called with it.
+// The error lists each unbound placeholder and the commands using it.
+func CheckPlaceholders(cmds []BdCommand) error {
+	bound := make(map[string]bool)
+	usedBy := make(map[string][]string)
+	var missing []string
+	for _, cmd := range cmds {
+		for _, a := range cmd.Args {
+			if !isPlaceholder(a) || bound[a] {
+				continue
+			}
+			if _, seen := usedBy[a]; !seen {
+				missing = append(missing, a)
+			}
+			usedBy[a] = append(usedBy[a], fmt.Sprintf("%s (%s)", cmd.Type, commandTaskID(cmd)))
+		}
+		switch cmd.Type {
+		case "create-epic", "existing-epic":
+			bound["<epic-id>"] = true
+		case "create-task", "existing-task", "update-task":
+			bound["<"+cmd.TaskID+"-id>"] = true
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	var sb strings.Builder
+	sb.WriteString("unresolved placeholder IDs; no bd command was run:")
+	for _, p := range missing {
+		fmt.Fprintf(&sb, "\n  %s is not bound by an earlier create command, used by %s", p, strings.Join(usedBy[p], ", "))
+	}
+	return fmt.Errorf("%s", sb.String())
+}
+
+// isPlaceholder reports whether arg is a whole placeholder ID. Text that
+// merely mentions one, in a description say, is not checked.
+func isPlaceholder(arg string) bool {
+	return len(arg) > len("<-id>") && strings.HasPrefix(arg, "<") && strings.HasSuffix(arg, "-id>") &&
+		!strings.ContainsAny(arg[1:len(arg)-1], "<> \t\n")
+}
+
+// recordExistingDep counts a dependency link that was already there.
+func recordExistingDep(result *CreationResult, taskBdID, depBdID string) {
+	result.DepsExisting++
+	result.DepsDetail = append(result.DepsDetail, DepLink{TaskBdID: taskBdID, DepBdID: depBdID, Existing: true})
+}
+
+// existingDeps returns the IDs of the issues id is blocked by, read from
+// 'bd show --json'. Newer bd versions list each dependency as an issue
+// with a dependency_type; older ones as a link with depends_on_id and type.
+func existingDeps(backend Backend, id string) (map[string]bool, error) {
+	out, err := backend.Output([]string{"show", id, "--json"})
+	if err != nil {
+		return nil, err
+	}
+	start := strings.IndexAny(out, "{[")
+	if start < 0 {
+		return nil, fmt.Errorf("bd show printed no JSON")
+	}
+	var v any
+	if err := json.NewDecoder(strings.NewReader(out[start:])).Decode(&v); err != nil {
+		return nil, fmt.Errorf("parsing bd show --json output: %w", err)
+	}
+	if list, ok := v.([]any); ok && len(list) > 0 {
+		v = list[0]
+	}
+	obj, _ := v.(map[string]any)
+	deps, _ := obj["dependencies"].([]any)
+	found := make(map[string]bool)
+	for _, d := range deps {
+		dep, _ := d.(map[string]any)
+		depType, _ := dep["dependency_type"].(string)
+		if depType == "" {
+			depType, _ = dep["type"].(string)
+		}
+		if depType != "" && depType != "blocks" {
+			continue
+		}
+		if on, ok := dep["depends_on_id"].(string); ok && on != "" {
+			found[on] = true
+		} else if on, ok := dep["id"].(string); ok && on != "" {
+			found[on] = true
+		}
+	}
+	return found, nil
+}
+
+// isAlreadyLinked reports whether a failed 'bd dep add' failed only
+// because the dependency exists.
+func isAlreadyLinked(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "already exists") || strings.Contains(msg, "already depends on")
+}
+
+// runBdCommand executes a single bd command and returns the issue ID (from
+// --silent output). Older bd versions may print notices before the ID, so
+// the last non-empty line is used.
 func runBdCommand(args []string) (string, error) {
+	out, err := runBd(args)
+	if err != nil {
+		return "", err
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	return strings.TrimSpace(lines[len(lines)-1]), nil
+}
+
+// parseCreateJSON extracts the issue ID from 'bd create --json' output.
+// bd prints the created issue as an object (or a one-element list); any
+// text before the JSON, such as upgrade notices, is skipped.
+func parseCreateJSON(out string) (string, error) {
+	start := strings.IndexAny(out, "{[")
+	if start < 0 {
+		return "", fmt.Errorf("bd create --json printed no JSON: %s", truncate(strings.TrimSpace(out), 200))
+	}
+	var v any
+	if err := json.NewDecoder(strings.NewReader(out[start:])).Decode(&v); err != nil {
+		return "", fmt.Errorf("parsing bd create --json output: %w", err)
+	}
+	if list, ok := v.([]any); ok && len(list) > 0 {
+		v = list[0]
+	}
+	if obj, ok := v.(map[string]any); ok {
+		if id, ok := obj["id"].(string); ok && id != "" {
+			return id, nil
+		}
+	}
+	return "", fmt.Errorf("bd create --json output has no issue id: %s", truncate(strings.TrimSpace(out[start:]), 200))
+}
+
+// runBd executes bd with args and returns its stdout. On failure the error
+// carries bd's stderr.
+func runBd(args []string) (string, error) {
 	cmd := exec.Command("bd", args...)
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
@@ -113,9 +504,7 @@ func runBdCommand(args []string) (string, error) {
 		return "", fmt.Errorf("%s", errMsg)
 	}
 
-	// For create commands with --silent, stdout contains just the issue ID.
-	id := strings.TrimSpace(stdout.String())
-	return id, nil
+	return stdout.String(), nil
 }
 
 // replaceIDs substitutes placeholder IDs with actual IDs in command arguments.
@@ -0,0 +1,45 @@
+package beads
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// PrunedDep is a dependency link left out because one of its tasks was
+// excluded by SkipTasks or OnlyTasks.
+type PrunedDep struct {
+	TaskID    string `json:"task_id"`
+	DependsOn string `json:"depends_on"`
+}
+
+// excludedTasks returns the task_ids of graph that SkipTasks and OnlyTasks
+// leave out. A listed task_id missing from the graph is an error, as is
+// leaving no task to create.
+func (c *Creator) excludedTasks(graph *validator.TaskGraph) (map[string]bool, error) {
+	if len(c.SkipTasks) == 0 && len(c.OnlyTasks) == 0 {
+		return nil, nil
+	}
+	var unknown []string
+	for _, id := range append(slices.Clone(c.SkipTasks), c.OnlyTasks...) {
+		if graph.FindTask(id) == nil && !slices.Contains(unknown, id) {
+			unknown = append(unknown, id)
+		}
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("task(s) not in the graph: %s", strings.Join(unknown, ", "))
+	}
+
+	excluded := make(map[string]bool)
+	for _, t := range graph.Tasks {
+		if slices.Contains(c.SkipTasks, t.TaskID) || (len(c.OnlyTasks) > 0 && !slices.Contains(c.OnlyTasks, t.TaskID)) {
+			excluded[t.TaskID] = true
+		}
+	}
+	if len(excluded) == len(graph.Tasks) {
+		return nil, fmt.Errorf("every task is excluded; there is nothing to create")
+	}
+	return excluded, nil
+}
@@ -4,16 +4,26 @@
 package beads
 
 import (
+	_ "embed"
 	"encoding/json"
 	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"text/template"
 
 	"github.com/nixlim/task_templating/internal/validator"
 )
 
 // MapPriority maps a task template priority string to a bd numeric priority.
-// Returns 2 (medium) as default for empty or unrecognized values.
+// A raw number (validated as 0-4) passes through unchanged. Returns 2
+// (medium) as default for empty or unrecognized values.
 func MapPriority(priority string) int {
+	if n, err := strconv.Atoi(priority); err == nil {
+		return n
+	}
 	switch strings.ToLower(priority) {
 	case "critical":
 		return 0
@@ -28,9 +38,13 @@ func MapPriority(priority string) int {
 	}
 }
 
-// MapEstimate maps a task template estimate string to minutes.
-// Returns 0 for "unknown" or empty string, signaling the estimate should be omitted.
+// MapEstimate maps a task template estimate string to minutes. A raw
+// number is already minutes and passes through unchanged. Returns 0 for
+// "unknown" or empty string, signaling the estimate should be omitted.
 func MapEstimate(estimate string) int {
+	if n, err := strconv.Atoi(estimate); err == nil {
+		return n
+	}
 	switch strings.ToLower(estimate) {
 	case "trivial":
 		return 15
@@ -45,57 +59,125 @@ func MapEstimate(estimate string) int {
 	}
 }
 
-// ComposeDescription builds a structured markdown description from task template
-// fields for use with the bd --description flag. Sections with no data or N/A
-// status are omitted.
-func ComposeDescription(task *validator.TaskNode) string {
-	var sb strings.Builder
+// EstimateUnit expresses estimates for people: a unit name and the number
+// of working minutes in one unit. bd itself always receives minutes.
+type EstimateUnit struct {
+	Name    string
+	Minutes float64
+}
 
-	// Goal is always first.
-	sb.WriteString(task.Goal)
+// DefaultPointMinutes is the size of a story point when "points" is given
+// without one: an hour, so small is 1 point and large is 8.
+const DefaultPointMinutes = 60
 
-	// Inputs section.
-	if len(task.Inputs) > 0 {
-		sb.WriteString("\n\n## Inputs\n")
-		for _, in := range task.Inputs {
-			sb.WriteString(fmt.Sprintf("- **%s** (`%s`): %s -- Source: %s\n", in.Name, in.Type, in.Constraints, in.Source))
+// ParseEstimateUnit parses "minutes", "hours", "pomodoros" (25 minutes),
+// or "points", optionally with the minutes per point ("points:120").
+func ParseEstimateUnit(s string) (EstimateUnit, error) {
+	name, size, sized := strings.Cut(s, ":")
+	switch name {
+	case "minutes", "hours", "pomodoros":
+		if sized {
+			return EstimateUnit{}, fmt.Errorf("estimate unit '%s' has a fixed size; only points take ':N'", name)
+		}
+		return EstimateUnit{Name: name, Minutes: map[string]float64{"minutes": 1, "hours": 60, "pomodoros": 25}[name]}, nil
+	case "points":
+		u := EstimateUnit{Name: name, Minutes: DefaultPointMinutes}
+		if sized {
+			n, err := strconv.ParseFloat(size, 64)
+			if err != nil || n <= 0 {
+				return EstimateUnit{}, fmt.Errorf("invalid minutes per point '%s'", size)
+			}
+			u.Minutes = n
 		}
+		return u, nil
+	default:
+		return EstimateUnit{}, fmt.Errorf("unknown estimate unit '%s'. Must be minutes, hours, pomodoros, or points[:N]", s)
 	}
+}
 
-	// Outputs section.
-	if len(task.Outputs) > 0 {
-		sb.WriteString("\n## Outputs\n")
-		for _, out := range task.Outputs {
-			sb.WriteString(fmt.Sprintf("- **%s** (`%s`): %s -- Dest: %s\n", out.Name, out.Type, out.Constraints, out.Destination))
-		}
+// Convert returns minutes expressed in the unit.
+func (u EstimateUnit) Convert(minutes int) float64 {
+	return float64(minutes) / u.Minutes
+}
+
+// Format renders minutes in the unit, e.g. "4h", "2.4 pomodoros", "1 point".
+func (u EstimateUnit) Format(minutes int) string {
+	v := math.Round(u.Convert(minutes)*100) / 100
+	n := strconv.FormatFloat(v, 'f', -1, 64)
+	switch u.Name {
+	case "minutes":
+		return n + "m"
+	case "hours":
+		return n + "h"
+	}
+	name := u.Name
+	if v == 1 {
+		name = strings.TrimSuffix(name, "s")
 	}
+	return n + " " + name
+}
 
-	// Constraints section.
-	constraints := parseStringArrayOrNA(task.Constraints)
-	if len(constraints) > 0 {
-		sb.WriteString("\n## Constraints\n")
-		for _, c := range constraints {
-			sb.WriteString(fmt.Sprintf("- %s\n", c))
-		}
+//go:embed templates/description.md.tmpl
+var defaultDescriptionSource string
+
+// descriptionFuncs are available to description templates.
+var descriptionFuncs = template.FuncMap{
+	"stringList": parseStringArrayOrNA,
+	"effects":    parseEffectsOrNA,
+	"criterion":  criterionText,
+}
+
+// criterionText returns acceptance criterion i, or a placeholder when the
+// index is out of range.
+func criterionText(acceptance []string, i int) string {
+	if i < 0 || i >= len(acceptance) {
+		return fmt.Sprintf("criterion %d", i)
 	}
+	return acceptance[i]
+}
 
-	// Non-Goals section.
-	if len(task.NonGoals) > 0 {
-		sb.WriteString("\n## Non-Goals\n")
-		for _, ng := range task.NonGoals {
-			sb.WriteString(fmt.Sprintf("- %s\n", ng))
-		}
+var defaultDescription = template.Must(template.New("description.md.tmpl").Funcs(descriptionFuncs).Parse(defaultDescriptionSource))
+
+// ComposeDescription builds a structured markdown description from task template
+// fields for use with the bd --description flag. Sections with no data or N/A
+// status are omitted.
+func ComposeDescription(task *validator.TaskNode) string {
+	desc, err := RenderDescription(nil, task)
+	if err != nil {
+		// The built-in template only reads fields that always exist.
+		panic(err)
 	}
+	return desc
+}
 
-	// Error Cases section.
-	if len(task.ErrorCases) > 0 {
-		sb.WriteString("\n## Error Cases\n")
-		for _, ec := range task.ErrorCases {
-			sb.WriteString(fmt.Sprintf("- **%s**: %s -> %s\n", ec.Condition, ec.Behavior, ec.Output))
-		}
+// LoadDescriptionTemplate parses a text/template file for RenderDescription.
+// The template is executed with a *validator.TaskNode; stringList decodes a
+// list field that may be N/A (constraints, files_scope, depends_on),
+// effects renders the effects field as text, and criterion returns the
+// acceptance criterion at an index (for verification entries).
+func LoadDescriptionTemplate(path string) (*template.Template, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading description template: %w", err)
+	}
+	tmpl, err := template.New(filepath.Base(path)).Funcs(descriptionFuncs).Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("parsing description template: %w", err)
 	}
+	return tmpl, nil
+}
 
-	return sb.String()
+// RenderDescription executes tmpl, or the default layout if tmpl is nil,
+// for task.
+func RenderDescription(tmpl *template.Template, task *validator.TaskNode) (string, error) {
+	if tmpl == nil {
+		tmpl = defaultDescription
+	}
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, task); err != nil {
+		return "", fmt.Errorf("rendering description for '%s': %w", task.TaskID, err)
+	}
+	return sb.String(), nil
 }
 
 // templateMetadata is the structure stored in the bd --design field.
@@ -110,11 +192,22 @@ type templateData struct {
 	Effects    string                 `json:"effects"`
 	Inputs     []validator.InputSpec  `json:"inputs"`
 	Outputs    []validator.OutputSpec `json:"outputs"`
+	Due        string                 `json:"due,omitempty"`
+	NotBefore  string                 `json:"not_before,omitempty"`
+	Risk       *validator.Risk        `json:"risk,omitempty"`
+
+	Extensions map[string]json.RawMessage `json:"extensions,omitempty"`
 }
 
 // BuildTemplateMetadata builds a JSON string containing machine-readable
 // template metadata for the bd --design flag.
 func BuildTemplateMetadata(task *validator.TaskNode) (string, error) {
+	return BuildTemplateMetadataWith(task, false)
+}
+
+// BuildTemplateMetadataWith is BuildTemplateMetadata that, when extensions
+// is set, also copies the task's x_ fields into _template.extensions.
+func BuildTemplateMetadataWith(task *validator.TaskNode, extensions bool) (string, error) {
 	filesScope := parseStringArrayOrNA(task.FilesScope)
 	if filesScope == nil {
 		filesScope = []string{}
@@ -124,14 +217,20 @@ func BuildTemplateMetadata(task *validator.TaskNode) (string, error) {
 
 	meta := templateMetadata{
 		Template: templateData{
-			Version:    "0.2.0",
+			Version:    validator.DesignVersion,
 			TaskID:     task.TaskID,
 			FilesScope: filesScope,
 			Effects:    effects,
 			Inputs:     task.Inputs,
 			Outputs:    task.Outputs,
+			Due:        task.Due,
+			NotBefore:  task.NotBefore,
+			Risk:       task.Risk,
 		},
 	}
+	if extensions {
+		meta.Template.Extensions = task.Extensions()
+	}
 
 	data, err := json.Marshal(meta)
 	if err != nil {
@@ -140,8 +239,36 @@ func BuildTemplateMetadata(task *validator.TaskNode) (string, error) {
 	return string(data), nil
 }
 
-// FormatAcceptance joins acceptance criteria into a markdown checklist.
+// Acceptance list styles accepted by AcceptanceBullet.
+const (
+	// AcceptanceBullets renders plain "- " bullets.
+	AcceptanceBullets = "bullets"
+
+	// AcceptanceCheckboxes renders "- [ ] " items, which trackers with
+	// GitHub-flavored markdown show as a tick-off list.
+	AcceptanceCheckboxes = "checkboxes"
+)
+
+// AcceptanceBullet returns the list marker for an acceptance style.
+func AcceptanceBullet(style string) (string, error) {
+	switch style {
+	case AcceptanceBullets:
+		return "- ", nil
+	case AcceptanceCheckboxes:
+		return "- [ ] ", nil
+	default:
+		return "", fmt.Errorf("unknown acceptance style '%s'. Must be '%s' or '%s'", style, AcceptanceBullets, AcceptanceCheckboxes)
+	}
+}
+
+// FormatAcceptance joins acceptance criteria into a markdown bullet list.
 func FormatAcceptance(criteria []string) string {
+	return FormatAcceptanceWith(criteria, "- ")
+}
+
+// FormatAcceptanceWith joins acceptance criteria into a markdown list,
+// starting each item with bullet (see AcceptanceBullet).
+func FormatAcceptanceWith(criteria []string, bullet string) string {
 	if len(criteria) == 0 {
 		return ""
 	}
@@ -150,7 +277,7 @@ func FormatAcceptance(criteria []string) string {
 		if i > 0 {
 			sb.WriteString("\n")
 		}
-		sb.WriteString("- " + c)
+		sb.WriteString(bullet + c)
 	}
 	return sb.String()
 }
@@ -0,0 +1,83 @@
+package beads
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// notifyTimeout bounds one webhook delivery.
+const notifyTimeout = 10 * time.Second
+
+// Notification is the body POSTed to --notify-webhook once issue creation
+// finishes. On failure it carries what was created before the error, so a
+// listener can report the partial plan.
+type Notification struct {
+	// Status is "created", or "failed" when a bd command failed.
+	Status string `json:"status"`
+
+	// Source is the input file name, "-" for stdin.
+	Source string `json:"source"`
+
+	EpicTitle string `json:"epic_title,omitempty"`
+
+	*BeadsJSON
+
+	// Error is the failure message when Status is "failed".
+	Error string `json:"error,omitempty"`
+}
+
+// NewNotification describes a creation run. runErr is the error
+// ExecuteWith returned, if any; result may then be partial or nil.
+func NewNotification(source string, result *CreationResult, runErr error) Notification {
+	n := Notification{Status: "created", Source: source, BeadsJSON: &BeadsJSON{Tasks: map[string]string{}, TaskOrder: []string{}}}
+	if result != nil {
+		n.EpicTitle = result.EpicTitle
+		n.BeadsJSON = FormatJSONOutput(result)
+	}
+	if runErr != nil {
+		n.Status = "failed"
+		n.Error = runErr.Error()
+	}
+	return n
+}
+
+// CheckWebhookURL reports whether raw is an absolute http or https URL.
+func CheckWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid webhook URL '%s': must be an absolute http or https URL", raw)
+	}
+	return nil
+}
+
+// Notify POSTs n as JSON to the webhook URL. Any status other than 2xx is
+// an error.
+func Notify(ctx context.Context, webhook string, n Notification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, notifyTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("notifying webhook: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("notifying webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("notifying webhook: %s returned %s", webhook, resp.Status)
+	}
+	return nil
+}
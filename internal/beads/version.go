@@ -0,0 +1,105 @@
+package beads
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// MinBdVersion is the oldest bd release taskval supports. Older releases
+// lack flags taskval relies on, such as --parent and --labels on create.
+const MinBdVersion = "0.9.0"
+
+// flagSince lists create flags newer than MinBdVersion with the first bd
+// release that accepts them. They are dropped when running an older bd.
+var flagSince = map[string]string{
+	"--estimate": "0.12.0",
+}
+
+var versionPattern = regexp.MustCompile(`\d+\.\d+\.\d+`)
+
+// bdVersion is a parsed major.minor.patch release number.
+type bdVersion [3]int
+
+func (v bdVersion) String() string { return fmt.Sprintf("%d.%d.%d", v[0], v[1], v[2]) }
+
+func (v bdVersion) less(o bdVersion) bool {
+	for i := range v {
+		if v[i] != o[i] {
+			return v[i] < o[i]
+		}
+	}
+	return false
+}
+
+// parseBdVersion finds the first x.y.z release number in s, as printed by
+// 'bd version' (e.g. "bd version 0.21.4 (dev)").
+func parseBdVersion(s string) (bdVersion, bool) {
+	m := versionPattern.FindString(s)
+	if m == "" {
+		return bdVersion{}, false
+	}
+	var v bdVersion
+	for i, part := range strings.Split(m, ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return bdVersion{}, false
+		}
+		v[i] = n
+	}
+	return v, true
+}
+
+func mustParseBdVersion(s string) bdVersion {
+	v, ok := parseBdVersion(s)
+	if !ok {
+		panic("invalid bd version constant " + s)
+	}
+	return v
+}
+
+// detectBdVersion runs 'bd version'. ok is false when the output has no
+// recognizable release number, e.g. on development builds.
+func detectBdVersion() (v bdVersion, ok bool, err error) {
+	out, err := exec.Command("bd", "version").CombinedOutput()
+	if err != nil {
+		return bdVersion{}, false, fmt.Errorf("running 'bd version': %s", strings.TrimSpace(string(out)))
+	}
+	v, ok = parseBdVersion(string(out))
+	return v, ok, nil
+}
+
+// InstalledVersion returns the release number 'bd version' prints and
+// whether it is one taskval supports. ok is false when the output holds
+// no release number, as on development builds; err is set when bd cannot
+// be run or is older than MinBdVersion.
+func InstalledVersion() (version string, ok bool, err error) {
+	v, ok, err := detectBdVersion()
+	if err != nil || !ok {
+		return "", ok, err
+	}
+	return v.String(), true, checkBdVersion(v)
+}
+
+// checkBdVersion rejects bd releases older than MinBdVersion.
+func checkBdVersion(v bdVersion) error {
+	if v.less(mustParseBdVersion(MinBdVersion)) {
+		return fmt.Errorf("bd %s is older than the minimum supported version %s. Upgrade beads: go install github.com/steveyegge/beads/cmd/bd@latest", v, MinBdVersion)
+	}
+	return nil
+}
+
+// compatArgs drops flags (and their values) that bd v does not accept.
+func compatArgs(args []string, v bdVersion) []string {
+	out := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		if since, ok := flagSince[args[i]]; ok && v.less(mustParseBdVersion(since)) {
+			i++ // Skip the flag's value too.
+			continue
+		}
+		out = append(out, args[i])
+	}
+	return out
+}
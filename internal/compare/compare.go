@@ -0,0 +1,117 @@
+// Package compare diffs two validation reports, so a CI bot can comment
+// on the findings a change introduced rather than on every pre-existing
+// warning.
+package compare
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// Report is the part of a taskval JSON report (--output=json or a JSON
+// --output-file) that comparison reads.
+type Report struct {
+	Valid  bool                        `json:"valid"`
+	Errors []validator.ValidationError `json:"errors"`
+	Stats  validator.ValidationStats   `json:"stats"`
+}
+
+// ParseReport decodes a JSON report. A document without "valid" and
+// "stats" is rejected, so a task graph passed by mistake is not read as
+// a report with no findings.
+func ParseReport(data []byte) (*Report, error) {
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("parsing report: %w", err)
+	}
+	if probe["valid"] == nil || probe["stats"] == nil {
+		return nil, fmt.Errorf("not a taskval JSON report: no \"valid\" and \"stats\" fields")
+	}
+	var r Report
+	if err := json.NewDecoder(bytes.NewReader(data)).Decode(&r); err != nil {
+		return nil, fmt.Errorf("parsing report: %w", err)
+	}
+	return &r, nil
+}
+
+// Counts tallies findings by severity.
+type Counts struct {
+	Errors   int `json:"errors"`
+	Warnings int `json:"warnings"`
+	Infos    int `json:"infos"`
+}
+
+func (c *Counts) add(sev validator.Severity) {
+	switch sev {
+	case validator.SeverityError:
+		c.Errors++
+	case validator.SeverityWarning:
+		c.Warnings++
+	case validator.SeverityInfo:
+		c.Infos++
+	}
+}
+
+// Total returns the number of findings counted.
+func (c Counts) Total() int {
+	return c.Errors + c.Warnings + c.Infos
+}
+
+// Diff is the result of comparing a base report with a head report.
+type Diff struct {
+	// New are findings in head that base does not have, in head order.
+	New []validator.ValidationError `json:"new"`
+
+	// Fixed are findings in base that head no longer has, in base order.
+	Fixed []validator.ValidationError `json:"fixed"`
+
+	// Unchanged counts the findings both reports have.
+	Unchanged Counts `json:"unchanged"`
+}
+
+// key identifies a finding across runs. The path is left out because it
+// holds task indexes, which shift when tasks are added or reordered; the
+// message and value name the task instead.
+type key struct {
+	rule     string
+	severity validator.Severity
+	message  string
+	context  string
+}
+
+func keyOf(e validator.ValidationError) key {
+	return key{e.Rule, e.Severity, e.Message, e.Context}
+}
+
+// Compare matches head's findings against base's. Identical findings are
+// matched one for one, so a warning that now occurs twice counts one
+// occurrence as new. A finding whose severity changed is both fixed (at
+// the old severity) and new (at the new one).
+func Compare(base, head *Report) Diff {
+	remaining := make(map[key]int, len(base.Errors))
+	for _, e := range base.Errors {
+		remaining[keyOf(e)]++
+	}
+
+	diff := Diff{New: []validator.ValidationError{}, Fixed: []validator.ValidationError{}}
+	for _, e := range head.Errors {
+		k := keyOf(e)
+		if remaining[k] > 0 {
+			remaining[k]--
+			diff.Unchanged.add(e.Severity)
+			continue
+		}
+		diff.New = append(diff.New, e)
+	}
+	for _, e := range base.Errors {
+		k := keyOf(e)
+		if remaining[k] > 0 {
+			remaining[k]--
+			diff.Fixed = append(diff.Fixed, e)
+		}
+	}
+	return diff
+}
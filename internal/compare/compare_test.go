@@ -0,0 +1,65 @@
+package compare
+
+import (
+	"testing"
+
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+func finding(rule string, sev validator.Severity, path, message string) validator.ValidationError {
+	return validator.ValidationError{Rule: rule, Severity: sev, Path: path, Message: message}
+}
+
+func TestCompare(t *testing.T) {
+	base := &Report{Errors: []validator.ValidationError{
+		finding("V7", validator.SeverityWarning, "tasks[0].acceptance[0]", "Task 'a': vague phrase."),
+		finding("V4", validator.SeverityError, "tasks[1].depends_on", "Task 'b' depends on 'gone'."),
+		finding("V13", validator.SeverityInfo, "tasks[2].estimate", "Task 'c' is large."),
+		finding("V9", validator.SeverityWarning, "tasks[2].constraints", "Task 'c' has no constraints."),
+	}}
+	head := &Report{Errors: []validator.ValidationError{
+		// Same findings after a task was inserted at the front.
+		finding("V7", validator.SeverityWarning, "tasks[1].acceptance[0]", "Task 'a': vague phrase."),
+		finding("V13", validator.SeverityInfo, "tasks[3].estimate", "Task 'c' is large."),
+		// Promoted from a warning.
+		finding("V9", validator.SeverityError, "tasks[3].constraints", "Task 'c' has no constraints."),
+		finding("V6", validator.SeverityError, "tasks[0].goal", "Task 'new' goal says 'try'."),
+	}}
+
+	diff := Compare(base, head)
+	if len(diff.New) != 2 || diff.New[0].Rule != "V9" || diff.New[1].Rule != "V6" {
+		t.Errorf("New = %+v, want the promoted V9 and the V6", diff.New)
+	}
+	if len(diff.Fixed) != 2 || diff.Fixed[0].Rule != "V4" || diff.Fixed[1].Rule != "V9" {
+		t.Errorf("Fixed = %+v, want V4 and the V9 warning", diff.Fixed)
+	}
+	if want := (Counts{Warnings: 1, Infos: 1}); diff.Unchanged != want {
+		t.Errorf("Unchanged = %+v, want %+v", diff.Unchanged, want)
+	}
+
+	// A repeat of an existing finding is new.
+	head = &Report{Errors: append(base.Errors, base.Errors[0])}
+	diff = Compare(base, head)
+	if len(diff.New) != 1 || len(diff.Fixed) != 0 || diff.Unchanged.Total() != 4 {
+		t.Errorf("duplicate finding: got %+v", diff)
+	}
+}
+
+func TestParseReport(t *testing.T) {
+	r, err := ParseReport([]byte(`{"valid": false, "errors": [{"rule": "V4", "severity": "ERROR", "path": "tasks", "message": "m"}], "stats": {"total_tasks": 1, "error_count": 1}}`))
+	if err != nil {
+		t.Fatalf("ParseReport error: %v", err)
+	}
+	if r.Valid || len(r.Errors) != 1 || r.Stats.ErrorCount != 1 {
+		t.Errorf("unexpected report: %+v", r)
+	}
+
+	for name, bad := range map[string]string{
+		"graph":    `{"version": "0.1.0", "tasks": []}`,
+		"not json": `VALIDATION PASSED`,
+	} {
+		if _, err := ParseReport([]byte(bad)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// azureAPIVersion is the REST API version of the generated requests, the
+// one the $batch endpoint documents.
+const azureAPIVersion = "4.1"
+
+// Azure DevOps link types: Parent points from a child to its parent,
+// Predecessor from a task to a task it depends on.
+const (
+	azureParent      = "System.LinkTypes.Hierarchy-Reverse"
+	azurePredecessor = "System.LinkTypes.Dependency-Reverse"
+)
+
+// azureExporter writes an Azure DevOps work item batch: one Epic for the
+// graph, a User Story per milestone, and a Task per task, to POST to the
+// organization's _apis/wit/$batch endpoint. Items reference each other by
+// negative temporary IDs, which Azure DevOps replaces as it creates them;
+// tasks are emitted after their dependencies so every link target exists.
+type azureExporter struct{}
+
+func (azureExporter) Name() string { return "azure-devops" }
+
+// azureRequest is one entry of a $batch request body.
+type azureRequest struct {
+	Method  string            `json:"method"`
+	URI     string            `json:"uri"`
+	Headers map[string]string `json:"headers"`
+	Body    []azurePatch      `json:"body"`
+}
+
+// azurePatch is a JSON Patch operation on a work item.
+type azurePatch struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	Value any    `json:"value"`
+}
+
+// azureField is a work item field to set; empty values are left unset.
+type azureField struct {
+	name  string
+	value any
+}
+
+type azureRelation struct {
+	Rel string `json:"rel"`
+	URL string `json:"url"`
+}
+
+func (azureExporter) Export(graph *validator.TaskGraph, opts Options) ([]File, error) {
+	collection, project, err := splitProjectURL(opts.ProjectURL)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := groupByMilestone(graph)
+	if err != nil {
+		return nil, err
+	}
+
+	var requests []azureRequest
+	nextID := 0
+	create := func(itemType string, fields []azureField, relations ...azureRelation) string {
+		nextID--
+		id := strconv.Itoa(nextID)
+		body := []azurePatch{{Op: "add", Path: "/id", Value: id}}
+		for _, f := range fields {
+			if f.value != "" && f.value != nil {
+				body = append(body, azurePatch{Op: "add", Path: "/fields/" + f.name, Value: f.value})
+			}
+		}
+		for _, r := range relations {
+			body = append(body, azurePatch{Op: "add", Path: "/relations/-", Value: r})
+		}
+		requests = append(requests, azureRequest{
+			Method:  "PATCH",
+			URI:     fmt.Sprintf("/%s/_apis/wit/workitems/$%s?api-version=%s", url.PathEscape(project), url.PathEscape(itemType), azureAPIVersion),
+			Headers: map[string]string{"Content-Type": "application/json-patch+json"},
+			Body:    body,
+		})
+		return id
+	}
+	link := func(rel, id string) azureRelation {
+		return azureRelation{Rel: rel, URL: collection + "/_apis/wit/workItems/" + id}
+	}
+
+	epicTitle := "Task Graph"
+	if len(graph.Milestones) > 0 {
+		epicTitle = "Task Graph: " + graph.Milestones[0].Name
+	}
+	epic := create("Epic", []azureField{{"System.Title", epicTitle}})
+
+	storyOf := make(map[string]string)
+	for _, g := range groups {
+		story := create("User Story", []azureField{{"System.Title", g.Name}}, link(azureParent, epic))
+		for _, t := range g.Tasks {
+			storyOf[t.TaskID] = story
+		}
+	}
+
+	tasks, err := resolvedTasks(graph)
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[string]string, len(tasks))
+	for _, t := range dependencyOrder(tasks) {
+		relations := []azureRelation{link(azureParent, storyOf[t.TaskID])}
+		deps, _, _ := t.ParseDependsOn()
+		for _, d := range deps {
+			if id, ok := ids[d]; ok {
+				relations = append(relations, link(azurePredecessor, id))
+			}
+		}
+		var effort any
+		if opts.EstimateMinutes != nil {
+			if m := opts.EstimateMinutes(string(t.Estimate)); m > 0 {
+				effort = float64(m) / 60
+			}
+		}
+		ids[t.TaskID] = create("Task", []azureField{
+			{"System.Title", t.TaskName},
+			{"System.Description", azureDescription(t)},
+			{"Microsoft.VSTS.Scheduling.Effort", effort},
+			{"System.Tags", strings.Join(t.Labels, "; ")},
+		}, relations...)
+	}
+
+	out, err := json.MarshalIndent(requests, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encoding Azure DevOps batch: %w", err)
+	}
+	return []File{{Name: "azure-devops.json", Content: append(out, '\n')}}, nil
+}
+
+// splitProjectURL splits an Azure DevOps project URL, such as
+// https://dev.azure.com/org/project, into its collection URL and project.
+func splitProjectURL(raw string) (collection, project string, err error) {
+	if raw == "" {
+		return "", "", fmt.Errorf("the azure-devops target needs the project URL (--project-url), e.g. https://dev.azure.com/org/project")
+	}
+	u, err := url.Parse(strings.TrimSuffix(raw, "/"))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", "", fmt.Errorf("invalid project URL '%s': must be an absolute http or https URL", raw)
+	}
+	i := strings.LastIndex(u.Path, "/")
+	if i < 0 || u.Path[i+1:] == "" {
+		return "", "", fmt.Errorf("invalid project URL '%s': must end with the project name", raw)
+	}
+	project, err = url.PathUnescape(u.Path[i+1:])
+	if err != nil {
+		return "", "", fmt.Errorf("invalid project URL '%s': %w", raw, err)
+	}
+	u.Path, u.RawPath = u.Path[:i], ""
+	return u.String(), project, nil
+}
+
+// azureDescription renders a task's goal and acceptance criteria as the
+// HTML Azure DevOps expects in System.Description.
+func azureDescription(t *validator.TaskNode) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "<p>%s</p>", html.EscapeString(t.Goal))
+	if len(t.Acceptance) > 0 {
+		sb.WriteString("<p><b>Acceptance criteria</b></p><ul>")
+		for _, a := range t.Acceptance {
+			fmt.Fprintf(&sb, "<li>%s</li>", html.EscapeString(a))
+		}
+		sb.WriteString("</ul>")
+	}
+	fmt.Fprintf(&sb, "<p><i>task_id: %s</i></p>", html.EscapeString(t.TaskID))
+	return sb.String()
+}
+
+// dependencyOrder returns tasks so that each follows the tasks it depends
+// on, otherwise keeping graph order. The graph has been validated, so it
+// has no cycles; tasks on one regardless come last in graph order.
+func dependencyOrder(tasks []*validator.TaskNode) []*validator.TaskNode {
+	placed := make(map[string]bool, len(tasks))
+	inGraph := make(map[string]bool, len(tasks))
+	for _, t := range tasks {
+		inGraph[t.TaskID] = true
+	}
+	ordered := make([]*validator.TaskNode, 0, len(tasks))
+	for len(ordered) < len(tasks) {
+		progress := false
+		for _, t := range tasks {
+			if placed[t.TaskID] {
+				continue
+			}
+			deps, _, _ := t.ParseDependsOn()
+			ready := true
+			for _, d := range deps {
+				if inGraph[d] && !placed[d] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				ordered = append(ordered, t)
+				placed[t.TaskID] = true
+				progress = true
+			}
+		}
+		if !progress {
+			for _, t := range tasks {
+				if !placed[t.TaskID] {
+					ordered = append(ordered, t)
+					placed[t.TaskID] = true
+				}
+			}
+		}
+	}
+	return ordered
+}
@@ -0,0 +1,159 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// unassignedGroup is the heading for tasks that belong to no milestone.
+const unassignedGroup = "Unassigned"
+
+// taskGroup is a milestone (or the unassigned bucket) with its tasks.
+type taskGroup struct {
+	Name  string
+	Tasks []*validator.TaskNode
+}
+
+// groupByMilestone returns tasks grouped by milestone in milestone order,
+// with tasks outside every milestone collected last under "Unassigned".
+// Graph defaults are applied to every task.
+func groupByMilestone(graph *validator.TaskGraph) ([]taskGroup, error) {
+	tasks, err := resolvedTasks(graph)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*validator.TaskNode, len(tasks))
+	for _, t := range tasks {
+		byID[t.TaskID] = t
+	}
+
+	var groups []taskGroup
+	assigned := make(map[string]bool)
+	for _, m := range graph.Milestones {
+		g := taskGroup{Name: m.Name}
+		for _, id := range m.TaskIDs {
+			if t, ok := byID[id]; ok && !assigned[id] {
+				g.Tasks = append(g.Tasks, t)
+				assigned[id] = true
+			}
+		}
+		groups = append(groups, g)
+	}
+
+	rest := taskGroup{Name: unassignedGroup}
+	for _, t := range tasks {
+		if !assigned[t.TaskID] {
+			rest.Tasks = append(rest.Tasks, t)
+		}
+	}
+	if len(rest.Tasks) > 0 {
+		groups = append(groups, rest)
+	}
+	return groups, nil
+}
+
+// markdownExporter writes a TODO.md checklist grouped by milestone.
+type markdownExporter struct{}
+
+func (markdownExporter) Name() string { return "markdown" }
+
+func (markdownExporter) Export(graph *validator.TaskGraph, opts Options) ([]File, error) {
+	groups, err := groupByMilestone(graph)
+	if err != nil {
+		return nil, err
+	}
+
+	var sb strings.Builder
+	sb.WriteString("# TODO\n")
+	for _, g := range groups {
+		fmt.Fprintf(&sb, "\n## %s\n\n", g.Name)
+		for _, t := range g.Tasks {
+			fmt.Fprintf(&sb, "- [ ] **%s** (`%s`)%s\n", t.TaskName, t.TaskID, taskSuffix(t, opts))
+			for _, c := range t.Acceptance {
+				fmt.Fprintf(&sb, "  %s%s\n", opts.bullet("- [ ] "), c)
+			}
+		}
+	}
+	return []File{{Name: "TODO.md", Content: []byte(sb.String())}}, nil
+}
+
+// orgExporter writes the same checklist as an org-mode outline.
+type orgExporter struct{}
+
+func (orgExporter) Name() string { return "org" }
+
+func (orgExporter) Export(graph *validator.TaskGraph, opts Options) ([]File, error) {
+	groups, err := groupByMilestone(graph)
+	if err != nil {
+		return nil, err
+	}
+
+	var sb strings.Builder
+	sb.WriteString("#+TITLE: TODO\n")
+	for _, g := range groups {
+		fmt.Fprintf(&sb, "\n* %s\n", g.Name)
+		for _, t := range g.Tasks {
+			fmt.Fprintf(&sb, "** TODO %s [/]%s\n", t.TaskName, orgTags(t.Labels))
+			sb.WriteString("   :PROPERTIES:\n")
+			fmt.Fprintf(&sb, "   :TASK_ID: %s\n", t.TaskID)
+			if t.Priority != "" {
+				fmt.Fprintf(&sb, "   :PRIORITY: %s\n", t.Priority)
+			}
+			if t.Estimate != "" {
+				fmt.Fprintf(&sb, "   :ESTIMATE: %s\n", opts.estimateLabel(string(t.Estimate)))
+			}
+			sb.WriteString("   :END:\n")
+			if stamp := orgPlanning(t); stamp != "" {
+				sb.WriteString("   " + stamp + "\n")
+			}
+			for _, c := range t.Acceptance {
+				fmt.Fprintf(&sb, "   %s%s\n", opts.bullet("- [ ] "), c)
+			}
+		}
+	}
+	return []File{{Name: "TODO.org", Content: []byte(sb.String())}}, nil
+}
+
+// orgTags renders labels as a heading tag list, e.g. " :backend:api_v2:".
+// Org tags cannot contain '-', so it becomes '_'.
+func orgTags(labels []string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	return " :" + strings.ReplaceAll(strings.Join(labels, ":"), "-", "_") + ":"
+}
+
+// orgPlanning renders a task's dates as an org planning line, e.g.
+// "SCHEDULED: <2026-03-01 Sun> DEADLINE: <2026-03-05 Thu>".
+func orgPlanning(t *validator.TaskNode) string {
+	var parts []string
+	for _, d := range []struct{ keyword, value string }{{"SCHEDULED", t.NotBefore}, {"DEADLINE", t.Due}} {
+		if ts, err := validator.ParseDate(d.value); d.value != "" && err == nil {
+			parts = append(parts, fmt.Sprintf("%s: <%s>", d.keyword, ts.Format("2006-01-02 Mon")))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// taskSuffix renders the priority/estimate annotation after a task line.
+func taskSuffix(t *validator.TaskNode, opts Options) string {
+	var parts []string
+	if t.Priority != "" {
+		parts = append(parts, "priority: "+string(t.Priority))
+	}
+	if t.Estimate != "" {
+		parts = append(parts, "estimate: "+opts.estimateLabel(string(t.Estimate)))
+	}
+	if t.Due != "" {
+		parts = append(parts, "due: "+t.Due)
+	}
+	if len(t.Labels) > 0 {
+		parts = append(parts, "labels: "+strings.Join(t.Labels, ", "))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " — " + strings.Join(parts, ", ")
+}
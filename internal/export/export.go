@@ -0,0 +1,122 @@
+// Package export converts validated task graphs into artifacts for other
+// tools (BDD suites, checklists, calendars, graph viewers, trackers). Every target
+// implements Exporter and is registered by name for the CLI.
+package export
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// File is a single generated artifact. Name is a relative file name.
+type File struct {
+	Name    string
+	Content []byte
+}
+
+// Options carries settings shared by all exporters. Exporters ignore
+// fields that do not apply to them.
+type Options struct {
+	// Start is the first working day of the plan (calendar targets).
+	Start time.Time
+
+	// Workers is the number of tasks that can run in parallel (calendar targets).
+	Workers int
+
+	// EstimateMinutes converts an estimate bucket into working minutes.
+	// Required by targets that schedule work.
+	EstimateMinutes func(estimate string) int
+
+	// FormatEstimate renders working minutes in the user's estimate unit.
+	// When set (with EstimateMinutes), targets that show estimates add the
+	// converted value, e.g. "small (1h)".
+	FormatEstimate func(minutes int) string
+
+	// AcceptanceBullet is the list marker for acceptance criteria in
+	// checklist targets, e.g. "- " or "- [ ] ". Empty uses the target's
+	// default, which for markdown and org is a checkbox.
+	AcceptanceBullet string
+
+	// ProjectURL is the tracker project to export into, e.g.
+	// https://dev.azure.com/org/project (azure-devops target).
+	ProjectURL string
+}
+
+// estimateLabel returns an estimate for display, followed by its value in
+// the configured unit when one is set.
+func (o Options) estimateLabel(estimate string) string {
+	if estimate == "" || o.FormatEstimate == nil || o.EstimateMinutes == nil {
+		return estimate
+	}
+	m := o.EstimateMinutes(estimate)
+	if m == 0 {
+		return estimate
+	}
+	return fmt.Sprintf("%s (%s)", estimate, o.FormatEstimate(m))
+}
+
+// bullet returns opts.AcceptanceBullet, or def when it is unset.
+func (o Options) bullet(def string) string {
+	if o.AcceptanceBullet != "" {
+		return o.AcceptanceBullet
+	}
+	return def
+}
+
+// Exporter converts a validated graph into one or more files.
+type Exporter interface {
+	// Name is the --target value that selects this exporter.
+	Name() string
+
+	// Export renders the graph. Multi-file targets return one File per unit.
+	Export(graph *validator.TaskGraph, opts Options) ([]File, error)
+}
+
+// exporters lists every registered target.
+var exporters = []Exporter{
+	gherkinExporter{},
+	markdownExporter{},
+	orgExporter{},
+	icsExporter{},
+	graphmlExporter{},
+	cytoscapeExporter{},
+	azureExporter{},
+	trelloExporter{},
+}
+
+// Lookup returns the exporter registered for target.
+func Lookup(target string) (Exporter, error) {
+	for _, e := range exporters {
+		if e.Name() == target {
+			return e, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown export target '%s'. Must be one of: %s", target, strings.Join(Targets(), ", "))
+}
+
+// Targets returns the names of all registered exporters, sorted.
+func Targets() []string {
+	names := make([]string, len(exporters))
+	for i, e := range exporters {
+		names[i] = e.Name()
+	}
+	sort.Strings(names)
+	return names
+}
+
+// resolvedTasks returns every task in graph order with graph defaults applied.
+func resolvedTasks(graph *validator.TaskGraph) ([]*validator.TaskNode, error) {
+	tasks := make([]*validator.TaskNode, 0, len(graph.Tasks))
+	for _, t := range graph.Tasks {
+		rt, err := graph.ResolvedTask(t.TaskID)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, rt)
+	}
+	return tasks, nil
+}
@@ -0,0 +1,413 @@
+package export
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+func testGraph() *validator.TaskGraph {
+	return &validator.TaskGraph{
+		Version:  "0.1.0",
+		Defaults: &validator.Defaults{Acceptance: []string{"go test ./... passes"}},
+		Milestones: []validator.Milestone{
+			{Name: "M1 - Core", TaskIDs: []string{"task-a"}},
+			{Name: "M2 - Extras", DependsOnMilestones: []string{"M1 - Core"}, TaskIDs: []string{"task-b"}},
+		},
+		Tasks: []validator.TaskNode{
+			{
+				TaskID:     "task-a",
+				TaskName:   "Implement parser",
+				Goal:       "Parse() returns a Config for valid input.",
+				Acceptance: []string{"Given an empty file, when Parse runs, then it returns ErrEmpty"},
+				DependsOn:  json.RawMessage(`{"status": "N/A", "reason": "First task"}`),
+				Priority:   "high",
+				Estimate:   "small",
+			},
+			{
+				TaskID:     "task-b",
+				TaskName:   "Implement loader",
+				Goal:       "Load() reads a file and returns its Config.",
+				Acceptance: []string{"Given input 'x', output is 'y'"},
+				DependsOn:  json.RawMessage(`["task-a"]`),
+				Estimate:   "medium",
+			},
+		},
+	}
+}
+
+func TestLookup(t *testing.T) {
+	if _, err := Lookup("gherkin"); err != nil {
+		t.Errorf("Lookup(gherkin) error: %v", err)
+	}
+	if _, err := Lookup("nope"); err == nil {
+		t.Error("expected error for unknown target")
+	}
+}
+
+func TestGherkinSteps(t *testing.T) {
+	tests := []struct {
+		criterion string
+		want      string
+	}{
+		{"Given a user, when they log in, then a session is created", "Given a user|When they log in|Then a session is created"},
+		{"When the cache is cold then the loader hits the DB", "When the cache is cold|Then the loader hits the DB"},
+		{"Given input 'x', output is 'y'", "Given input 'x'|Then output is 'y'"},
+		{"go test ./... passes", "Then go test ./... passes"},
+	}
+	for _, tt := range tests {
+		var parts []string
+		for _, s := range gherkinSteps(tt.criterion) {
+			parts = append(parts, s.keyword+" "+s.text)
+		}
+		if got := strings.Join(parts, "|"); got != tt.want {
+			t.Errorf("gherkinSteps(%q) = %q, want %q", tt.criterion, got, tt.want)
+		}
+	}
+}
+
+func TestGherkinExport(t *testing.T) {
+	files, err := gherkinExporter{}.Export(testGraph(), Options{})
+	if err != nil {
+		t.Fatalf("Export error: %v", err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("expected 2 feature files, got %d", len(files))
+	}
+	if files[0].Name != "task-a.feature" {
+		t.Errorf("file name = %s, want task-a.feature", files[0].Name)
+	}
+
+	feature := string(files[0].Content)
+	for _, want := range []string{
+		"@task-a @priority-high @milestone-m1-core",
+		"Feature: Implement parser",
+		"Scenario: go test ./... passes",
+		"    Given an empty file",
+		"    When Parse runs",
+		"    Then it returns ErrEmpty",
+	} {
+		if !strings.Contains(feature, want) {
+			t.Errorf("feature missing %q\n%s", want, feature)
+		}
+	}
+}
+
+func TestMarkdownExport(t *testing.T) {
+	graph := testGraph()
+	graph.Tasks = append(graph.Tasks, validator.TaskNode{
+		TaskID: "task-c", TaskName: "Write docs", Goal: "Docs exist.", Acceptance: []string{"README documents the loader"},
+	})
+
+	files, err := markdownExporter{}.Export(graph, Options{})
+	if err != nil {
+		t.Fatalf("Export error: %v", err)
+	}
+	if len(files) != 1 || files[0].Name != "TODO.md" {
+		t.Fatalf("expected single TODO.md, got %+v", files)
+	}
+	doc := string(files[0].Content)
+
+	for _, want := range []string{
+		"## M1 - Core\n\n- [ ] **Implement parser** (`task-a`) — priority: high, estimate: small\n  - [ ] go test ./... passes\n",
+		"## M2 - Extras",
+		"## Unassigned\n\n- [ ] **Write docs** (`task-c`)\n  - [ ] go test ./... passes\n  - [ ] README documents the loader\n",
+	} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("TODO.md missing %q\n%s", want, doc)
+		}
+	}
+}
+
+func TestOrgExport(t *testing.T) {
+	files, err := orgExporter{}.Export(testGraph(), Options{})
+	if err != nil {
+		t.Fatalf("Export error: %v", err)
+	}
+	doc := string(files[0].Content)
+	for _, want := range []string{"* M1 - Core", "** TODO Implement parser [/]", ":TASK_ID: task-a", "   - [ ] go test ./... passes"} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("TODO.org missing %q\n%s", want, doc)
+		}
+	}
+}
+
+func TestChecklistDates(t *testing.T) {
+	graph := testGraph()
+	graph.Tasks[0].NotBefore = "2026-03-02"
+	graph.Tasks[0].Due = "2026-03-06"
+
+	files, err := markdownExporter{}.Export(graph, Options{})
+	if err != nil {
+		t.Fatalf("Export error: %v", err)
+	}
+	if doc := string(files[0].Content); !strings.Contains(doc, "estimate: small, due: 2026-03-06") {
+		t.Errorf("TODO.md should show the due date\n%s", doc)
+	}
+
+	files, err = orgExporter{}.Export(graph, Options{})
+	if err != nil {
+		t.Fatalf("Export error: %v", err)
+	}
+	if doc := string(files[0].Content); !strings.Contains(doc, "   :END:\n   SCHEDULED: <2026-03-02 Mon> DEADLINE: <2026-03-06 Fri>\n") {
+		t.Errorf("TODO.org should carry planning dates\n%s", doc)
+	}
+}
+
+func TestLabelTags(t *testing.T) {
+	graph := testGraph()
+	graph.Tasks[0].Labels = []string{"backend", "api-v2"}
+
+	files, err := gherkinExporter{}.Export(graph, Options{})
+	if err != nil {
+		t.Fatalf("Export error: %v", err)
+	}
+	if doc := string(files[0].Content); !strings.HasPrefix(doc, "@task-a @priority-high @milestone-m1-core @backend @api-v2\n") {
+		t.Errorf("feature should carry label tags\n%s", doc)
+	}
+
+	files, err = orgExporter{}.Export(graph, Options{})
+	if err != nil {
+		t.Fatalf("Export error: %v", err)
+	}
+	if doc := string(files[0].Content); !strings.Contains(doc, "** TODO Implement parser [/] :backend:api_v2:\n") {
+		t.Errorf("TODO.org should tag the heading\n%s", doc)
+	}
+}
+
+func TestChecklistAcceptanceBullet(t *testing.T) {
+	files, err := markdownExporter{}.Export(testGraph(), Options{AcceptanceBullet: "- "})
+	if err != nil {
+		t.Fatalf("Export error: %v", err)
+	}
+	doc := string(files[0].Content)
+	if !strings.Contains(doc, "\n  - go test ./... passes\n") || strings.Contains(doc, "  - [ ] go test") {
+		t.Errorf("TODO.md should use plain bullets for acceptance\n%s", doc)
+	}
+}
+
+func TestEstimateLabel(t *testing.T) {
+	opts := Options{EstimateMinutes: testMinutes, FormatEstimate: func(m int) string { return fmt.Sprintf("%dmin", m) }}
+	files, err := markdownExporter{}.Export(testGraph(), opts)
+	if err != nil {
+		t.Fatalf("Export error: %v", err)
+	}
+	if doc := string(files[0].Content); !strings.Contains(doc, "estimate: small (60min)") {
+		t.Errorf("TODO.md should show the converted estimate\n%s", doc)
+	}
+}
+
+func testMinutes(estimate string) int {
+	switch estimate {
+	case "small":
+		return 60
+	case "medium":
+		return 240
+	}
+	return 0
+}
+
+func TestICSExport(t *testing.T) {
+	opts := Options{
+		Start:           time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC), // Monday
+		Workers:         1,
+		EstimateMinutes: testMinutes,
+	}
+	files, err := icsExporter{}.Export(testGraph(), opts)
+	if err != nil {
+		t.Fatalf("Export error: %v", err)
+	}
+	cal := string(files[0].Content)
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"SUMMARY:Milestone: M1 - Core\r\n",
+		"UID:task-task-b@taskval\r\n",
+		"SUMMARY:[critical] Implement loader\r\n",
+		// task-a (1h) then task-b (4h) both fit on Monday.
+		"DTSTART;VALUE=DATE:20260105\r\nDTEND;VALUE=DATE:20260106\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(cal, want) {
+			t.Errorf("calendar missing %q\n%s", want, cal)
+		}
+	}
+
+	if _, err := (icsExporter{}).Export(testGraph(), Options{}); err == nil {
+		t.Error("expected error without estimate conversion")
+	}
+}
+
+func TestICSLineFolding(t *testing.T) {
+	var sb strings.Builder
+	writeICSLine(&sb, "DESCRIPTION:"+strings.Repeat("x", 300))
+	for _, line := range strings.Split(strings.TrimSuffix(sb.String(), "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line exceeds 75 octets: %d", len(line))
+		}
+	}
+}
+
+func TestGraphMLExport(t *testing.T) {
+	files, err := graphmlExporter{}.Export(testGraph(), Options{})
+	if err != nil {
+		t.Fatalf("Export error: %v", err)
+	}
+	doc := string(files[0].Content)
+	for _, want := range []string{
+		`<graph id="tasks" edgedefault="directed">`,
+		`<node id="task-a">`,
+		`<data key="milestone">M1 - Core</data>`,
+		`<edge id="e0" source="task-a" target="task-b"></edge>`,
+	} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("GraphML missing %q\n%s", want, doc)
+		}
+	}
+
+	var parsed graphmlDoc
+	if err := xml.Unmarshal(files[0].Content, &parsed); err != nil {
+		t.Fatalf("GraphML is not well-formed XML: %v", err)
+	}
+}
+
+func TestCytoscapeExport(t *testing.T) {
+	files, err := cytoscapeExporter{}.Export(testGraph(), Options{})
+	if err != nil {
+		t.Fatalf("Export error: %v", err)
+	}
+
+	var doc struct {
+		Elements struct {
+			Nodes []cytoscapeElement `json:"nodes"`
+			Edges []cytoscapeElement `json:"edges"`
+		} `json:"elements"`
+	}
+	if err := json.Unmarshal(files[0].Content, &doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(doc.Elements.Nodes) != 2 || len(doc.Elements.Edges) != 1 {
+		t.Fatalf("got %d nodes, %d edges; want 2, 1", len(doc.Elements.Nodes), len(doc.Elements.Edges))
+	}
+	if e := doc.Elements.Edges[0].Data; e["source"] != "task-a" || e["target"] != "task-b" {
+		t.Errorf("edge = %v, want task-a -> task-b", e)
+	}
+}
+
+func TestAzureDevOpsExport(t *testing.T) {
+	graph := testGraph()
+	// List the dependent first: it must still be created after task-a.
+	graph.Tasks[0], graph.Tasks[1] = graph.Tasks[1], graph.Tasks[0]
+	opts := Options{EstimateMinutes: func(string) int { return 90 }, ProjectURL: "https://dev.azure.com/org/My%20Project/"}
+	files, err := azureExporter{}.Export(graph, opts)
+	if err != nil {
+		t.Fatalf("Export error: %v", err)
+	}
+	var requests []azureRequest
+	if err := json.Unmarshal(files[0].Content, &requests); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	// Epic, two stories (one per milestone), two tasks.
+	var got []string
+	for _, r := range requests {
+		got = append(got, r.URI)
+	}
+	want := []string{
+		"/My%20Project/_apis/wit/workitems/$Epic?api-version=4.1",
+		"/My%20Project/_apis/wit/workitems/$User%20Story?api-version=4.1",
+		"/My%20Project/_apis/wit/workitems/$User%20Story?api-version=4.1",
+		"/My%20Project/_apis/wit/workitems/$Task?api-version=4.1",
+		"/My%20Project/_apis/wit/workitems/$Task?api-version=4.1",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("request URIs =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	body := string(files[0].Content)
+	for _, want := range []string{
+		`"value": "Implement parser"`,
+		`"path": "/fields/Microsoft.VSTS.Scheduling.Effort",` + "\n" + `        "value": 1.5`,
+		`"url": "https://dev.azure.com/org/_apis/wit/workItems/-1"`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("batch missing %q", want)
+		}
+	}
+
+	// task-a (-4) is created first and is task-b's predecessor; task-b
+	// sits under the second story (-3).
+	last := requests[4].Body
+	if last[1].Value != "Implement loader" {
+		t.Fatalf("last task = %v, want Implement loader", last[1].Value)
+	}
+	var rels []string
+	for _, p := range last {
+		if p.Path == "/relations/-" {
+			r := p.Value.(map[string]any)
+			rels = append(rels, fmt.Sprintf("%s %s", r["rel"], r["url"]))
+		}
+	}
+	wantRels := []string{
+		"System.LinkTypes.Hierarchy-Reverse https://dev.azure.com/org/_apis/wit/workItems/-3",
+		"System.LinkTypes.Dependency-Reverse https://dev.azure.com/org/_apis/wit/workItems/-4",
+	}
+	if strings.Join(rels, "\n") != strings.Join(wantRels, "\n") {
+		t.Errorf("task-b relations =\n%s\nwant\n%s", strings.Join(rels, "\n"), strings.Join(wantRels, "\n"))
+	}
+
+	for _, bad := range []string{"", "dev.azure.com/org/proj", "https://dev.azure.com/"} {
+		if _, err := (azureExporter{}).Export(graph, Options{ProjectURL: bad}); err == nil {
+			t.Errorf("ProjectURL %q: expected an error", bad)
+		}
+	}
+}
+
+func TestTrelloExport(t *testing.T) {
+	graph := testGraph()
+	graph.Tasks[1].Priority = "critical"
+	graph.Tasks = append(graph.Tasks, validator.TaskNode{
+		TaskID: "task-c", TaskName: "Write docs", Goal: "README documents Load.", Priority: "high",
+	})
+	files, err := trelloExporter{}.Export(graph, Options{})
+	if err != nil {
+		t.Fatalf("Export error: %v", err)
+	}
+	var board trelloBoard
+	if err := json.Unmarshal(files[0].Content, &board); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	var lists []string
+	for _, l := range board.Lists {
+		lists = append(lists, l.Name)
+	}
+	if got := strings.Join(lists, "|"); got != "M1 - Core|M2 - Extras|Unassigned" {
+		t.Errorf("lists = %s, want one per milestone plus Unassigned", got)
+	}
+	if len(board.Cards) != 3 || board.Cards[1].IDList != board.Lists[1].ID {
+		t.Fatalf("cards = %+v, want task-b in the M2 list", board.Cards)
+	}
+
+	// One label per priority, shared by the cards that have it.
+	if len(board.Labels) != 2 || board.Labels[0].Color != "orange" || board.Labels[1].Name != "priority: critical" || board.Labels[1].Color != "red" {
+		t.Errorf("labels = %+v, want high (orange) and critical (red)", board.Labels)
+	}
+	if board.Cards[0].IDLabels[0] != board.Cards[2].IDLabels[0] {
+		t.Errorf("task-a and task-c should share the high label")
+	}
+
+	// Defaults are applied: task-a has the default criterion plus its own.
+	cl := board.Checklists[0]
+	if cl.IDCard != board.Cards[0].ID || len(cl.CheckItems) != 2 || cl.CheckItems[0].Name != "go test ./... passes" {
+		t.Errorf("task-a checklist = %+v", cl)
+	}
+	if !strings.Contains(board.Cards[1].Desc, "**depends on:** task-a") {
+		t.Errorf("task-b description = %q, want its dependency", board.Cards[1].Desc)
+	}
+}
@@ -0,0 +1,110 @@
+package export
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// gherkinExporter writes one .feature file per task, with one scenario per
+// acceptance criterion.
+type gherkinExporter struct{}
+
+func (gherkinExporter) Name() string { return "gherkin" }
+
+func (gherkinExporter) Export(graph *validator.TaskGraph, _ Options) ([]File, error) {
+	tasks, err := resolvedTasks(graph)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]File, 0, len(tasks))
+	for _, t := range tasks {
+		files = append(files, File{
+			Name:    t.TaskID + ".feature",
+			Content: []byte(renderFeature(graph, t)),
+		})
+	}
+	return files, nil
+}
+
+// renderFeature renders a single task as a Gherkin feature.
+func renderFeature(graph *validator.TaskGraph, t *validator.TaskNode) string {
+	var sb strings.Builder
+
+	tags := []string{"@" + t.TaskID}
+	if t.Priority != "" {
+		tags = append(tags, "@priority-"+string(t.Priority))
+	}
+	if m := graph.MilestoneOf(t.TaskID); m != "" {
+		tags = append(tags, "@milestone-"+tagSafe(m))
+	}
+	for _, l := range t.Labels {
+		tags = append(tags, "@"+l)
+	}
+	sb.WriteString(strings.Join(tags, " ") + "\n")
+	fmt.Fprintf(&sb, "Feature: %s\n", t.TaskName)
+	// Prefix the description so a goal starting with "Given" is not read as a step.
+	fmt.Fprintf(&sb, "  Goal: %s\n", t.Goal)
+
+	for _, criterion := range t.Acceptance {
+		steps := gherkinSteps(criterion)
+		fmt.Fprintf(&sb, "\n  Scenario: %s\n", scenarioTitle(criterion))
+		for _, step := range steps {
+			fmt.Fprintf(&sb, "    %s %s\n", step.keyword, step.text)
+		}
+	}
+	return sb.String()
+}
+
+type gherkinStep struct {
+	keyword string
+	text    string
+}
+
+var (
+	// givenWhenThen matches "Given A, when B, then C" in any casing.
+	givenWhenThen = regexp.MustCompile(`(?i)^\s*given\s+(.+?),?\s+when\s+(.+?),?\s+then\s+(.+)$`)
+	// whenThen matches "When B, then C".
+	whenThen = regexp.MustCompile(`(?i)^\s*when\s+(.+?),?\s+then\s+(.+)$`)
+	// givenComma matches "Given A, C" where C is the expected outcome.
+	givenComma = regexp.MustCompile(`(?i)^\s*given\s+([^,]+),\s*(.+)$`)
+	// whenComma matches "When B, C" where C is the expected outcome.
+	whenComma = regexp.MustCompile(`(?i)^\s*when\s+([^,]+),\s*(.+)$`)
+
+	tagUnsafe = regexp.MustCompile(`[^A-Za-z0-9]+`)
+)
+
+// gherkinSteps derives Given/When/Then steps from an acceptance criterion.
+// Criteria without recognizable structure become a single Then step.
+func gherkinSteps(criterion string) []gherkinStep {
+	if m := givenWhenThen.FindStringSubmatch(criterion); m != nil {
+		return []gherkinStep{{"Given", m[1]}, {"When", m[2]}, {"Then", m[3]}}
+	}
+	if m := whenThen.FindStringSubmatch(criterion); m != nil {
+		return []gherkinStep{{"When", m[1]}, {"Then", m[2]}}
+	}
+	if m := givenComma.FindStringSubmatch(criterion); m != nil {
+		return []gherkinStep{{"Given", m[1]}, {"Then", m[2]}}
+	}
+	if m := whenComma.FindStringSubmatch(criterion); m != nil {
+		return []gherkinStep{{"When", m[1]}, {"Then", m[2]}}
+	}
+	return []gherkinStep{{"Then", criterion}}
+}
+
+// scenarioTitle shortens a criterion to a single-line scenario name.
+func scenarioTitle(criterion string) string {
+	title := strings.Join(strings.Fields(criterion), " ")
+	if len(title) > 100 {
+		title = title[:97] + "..."
+	}
+	return title
+}
+
+// tagSafe converts free text into a Gherkin tag fragment.
+func tagSafe(s string) string {
+	return strings.Trim(tagUnsafe.ReplaceAllString(strings.ToLower(s), "-"), "-")
+}
@@ -0,0 +1,176 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// graphEdges returns dependency edges as (dependency, dependent) pairs,
+// skipping references to tasks that are not in the graph.
+func graphEdges(graph *validator.TaskGraph) [][2]string {
+	var edges [][2]string
+	for _, t := range graph.Tasks {
+		deps, _, err := t.ParseDependsOn()
+		if err != nil {
+			continue
+		}
+		for _, d := range deps {
+			if graph.FindTask(d) != nil {
+				edges = append(edges, [2]string{d, t.TaskID})
+			}
+		}
+	}
+	return edges
+}
+
+// graphmlExporter writes the dependency DAG as GraphML for yEd or Gephi.
+type graphmlExporter struct{}
+
+func (graphmlExporter) Name() string { return "graphml" }
+
+type graphmlDoc struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphmlKey `xml:"key"`
+	Graph   graphmlGraph `xml:"graph"`
+}
+
+type graphmlKey struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+type graphmlGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphmlNode `xml:"node"`
+	Edges       []graphmlEdge `xml:"edge"`
+}
+
+type graphmlNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphmlData `xml:"data"`
+}
+
+type graphmlEdge struct {
+	ID     string `xml:"id,attr"`
+	Source string `xml:"source,attr"`
+	Target string `xml:"target,attr"`
+}
+
+type graphmlData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+func (graphmlExporter) Export(graph *validator.TaskGraph, opts Options) ([]File, error) {
+	doc := graphmlDoc{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphmlKey{
+			{ID: "name", For: "node", AttrName: "task_name", AttrType: "string"},
+			{ID: "goal", For: "node", AttrName: "goal", AttrType: "string"},
+			{ID: "milestone", For: "node", AttrName: "milestone", AttrType: "string"},
+			{ID: "priority", For: "node", AttrName: "priority", AttrType: "string"},
+			{ID: "estimate", For: "node", AttrName: "estimate", AttrType: "string"},
+			{ID: "not_before", For: "node", AttrName: "not_before", AttrType: "string"},
+			{ID: "due", For: "node", AttrName: "due", AttrType: "string"},
+			{ID: "labels", For: "node", AttrName: "labels", AttrType: "string"},
+		},
+		Graph: graphmlGraph{ID: "tasks", EdgeDefault: "directed"},
+	}
+
+	for _, t := range graph.Tasks {
+		node := graphmlNode{ID: t.TaskID}
+		for _, kv := range [][2]string{
+			{"name", t.TaskName},
+			{"goal", t.Goal},
+			{"milestone", graph.MilestoneOf(t.TaskID)},
+			{"priority", string(t.Priority)},
+			{"estimate", opts.estimateLabel(string(t.Estimate))},
+			{"not_before", t.NotBefore},
+			{"due", t.Due},
+			{"labels", strings.Join(t.Labels, ",")},
+		} {
+			if kv[1] != "" {
+				node.Data = append(node.Data, graphmlData{Key: kv[0], Value: kv[1]})
+			}
+		}
+		doc.Graph.Nodes = append(doc.Graph.Nodes, node)
+	}
+	for i, e := range graphEdges(graph) {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphmlEdge{ID: fmt.Sprintf("e%d", i), Source: e[0], Target: e[1]})
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return nil, fmt.Errorf("encoding GraphML: %w", err)
+	}
+	buf.WriteString("\n")
+	return []File{{Name: "tasks.graphml", Content: buf.Bytes()}}, nil
+}
+
+// cytoscapeExporter writes the DAG in Cytoscape.js elements JSON format.
+type cytoscapeExporter struct{}
+
+func (cytoscapeExporter) Name() string { return "cytoscape" }
+
+type cytoscapeElement struct {
+	Data map[string]string `json:"data"`
+}
+
+func (cytoscapeExporter) Export(graph *validator.TaskGraph, opts Options) ([]File, error) {
+	var doc struct {
+		Elements struct {
+			Nodes []cytoscapeElement `json:"nodes"`
+			Edges []cytoscapeElement `json:"edges"`
+		} `json:"elements"`
+	}
+	doc.Elements.Nodes = []cytoscapeElement{}
+	doc.Elements.Edges = []cytoscapeElement{}
+
+	for _, t := range graph.Tasks {
+		data := map[string]string{"id": t.TaskID, "name": t.TaskName, "goal": t.Goal}
+		if m := graph.MilestoneOf(t.TaskID); m != "" {
+			data["milestone"] = m
+		}
+		if t.Priority != "" {
+			data["priority"] = string(t.Priority)
+		}
+		if t.Estimate != "" {
+			data["estimate"] = opts.estimateLabel(string(t.Estimate))
+		}
+		if t.NotBefore != "" {
+			data["not_before"] = t.NotBefore
+		}
+		if t.Due != "" {
+			data["due"] = t.Due
+		}
+		if len(t.Labels) > 0 {
+			data["labels"] = strings.Join(t.Labels, ",")
+		}
+		doc.Elements.Nodes = append(doc.Elements.Nodes, cytoscapeElement{Data: data})
+	}
+	for _, e := range graphEdges(graph) {
+		doc.Elements.Edges = append(doc.Elements.Edges, cytoscapeElement{Data: map[string]string{
+			"id":     e[0] + "->" + e[1],
+			"source": e[0],
+			"target": e[1],
+		}})
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encoding Cytoscape JSON: %w", err)
+	}
+	return []File{{Name: "tasks.cyjs", Content: append(data, '\n')}}, nil
+}
@@ -0,0 +1,137 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nixlim/task_templating/internal/schedule"
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// icsExporter writes an iCalendar file with one all-day event per milestone
+// and per critical-path task, dated by the computed schedule.
+type icsExporter struct{}
+
+func (icsExporter) Name() string { return "ics" }
+
+func (icsExporter) Export(graph *validator.TaskGraph, opts Options) ([]File, error) {
+	if opts.EstimateMinutes == nil {
+		return nil, fmt.Errorf("ics export requires an estimate conversion")
+	}
+	start := opts.Start
+	if start.IsZero() {
+		start = time.Now()
+	}
+
+	sched, err := schedule.Compute(graph, schedule.Options{
+		Workers:        opts.Workers,
+		Minutes:        opts.EstimateMinutes,
+		UnknownMinutes: opts.EstimateMinutes("medium"),
+	})
+	if err != nil {
+		return nil, err
+	}
+	cal := schedule.Calendar{Start: start, HoursPerDay: 8}
+	stamp := cal.Date(0, false).Format("20060102T150405Z")
+
+	var sb strings.Builder
+	writeICSLine(&sb, "BEGIN:VCALENDAR")
+	writeICSLine(&sb, "VERSION:2.0")
+	writeICSLine(&sb, "PRODID:-//taskval//task graph schedule//EN")
+	writeICSLine(&sb, "CALSCALE:GREGORIAN")
+
+	for _, m := range graph.Milestones {
+		first, last := -1, -1
+		for _, id := range m.TaskIDs {
+			slot := sched.Slot(id)
+			if slot == nil {
+				continue
+			}
+			if first < 0 || slot.Start < first {
+				first = slot.Start
+			}
+			if slot.End > last {
+				last = slot.End
+			}
+		}
+		if first < 0 {
+			continue
+		}
+		writeICSEvent(&sb, icsEvent{
+			UID:         "milestone-" + tagSafe(m.Name) + "@taskval",
+			Stamp:       stamp,
+			Start:       cal.Date(first, false),
+			End:         cal.Date(last, true),
+			Summary:     "Milestone: " + m.Name,
+			Description: fmt.Sprintf("%d task(s): %s", len(m.TaskIDs), strings.Join(m.TaskIDs, ", ")),
+		})
+	}
+
+	for _, id := range sched.CriticalPath {
+		slot := sched.Slot(id)
+		task := graph.FindTask(id)
+		if slot == nil || task == nil {
+			continue
+		}
+		writeICSEvent(&sb, icsEvent{
+			UID:         "task-" + id + "@taskval",
+			Stamp:       stamp,
+			Start:       cal.Date(slot.Start, false),
+			End:         cal.Date(slot.End, true),
+			Summary:     "[critical] " + task.TaskName,
+			Description: task.Goal,
+			Categories:  task.Labels,
+		})
+	}
+
+	writeICSLine(&sb, "END:VCALENDAR")
+	return []File{{Name: "schedule.ics", Content: []byte(sb.String())}}, nil
+}
+
+type icsEvent struct {
+	UID         string
+	Stamp       string
+	Start, End  time.Time
+	Summary     string
+	Description string
+	Categories  []string
+}
+
+// writeICSEvent writes an all-day VEVENT. DTEND is exclusive, so it is the
+// day after the last working day.
+func writeICSEvent(sb *strings.Builder, e icsEvent) {
+	writeICSLine(sb, "BEGIN:VEVENT")
+	writeICSLine(sb, "UID:"+e.UID)
+	writeICSLine(sb, "DTSTAMP:"+e.Stamp)
+	writeICSLine(sb, "DTSTART;VALUE=DATE:"+e.Start.Format("20060102"))
+	writeICSLine(sb, "DTEND;VALUE=DATE:"+e.End.AddDate(0, 0, 1).Format("20060102"))
+	writeICSLine(sb, "SUMMARY:"+icsEscape(e.Summary))
+	writeICSLine(sb, "DESCRIPTION:"+icsEscape(e.Description))
+	if len(e.Categories) > 0 {
+		writeICSLine(sb, "CATEGORIES:"+strings.Join(e.Categories, ","))
+	}
+	writeICSLine(sb, "END:VEVENT")
+}
+
+// writeICSLine writes a content line with CRLF endings, folding lines longer
+// than 75 octets as required by RFC 5545.
+func writeICSLine(sb *strings.Builder, line string) {
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut-- // Do not split a UTF-8 sequence.
+		}
+		sb.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		limit = 74 // Continuation lines start with a space.
+	}
+	sb.WriteString(line + "\r\n")
+}
+
+// icsEscape escapes TEXT values per RFC 5545.
+func icsEscape(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+	return r.Replace(s)
+}
@@ -0,0 +1,167 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// trelloExporter writes a board in the JSON format of Trello's own board
+// export: a list per milestone, a card per task with its acceptance
+// criteria as a checklist, and a label per priority.
+type trelloExporter struct{}
+
+func (trelloExporter) Name() string { return "trello" }
+
+type trelloBoard struct {
+	Name       string            `json:"name"`
+	Labels     []trelloLabel     `json:"labels"`
+	Lists      []trelloList      `json:"lists"`
+	Cards      []trelloCard      `json:"cards"`
+	Checklists []trelloChecklist `json:"checklists"`
+}
+
+type trelloLabel struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+type trelloList struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Closed bool   `json:"closed"`
+	Pos    int    `json:"pos"`
+}
+
+type trelloCard struct {
+	ID           string   `json:"id"`
+	Name         string   `json:"name"`
+	Desc         string   `json:"desc"`
+	IDList       string   `json:"idList"`
+	IDLabels     []string `json:"idLabels"`
+	IDChecklists []string `json:"idChecklists"`
+	Due          string   `json:"due,omitempty"`
+	Closed       bool     `json:"closed"`
+	Pos          int      `json:"pos"`
+}
+
+type trelloChecklist struct {
+	ID         string            `json:"id"`
+	IDCard     string            `json:"idCard"`
+	Name       string            `json:"name"`
+	CheckItems []trelloCheckItem `json:"checkItems"`
+}
+
+type trelloCheckItem struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	State string `json:"state"`
+	Pos   int    `json:"pos"`
+}
+
+// trelloPosStep spaces positions the way Trello does, leaving room to
+// insert between items.
+const trelloPosStep = 16384
+
+// trelloColors maps priorities, named or bd's 0-4, to label colors.
+var trelloColors = map[string]string{
+	"critical": "red", "0": "red",
+	"high": "orange", "1": "orange",
+	"medium": "yellow", "2": "yellow",
+	"low": "green", "3": "green",
+	"4": "blue",
+}
+
+func (trelloExporter) Export(graph *validator.TaskGraph, opts Options) ([]File, error) {
+	groups, err := groupByMilestone(graph)
+	if err != nil {
+		return nil, err
+	}
+
+	board := trelloBoard{
+		Name:       "Task Graph",
+		Labels:     []trelloLabel{},
+		Lists:      []trelloList{},
+		Cards:      []trelloCard{},
+		Checklists: []trelloChecklist{},
+	}
+	if len(graph.Milestones) > 0 {
+		board.Name = "Task Graph: " + graph.Milestones[0].Name
+	}
+	// Trello IDs are 24 hex digits; number objects in creation order.
+	n := 0
+	newID := func() string {
+		n++
+		return fmt.Sprintf("%024x", n)
+	}
+
+	labelIDs := make(map[string]string)
+	for i, g := range groups {
+		list := trelloList{ID: newID(), Name: g.Name, Pos: (i + 1) * trelloPosStep}
+		board.Lists = append(board.Lists, list)
+		for j, t := range g.Tasks {
+			card := trelloCard{
+				ID:           newID(),
+				Name:         t.TaskName,
+				Desc:         trelloDescription(t, opts),
+				IDList:       list.ID,
+				IDLabels:     []string{},
+				IDChecklists: []string{},
+				Pos:          (j + 1) * trelloPosStep,
+			}
+			if d, err := validator.ParseDate(t.Due); t.Due != "" && err == nil {
+				card.Due = d.UTC().Format("2006-01-02T15:04:05.000Z")
+			}
+			if p := string(t.Priority); p != "" {
+				id, ok := labelIDs[p]
+				if !ok {
+					id = newID()
+					labelIDs[p] = id
+					color := trelloColors[strings.ToLower(p)]
+					if color == "" {
+						color = "sky"
+					}
+					board.Labels = append(board.Labels, trelloLabel{ID: id, Name: "priority: " + p, Color: color})
+				}
+				card.IDLabels = append(card.IDLabels, id)
+			}
+			if len(t.Acceptance) > 0 {
+				cl := trelloChecklist{ID: newID(), IDCard: card.ID, Name: "Acceptance criteria"}
+				for k, a := range t.Acceptance {
+					cl.CheckItems = append(cl.CheckItems, trelloCheckItem{ID: newID(), Name: a, State: "incomplete", Pos: (k + 1) * trelloPosStep})
+				}
+				board.Checklists = append(board.Checklists, cl)
+				card.IDChecklists = append(card.IDChecklists, cl.ID)
+			}
+			board.Cards = append(board.Cards, card)
+		}
+	}
+
+	out, err := json.MarshalIndent(board, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encoding Trello board: %w", err)
+	}
+	return []File{{Name: "trello.json", Content: append(out, '\n')}}, nil
+}
+
+// trelloDescription renders the card description in Trello's markdown:
+// the goal, then the task ID, estimate, labels, and dependencies.
+func trelloDescription(t *validator.TaskNode, opts Options) string {
+	var sb strings.Builder
+	sb.WriteString(t.Goal)
+	sb.WriteString("\n\n")
+	fmt.Fprintf(&sb, "**task_id:** `%s`", t.TaskID)
+	if t.Estimate != "" {
+		fmt.Fprintf(&sb, "\n**estimate:** %s", opts.estimateLabel(string(t.Estimate)))
+	}
+	if len(t.Labels) > 0 {
+		fmt.Fprintf(&sb, "\n**labels:** %s", strings.Join(t.Labels, ", "))
+	}
+	if deps, _, err := t.ParseDependsOn(); err == nil && len(deps) > 0 {
+		fmt.Fprintf(&sb, "\n**depends on:** %s", strings.Join(deps, ", "))
+	}
+	return sb.String()
+}
@@ -0,0 +1,322 @@
+// Package grpcserver serves the TaskVal gRPC service of proto/taskval.proto.
+// It speaks the gRPC protocol over the standard library's HTTP/2 server,
+// with messages encoded by protoreport, so taskval needs no gRPC runtime;
+// clients generated from the .proto file call it as any gRPC server.
+package grpcserver
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/nixlim/task_templating/internal/beads"
+	"github.com/nixlim/task_templating/internal/protoreport"
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// servicePath prefixes the request path of every TaskVal method.
+const servicePath = "/taskval.v1.TaskVal/"
+
+// DefaultMaxMessageSize bounds a request message when Server sets no limit.
+const DefaultMaxMessageSize = 50 << 20
+
+// gRPC status codes returned by the service.
+const (
+	codeOK                 = 0
+	codeInvalidArgument    = 3
+	codeResourceExhausted  = 8
+	codeFailedPrecondition = 9
+	codeAborted            = 10
+	codeUnimplemented      = 12
+	codeUnavailable        = 14
+)
+
+// Server is an http.Handler serving the TaskVal service. Serve it over
+// HTTP/2; gRPC clients connect without TLS unless the http.Server has it.
+type Server struct {
+	// Backend runs the bd commands of PlanBeads and ApplyPlan. Calls that
+	// use it run one at a time, as bd writes to a single database.
+	Backend beads.Backend
+
+	// MaxMessageSize bounds a request message in bytes; 0 means
+	// DefaultMaxMessageSize.
+	MaxMessageSize int64
+
+	mu sync.Mutex
+}
+
+// statusError is a call's non-OK gRPC status.
+type statusError struct {
+	code int
+	msg  string
+}
+
+func (e *statusError) Error() string { return e.msg }
+
+func errorf(code int, format string, args ...any) error {
+	return &statusError{code: code, msg: fmt.Sprintf(format, args...)}
+}
+
+// ServeHTTP handles one gRPC call: it reads the request message, writes
+// each response message as it is ready, and ends with the status trailers.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || !strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+		http.Error(w, "taskval serves gRPC only: POST application/grpc to "+servicePath+"<method>", http.StatusUnsupportedMediaType)
+		return
+	}
+	w.Header().Set("Content-Type", "application/grpc")
+	rc := http.NewResponseController(w)
+	send := func(msg []byte) error {
+		frame := make([]byte, 5, 5+len(msg))
+		binary.BigEndian.PutUint32(frame[1:], uint32(len(msg)))
+		if _, err := w.Write(append(frame, msg...)); err != nil {
+			return err
+		}
+		return rc.Flush()
+	}
+
+	err := s.call(r, strings.TrimPrefix(r.URL.Path, servicePath), send)
+	code, msg := codeOK, ""
+	if err != nil {
+		code, msg = codeAborted, err.Error()
+		if se, ok := err.(*statusError); ok {
+			code = se.code
+		}
+	}
+	w.Header().Set(http.TrailerPrefix+"Grpc-Status", strconv.Itoa(code))
+	if msg != "" {
+		w.Header().Set(http.TrailerPrefix+"Grpc-Message", percentEncode(msg))
+	}
+}
+
+// call reads the request of the named method and runs it.
+func (s *Server) call(r *http.Request, method string, send func([]byte) error) error {
+	var handle func(msg []byte, send func([]byte) error) error
+	switch method {
+	case "Validate":
+		handle = s.validate
+	case "PlanBeads":
+		handle = s.planBeads
+	case "ApplyPlan":
+		handle = s.applyPlan
+	default:
+		return errorf(codeUnimplemented, "unknown method '%s'", r.URL.Path)
+	}
+	msg, err := s.readMessage(r.Body)
+	if err != nil {
+		return err
+	}
+	return handle(msg, send)
+}
+
+// readMessage reads one length-prefixed request message.
+func (s *Server) readMessage(body io.Reader) ([]byte, error) {
+	var header [5]byte
+	if _, err := io.ReadFull(body, header[:]); err != nil {
+		return nil, errorf(codeInvalidArgument, "reading request: %s", err)
+	}
+	if header[0] != 0 {
+		return nil, errorf(codeUnimplemented, "compressed requests are not supported")
+	}
+	size := int64(binary.BigEndian.Uint32(header[1:]))
+	limit := s.MaxMessageSize
+	if limit <= 0 {
+		limit = DefaultMaxMessageSize
+	}
+	if size > limit {
+		return nil, errorf(codeResourceExhausted, "request of %d bytes is over the %d byte limit", size, limit)
+	}
+	msg := make([]byte, size)
+	if _, err := io.ReadFull(body, msg); err != nil {
+		return nil, errorf(codeInvalidArgument, "reading request: %s", err)
+	}
+	return msg, nil
+}
+
+func (s *Server) validate(msg []byte, send func([]byte) error) error {
+	req, err := protoreport.UnmarshalValidateRequest(msg)
+	if err != nil {
+		return errorf(codeInvalidArgument, "decoding ValidateRequest: %s", err)
+	}
+	result, _, err := check(req)
+	if err != nil {
+		return err
+	}
+	return send(protoreport.Marshal(result, nil, nil, nil))
+}
+
+func (s *Server) planBeads(msg []byte, send func([]byte) error) error {
+	req, err := protoreport.UnmarshalPlanRequest(msg)
+	if err != nil {
+		return errorf(codeInvalidArgument, "decoding PlanRequest: %s", err)
+	}
+	result, mode, err := check(req.Validation)
+	if err != nil {
+		return err
+	}
+	if !result.Valid {
+		return send(protoreport.Marshal(result, nil, nil, nil))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cmds, err := s.plan(result, mode, req, true)
+	if err != nil {
+		return err
+	}
+	return send(protoreport.Marshal(result, nil, beads.FormatDryRunJSON(cmds), nil))
+}
+
+func (s *Server) applyPlan(msg []byte, send func([]byte) error) error {
+	req, err := protoreport.UnmarshalPlanRequest(msg)
+	if err != nil {
+		return errorf(codeInvalidArgument, "decoding PlanRequest: %s", err)
+	}
+	result, mode, err := check(req.Validation)
+	if err != nil {
+		return err
+	}
+	if !result.Valid {
+		if err := send(protoreport.MarshalResult(protoreport.Marshal(result, nil, nil, nil))); err != nil {
+			return err
+		}
+		return errorf(codeFailedPrecondition, "the plan failed validation; no issues were created")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cmds, err := s.plan(result, mode, req, false)
+	if err != nil {
+		return err
+	}
+
+	// A client that goes away does not stop creation half way; the
+	// remaining events are dropped.
+	total, index := runnable(cmds), 0
+	var sendErr error
+	observe := func(ev beads.CommandEvent) {
+		index++
+		if sendErr == nil {
+			sendErr = send(protoreport.MarshalCommandDone(index, total, ev))
+		}
+	}
+	created, err := beads.ExecuteObserved(s.Backend, cmds, observe)
+	if sendErr != nil {
+		return sendErr
+	}
+	var partial *beads.BeadsJSON
+	if created != nil {
+		partial = beads.FormatJSONOutput(created)
+	}
+	if err != nil {
+		if sendErr := send(protoreport.MarshalResult(protoreport.Marshal(result, partial, nil, beads.FormatErrorJSON(err)))); sendErr != nil {
+			return sendErr
+		}
+		return errorf(codeAborted, "%s", err)
+	}
+	return send(protoreport.MarshalResult(protoreport.Marshal(result, partial, nil, nil)))
+}
+
+// check validates the request's document, as taskval does a file.
+func check(req protoreport.ValidateRequest) (*validator.ValidationResult, validator.Mode, error) {
+	var mode validator.Mode
+	switch req.Mode {
+	case protoreport.ModeGraph:
+		mode = validator.ModeTaskGraph
+	case protoreport.ModeTask:
+		mode = validator.ModeSingleTask
+	default:
+		return nil, 0, errorf(codeInvalidArgument, "unknown mode %d", req.Mode)
+	}
+	rules, err := validator.Profile(req.Profile)
+	if err != nil {
+		return nil, 0, errorf(codeInvalidArgument, "%s", err)
+	}
+	result, err := validator.ValidateWithOptions(req.Document, mode, validator.Options{Rules: rules})
+	if err != nil {
+		return nil, 0, errorf(codeInvalidArgument, "%s", err)
+	}
+	return result, mode, nil
+}
+
+// plan builds the bd commands for a validated plan, as --create-beads
+// does, applying the duplicate policy against the open issues.
+func (s *Server) plan(result *validator.ValidationResult, mode validator.Mode, req protoreport.PlanRequest, dryRun bool) ([]beads.BdCommand, error) {
+	switch req.OnDuplicate {
+	case "", beads.OnDuplicateSkip, beads.OnDuplicateUpdate, beads.OnDuplicateError:
+	default:
+		return nil, errorf(codeInvalidArgument, "invalid on_duplicate '%s'. Must be 'skip', 'update', or 'error'", req.OnDuplicate)
+	}
+	if !dryRun || req.OnDuplicate != "" {
+		if err := s.Backend.Check(); err != nil {
+			return nil, errorf(codeUnavailable, "%s", err)
+		}
+	}
+
+	creator := &beads.Creator{
+		DryRun:    dryRun,
+		EpicTitle: req.EpicTitle,
+		Filename:  req.Validation.Filename,
+	}
+	if req.AttachReport {
+		creator.Report = result
+	}
+	var cmds []beads.BdCommand
+	var err error
+	if mode == validator.ModeSingleTask {
+		cmds, err = creator.BuildSingleTaskCommands(&result.Graph.Tasks[0])
+	} else {
+		cmds, err = creator.BuildGraphCommands(result.Graph)
+	}
+	if err != nil {
+		return nil, errorf(codeInvalidArgument, "building commands: %s", err)
+	}
+
+	if req.OnDuplicate != "" {
+		existing, err := beads.ListOpenIssues(s.Backend)
+		if err != nil {
+			return nil, errorf(codeUnavailable, "%s", err)
+		}
+		cmds, err = beads.ResolveDuplicates(cmds, beads.FindDuplicates(cmds, existing), req.OnDuplicate)
+		if err != nil {
+			return nil, errorf(codeFailedPrecondition, "%s", err)
+		}
+	}
+	if err := beads.CheckPlaceholders(cmds); err != nil {
+		return nil, errorf(codeInvalidArgument, "%s", err)
+	}
+	return cmds, nil
+}
+
+// runnable counts the commands that run bd, leaving out the entries that
+// only record reuse or omission.
+func runnable(cmds []beads.BdCommand) int {
+	n := 0
+	for _, cmd := range cmds {
+		switch cmd.Type {
+		case "existing-epic", "existing-task", "excluded-task", "pruned-dep":
+		default:
+			n++
+		}
+	}
+	return n
+}
+
+// percentEncode escapes a grpc-message value: bytes outside printable
+// ASCII, and '%', become %XX.
+func percentEncode(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < ' ' || c > '~' || c == '%' {
+			fmt.Fprintf(&sb, "%%%02X", c)
+		} else {
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,208 @@
+package grpcserver
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// fakeBackend hands out sequential issue IDs and fails the create command
+// whose title is failTitle.
+type fakeBackend struct {
+	calls     [][]string
+	failTitle string
+}
+
+func (f *fakeBackend) Check() error { return nil }
+
+func (f *fakeBackend) Output(args []string) (string, error) { return "[]", nil }
+
+func (f *fakeBackend) Run(args []string) (string, error) {
+	f.calls = append(f.calls, args)
+	if args[0] != "create" {
+		return "", nil
+	}
+	for i, a := range args {
+		if a == "--title" && f.failTitle != "" && args[i+1] == f.failTitle {
+			return "", errors.New("database is locked")
+		}
+	}
+	return fmt.Sprintf("bd-%d", len(f.calls)), nil
+}
+
+// invalidPlan fails V4.
+const invalidPlan = `{"version": "0.1.0", "tasks": [{"task_id": "x", "depends_on": ["gone"]}]}`
+
+const plan = `{"version": "0.1.0", "tasks": [
+  {"task_id": "store", "task_name": "Implement the store", "goal": "Store.Get returns the saved value.",
+   "inputs": [{"name": "key", "type": "string", "constraints": "non-empty", "source": "caller"}],
+   "outputs": [{"name": "value", "type": "string", "constraints": "none", "destination": "return value"}],
+   "acceptance": ["Get returns the value saved by Put"], "depends_on": {"status": "N/A", "reason": "First task"},
+   "files_scope": ["store.go"]},
+  {"task_id": "api", "task_name": "Implement the API", "goal": "GET /v1/items/{key} returns the stored value.",
+   "inputs": [{"name": "key", "type": "string", "constraints": "non-empty", "source": "URL path"}],
+   "outputs": [{"name": "body", "type": "JSON", "constraints": "none", "destination": "HTTP response"}],
+   "acceptance": ["GET returns 200 and the value for a saved key"], "depends_on": ["store"],
+   "files_scope": ["api.go"]}
+]}`
+
+// bytesField encodes a length-delimited field of a request message.
+func bytesField(num int, b []byte) []byte {
+	out := binary.AppendUvarint(nil, uint64(num)<<3|2)
+	out = binary.AppendUvarint(out, uint64(len(b)))
+	return append(out, b...)
+}
+
+// validateRequest encodes a ValidateRequest for doc in graph mode.
+func validateRequest(doc string) []byte {
+	return bytesField(1, []byte(doc))
+}
+
+// call runs method on srv over unencrypted HTTP/2 and returns the response
+// messages and the grpc-status trailer.
+func call(t *testing.T, srv *httptest.Server, method string, req []byte) ([][]byte, string) {
+	t.Helper()
+	frame := make([]byte, 5, 5+len(req))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(req)))
+	httpReq, err := http.NewRequest(http.MethodPost, srv.URL+servicePath+method, bytes.NewReader(append(frame, req...)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpReq.Header.Set("Content-Type", "application/grpc")
+	resp, err := srv.Client().Do(httpReq)
+	if err != nil {
+		t.Fatalf("%s: %v", method, err)
+	}
+	defer resp.Body.Close()
+	if resp.ProtoMajor != 2 {
+		t.Fatalf("%s: served over %s, want HTTP/2", method, resp.Proto)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var msgs [][]byte
+	for len(body) >= 5 {
+		n := binary.BigEndian.Uint32(body[1:5])
+		msgs = append(msgs, body[5:5+n])
+		body = body[5+n:]
+	}
+	return msgs, resp.Trailer.Get("Grpc-Status")
+}
+
+// fields decodes the top-level fields of msg by number: varints as their
+// value, length-delimited fields as their bytes.
+func fields(t *testing.T, msg []byte) map[int][]any {
+	t.Helper()
+	out := make(map[int][]any)
+	for len(msg) > 0 {
+		key, n := binary.Uvarint(msg)
+		msg = msg[n:]
+		v, n := binary.Uvarint(msg)
+		msg = msg[n:]
+		if key&7 == 2 {
+			out[int(key>>3)] = append(out[int(key>>3)], msg[:v])
+			msg = msg[v:]
+		} else {
+			out[int(key>>3)] = append(out[int(key>>3)], v)
+		}
+	}
+	return out
+}
+
+func newServer(t *testing.T, backend *fakeBackend) *httptest.Server {
+	srv := httptest.NewUnstartedServer(&Server{Backend: backend})
+	srv.Config.Protocols = new(http.Protocols)
+	srv.Config.Protocols.SetUnencryptedHTTP2(true)
+	srv.Start()
+	t.Cleanup(srv.Close)
+	srv.Client().Transport = &http.Transport{Protocols: srv.Config.Protocols}
+	return srv
+}
+
+func TestValidate(t *testing.T) {
+	srv := newServer(t, &fakeBackend{})
+
+	msgs, status := call(t, srv, "Validate", validateRequest(plan))
+	if status != "0" || len(msgs) != 1 {
+		t.Fatalf("Validate: status %s, %d messages", status, len(msgs))
+	}
+	if report := fields(t, msgs[0]); len(report[1]) != 1 || report[1][0] != uint64(1) {
+		t.Errorf("Validate: report is not valid: %v", report)
+	}
+
+	msgs, status = call(t, srv, "Validate", validateRequest(invalidPlan))
+	if status != "0" || len(msgs) != 1 || len(fields(t, msgs[0])[3]) == 0 {
+		t.Errorf("Validate of an invalid plan: status %s, want OK and a report with findings", status)
+	}
+
+	if _, status := call(t, srv, "Validate", []byte{0xff}); status != "3" {
+		t.Errorf("malformed request: status %s, want INVALID_ARGUMENT", status)
+	}
+	if _, status := call(t, srv, "Delete", nil); status != "12" {
+		t.Errorf("unknown method: status %s, want UNIMPLEMENTED", status)
+	}
+}
+
+func TestPlanBeads(t *testing.T) {
+	backend := &fakeBackend{}
+	srv := newServer(t, backend)
+
+	req := append(bytesField(1, validateRequest(plan)), bytesField(2, []byte("Items service"))...)
+	msgs, status := call(t, srv, "PlanBeads", req)
+	if status != "0" || len(msgs) != 1 {
+		t.Fatalf("PlanBeads: status %s, %d messages", status, len(msgs))
+	}
+	dryRun := fields(t, msgs[0])[6]
+	if len(dryRun) != 1 || !bytes.Contains(dryRun[0].([]byte), []byte("Items service")) {
+		t.Errorf("PlanBeads: dry_run = %q, want the planned commands with the epic title", dryRun)
+	}
+	if len(backend.calls) != 0 {
+		t.Errorf("PlanBeads ran %d bd commands, want none", len(backend.calls))
+	}
+}
+
+func TestApplyPlan(t *testing.T) {
+	backend := &fakeBackend{}
+	srv := newServer(t, backend)
+
+	msgs, status := call(t, srv, "ApplyPlan", bytesField(1, validateRequest(plan)))
+	if status != "0" {
+		t.Fatalf("ApplyPlan: status %s", status)
+	}
+	// One CommandDone per bd command, then the report.
+	if len(msgs) != len(backend.calls)+1 {
+		t.Fatalf("ApplyPlan: %d messages for %d bd commands, want one each and the report", len(msgs), len(backend.calls))
+	}
+	first := fields(t, fields(t, msgs[0])[1][0].([]byte))
+	if first[1][0] != uint64(1) || first[2][0] != uint64(len(backend.calls)) || string(first[4][0].([]byte)) != "bd-1" {
+		t.Errorf("first CommandDone = %v, want index 1 of %d creating bd-1", first, len(backend.calls))
+	}
+	result := fields(t, msgs[len(msgs)-1])[2]
+	if len(result) != 1 || len(fields(t, result[0].([]byte))[5]) != 1 {
+		t.Errorf("ApplyPlan should end with a Report carrying beads, got %v", fields(t, msgs[len(msgs)-1]))
+	}
+
+	// A failed bd command ends the stream with beads_error and ABORTED.
+	failing := &fakeBackend{failTitle: "Implement the API"}
+	msgs, status = call(t, newServer(t, failing), "ApplyPlan", bytesField(1, validateRequest(plan)))
+	if status != "10" {
+		t.Errorf("failed creation: status %s, want ABORTED", status)
+	}
+	last := fields(t, msgs[len(msgs)-1])[2]
+	if len(last) != 1 || len(fields(t, last[0].([]byte))[7]) != 1 {
+		t.Errorf("failed creation should end with a Report carrying beads_error")
+	}
+
+	// An invalid plan creates nothing.
+	backend = &fakeBackend{}
+	msgs, status = call(t, newServer(t, backend), "ApplyPlan", bytesField(1, validateRequest(invalidPlan)))
+	if status != "9" || len(msgs) != 1 || len(backend.calls) != 0 {
+		t.Errorf("invalid plan: status %s, %d messages, %d bd commands; want FAILED_PRECONDITION, the report, and none run", status, len(msgs), len(backend.calls))
+	}
+}
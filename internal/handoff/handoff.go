@@ -0,0 +1,162 @@
+// Package handoff renders self-contained markdown briefs for individual tasks
+// in a validated graph, suitable for pasting into a coding agent session.
+package handoff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// Brief renders the markdown handoff document for one task. Graph defaults
+// are merged in and the goals and outputs of direct dependencies are
+// included so the brief can be read without the rest of the graph.
+func Brief(graph *validator.TaskGraph, taskID string) (string, error) {
+	task, err := graph.ResolvedTask(taskID)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s\n\n", task.TaskName)
+	fmt.Fprintf(&sb, "- **Task ID:** `%s`\n", task.TaskID)
+	if m := graph.MilestoneOf(task.TaskID); m != "" {
+		fmt.Fprintf(&sb, "- **Milestone:** %s\n", m)
+	}
+	if task.Priority != "" {
+		fmt.Fprintf(&sb, "- **Priority:** %s\n", task.Priority)
+	}
+	if task.Estimate != "" {
+		fmt.Fprintf(&sb, "- **Estimate:** %s\n", task.Estimate)
+	}
+	if task.NotBefore != "" {
+		fmt.Fprintf(&sb, "- **Not before:** %s\n", task.NotBefore)
+	}
+	if task.Due != "" {
+		fmt.Fprintf(&sb, "- **Due:** %s\n", task.Due)
+	}
+	if task.Risk != nil {
+		fmt.Fprintf(&sb, "- **Risk:** %s\n", task.Risk.Level)
+		if task.Risk.Mitigation != "" {
+			fmt.Fprintf(&sb, "- **Mitigation:** %s\n", task.Risk.Mitigation)
+		}
+	}
+
+	sb.WriteString("\n## Goal\n\n")
+	sb.WriteString(task.Goal + "\n")
+
+	sb.WriteString("\n## Inputs\n\n")
+	for _, in := range task.Inputs {
+		fmt.Fprintf(&sb, "- **%s** (`%s`): %s -- Source: %s\n", in.Name, in.Type, in.Constraints, in.Source)
+	}
+
+	sb.WriteString("\n## Outputs\n\n")
+	for _, out := range task.Outputs {
+		fmt.Fprintf(&sb, "- **%s** (`%s`): %s -- Dest: %s\n", out.Name, out.Type, out.Constraints, out.Destination)
+	}
+
+	if constraints, _, err := task.ParseConstraints(); err == nil && len(constraints) > 0 {
+		sb.WriteString("\n## Constraints\n\n")
+		for _, c := range constraints {
+			fmt.Fprintf(&sb, "- %s\n", c)
+		}
+	}
+
+	sb.WriteString("\n## Files in Scope\n\n")
+	files, na, err := task.ParseFilesScope()
+	switch {
+	case err == nil && len(files) > 0:
+		for _, f := range files {
+			fmt.Fprintf(&sb, "- `%s`\n", f)
+		}
+	case na != nil:
+		fmt.Fprintf(&sb, "Not applicable: %s\n", na.Reason)
+	default:
+		sb.WriteString("Not specified. Confirm scope before modifying files.\n")
+	}
+
+	if effects, none, err := task.ParseEffects(); err == nil && (none || len(effects) > 0) {
+		sb.WriteString("\n## Side Effects\n\n")
+		if none {
+			sb.WriteString("None.\n")
+		}
+		for _, e := range effects {
+			fmt.Fprintf(&sb, "- %s: %s\n", e.Type, e.Target)
+		}
+	}
+
+	if len(task.NonGoals) > 0 {
+		sb.WriteString("\n## Non-Goals\n\n")
+		for _, ng := range task.NonGoals {
+			fmt.Fprintf(&sb, "- %s\n", ng)
+		}
+	}
+
+	if len(task.ErrorCases) > 0 {
+		sb.WriteString("\n## Error Cases\n\n")
+		for _, ec := range task.ErrorCases {
+			fmt.Fprintf(&sb, "- **%s**: %s -> %s\n", ec.Condition, ec.Behavior, ec.Output)
+		}
+	}
+
+	writeDependencyContext(&sb, graph, task)
+
+	sb.WriteString("\n## Acceptance Checklist\n\n")
+	for i, c := range task.Acceptance {
+		fmt.Fprintf(&sb, "- [ ] %s\n", c)
+		for _, v := range task.Verification {
+			if v.Criterion != i {
+				continue
+			}
+			if v.Command != "" {
+				fmt.Fprintf(&sb, "  - Verify: `%s`\n", v.Command)
+			}
+			if v.TestFile != "" {
+				fmt.Fprintf(&sb, "  - Test: `%s`\n", v.TestFile)
+			}
+		}
+	}
+
+	if task.Notes != "" {
+		sb.WriteString("\n## Notes\n\n")
+		sb.WriteString(task.Notes + "\n")
+	}
+
+	return sb.String(), nil
+}
+
+// writeDependencyContext lists each direct dependency with its goal and
+// declared outputs, which are what the task is allowed to assume exists.
+func writeDependencyContext(sb *strings.Builder, graph *validator.TaskGraph, task *validator.TaskNode) {
+	deps, na, err := task.ParseDependsOn()
+	if err != nil {
+		return
+	}
+
+	sb.WriteString("\n## Dependency Context\n\n")
+	if len(deps) == 0 {
+		if na != nil {
+			fmt.Fprintf(sb, "No upstream tasks: %s\n", na.Reason)
+		} else {
+			sb.WriteString("No upstream tasks.\n")
+		}
+		return
+	}
+
+	sb.WriteString("These tasks are complete before this one starts. Rely on their outputs; do not reimplement them.\n")
+	for _, dep := range deps {
+		up := graph.FindTask(dep)
+		if up == nil {
+			fmt.Fprintf(sb, "\n### `%s`\n\nNot defined in this graph.\n", dep)
+			continue
+		}
+		fmt.Fprintf(sb, "\n### %s (`%s`)\n\n%s\n", up.TaskName, up.TaskID, up.Goal)
+		if len(up.Outputs) > 0 {
+			sb.WriteString("\nProvides:\n")
+			for _, out := range up.Outputs {
+				fmt.Fprintf(sb, "- **%s** (`%s`) -- Dest: %s\n", out.Name, out.Type, out.Destination)
+			}
+		}
+	}
+}
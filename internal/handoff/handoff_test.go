@@ -0,0 +1,84 @@
+package handoff
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+func testGraph() *validator.TaskGraph {
+	return &validator.TaskGraph{
+		Version:  "0.1.0",
+		Defaults: &validator.Defaults{Acceptance: []string{"go test ./... passes"}},
+		Milestones: []validator.Milestone{
+			{Name: "M1 - Core", TaskIDs: []string{"task-a", "task-b"}},
+		},
+		Tasks: []validator.TaskNode{
+			{
+				TaskID:     "task-a",
+				TaskName:   "Implement parser",
+				Goal:       "Parse() returns a Config for valid input.",
+				Outputs:    []validator.OutputSpec{{Name: "config", Type: "Config", Constraints: "none", Destination: "return"}},
+				Acceptance: []string{"Parse returns Config"},
+				DependsOn:  json.RawMessage(`{"status": "N/A", "reason": "First task"}`),
+			},
+			{
+				TaskID:      "task-b",
+				TaskName:    "Implement loader",
+				Goal:        "Load() reads a file and returns its Config.",
+				Inputs:      []validator.InputSpec{{Name: "config", Type: "Config", Constraints: "none", Source: "task-a"}},
+				Acceptance:  []string{"Load returns Config"},
+				DependsOn:   json.RawMessage(`["task-a"]`),
+				FilesScope:  json.RawMessage(`["internal/load.go"]`),
+				Constraints: json.RawMessage(`["No global state"]`),
+				Priority:    "high",
+			},
+		},
+	}
+}
+
+func TestBriefIncludesDependencyContext(t *testing.T) {
+	brief, err := Brief(testGraph(), "task-b")
+	if err != nil {
+		t.Fatalf("Brief error: %v", err)
+	}
+
+	for _, want := range []string{
+		"# Implement loader",
+		"**Milestone:** M1 - Core",
+		"**Priority:** high",
+		"## Goal",
+		"- `internal/load.go`",
+		"- No global state",
+		"### Implement parser (`task-a`)",
+		"Parse() returns a Config for valid input.",
+		"- **config** (`Config`)",
+		"- [ ] go test ./... passes",
+		"- [ ] Load returns Config",
+	} {
+		if !strings.Contains(brief, want) {
+			t.Errorf("brief missing %q\n%s", want, brief)
+		}
+	}
+}
+
+func TestBriefStandaloneTask(t *testing.T) {
+	brief, err := Brief(testGraph(), "task-a")
+	if err != nil {
+		t.Fatalf("Brief error: %v", err)
+	}
+	if !strings.Contains(brief, "No upstream tasks: First task") {
+		t.Errorf("expected N/A dependency reason, got:\n%s", brief)
+	}
+	if !strings.Contains(brief, "Not specified. Confirm scope") {
+		t.Errorf("expected missing files_scope note, got:\n%s", brief)
+	}
+}
+
+func TestBriefUnknownTask(t *testing.T) {
+	if _, err := Brief(testGraph(), "nope"); err == nil {
+		t.Error("expected error for unknown task")
+	}
+}
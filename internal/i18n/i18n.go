@@ -0,0 +1,155 @@
+// Package i18n translates validation findings into other languages. The
+// validator writes every finding in English; a catalog per language maps
+// English message formats to translations, and a finding whose text has
+// no entry stays in English.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// English is the language findings are written in; it needs no catalog.
+const English = "en"
+
+//go:embed catalogs/*.json
+var catalogFiles embed.FS
+
+// catalogFile is the on-disk form of a catalog. Keys of Messages are the
+// validator's format strings, with %s and %d where values go; values use
+// %[n]s for the n-th of those values, so a translation can reorder them.
+type catalogFile struct {
+	Language string            `json:"language"`
+	Name     string            `json:"name"`
+	Messages map[string]string `json:"messages"`
+}
+
+// entry is one compiled catalog message.
+type entry struct {
+	pattern     *regexp.Regexp
+	translation string
+}
+
+// catalog is a compiled catalog. exact holds entries without
+// placeholders, looked up directly; patterns are tried in key order.
+type catalog struct {
+	exact    map[string]string
+	patterns []entry
+}
+
+var loadCatalogs = sync.OnceValues(func() (map[string]*catalog, error) {
+	files, err := catalogFiles.ReadDir("catalogs")
+	if err != nil {
+		return nil, err
+	}
+	catalogs := make(map[string]*catalog, len(files))
+	for _, f := range files {
+		data, err := catalogFiles.ReadFile(path.Join("catalogs", f.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var cf catalogFile
+		if err := json.Unmarshal(data, &cf); err != nil {
+			return nil, fmt.Errorf("catalog %s: %w", f.Name(), err)
+		}
+		c := &catalog{exact: make(map[string]string)}
+		for _, key := range slices.Sorted(maps.Keys(cf.Messages)) {
+			if !strings.Contains(key, "%") {
+				c.exact[key] = cf.Messages[key]
+				continue
+			}
+			c.patterns = append(c.patterns, entry{pattern: formatPattern(key), translation: cf.Messages[key]})
+		}
+		catalogs[cf.Language] = c
+	}
+	return catalogs, nil
+})
+
+// placeholder matches the verbs a catalog key may contain.
+var placeholder = regexp.MustCompile(`%[sd]`)
+
+// formatPattern turns a format string into a regexp matching its output
+// and capturing each formatted value.
+func formatPattern(format string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^")
+	last := 0
+	for _, loc := range placeholder.FindAllStringIndex(format, -1) {
+		b.WriteString(regexp.QuoteMeta(format[last:loc[0]]))
+		if format[loc[0]+1] == 'd' {
+			b.WriteString(`(-?[0-9]+)`)
+		} else {
+			b.WriteString(`(.*?)`)
+		}
+		last = loc[1]
+	}
+	b.WriteString(regexp.QuoteMeta(format[last:]))
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
+
+// Languages returns the supported language codes, English first.
+func Languages() []string {
+	catalogs, err := loadCatalogs()
+	if err != nil {
+		return []string{English}
+	}
+	return append([]string{English}, slices.Sorted(maps.Keys(catalogs))...)
+}
+
+// Translate returns a copy of result with each finding's message and
+// suggestion in lang. Text the catalog does not cover stays in English.
+func Translate(result *validator.ValidationResult, lang string) (*validator.ValidationResult, error) {
+	if lang == English || lang == "" {
+		return result, nil
+	}
+	catalogs, err := loadCatalogs()
+	if err != nil {
+		return nil, fmt.Errorf("loading message catalogs: %w", err)
+	}
+	c, ok := catalogs[lang]
+	if !ok {
+		return nil, fmt.Errorf("unsupported language '%s'. Must be one of: %s", lang, strings.Join(Languages(), ", "))
+	}
+
+	out := *result
+	out.Errors = make([]validator.ValidationError, len(result.Errors))
+	for i, e := range result.Errors {
+		e.Message = c.translate(e.Message)
+		e.Suggestion = c.translate(e.Suggestion)
+		out.Errors[i] = e
+	}
+	return &out, nil
+}
+
+// translate returns the translation of one English message, or the
+// message itself when no entry matches.
+func (c *catalog) translate(s string) string {
+	if s == "" {
+		return s
+	}
+	if t, ok := c.exact[s]; ok {
+		return t
+	}
+	for _, e := range c.patterns {
+		m := e.pattern.FindStringSubmatch(s)
+		if m == nil {
+			continue
+		}
+		args := make([]any, len(m)-1)
+		for i, v := range m[1:] {
+			args[i] = v
+		}
+		return fmt.Sprintf(e.translation, args...)
+	}
+	return s
+}
@@ -0,0 +1,118 @@
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+func TestTranslate(t *testing.T) {
+	graph := []byte(`{
+		"version": "0.1.0",
+		"tasks": [{
+			"task_id": "task-a",
+			"task_name": "Implement task-a",
+			"goal": "Try to produce output X.",
+			"inputs": [{"name": "in", "type": "string", "constraints": "none", "source": "caller"}],
+			"outputs": [{"name": "out", "type": "string", "constraints": "none", "destination": "return"}],
+			"acceptance": ["Given in, out works correctly"],
+			"depends_on": ["missing-task"],
+			"files_scope": ["a.go"]
+		}]
+	}`)
+	result, err := validator.Validate(graph, validator.ModeTaskGraph)
+	if err != nil {
+		t.Fatalf("validation error: %v", err)
+	}
+
+	de, err := Translate(result, "de")
+	if err != nil {
+		t.Fatalf("Translate error: %v", err)
+	}
+	want := map[string]string{
+		"V4": "Aufgabe 'task-a' hängt von 'missing-task' ab, aber im Graphen gibt es keine Aufgabe mit dieser task_id.",
+		"V6": "Das Ziel enthält das verbotene Wort bzw. die verbotene Wendung 'try'. Ziele müssen prüfbare Ergebnisse beschreiben, keine Tätigkeiten oder Erkundungen.",
+		"V7": "Das Abnahmekriterium enthält die vage Wendung 'works correctly'. Kriterien müssen mit konkreten erwarteten Werten unabhängig prüfbar sein.",
+	}
+	for _, e := range de.Errors {
+		if w, ok := want[e.Rule]; ok {
+			if e.Message != w {
+				t.Errorf("%s message = %q, want %q", e.Rule, e.Message, w)
+			}
+			delete(want, e.Rule)
+		}
+	}
+	if len(want) > 0 {
+		t.Errorf("findings not translated: %v", want)
+	}
+
+	// The input is left alone, and everything but the text is kept.
+	if result.Errors[0].Message == de.Errors[0].Message {
+		t.Error("expected a translated copy, not the English result")
+	}
+	for i := range result.Errors {
+		a, b := result.Errors[i], de.Errors[i]
+		if a.Rule != b.Rule || a.Path != b.Path || a.Severity != b.Severity || a.Context != b.Context {
+			t.Errorf("finding %d changed beyond its text: %+v -> %+v", i, a, b)
+		}
+	}
+
+	// Text without an entry stays in English.
+	c, _ := loadCatalogs()
+	if got := c["de"].translate("Some future message."); got != "Some future message." {
+		t.Errorf("untranslated text = %q", got)
+	}
+
+	if same, err := Translate(result, English); err != nil || same != result {
+		t.Errorf("English should return the result unchanged, got %v, %v", same, err)
+	}
+	if _, err := Translate(result, "xx"); err == nil {
+		t.Error("expected an error for an unsupported language")
+	}
+}
+
+// TestCatalogs checks that each translation uses exactly the values its
+// English format provides.
+func TestCatalogs(t *testing.T) {
+	files, err := catalogFiles.ReadDir("catalogs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	index := regexp.MustCompile(`%\[(\d+)\]s`)
+	for _, f := range files {
+		data, err := catalogFiles.ReadFile("catalogs/" + f.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		var cf catalogFile
+		if err := json.Unmarshal(data, &cf); err != nil {
+			t.Fatalf("%s: %v", f.Name(), err)
+		}
+		if cf.Language+".json" != f.Name() {
+			t.Errorf("%s declares language %q", f.Name(), cf.Language)
+		}
+		for key, tr := range cf.Messages {
+			n := len(placeholder.FindAllString(key, -1))
+			used := make(map[string]bool)
+			for _, m := range index.FindAllStringSubmatch(tr, -1) {
+				used[m[1]] = true
+			}
+			for i := 1; i <= n; i++ {
+				if !used[fmt.Sprint(i)] {
+					t.Errorf("%s: translation of %q does not use value %d", f.Name(), key, i)
+				}
+			}
+			args := make([]any, n)
+			for i := range args {
+				args[i] = "v"
+			}
+			if out := fmt.Sprintf(tr, args...); strings.Contains(out, "%!") {
+				t.Errorf("%s: translation of %q formats as %q", f.Name(), key, out)
+			}
+		}
+	}
+}
@@ -0,0 +1,231 @@
+// Package llmreview asks a language model to critique task goals and
+// acceptance criteria, catching vagueness the regex heuristics miss. It
+// speaks the OpenAI-compatible chat completions API, so any compatible
+// server (hosted or local) can be used. Nothing is sent unless a caller
+// runs a Reviewer explicitly.
+package llmreview
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// Rule is the rule ID of every finding produced by a review.
+const Rule = "LLM"
+
+// Environment variables read by ConfigFromEnv.
+const (
+	EnvEndpoint = "TASKVAL_LLM_ENDPOINT"
+	EnvModel    = "TASKVAL_LLM_MODEL"
+	EnvAPIKey   = "TASKVAL_LLM_API_KEY"
+)
+
+// Config selects the model server.
+type Config struct {
+	// Endpoint is the API base URL, e.g. "https://api.openai.com/v1" or
+	// "http://localhost:11434/v1". Required.
+	Endpoint string
+
+	// Model is the model name passed to the server. Required.
+	Model string
+
+	// APIKey is sent as a bearer token when set.
+	APIKey string
+
+	// Timeout bounds the whole review. Zero means one minute.
+	Timeout time.Duration
+}
+
+// ConfigFromEnv fills unset fields of c from the TASKVAL_LLM_*
+// environment variables.
+func ConfigFromEnv(c Config) Config {
+	if c.Endpoint == "" {
+		c.Endpoint = os.Getenv(EnvEndpoint)
+	}
+	if c.Model == "" {
+		c.Model = os.Getenv(EnvModel)
+	}
+	if c.APIKey == "" {
+		c.APIKey = os.Getenv(EnvAPIKey)
+	}
+	return c
+}
+
+// Reviewer sends goals and acceptance criteria to a model for critique.
+type Reviewer struct {
+	cfg    Config
+	client *http.Client
+}
+
+// New returns a Reviewer for cfg, or an error if the endpoint or model is
+// missing.
+func New(cfg Config) (*Reviewer, error) {
+	if cfg.Endpoint == "" {
+		return nil, fmt.Errorf("LLM review needs an endpoint: set --llm-endpoint or %s", EnvEndpoint)
+	}
+	if cfg.Model == "" {
+		return nil, fmt.Errorf("LLM review needs a model: set --llm-model or %s", EnvModel)
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = time.Minute
+	}
+	return &Reviewer{cfg: cfg, client: &http.Client{Timeout: cfg.Timeout}}, nil
+}
+
+const systemPrompt = `You review task specifications written for coding agents.
+For each task you receive a goal and acceptance criteria. Report only real problems:
+a goal that is not a single verifiable outcome, or a criterion that is vague,
+untestable, or does not check the goal. Answer with a JSON object:
+{"findings": [{"task_id": "...", "field": "goal" or "acceptance", "index": <criterion index, 0-based; omit for goal>,
+"severity": "warning" or "info", "message": "...", "suggestion": "..."}]}
+Use "warning" when an agent could not tell whether it is done, "info" for improvements.
+Return {"findings": []} when everything is clear.`
+
+// reviewTask is the part of a task sent to the model.
+type reviewTask struct {
+	TaskID     string   `json:"task_id"`
+	Goal       string   `json:"goal"`
+	Acceptance []string `json:"acceptance"`
+}
+
+// finding is one critique item in the model's answer.
+type finding struct {
+	TaskID     string `json:"task_id"`
+	Field      string `json:"field"`
+	Index      *int   `json:"index"`
+	Severity   string `json:"severity"`
+	Message    string `json:"message"`
+	Suggestion string `json:"suggestion"`
+}
+
+// Review sends every task's goal and acceptance criteria in one request
+// and converts the critique into findings with rule LLM. Findings for
+// unknown tasks or fields are dropped; severities other than warning
+// become info, so the model can never fail a validation on its own.
+func (r *Reviewer) Review(ctx context.Context, graph *validator.TaskGraph) ([]validator.ValidationError, error) {
+	tasks := make([]reviewTask, len(graph.Tasks))
+	index := make(map[string]int, len(graph.Tasks))
+	for i, t := range graph.Tasks {
+		tasks[i] = reviewTask{TaskID: t.TaskID, Goal: t.Goal, Acceptance: t.Acceptance}
+		index[t.TaskID] = i
+	}
+	payload, err := json.Marshal(tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := r.complete(ctx, string(payload))
+	if err != nil {
+		return nil, err
+	}
+	var answer struct {
+		Findings []finding `json:"findings"`
+	}
+	if err := json.Unmarshal([]byte(extractJSON(content)), &answer); err != nil {
+		return nil, fmt.Errorf("LLM review: model answer is not the expected JSON: %w", err)
+	}
+
+	var out []validator.ValidationError
+	for _, f := range answer.Findings {
+		i, ok := index[f.TaskID]
+		if !ok || f.Message == "" {
+			continue
+		}
+		task := graph.Tasks[i]
+		ve := validator.ValidationError{
+			Rule:       Rule,
+			Severity:   validator.SeverityInfo,
+			Message:    fmt.Sprintf("Task '%s': %s", f.TaskID, f.Message),
+			Suggestion: f.Suggestion,
+		}
+		if strings.EqualFold(f.Severity, "warning") {
+			ve.Severity = validator.SeverityWarning
+		}
+		switch {
+		case f.Field == "goal":
+			ve.Path = fmt.Sprintf("tasks[%d].goal", i)
+			ve.Context = task.Goal
+		case f.Field == "acceptance" && f.Index != nil && *f.Index >= 0 && *f.Index < len(task.Acceptance):
+			ve.Path = fmt.Sprintf("tasks[%d].acceptance[%d]", i, *f.Index)
+			ve.Context = task.Acceptance[*f.Index]
+		case f.Field == "acceptance":
+			ve.Path = fmt.Sprintf("tasks[%d].acceptance", i)
+		default:
+			continue
+		}
+		out = append(out, ve)
+	}
+	return out, nil
+}
+
+// complete runs one chat completion and returns the answer text.
+func (r *Reviewer) complete(ctx context.Context, user string) (string, error) {
+	body, err := json.Marshal(map[string]any{
+		"model": r.cfg.Model,
+		"messages": []map[string]string{
+			{"role": "system", "content": systemPrompt},
+			{"role": "user", "content": user},
+		},
+		"temperature":     0,
+		"response_format": map[string]string{"type": "json_object"},
+	})
+	if err != nil {
+		return "", err
+	}
+	url := strings.TrimSuffix(r.cfg.Endpoint, "/") + "/chat/completions"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("LLM review: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if r.cfg.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+r.cfg.APIKey)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("LLM review: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return "", fmt.Errorf("LLM review: reading response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("LLM review: %s returned %s: %s", url, resp.Status, strings.TrimSpace(string(data)))
+	}
+
+	var completion struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	if err := json.Unmarshal(data, &completion); err != nil {
+		return "", fmt.Errorf("LLM review: parsing response: %w", err)
+	}
+	if len(completion.Choices) == 0 {
+		return "", fmt.Errorf("LLM review: response has no choices")
+	}
+	return completion.Choices[0].Message.Content, nil
+}
+
+// extractJSON strips a markdown code fence or leading prose around the
+// JSON object in a model answer.
+func extractJSON(s string) string {
+	start, end := strings.Index(s, "{"), strings.LastIndex(s, "}")
+	if start < 0 || end < start {
+		return s
+	}
+	return s[start : end+1]
+}
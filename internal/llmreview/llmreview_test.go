@@ -0,0 +1,97 @@
+package llmreview
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+func testGraph() *validator.TaskGraph {
+	return &validator.TaskGraph{Tasks: []validator.TaskNode{{
+		TaskID:     "task-a",
+		Goal:       "Improve the parser.",
+		Acceptance: []string{"Parser is faster", "go test ./... passes"},
+	}}}
+}
+
+// serve answers every chat completion with content and records the request.
+func serve(t *testing.T, content string, got *map[string]any) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+		}
+		if got != nil {
+			_ = json.NewDecoder(r.Body).Decode(got)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"choices": []any{map[string]any{"message": map[string]string{"role": "assistant", "content": content}}},
+		})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestReview(t *testing.T) {
+	answer := "```json\n" + `{"findings": [
+		{"task_id": "task-a", "field": "goal", "severity": "warning", "message": "Improve is not verifiable.", "suggestion": "State a measurable outcome."},
+		{"task_id": "task-a", "field": "acceptance", "index": 0, "severity": "error", "message": "Faster than what?"},
+		{"task_id": "task-a", "field": "acceptance", "index": 9, "severity": "info", "message": "Out of range index."},
+		{"task_id": "nope", "field": "goal", "severity": "warning", "message": "Unknown task."}
+	]}` + "\n```"
+	var req map[string]any
+	srv := serve(t, answer, &req)
+
+	r, err := New(Config{Endpoint: srv.URL + "/v1/", Model: "test-model", APIKey: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	findings, err := r.Review(context.Background(), testGraph())
+	if err != nil {
+		t.Fatalf("Review error: %v", err)
+	}
+	if req["model"] != "test-model" || !strings.Contains(req["messages"].([]any)[1].(map[string]any)["content"].(string), "Parser is faster") {
+		t.Errorf("request did not carry the model and task: %v", req)
+	}
+
+	want := []struct {
+		path string
+		sev  validator.Severity
+	}{
+		{"tasks[0].goal", validator.SeverityWarning},
+		{"tasks[0].acceptance[0]", validator.SeverityInfo}, // "error" is capped at info
+		{"tasks[0].acceptance", validator.SeverityInfo},
+	}
+	if len(findings) != len(want) {
+		t.Fatalf("got %d findings, want %d: %+v", len(findings), len(want), findings)
+	}
+	for i, w := range want {
+		if f := findings[i]; f.Rule != Rule || f.Path != w.path || f.Severity != w.sev {
+			t.Errorf("finding %d = %s %s %s, want LLM %s %s", i, f.Rule, f.Path, f.Severity, w.path, w.sev)
+		}
+	}
+}
+
+func TestReviewErrors(t *testing.T) {
+	if _, err := New(Config{Model: "m"}); err == nil {
+		t.Error("expected error without an endpoint")
+	}
+	if _, err := New(Config{Endpoint: "http://localhost"}); err == nil {
+		t.Error("expected error without a model")
+	}
+
+	srv := serve(t, "I cannot answer that.", nil)
+	r, _ := New(Config{Endpoint: srv.URL + "/v1", Model: "m", APIKey: "secret"})
+	if _, err := r.Review(context.Background(), testGraph()); err == nil {
+		t.Error("expected error for a non-JSON answer")
+	}
+}
@@ -0,0 +1,161 @@
+// Package migrate upgrades task graph documents between spec versions and
+// reports every change it makes, so existing plans survive spec bumps.
+package migrate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// Change describes one edit made during migration.
+type Change struct {
+	Path        string `json:"path"`
+	Description string `json:"description"`
+}
+
+// Report lists the changes made to migrate a document.
+type Report struct {
+	From    string   `json:"from"`
+	To      string   `json:"to"`
+	Changes []Change `json:"changes"`
+
+	// Unknown lists the paths of fields the target spec does not define.
+	// They are kept as they are, for tools written against a newer spec.
+	Unknown []string `json:"unknown,omitempty"`
+}
+
+func (r *Report) add(path, format string, args ...any) {
+	r.Changes = append(r.Changes, Change{Path: path, Description: fmt.Sprintf(format, args...)})
+}
+
+// step upgrades a decoded document from one spec version to the next.
+// Steps work on the generic JSON form so they can handle fields that the
+// current TaskGraph type no longer has.
+type step struct {
+	from, to string
+	apply    func(doc map[string]any, r *Report)
+}
+
+// steps is the upgrade chain, oldest first.
+var steps = []step{
+	{validator.SpecVersion010, validator.SpecVersion020, addContextPlaceholders},
+}
+
+// PlaceholderReason is the N/A reason written for contextual fields that
+// a migration had to add.
+const PlaceholderReason = "TODO: added by taskval migrate; replace with real values or justify N/A"
+
+// NormalizeVersion expands a short version such as "0.2" to "0.2.0".
+func NormalizeVersion(v string) string {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	for strings.Count(v, ".") < 2 && v != "" {
+		v += ".0"
+	}
+	return v
+}
+
+// Migrate upgrades a task graph document to the spec version to. The
+// returned graph is ready to be written back; the report lists each change.
+func Migrate(data []byte, to string) (*validator.TaskGraph, *Report, error) {
+	to = NormalizeVersion(to)
+	target := slices.Index(validator.SpecVersions, to)
+	if target < 0 {
+		return nil, nil, fmt.Errorf("unknown target spec version '%s'. Supported: %s", to, strings.Join(validator.SpecVersions, ", "))
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc map[string]any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, nil, fmt.Errorf("parsing task graph: %w", err)
+	}
+	if err := validator.EndOfInput(dec); err != nil {
+		return nil, nil, fmt.Errorf("parsing task graph: the graph is complete, but %w", err)
+	}
+	if _, ok := doc["tasks"].([]any); !ok {
+		return nil, nil, fmt.Errorf("document has no 'tasks' array; migrate works on task graphs")
+	}
+
+	from, _ := doc["version"].(string)
+	current := slices.Index(validator.SpecVersions, from)
+	if current < 0 {
+		return nil, nil, fmt.Errorf("document declares unknown spec version '%s'. Supported: %s", from, strings.Join(validator.SpecVersions, ", "))
+	}
+	if current > target {
+		return nil, nil, fmt.Errorf("cannot migrate from %s down to %s; downgrades are not supported", from, to)
+	}
+
+	report := &Report{From: from, To: to}
+	for _, s := range steps {
+		v := slices.Index(validator.SpecVersions, s.from)
+		if v < current || v >= target {
+			continue
+		}
+		report.add("version", "changed from %s to %s", s.from, s.to)
+		doc["version"] = s.to
+		s.apply(doc, report)
+	}
+
+	// Unknown graph and task fields are carried through and reported;
+	// one nested where the model would drop it is an error.
+	migrated, err := json.Marshal(doc)
+	if err != nil {
+		return nil, nil, fmt.Errorf("encoding migrated document: %w", err)
+	}
+	var graph validator.TaskGraph
+	if err := json.Unmarshal(migrated, &graph); err != nil {
+		return nil, nil, fmt.Errorf("migrated document does not match spec %s: %w", to, err)
+	}
+	lost, err := validator.DroppedFields(migrated, validator.ModeTaskGraph)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(lost) > 0 {
+		return nil, nil, fmt.Errorf("migrated document does not match spec %s: %s would be dropped", to, strings.Join(lost, ", "))
+	}
+	for _, k := range slices.Sorted(maps.Keys(graph.Extra)) {
+		report.Unknown = append(report.Unknown, k)
+	}
+	for i, t := range graph.Tasks {
+		for _, k := range slices.Sorted(maps.Keys(t.Extra)) {
+			report.Unknown = append(report.Unknown, fmt.Sprintf("tasks[%d].%s", i, k))
+		}
+	}
+
+	if len(report.Changes) > 0 {
+		prev := graph.GraphRevision
+		rev, err := graph.BumpRevision(validator.RevisionMinor)
+		if err != nil {
+			return nil, nil, err
+		}
+		if rev != "" {
+			report.add("graph_revision", "bumped from %s to %s", prev, rev)
+		}
+	}
+	return &graph, report, nil
+}
+
+// addContextPlaceholders implements 0.1.0 -> 0.2.0: contextual fields
+// become required, so missing ones get an explicit N/A to be reviewed.
+func addContextPlaceholders(doc map[string]any, r *Report) {
+	tasks, _ := doc["tasks"].([]any)
+	for i, item := range tasks {
+		task, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		for _, field := range []string{"depends_on", "constraints", "files_scope"} {
+			if task[field] != nil {
+				continue
+			}
+			task[field] = map[string]any{"status": "N/A", "reason": PlaceholderReason}
+			r.add(fmt.Sprintf("tasks[%d].%s", i, field), "added N/A placeholder for required contextual field")
+		}
+	}
+}
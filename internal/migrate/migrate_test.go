@@ -0,0 +1,113 @@
+package migrate
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+const legacyGraph = `{
+  "version": "0.1.0",
+  "tasks": [
+    {"task_id": "task-a", "task_name": "Implement task A", "goal": "Task A produces output X.",
+     "inputs": [], "outputs": [], "acceptance": ["Output X is produced"],
+     "files_scope": ["a.go"], "estimate": "small"}
+  ]
+}`
+
+func TestMigrateAddsPlaceholders(t *testing.T) {
+	graph, report, err := Migrate([]byte(legacyGraph), "0.2")
+	if err != nil {
+		t.Fatalf("Migrate error: %v", err)
+	}
+	if graph.Version != validator.SpecVersion020 || report.From != "0.1.0" || report.To != "0.2.0" {
+		t.Errorf("unexpected versions: graph %s, report %s -> %s", graph.Version, report.From, report.To)
+	}
+
+	task := graph.Tasks[0]
+	for name, raw := range map[string][]byte{"depends_on": task.DependsOn, "constraints": task.Constraints} {
+		if !strings.Contains(string(raw), PlaceholderReason) {
+			t.Errorf("%s = %s, want N/A placeholder", name, raw)
+		}
+	}
+	if string(task.FilesScope) != `["a.go"]` {
+		t.Errorf("existing files_scope should be kept, got %s", task.FilesScope)
+	}
+	if task.Estimate != "small" {
+		t.Error("unrelated fields should be kept")
+	}
+
+	// Two placeholders plus the version bump.
+	if len(report.Changes) != 3 {
+		t.Errorf("expected 3 changes, got %+v", report.Changes)
+	}
+}
+
+func TestMigrateNoop(t *testing.T) {
+	_, report, err := Migrate([]byte(legacyGraph), "0.1.0")
+	if err != nil {
+		t.Fatalf("Migrate error: %v", err)
+	}
+	if len(report.Changes) != 0 {
+		t.Errorf("expected no changes, got %+v", report.Changes)
+	}
+}
+
+func TestMigrateErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		to   string
+	}{
+		{"unknown target", legacyGraph, "9.9"},
+		{"downgrade", strings.Replace(legacyGraph, "0.1.0", "0.2.0", 1), "0.1"},
+		{"unknown source", strings.Replace(legacyGraph, "0.1.0", "0.0.1", 1), "0.2"},
+		{"not a graph", `{"task_id": "x"}`, "0.2"},
+		{"nested unknown field", strings.Replace(legacyGraph, `"tasks"`, `"milestones": [{"name": "m", "task_ids": ["task-a"], "owner": "ana"}], "tasks"`, 1), "0.2"},
+	}
+	for _, tt := range tests {
+		if _, _, err := Migrate([]byte(tt.doc), tt.to); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+}
+
+func TestMigrateKeepsUnknownFields(t *testing.T) {
+	doc := strings.Replace(legacyGraph, `"estimate"`, `"effort"`, 1)
+	doc = strings.Replace(doc, `"tasks"`, `"x_owner": "ana", "tasks"`, 1)
+	graph, report, err := Migrate([]byte(doc), "0.2")
+	if err != nil {
+		t.Fatalf("Migrate error: %v", err)
+	}
+	if string(graph.Extra["x_owner"]) != `"ana"` || string(graph.Tasks[0].Extra["effort"]) != `"small"` {
+		t.Errorf("unknown fields not kept: graph %v, task %v", graph.Extra, graph.Tasks[0].Extra)
+	}
+	if want := []string{"x_owner", "tasks[0].effort"}; !slices.Equal(report.Unknown, want) {
+		t.Errorf("Unknown = %v, want %v", report.Unknown, want)
+	}
+}
+
+func TestNormalizeVersion(t *testing.T) {
+	for in, want := range map[string]string{"0.2": "0.2.0", "v0.2.0": "0.2.0", "1": "1.0.0"} {
+		if got := NormalizeVersion(in); got != want {
+			t.Errorf("NormalizeVersion(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestMigrateBumpsRevision(t *testing.T) {
+	doc := strings.Replace(legacyGraph, `"version": "0.1.0",`, `"version": "0.1.0", "graph_revision": "1.4.2",`, 1)
+	graph, report, err := Migrate([]byte(doc), "0.2")
+	if err != nil {
+		t.Fatalf("Migrate error: %v", err)
+	}
+	if graph.GraphRevision != "1.5.0" {
+		t.Errorf("graph_revision = %s, want 1.5.0", graph.GraphRevision)
+	}
+	last := report.Changes[len(report.Changes)-1]
+	if last.Path != "graph_revision" {
+		t.Errorf("expected revision change in report, got %+v", last)
+	}
+}
@@ -0,0 +1,134 @@
+// Package policy turns a task's declared effects and files_scope into a
+// sandbox policy for agent runners: where the agent may write, and whether
+// it may reach the network, databases, or run other programs.
+package policy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// Version is the policy format version written in Policy.Version.
+const Version = 1
+
+// Policy is the sandbox policy for one task. Anything not granted here is
+// denied: a runner should refuse writes outside WritePaths and any access
+// whose Permission is not allowed.
+type Policy struct {
+	Version int    `json:"version"`
+	TaskID  string `json:"task_id"`
+
+	// EffectsDeclared is false when the task has no effects field, so the
+	// policy is deny-all by default rather than by declaration.
+	EffectsDeclared bool `json:"effects_declared"`
+
+	// WritePaths are the task's files_scope entries, relative to the
+	// repository root; glob patterns are kept as written.
+	WritePaths []string `json:"write_paths"`
+
+	// FilesystemWrite covers writes outside the repository, such as a
+	// cache directory (Filesystem.Write effects).
+	FilesystemWrite Permission `json:"filesystem_write"`
+
+	Network       Permission `json:"network"`
+	Subprocess    Permission `json:"subprocess"`
+	DatabaseRead  Permission `json:"database_read"`
+	DatabaseWrite Permission `json:"database_write"`
+}
+
+// Permission grants one kind of access. Targets are the effect targets as
+// declared (free text such as "Weaviate at localhost:8080"), for a runner
+// or a reviewer to narrow the grant further.
+type Permission struct {
+	Allowed bool     `json:"allowed"`
+	Targets []string `json:"targets,omitempty"`
+}
+
+func (p *Permission) grant(target string) {
+	p.Allowed = true
+	p.Targets = append(p.Targets, target)
+}
+
+// ForTask builds the policy for the named task, with graph defaults applied.
+func ForTask(graph *validator.TaskGraph, taskID string) (*Policy, error) {
+	task, err := graph.ResolvedTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Policy{Version: Version, TaskID: task.TaskID, WritePaths: []string{}}
+	if files, _, err := task.ParseFilesScope(); err == nil {
+		p.WritePaths = append(p.WritePaths, files...)
+	}
+
+	effects, none, err := task.ParseEffects()
+	if err != nil {
+		return nil, fmt.Errorf("task '%s': %w", task.TaskID, err)
+	}
+	p.EffectsDeclared = none || effects != nil
+	for _, e := range effects {
+		switch e.Type {
+		case validator.EffectFilesystemWrite:
+			p.FilesystemWrite.grant(e.Target)
+		case validator.EffectNetworkOut:
+			p.Network.grant(e.Target)
+		case validator.EffectSubprocess:
+			p.Subprocess.grant(e.Target)
+		case validator.EffectDBRead:
+			p.DatabaseRead.grant(e.Target)
+		case validator.EffectDBWrite:
+			p.DatabaseWrite.grant(e.Target)
+		}
+	}
+	return p, nil
+}
+
+// YAML renders the policy as YAML with the same keys as its JSON form.
+// Strings are written double-quoted, which YAML reads like JSON strings.
+func (p *Policy) YAML() []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "version: %d\n", p.Version)
+	fmt.Fprintf(&b, "task_id: %s\n", quote(p.TaskID))
+	fmt.Fprintf(&b, "effects_declared: %t\n", p.EffectsDeclared)
+	writeList(&b, "write_paths", "", p.WritePaths)
+	for _, s := range []struct {
+		key  string
+		perm Permission
+	}{
+		{"filesystem_write", p.FilesystemWrite},
+		{"network", p.Network},
+		{"subprocess", p.Subprocess},
+		{"database_read", p.DatabaseRead},
+		{"database_write", p.DatabaseWrite},
+	} {
+		fmt.Fprintf(&b, "%s:\n  allowed: %t\n", s.key, s.perm.Allowed)
+		if len(s.perm.Targets) > 0 {
+			writeList(&b, "targets", "  ", s.perm.Targets)
+		}
+	}
+	return b.Bytes()
+}
+
+// writeList writes a YAML sequence under key, or [] when it is empty.
+func writeList(b *bytes.Buffer, key, indent string, items []string) {
+	if len(items) == 0 {
+		fmt.Fprintf(b, "%s%s: []\n", indent, key)
+		return
+	}
+	fmt.Fprintf(b, "%s%s:\n", indent, key)
+	for _, item := range items {
+		fmt.Fprintf(b, "%s  - %s\n", indent, quote(item))
+	}
+}
+
+// quote returns s as a double-quoted scalar.
+func quote(s string) string {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	return string(bytes.TrimSuffix(b.Bytes(), []byte("\n")))
+}
@@ -0,0 +1,85 @@
+package policy
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+func testGraph() *validator.TaskGraph {
+	return &validator.TaskGraph{
+		Version: "0.1.0",
+		Tasks: []validator.TaskNode{
+			{
+				TaskID:     "task-a",
+				FilesScope: json.RawMessage(`["internal/index/*.go", "cmd/index/main.go"]`),
+				Effects: json.RawMessage(`[
+					{"type": "Network.Out", "target": "Weaviate at localhost:8080"},
+					{"type": "DB.Read", "target": "SQLite \"chunks\" table"},
+					{"type": "Network.Out", "target": "OpenAI API"}
+				]`),
+			},
+			{
+				TaskID:     "task-b",
+				FilesScope: json.RawMessage(`{"status": "N/A", "reason": "Docs only"}`),
+				Effects:    json.RawMessage(`"None"`),
+			},
+			{TaskID: "task-c"},
+		},
+	}
+}
+
+func TestForTask(t *testing.T) {
+	graph := testGraph()
+
+	p, err := ForTask(graph, "task-a")
+	if err != nil {
+		t.Fatalf("ForTask error: %v", err)
+	}
+	if !p.EffectsDeclared || !slices.Equal(p.WritePaths, []string{"internal/index/*.go", "cmd/index/main.go"}) {
+		t.Errorf("unexpected policy: %+v", p)
+	}
+	if !p.Network.Allowed || len(p.Network.Targets) != 2 || !p.DatabaseRead.Allowed {
+		t.Errorf("declared effects not granted: %+v", p)
+	}
+	if p.Subprocess.Allowed || p.DatabaseWrite.Allowed || p.FilesystemWrite.Allowed {
+		t.Errorf("undeclared effects granted: %+v", p)
+	}
+
+	p, err = ForTask(graph, "task-b")
+	if err != nil {
+		t.Fatalf("ForTask error: %v", err)
+	}
+	if !p.EffectsDeclared || p.WritePaths == nil || len(p.WritePaths) != 0 || p.Network.Allowed {
+		t.Errorf("N/A scope and no effects should deny everything: %+v", p)
+	}
+
+	if p, _ := ForTask(graph, "task-c"); p.EffectsDeclared {
+		t.Error("a task without effects should be marked undeclared")
+	}
+	if _, err := ForTask(graph, "missing"); err == nil {
+		t.Error("expected an error for an unknown task")
+	}
+}
+
+func TestYAML(t *testing.T) {
+	p, err := ForTask(testGraph(), "task-a")
+	if err != nil {
+		t.Fatalf("ForTask error: %v", err)
+	}
+	got := string(p.YAML())
+	for _, want := range []string{
+		"version: 1\ntask_id: \"task-a\"\neffects_declared: true\n",
+		"write_paths:\n  - \"internal/index/*.go\"\n  - \"cmd/index/main.go\"\n",
+		"network:\n  allowed: true\n  targets:\n    - \"Weaviate at localhost:8080\"\n    - \"OpenAI API\"\n",
+		"database_read:\n  allowed: true\n  targets:\n    - \"SQLite \\\"chunks\\\" table\"\n",
+		"subprocess:\n  allowed: false\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("YAML missing %q:\n%s", want, got)
+		}
+	}
+}
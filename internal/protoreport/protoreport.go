@@ -0,0 +1,163 @@
+// Package protoreport encodes validation output as the Report message of
+// proto/taskval.proto, and the requests and progress events of its TaskVal
+// service. The wire format is handled directly with the standard library,
+// so taskval needs no protobuf runtime; any generated proto3 client
+// decodes it.
+package protoreport
+
+import (
+	"encoding/binary"
+	"maps"
+	"slices"
+
+	"github.com/nixlim/task_templating/internal/beads"
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// Wire types used by the messages in taskval.proto.
+const (
+	wireVarint = 0
+	wireBytes  = 2
+)
+
+// severities maps finding severities to the Severity enum.
+var severities = map[validator.Severity]uint64{
+	validator.SeverityError:   1,
+	validator.SeverityWarning: 2,
+	validator.SeverityInfo:    3,
+}
+
+// Marshal returns the Report message for a validation result and, when
+// set, the issues created or the commands planned, and why creation
+// failed. Zero values are left out, as proto3 encoders do.
+func Marshal(result *validator.ValidationResult, created *beads.BeadsJSON, plan *beads.DryRunJSON, failure *beads.ErrorJSON) []byte {
+	var b buffer
+	b.bool(1, result.Valid)
+	b.bool(2, result.Partial)
+	for _, e := range result.Errors {
+		b.message(3, func(m *buffer) {
+			m.string(1, e.Rule)
+			m.varint(2, severities[e.Severity])
+			m.string(3, e.Path)
+			m.string(4, e.Message)
+			m.string(5, e.Suggestion)
+			m.string(6, e.Context)
+			m.bool(7, e.Provisional)
+		})
+	}
+	b.message(4, func(m *buffer) {
+		m.varint(1, uint64(result.Stats.TotalTasks))
+		m.varint(2, uint64(result.Stats.ErrorCount))
+		m.varint(3, uint64(result.Stats.WarningCount))
+		m.varint(4, uint64(result.Stats.InfoCount))
+	})
+	if created != nil {
+		b.message(5, func(m *buffer) {
+			m.string(1, created.EpicID)
+			for _, id := range slices.Sorted(maps.Keys(created.Tasks)) {
+				m.message(2, func(entry *buffer) {
+					entry.string(1, id)
+					entry.string(2, created.Tasks[id])
+				})
+			}
+			m.varint(3, uint64(created.DepsLinked))
+			m.varint(4, uint64(created.TotalCreated))
+			for _, s := range created.Skipped {
+				m.repeatedString(5, s)
+			}
+			for _, s := range created.Updated {
+				m.repeatedString(6, s)
+			}
+			for _, s := range created.Excluded {
+				m.repeatedString(7, s)
+			}
+			for _, d := range created.PrunedDeps {
+				m.message(8, func(dm *buffer) {
+					dm.string(1, d.TaskID)
+					dm.string(2, d.DependsOn)
+				})
+			}
+			m.varint(9, uint64(created.DepsExisting))
+			for _, w := range created.Warnings {
+				m.repeatedString(10, w)
+			}
+			for _, id := range created.TaskOrder {
+				m.repeatedString(11, id)
+			}
+		})
+	}
+	if plan != nil {
+		b.message(6, func(m *buffer) {
+			for _, c := range plan.Commands {
+				m.message(1, func(cm *buffer) {
+					cm.string(1, c.Type)
+					cm.string(2, c.TaskID)
+					for _, a := range c.Args {
+						cm.repeatedString(3, a)
+					}
+					cm.string(4, c.ExistingID)
+					cm.string(5, c.DependsOn)
+				})
+			}
+		})
+	}
+	if failure != nil {
+		b.message(7, func(m *buffer) {
+			m.string(1, failure.Message)
+			m.string(2, failure.Type)
+			m.string(3, failure.TaskID)
+			for _, a := range failure.Command {
+				m.repeatedString(4, a)
+			}
+			m.string(5, failure.Stderr)
+		})
+	}
+	return b
+}
+
+// buffer accumulates an encoded message.
+type buffer []byte
+
+func (b *buffer) tag(field int, wireType int) {
+	*b = binary.AppendUvarint(*b, uint64(field)<<3|uint64(wireType))
+}
+
+// varint writes a non-zero integer field.
+func (b *buffer) varint(field int, v uint64) {
+	if v == 0 {
+		return
+	}
+	b.tag(field, wireVarint)
+	*b = binary.AppendUvarint(*b, v)
+}
+
+func (b *buffer) bool(field int, v bool) {
+	if v {
+		b.varint(field, 1)
+	}
+}
+
+// string writes a non-empty string field.
+func (b *buffer) string(field int, s string) {
+	if s != "" {
+		b.repeatedString(field, s)
+	}
+}
+
+// repeatedString writes one element of a repeated string field; unlike a
+// singular field, an empty element is kept.
+func (b *buffer) repeatedString(field int, s string) {
+	b.tag(field, wireBytes)
+	*b = binary.AppendUvarint(*b, uint64(len(s)))
+	*b = append(*b, s...)
+}
+
+// message writes a sub-message field built by fill. Sub-messages are
+// written even when empty, so their presence is kept.
+func (b *buffer) message(field int, fill func(*buffer)) {
+	var m buffer
+	fill(&m)
+	b.tag(field, wireBytes)
+	*b = binary.AppendUvarint(*b, uint64(len(m)))
+	*b = append(*b, m...)
+}
@@ -0,0 +1,174 @@
+package protoreport
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/nixlim/task_templating/internal/beads"
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// field is one decoded field: a varint value or the bytes of a
+// length-delimited one.
+type field struct {
+	num   int
+	value uint64
+	bytes []byte
+}
+
+// decode splits a message into its fields, failing on anything Marshal
+// should not produce.
+func decode(t *testing.T, msg []byte) []field {
+	t.Helper()
+	var fields []field
+	for len(msg) > 0 {
+		key, n := binary.Uvarint(msg)
+		if n <= 0 {
+			t.Fatalf("bad tag in %x", msg)
+		}
+		msg = msg[n:]
+		v, n := binary.Uvarint(msg)
+		if n <= 0 {
+			t.Fatalf("bad value in %x", msg)
+		}
+		msg = msg[n:]
+		f := field{num: int(key >> 3), value: v}
+		switch key & 7 {
+		case wireVarint:
+		case wireBytes:
+			if uint64(len(msg)) < v {
+				t.Fatalf("field %d: length %d past end of message", f.num, v)
+			}
+			f.bytes, msg = msg[:v], msg[v:]
+		default:
+			t.Fatalf("field %d: unexpected wire type %d", f.num, key&7)
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// get returns the fields numbered num.
+func get(fields []field, num int) []field {
+	var out []field
+	for _, f := range fields {
+		if f.num == num {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+func TestMarshal(t *testing.T) {
+	result := &validator.ValidationResult{Valid: false}
+	result.AddError(validator.ValidationError{
+		Rule: "V4", Severity: validator.SeverityError, Path: "tasks[1].depends_on",
+		Message: "Task 'b' depends on 'gone'.", Context: "gone",
+	})
+	result.AddError(validator.ValidationError{
+		Rule: "V7", Severity: validator.SeverityWarning, Path: "tasks[0].acceptance[0]",
+		Message: "Vague phrase.", Provisional: true,
+	})
+	result.Stats.TotalTasks = 2
+
+	report := decode(t, Marshal(result, nil, nil, nil))
+	if got := get(report, 1); len(got) != 0 {
+		t.Errorf("valid = %+v, want it left out when false", got)
+	}
+	findings := get(report, 3)
+	if len(findings) != 2 {
+		t.Fatalf("got %d findings, want 2", len(findings))
+	}
+	first := decode(t, findings[0].bytes)
+	if rule := string(get(first, 1)[0].bytes); rule != "V4" {
+		t.Errorf("rule = %q, want V4", rule)
+	}
+	if sev := get(first, 2)[0].value; sev != 1 {
+		t.Errorf("severity = %d, want 1 (SEVERITY_ERROR)", sev)
+	}
+	if ctx := string(get(first, 6)[0].bytes); ctx != "gone" {
+		t.Errorf("context = %q, want gone", ctx)
+	}
+	if len(get(first, 5)) != 0 || len(get(first, 7)) != 0 {
+		t.Errorf("empty suggestion and false provisional should be left out: %+v", first)
+	}
+	second := decode(t, findings[1].bytes)
+	if sev := get(second, 2)[0].value; sev != 2 {
+		t.Errorf("severity = %d, want 2 (SEVERITY_WARNING)", sev)
+	}
+	if p := get(second, 7); len(p) != 1 || p[0].value != 1 {
+		t.Errorf("provisional = %+v, want true", p)
+	}
+
+	stats := decode(t, get(report, 4)[0].bytes)
+	for num, want := range map[int]uint64{1: 2, 2: 1, 3: 1} {
+		if got := get(stats, num); len(got) != 1 || got[0].value != want {
+			t.Errorf("stats field %d = %+v, want %d", num, got, want)
+		}
+	}
+	if len(get(report, 5)) != 0 || len(get(report, 6)) != 0 {
+		t.Error("beads and dry_run should be left out when not given")
+	}
+}
+
+func TestMarshalBeads(t *testing.T) {
+	result := &validator.ValidationResult{Valid: true}
+	created := &beads.BeadsJSON{
+		EpicID:       "bd-1",
+		Tasks:        map[string]string{"zeta": "bd-3", "alpha": "bd-2"},
+		DepsLinked:   1,
+		TotalCreated: 3,
+		Skipped:      []string{""},
+		Excluded:     []string{"beta"},
+		PrunedDeps:   []beads.PrunedDep{{TaskID: "zeta", DependsOn: "beta"}},
+	}
+	report := decode(t, Marshal(result, created, nil, nil))
+	if v := get(report, 1); len(v) != 1 || v[0].value != 1 {
+		t.Errorf("valid = %+v, want true", v)
+	}
+
+	b := decode(t, get(report, 5)[0].bytes)
+	entries := get(b, 2)
+	if len(entries) != 2 {
+		t.Fatalf("got %d task entries, want 2", len(entries))
+	}
+	// Map entries are written in key order, so output is reproducible.
+	for i, want := range [][2]string{{"alpha", "bd-2"}, {"zeta", "bd-3"}} {
+		e := decode(t, entries[i].bytes)
+		if k, v := string(get(e, 1)[0].bytes), string(get(e, 2)[0].bytes); k != want[0] || v != want[1] {
+			t.Errorf("entry %d = %s: %s, want %s: %s", i, k, v, want[0], want[1])
+		}
+	}
+	// An empty element of a repeated field is kept.
+	if s := get(b, 5); len(s) != 1 || len(s[0].bytes) != 0 {
+		t.Errorf("skipped = %+v, want one empty string", s)
+	}
+	if s := get(b, 7); len(s) != 1 || string(s[0].bytes) != "beta" {
+		t.Errorf("excluded = %+v, want [beta]", s)
+	}
+	if d := get(b, 8); len(d) != 1 || string(get(decode(t, d[0].bytes), 2)[0].bytes) != "beta" {
+		t.Errorf("pruned_dependencies = %+v, want zeta -> beta", d)
+	}
+
+	plan := &beads.DryRunJSON{Commands: []beads.PlannedCommand{
+		{Type: "create", TaskID: "a", Args: []string{"create", "--title", "A"}},
+	}}
+	report = decode(t, Marshal(result, nil, plan, nil))
+	cmds := get(decode(t, get(report, 6)[0].bytes), 1)
+	if len(cmds) != 1 {
+		t.Fatalf("got %d planned commands, want 1", len(cmds))
+	}
+	if args := get(decode(t, cmds[0].bytes), 3); len(args) != 3 || string(args[2].bytes) != "A" {
+		t.Errorf("args = %+v, want create --title A", args)
+	}
+
+	failure := &beads.ErrorJSON{Message: "bd command failed", Type: "dep-add", Command: []string{"dep", "add", "bd-2", "bd-1"}, Stderr: "no such issue"}
+	report = decode(t, Marshal(result, created, nil, failure))
+	if len(get(report, 5)) != 1 {
+		t.Error("partial beads result should be kept beside the failure")
+	}
+	f := decode(t, get(report, 7)[0].bytes)
+	if len(get(f, 4)) != 4 || string(get(f, 5)[0].bytes) != "no such issue" || len(get(f, 3)) != 0 {
+		t.Errorf("beads_error = %+v, want the command and stderr, no task_id", f)
+	}
+}
@@ -0,0 +1,151 @@
+package protoreport
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/nixlim/task_templating/internal/beads"
+)
+
+// Wire types a request may carry besides varints and length-delimited
+// fields. They are skipped, as proto3 decoders skip unknown fields.
+const (
+	wireFixed64 = 1
+	wireFixed32 = 5
+)
+
+// Modes of the Mode enum.
+const (
+	ModeGraph = 0
+	ModeTask  = 1
+)
+
+// ValidateRequest is the ValidateRequest message of the TaskVal service.
+type ValidateRequest struct {
+	Document []byte
+	Mode     uint64
+	Profile  string
+	Filename string
+}
+
+// PlanRequest is the PlanRequest message of the TaskVal service.
+type PlanRequest struct {
+	Validation   ValidateRequest
+	EpicTitle    string
+	OnDuplicate  string
+	AttachReport bool
+}
+
+// UnmarshalValidateRequest decodes a ValidateRequest message.
+func UnmarshalValidateRequest(msg []byte) (ValidateRequest, error) {
+	var req ValidateRequest
+	err := eachField(msg, func(num int, v uint64, b []byte) {
+		switch num {
+		case 1:
+			req.Document = b
+		case 2:
+			req.Mode = v
+		case 3:
+			req.Profile = string(b)
+		case 4:
+			req.Filename = string(b)
+		}
+	})
+	return req, err
+}
+
+// UnmarshalPlanRequest decodes a PlanRequest message.
+func UnmarshalPlanRequest(msg []byte) (PlanRequest, error) {
+	var req PlanRequest
+	var validation []byte
+	err := eachField(msg, func(num int, v uint64, b []byte) {
+		switch num {
+		case 1:
+			validation = b
+		case 2:
+			req.EpicTitle = string(b)
+		case 3:
+			req.OnDuplicate = string(b)
+		case 4:
+			req.AttachReport = v != 0
+		}
+	})
+	if err != nil {
+		return req, err
+	}
+	req.Validation, err = UnmarshalValidateRequest(validation)
+	return req, err
+}
+
+// eachField calls fn with each field of msg: its number and, by wire type,
+// its varint value or its bytes. Fixed-width fields are skipped.
+func eachField(msg []byte, fn func(num int, v uint64, b []byte)) error {
+	for len(msg) > 0 {
+		key, n := binary.Uvarint(msg)
+		if n <= 0 {
+			return errors.New("malformed field tag")
+		}
+		msg = msg[n:]
+		num := int(key >> 3)
+		switch key & 7 {
+		case wireVarint:
+			v, n := binary.Uvarint(msg)
+			if n <= 0 {
+				return fmt.Errorf("field %d: malformed varint", num)
+			}
+			msg = msg[n:]
+			fn(num, v, nil)
+		case wireBytes:
+			l, n := binary.Uvarint(msg)
+			if n <= 0 || uint64(len(msg)-n) < l {
+				return fmt.Errorf("field %d: length past end of message", num)
+			}
+			msg = msg[n:]
+			fn(num, 0, msg[:l])
+			msg = msg[l:]
+		case wireFixed64, wireFixed32:
+			size := 8
+			if key&7 == wireFixed32 {
+				size = 4
+			}
+			if len(msg) < size {
+				return fmt.Errorf("field %d: value past end of message", num)
+			}
+			msg = msg[size:]
+		default:
+			return fmt.Errorf("field %d: unsupported wire type %d", num, key&7)
+		}
+	}
+	return nil
+}
+
+// MarshalCommandDone returns the Progress message reporting a bd command
+// run by ApplyPlan: the index-th of total planned.
+func MarshalCommandDone(index, total int, ev beads.CommandEvent) []byte {
+	var b buffer
+	b.message(1, func(m *buffer) {
+		m.varint(1, uint64(index))
+		m.varint(2, uint64(total))
+		m.message(3, func(cm *buffer) {
+			cm.string(1, ev.Type)
+			cm.string(2, ev.TaskID)
+			for _, a := range ev.Args {
+				cm.repeatedString(3, a)
+			}
+		})
+		m.string(4, ev.IssueID)
+		if ev.Err != nil {
+			m.string(5, ev.Err.Error())
+		}
+	})
+	return b
+}
+
+// MarshalResult returns the Progress message ending ApplyPlan, carrying a
+// Report encoded by Marshal.
+func MarshalResult(report []byte) []byte {
+	var b buffer
+	b.message(2, func(m *buffer) { *m = append(*m, report...) })
+	return b
+}
@@ -0,0 +1,99 @@
+package query
+
+import (
+	"slices"
+
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// Filter narrows a graph to the tasks matching every set field.
+type Filter struct {
+	// Milestone keeps tasks listed in the milestone with this name.
+	Milestone string
+
+	// DependsOn keeps tasks that directly depend on this task_id.
+	DependsOn string
+
+	// NoFilesScope keeps tasks whose files_scope is absent, empty, or N/A.
+	NoFilesScope bool
+
+	// Label keeps tasks carrying this label.
+	Label string
+}
+
+// IsZero reports whether the filter matches every task.
+func (f Filter) IsZero() bool {
+	return f == Filter{}
+}
+
+// Apply returns a copy of graph containing only the matching tasks.
+// Milestones keep only matching task_ids and are dropped when empty.
+func (f Filter) Apply(graph *validator.TaskGraph) *validator.TaskGraph {
+	out := *graph
+	out.Tasks = nil
+	out.Milestones = nil
+
+	keep := make(map[string]bool)
+	for _, t := range graph.Tasks {
+		if f.matches(graph, &t) {
+			out.Tasks = append(out.Tasks, t)
+			keep[t.TaskID] = true
+		}
+	}
+	for _, m := range graph.Milestones {
+		var ids []string
+		for _, id := range m.TaskIDs {
+			if keep[id] {
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) > 0 {
+			m.TaskIDs = ids
+			out.Milestones = append(out.Milestones, m)
+		}
+	}
+	return &out
+}
+
+func (f Filter) matches(graph *validator.TaskGraph, t *validator.TaskNode) bool {
+	if f.Milestone != "" && !inMilestone(graph, f.Milestone, t.TaskID) {
+		return false
+	}
+	if f.DependsOn != "" {
+		deps, _, _ := t.ParseDependsOn()
+		found := false
+		for _, d := range deps {
+			if d == f.DependsOn {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if f.Label != "" && !slices.Contains(t.Labels, f.Label) {
+		return false
+	}
+	if f.NoFilesScope {
+		files, _, err := t.ParseFilesScope()
+		if err == nil && len(files) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func inMilestone(graph *validator.TaskGraph, name, taskID string) bool {
+	for _, m := range graph.Milestones {
+		if m.Name != name {
+			continue
+		}
+		for _, id := range m.TaskIDs {
+			if id == taskID {
+				return true
+			}
+		}
+	}
+	return false
+}
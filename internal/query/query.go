@@ -0,0 +1,404 @@
+// Package query evaluates JMESPath-style selector expressions against
+// decoded JSON documents, for scripting over task graphs.
+//
+// Supported syntax:
+//
+//	tasks                      field access
+//	tasks[0], tasks[-1]        index (negative counts from the end)
+//	tasks[*].task_id           projection over a list
+//	tasks[].inputs[].name      flatten; nested projections are flattened as in jq
+//	tasks[?priority==critical] filter with ==, !=, truthiness ([?notes]),
+//	                           and && between conditions
+//	tasks[?@==x]               @ is the current element
+//	tasks[?estimate==large] | [0]  pipe: stop projecting and continue on the result
+//
+// Filter values may be bare words, 'quoted' or "quoted" strings, numbers,
+// true, false, null, or `JSON` literals.
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+type stepKind int
+
+const (
+	stepField stepKind = iota
+	stepIndex
+	stepWildcard
+	stepFlatten
+	stepFilter
+)
+
+type step struct {
+	kind    stepKind
+	name    string
+	index   int
+	clauses []clause
+}
+
+// clause is one condition of a filter. When op is empty the clause tests
+// that lhs is truthy.
+type clause struct {
+	lhs []step
+	op  string
+	rhs any
+}
+
+// Expr is a compiled selector expression.
+type Expr struct {
+	src      string
+	segments [][]step
+}
+
+// String returns the source text of the expression.
+func (e *Expr) String() string { return e.src }
+
+// Compile parses a selector expression.
+func Compile(src string) (*Expr, error) {
+	e := &Expr{src: src}
+	for _, part := range splitTopLevel(src, "|") {
+		steps, err := parseSteps(strings.TrimSpace(part))
+		if err != nil {
+			return nil, fmt.Errorf("invalid selector '%s': %w", src, err)
+		}
+		e.segments = append(e.segments, steps)
+	}
+	return e, nil
+}
+
+// Eval applies the expression to a value decoded by encoding/json. Missing
+// fields evaluate to nil; projections return a (possibly empty) list.
+func (e *Expr) Eval(doc any) any {
+	v := doc
+	for _, steps := range e.segments {
+		v = eval(steps, v)
+	}
+	return v
+}
+
+func parseSteps(src string) ([]step, error) {
+	var steps []step
+	src = strings.TrimPrefix(src, "@")
+	for i := 0; i < len(src); {
+		switch c := src[i]; {
+		case c == '.':
+			// A leading dot is accepted for jq familiarity.
+			if i == len(src)-1 || src[i+1] == '.' {
+				return nil, fmt.Errorf("unexpected '.' at offset %d", i)
+			}
+			i++
+		case c == '[':
+			end, err := matchBracket(src, i)
+			if err != nil {
+				return nil, err
+			}
+			s, err := parseBracket(src[i+1 : end])
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, s)
+			i = end + 1
+		case c == '"':
+			end := strings.IndexByte(src[i+1:], '"')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated quoted field at offset %d", i)
+			}
+			steps = append(steps, step{kind: stepField, name: src[i+1 : i+1+end]})
+			i += end + 2
+		case isIdentStart(c):
+			j := i + 1
+			for j < len(src) && isIdentPart(src[j]) {
+				j++
+			}
+			steps = append(steps, step{kind: stepField, name: src[i:j]})
+			i = j
+		default:
+			return nil, fmt.Errorf("unexpected '%c' at offset %d", c, i)
+		}
+	}
+	return steps, nil
+}
+
+func parseBracket(inner string) (step, error) {
+	inner = strings.TrimSpace(inner)
+	switch {
+	case inner == "":
+		return step{kind: stepFlatten}, nil
+	case inner == "*":
+		return step{kind: stepWildcard}, nil
+	case strings.HasPrefix(inner, "?"):
+		var clauses []clause
+		for _, part := range splitTopLevel(inner[1:], "&&") {
+			c, err := parseClause(strings.TrimSpace(part))
+			if err != nil {
+				return step{}, err
+			}
+			clauses = append(clauses, c)
+		}
+		return step{kind: stepFilter, clauses: clauses}, nil
+	default:
+		n, err := strconv.Atoi(inner)
+		if err != nil {
+			return step{}, fmt.Errorf("invalid index '%s'", inner)
+		}
+		return step{kind: stepIndex, index: n}, nil
+	}
+}
+
+func parseClause(src string) (clause, error) {
+	if src == "" {
+		return clause{}, fmt.Errorf("empty filter condition")
+	}
+	for _, op := range []string{"==", "!="} {
+		parts := splitTopLevel(src, op)
+		if len(parts) == 1 {
+			continue
+		}
+		if len(parts) > 2 {
+			return clause{}, fmt.Errorf("condition '%s' has more than one '%s'", src, op)
+		}
+		lhs, err := parseSteps(strings.TrimSpace(parts[0]))
+		if err != nil {
+			return clause{}, err
+		}
+		rhs, err := parseLiteral(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return clause{}, err
+		}
+		return clause{lhs: lhs, op: op, rhs: rhs}, nil
+	}
+	lhs, err := parseSteps(src)
+	if err != nil {
+		return clause{}, err
+	}
+	return clause{lhs: lhs}, nil
+}
+
+// parseLiteral converts a filter operand into a JSON-compatible value.
+// Anything that is not quoted, a number, or a JSON keyword is a bare string.
+func parseLiteral(src string) (any, error) {
+	if len(src) >= 2 {
+		switch q := src[0]; {
+		case (q == '\'' || q == '"') && src[len(src)-1] == q:
+			return src[1 : len(src)-1], nil
+		case q == '`' && src[len(src)-1] == '`':
+			var v any
+			if err := json.Unmarshal([]byte(src[1:len(src)-1]), &v); err != nil {
+				return nil, fmt.Errorf("invalid JSON literal %s: %w", src, err)
+			}
+			return v, nil
+		}
+	}
+	switch src {
+	case "":
+		return nil, fmt.Errorf("missing value after comparison operator")
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	if f, err := strconv.ParseFloat(src, 64); err == nil {
+		return f, nil
+	}
+	return src, nil
+}
+
+// matchBracket returns the index of the ']' closing the '[' at open,
+// skipping quoted text and nested brackets.
+func matchBracket(src string, open int) (int, error) {
+	depth := 0
+	var quote byte
+	for i := open; i < len(src); i++ {
+		c := src[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+			if depth == 0 {
+				return i, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("unterminated '[' at offset %d", open)
+}
+
+// splitTopLevel splits src on sep where sep is outside quotes and brackets.
+func splitTopLevel(src, sep string) []string {
+	var parts []string
+	depth, start := 0, 0
+	var quote byte
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+		case depth == 0 && strings.HasPrefix(src[i:], sep):
+			parts = append(parts, src[start:i])
+			start = i + len(sep)
+			i += len(sep) - 1
+		}
+	}
+	return append(parts, src[start:])
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || c >= '0' && c <= '9'
+}
+
+// eval walks the steps left to right. Once a projection step is reached,
+// later steps apply to each projected element and nil results are dropped.
+func eval(steps []step, v any) any {
+	cur := v
+	var items []any
+	projecting := false
+
+	for _, s := range steps {
+		if !projecting {
+			switch s.kind {
+			case stepField, stepIndex:
+				cur = access(s, cur)
+			default:
+				list, ok := cur.([]any)
+				if !ok {
+					return nil
+				}
+				items = project(s, list)
+				projecting = true
+			}
+			continue
+		}
+
+		var next []any
+		for _, it := range items {
+			switch s.kind {
+			case stepField, stepIndex:
+				if r := access(s, it); r != nil {
+					next = append(next, r)
+				}
+			default:
+				if list, ok := it.([]any); ok {
+					next = append(next, project(s, list)...)
+				} else if s.kind == stepFlatten {
+					next = append(next, it)
+				}
+			}
+		}
+		items = next
+	}
+
+	if projecting {
+		if items == nil {
+			items = []any{}
+		}
+		return items
+	}
+	return cur
+}
+
+func access(s step, v any) any {
+	switch s.kind {
+	case stepField:
+		if m, ok := v.(map[string]any); ok {
+			return m[s.name]
+		}
+	case stepIndex:
+		if list, ok := v.([]any); ok {
+			i := s.index
+			if i < 0 {
+				i += len(list)
+			}
+			if i >= 0 && i < len(list) {
+				return list[i]
+			}
+		}
+	}
+	return nil
+}
+
+func project(s step, list []any) []any {
+	switch s.kind {
+	case stepFlatten:
+		var out []any
+		for _, it := range list {
+			if inner, ok := it.([]any); ok {
+				out = append(out, inner...)
+			} else {
+				out = append(out, it)
+			}
+		}
+		return out
+	case stepFilter:
+		var out []any
+		for _, it := range list {
+			if matches(s.clauses, it) {
+				out = append(out, it)
+			}
+		}
+		return out
+	default:
+		return list
+	}
+}
+
+func matches(clauses []clause, v any) bool {
+	for _, c := range clauses {
+		got := eval(c.lhs, v)
+		var ok bool
+		switch c.op {
+		case "==":
+			ok = reflect.DeepEqual(got, c.rhs)
+		case "!=":
+			ok = !reflect.DeepEqual(got, c.rhs)
+		default:
+			ok = truthy(got)
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// truthy follows JMESPath: null, false, "", empty lists and empty objects are false.
+func truthy(v any) bool {
+	switch x := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return x
+	case string:
+		return x != ""
+	case []any:
+		return len(x) > 0
+	case map[string]any:
+		return len(x) > 0
+	default:
+		return true
+	}
+}
@@ -0,0 +1,108 @@
+package query
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+const testDoc = `{
+  "version": "0.1.0",
+  "milestones": [{"name": "M1", "task_ids": ["task-a", "task-b"]}],
+  "tasks": [
+    {"task_id": "task-a", "priority": "critical", "estimate": "small",
+     "inputs": [{"name": "path"}, {"name": "mode"}], "files_scope": ["a.go"]},
+    {"task_id": "task-b", "priority": "low", "depends_on": ["task-a"],
+     "inputs": [{"name": "config"}], "files_scope": {"status": "N/A", "reason": "docs"}},
+    {"task_id": "task-c", "priority": "critical", "depends_on": ["task-a", "task-b"], "notes": "x"}
+  ]
+}`
+
+func decode(t *testing.T, s string) any {
+	t.Helper()
+	var v any
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestEval(t *testing.T) {
+	doc := decode(t, testDoc)
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"version", `"0.1.0"`},
+		{".tasks[0].task_id", `"task-a"`},
+		{"tasks[-1].task_id", `"task-c"`},
+		{"tasks[*].task_id", `["task-a","task-b","task-c"]`},
+		{"tasks[?priority==critical].task_id", `["task-a","task-c"]`},
+		{"tasks[?priority=='critical' && estimate].task_id", `["task-a"]`},
+		{"tasks[?priority!=critical].task_id", `["task-b"]`},
+		{"tasks[?notes].task_id", `["task-c"]`},
+		{"tasks[].inputs[].name", `["path","mode","config"]`},
+		{"tasks[?depends_on[?@==task-b]].task_id", `["task-c"]`},
+		{"tasks[?priority==critical] | [0].task_id", `"task-a"`},
+		{"tasks[?priority==none].task_id", `[]`},
+		{"missing.field", `null`},
+	}
+	for _, tt := range tests {
+		e, err := Compile(tt.expr)
+		if err != nil {
+			t.Errorf("Compile(%q) error: %v", tt.expr, err)
+			continue
+		}
+		got, _ := json.Marshal(e.Eval(doc))
+		if string(got) != tt.want {
+			t.Errorf("Eval(%q) = %s, want %s", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	for _, expr := range []string{"tasks[", "tasks[abc]", "tasks..id", "tasks[?priority==]", "tasks.", "$"} {
+		if _, err := Compile(expr); err == nil {
+			t.Errorf("Compile(%q) expected error", expr)
+		}
+	}
+}
+
+func TestFilterApply(t *testing.T) {
+	var graph validator.TaskGraph
+	if err := json.Unmarshal([]byte(testDoc), &graph); err != nil {
+		t.Fatal(err)
+	}
+
+	ids := func(g *validator.TaskGraph) []string {
+		var out []string
+		for _, task := range g.Tasks {
+			out = append(out, task.TaskID)
+		}
+		return out
+	}
+
+	if got := ids(Filter{DependsOn: "task-a"}.Apply(&graph)); !reflect.DeepEqual(got, []string{"task-b", "task-c"}) {
+		t.Errorf("DependsOn filter = %v", got)
+	}
+	if got := ids(Filter{NoFilesScope: true}.Apply(&graph)); !reflect.DeepEqual(got, []string{"task-b", "task-c"}) {
+		t.Errorf("NoFilesScope filter = %v", got)
+	}
+	graph.Tasks[2].Labels = []string{"backend", "security"}
+	if got := ids(Filter{Label: "security"}.Apply(&graph)); !reflect.DeepEqual(got, []string{"task-c"}) {
+		t.Errorf("Label filter = %v", got)
+	}
+
+	filtered := Filter{Milestone: "M1", DependsOn: "task-a"}.Apply(&graph)
+	if got := ids(filtered); !reflect.DeepEqual(got, []string{"task-b"}) {
+		t.Errorf("combined filter = %v", got)
+	}
+	if len(filtered.Milestones) != 1 || !reflect.DeepEqual(filtered.Milestones[0].TaskIDs, []string{"task-b"}) {
+		t.Errorf("milestones should keep only matching tasks: %+v", filtered.Milestones)
+	}
+	if len(graph.Tasks) != 3 {
+		t.Error("Apply must not modify the input graph")
+	}
+}
@@ -0,0 +1,113 @@
+// Package report renders validation results in formats beyond the CLI's
+// built-in text and JSON output.
+package report
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"strings"
+
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+//go:embed templates/report.html.tmpl
+var templateFS embed.FS
+
+var htmlTemplate = template.Must(template.New("report.html.tmpl").Funcs(template.FuncMap{
+	"lower": func(s validator.Severity) string { return strings.ToLower(string(s)) },
+}).ParseFS(templateFS, "templates/report.html.tmpl"))
+
+// htmlData is the view model passed to the HTML template.
+type htmlData struct {
+	Title     string
+	Result    *validator.ValidationResult
+	Tasks     []htmlTask
+	GraphJSON template.JS
+}
+
+// htmlTask is a per-task detail card.
+type htmlTask struct {
+	validator.TaskNode
+	Index     int
+	Milestone string
+	DependsOn []string
+	Files     []string
+	Findings  []validator.ValidationError
+}
+
+// graphNode and graphEdge feed the embedded dependency graph script.
+type graphNode struct {
+	ID       string `json:"id"`
+	Label    string `json:"label"`
+	Severity string `json:"severity,omitempty"`
+}
+
+type graphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// HTML renders a single self-contained HTML page: a filterable findings
+// table, a detail card per task, and an interactive dependency graph. graph
+// may be nil (for example when the input failed to parse), in which case
+// only the findings are shown.
+func HTML(title string, result *validator.ValidationResult, graph *validator.TaskGraph) ([]byte, error) {
+	data := htmlData{Title: title, Result: result, GraphJSON: template.JS("null")}
+
+	if graph != nil {
+		var nodes []graphNode
+		var edges []graphEdge
+		for i, t := range graph.Tasks {
+			deps, _, _ := t.ParseDependsOn()
+			files, _, _ := t.ParseFilesScope()
+			card := htmlTask{
+				TaskNode:  t,
+				Index:     i,
+				Milestone: graph.MilestoneOf(t.TaskID),
+				DependsOn: deps,
+				Files:     files,
+				Findings:  result.ForTask(i),
+			}
+			data.Tasks = append(data.Tasks, card)
+
+			nodes = append(nodes, graphNode{ID: t.TaskID, Label: t.TaskName, Severity: worstSeverity(card.Findings)})
+			for _, d := range deps {
+				edges = append(edges, graphEdge{From: d, To: t.TaskID})
+			}
+		}
+
+		payload, err := json.Marshal(map[string]any{"nodes": nodes, "edges": edges})
+		if err != nil {
+			return nil, fmt.Errorf("marshaling graph data: %w", err)
+		}
+		// json.Marshal escapes <, >, and & so the payload is safe inside <script>.
+		data.GraphJSON = template.JS(payload)
+	}
+
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("rendering HTML report: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// worstSeverity returns the most severe finding level, or "" if none.
+func worstSeverity(findings []validator.ValidationError) string {
+	worst := ""
+	for _, f := range findings {
+		switch f.Severity {
+		case validator.SeverityError:
+			return "error"
+		case validator.SeverityWarning:
+			worst = "warning"
+		case validator.SeverityInfo:
+			if worst == "" {
+				worst = "info"
+			}
+		}
+	}
+	return worst
+}
@@ -0,0 +1,119 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/nixlim/task_templating/internal/beads"
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+func TestHTML(t *testing.T) {
+	graph := &validator.TaskGraph{
+		Version: "0.1.0",
+		Tasks: []validator.TaskNode{
+			{TaskID: "task-a", TaskName: "Build <parser>", Goal: "Parse returns Config.", Acceptance: []string{"Parse works"}},
+			{TaskID: "task-b", TaskName: "Build loader", Goal: "Load returns Config.", Acceptance: []string{"Load works"}, DependsOn: json.RawMessage(`["task-a"]`)},
+		},
+	}
+	result := &validator.ValidationResult{Valid: true}
+	result.AddError(validator.ValidationError{
+		Rule: "V7", Severity: validator.SeverityWarning, Path: "tasks[1].acceptance[0]", Message: "Vague criterion",
+	})
+
+	out, err := HTML("plan.json", result, graph)
+	if err != nil {
+		t.Fatalf("HTML error: %v", err)
+	}
+	page := string(out)
+
+	for _, want := range []string{
+		"<title>plan.json</title>",
+		"VALIDATION PASSED",
+		`data-severity="warning"`,
+		`id="task-task-b"`,
+		`<a href="#task-task-a">`,
+		"Build &lt;parser&gt;",
+		`"from":"task-a","to":"task-b"`,
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("HTML missing %q", want)
+		}
+	}
+	if strings.Contains(page, "Build <parser>") {
+		t.Error("task name must be HTML-escaped")
+	}
+}
+
+func TestHTMLWithoutGraph(t *testing.T) {
+	result := &validator.ValidationResult{}
+	result.AddError(validator.ValidationError{Rule: "SCHEMA", Severity: validator.SeverityError, Path: "$", Message: "bad"})
+
+	out, err := HTML("(stdin)", result, nil)
+	if err != nil {
+		t.Fatalf("HTML error: %v", err)
+	}
+	if !strings.Contains(string(out), "VALIDATION FAILED") {
+		t.Error("expected failure banner")
+	}
+	if strings.Contains(string(out), `id="graph"`) {
+		t.Error("graph section should be omitted without a parsed graph")
+	}
+}
+
+func TestFindingsForTask(t *testing.T) {
+	result := &validator.ValidationResult{Errors: []validator.ValidationError{
+		{Path: "tasks[1].goal"},
+		{Path: "tasks[10].goal"},
+		{Path: "tasks[1]"},
+	}}
+	if got := len(result.ForTask(1)); got != 2 {
+		t.Errorf("ForTask(1) = %d findings, want 2", got)
+	}
+}
+
+func TestSlack(t *testing.T) {
+	result := &validator.ValidationResult{Valid: true, Stats: validator.ValidationStats{TotalTasks: 3}}
+	for i := range 7 {
+		result.AddError(validator.ValidationError{
+			Rule: "V7", Severity: validator.SeverityWarning, Path: fmt.Sprintf("tasks[%d].acceptance[0]", i), Message: "Vague <criterion>",
+		})
+	}
+	result.AddError(validator.ValidationError{Rule: "V13", Severity: validator.SeverityInfo, Path: "tasks[0]", Message: "Large estimate"})
+	result.AddError(validator.ValidationError{Rule: "V4", Severity: validator.SeverityError, Path: "tasks[2].depends_on", Message: "Unknown dependency"})
+
+	out := Slack("plan.json", result, nil, nil, nil)
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	if lines[0] != ":x: *plan.json failed*" || lines[1] != "3 tasks · 1 errors · 7 warnings · 1 infos" {
+		t.Errorf("header = %q", lines[:2])
+	}
+	// The error is listed first; infos are left out.
+	if !strings.HasPrefix(lines[3], "• :x: `V4` `tasks[2].depends_on`") {
+		t.Errorf("first finding = %q, want the V4 error", lines[3])
+	}
+	if strings.Contains(out, "V13") {
+		t.Errorf("infos should not be listed:\n%s", out)
+	}
+	if !strings.Contains(out, "Vague &lt;criterion&gt;") {
+		t.Errorf("messages should be escaped for Slack:\n%s", out)
+	}
+	if lines[len(lines)-1] != "_…and 3 more_" {
+		t.Errorf("last line = %q, want the count of unlisted findings", lines[len(lines)-1])
+	}
+
+	clean := &validator.ValidationResult{Valid: true, Stats: validator.ValidationStats{TotalTasks: 2}}
+	out = Slack("plan.json", clean, &beads.BeadsJSON{EpicID: "bd-a1", TotalCreated: 3, DepsLinked: 1}, nil, nil)
+	if want := ":white_check_mark: *plan.json passed*\n2 tasks · 0 errors · 0 warnings · 0 infos\n*Beads:* epic `bd-a1` · 3 issues created · 1 dependencies linked\n"; out != want {
+		t.Errorf("creation summary =\n%s\nwant\n%s", out, want)
+	}
+	out = Slack("plan.json", clean, nil, &beads.DryRunJSON{Commands: make([]beads.PlannedCommand, 4)}, nil)
+	if !strings.HasSuffix(out, "*Beads dry run:* 4 bd commands planned\n") {
+		t.Errorf("dry-run summary =\n%s", out)
+	}
+	out = Slack("plan.json", clean, &beads.BeadsJSON{EpicID: "bd-a1", TotalCreated: 1}, nil, &beads.ErrorJSON{Message: "bd create failed for <task-b>"})
+	if !strings.HasSuffix(out, "1 issues created · 0 dependencies linked\n:x: *Beads creation failed:* bd create failed for &lt;task-b&gt;\n") {
+		t.Errorf("failure summary =\n%s", out)
+	}
+}
@@ -0,0 +1,78 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nixlim/task_templating/internal/beads"
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// slackListed is how many findings the Slack summary names.
+const slackListed = 5
+
+// slackEscaper escapes the characters Slack treats as markup in text.
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// Slack renders a compact summary in Slack mrkdwn, short enough to post to
+// a channel: the verdict, the counts, the first errors and warnings, and,
+// for a creation run, the epic and issue counts (created), the number of
+// planned commands (plan), or why creation stopped (failure) alongside
+// what it created first. created, plan, and failure may be nil.
+func Slack(title string, result *validator.ValidationResult, created *beads.BeadsJSON, plan *beads.DryRunJSON, failure *beads.ErrorJSON) string {
+	var sb strings.Builder
+	if result.Valid {
+		fmt.Fprintf(&sb, ":white_check_mark: *%s passed*\n", slackEscaper.Replace(title))
+	} else {
+		fmt.Fprintf(&sb, ":x: *%s failed*\n", slackEscaper.Replace(title))
+	}
+	s := result.Stats
+	fmt.Fprintf(&sb, "%d tasks · %d errors · %d warnings · %d infos\n", s.TotalTasks, s.ErrorCount, s.WarningCount, s.InfoCount)
+
+	var top []validator.ValidationError
+	for _, sev := range []validator.Severity{validator.SeverityError, validator.SeverityWarning} {
+		for _, e := range result.Errors {
+			if e.Severity == sev {
+				top = append(top, e)
+			}
+		}
+	}
+	if len(top) > 0 {
+		sb.WriteString("*Top findings*\n")
+		for _, e := range top[:min(len(top), slackListed)] {
+			icon := ":warning:"
+			if e.Severity == validator.SeverityError {
+				icon = ":x:"
+			}
+			fmt.Fprintf(&sb, "• %s `%s` `%s` %s\n", icon, e.Rule, e.Path, slackEscaper.Replace(e.Message))
+		}
+		if n := len(top) - slackListed; n > 0 {
+			fmt.Fprintf(&sb, "_…and %d more_\n", n)
+		}
+	}
+
+	switch {
+	case created != nil:
+		sb.WriteString("*Beads:* ")
+		if created.EpicID != "" {
+			fmt.Fprintf(&sb, "epic `%s` · ", created.EpicID)
+		}
+		fmt.Fprintf(&sb, "%d issues created · %d dependencies linked", created.TotalCreated, created.DepsLinked)
+		if created.DepsExisting > 0 {
+			fmt.Fprintf(&sb, " (%d already linked)", created.DepsExisting)
+		}
+		if len(created.Skipped) > 0 || len(created.Updated) > 0 {
+			fmt.Fprintf(&sb, " · %d existing skipped · %d updated", len(created.Skipped), len(created.Updated))
+		}
+		if len(created.Excluded) > 0 {
+			fmt.Fprintf(&sb, " · %d left out · %d dependencies dropped", len(created.Excluded), len(created.PrunedDeps))
+		}
+		sb.WriteString("\n")
+	case plan != nil:
+		fmt.Fprintf(&sb, "*Beads dry run:* %d bd commands planned\n", len(plan.Commands))
+	}
+	if failure != nil {
+		fmt.Fprintf(&sb, ":x: *Beads creation failed:* %s\n", slackEscaper.Replace(failure.Message))
+	}
+	return sb.String()
+}
@@ -0,0 +1,112 @@
+package scaffold
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// CSVColumns are the columns FromCSV reads, in documentation order.
+var CSVColumns = []string{"id", "name", "goal", "depends_on", "priority", "estimate", "milestone"}
+
+// FromCSV converts a spreadsheet plan into a draft task graph. The first
+// row names the columns (see CSVColumns; case and surrounding spaces are
+// ignored, as are columns with other names), and each later row is a task.
+// Only name is required: a missing id is derived from the name, a missing
+// goal is a TODO value. depends_on lists task ids separated by commas,
+// semicolons, or spaces. Tasks join milestones in order of first mention.
+func FromCSV(r io.Reader) (*validator.TaskGraph, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("CSV is empty; the first row must name the columns (%s)", strings.Join(CSVColumns, ", "))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading CSV: %w", err)
+	}
+	// Spreadsheet exports may start with a byte order mark.
+	col := make(map[string]int)
+	for i, h := range header {
+		h = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+		if slices.Contains(CSVColumns, h) {
+			col[h] = i
+		}
+	}
+	if _, ok := col["name"]; !ok {
+		return nil, fmt.Errorf("CSV has no 'name' column; the first row must name the columns (%s)", strings.Join(CSVColumns, ", "))
+	}
+
+	graph := &validator.TaskGraph{Version: "0.1.0"}
+	usedIDs := make(map[string]bool)
+	milestoneIndex := make(map[string]int)
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading CSV: %w", err)
+		}
+		field := func(name string) string {
+			if i, ok := col[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		if strings.Join(record, "") == "" {
+			continue // Blank spreadsheet row.
+		}
+
+		name := field("name")
+		if name == "" {
+			return nil, fmt.Errorf("CSV line %d: the task has no name", line)
+		}
+		id := field("id")
+		switch {
+		case id == "":
+			id = uniqueID(slugify(name), usedIDs)
+		case usedIDs[id]:
+			return nil, fmt.Errorf("CSV line %d: duplicate id '%s'", line, id)
+		default:
+			usedIDs[id] = true
+		}
+
+		t := draftTask(id, name)
+		if goal := field("goal"); goal != "" {
+			t.Goal = goal
+		}
+		t.Acceptance = []string{"TODO: verifiable assertion that proves this task is complete"}
+		if deps := strings.FieldsFunc(field("depends_on"), func(r rune) bool {
+			return r == ',' || r == ';' || r == ' ' || r == '\t' || r == '\n'
+		}); len(deps) > 0 {
+			t.DependsOn, _ = json.Marshal(deps)
+		}
+		t.Priority = validator.Level(strings.ToLower(field("priority")))
+		t.Estimate = validator.Level(strings.ToLower(field("estimate")))
+		graph.Tasks = append(graph.Tasks, *t)
+
+		if m := field("milestone"); m != "" {
+			i, ok := milestoneIndex[m]
+			if !ok {
+				i = len(graph.Milestones)
+				milestoneIndex[m] = i
+				graph.Milestones = append(graph.Milestones, validator.Milestone{Name: m})
+			}
+			graph.Milestones[i].TaskIDs = append(graph.Milestones[i].TaskIDs, id)
+		}
+	}
+
+	if len(graph.Tasks) == 0 {
+		return nil, fmt.Errorf("CSV has no task rows below the header")
+	}
+	return graph, nil
+}
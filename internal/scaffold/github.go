@@ -0,0 +1,310 @@
+package scaffold
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// GitHubAPI is the REST endpoint FetchGitHubIssues reads from.
+const GitHubAPI = "https://api.github.com"
+
+// GitHubIssue is the part of a GitHub issue the importer reads. It decodes
+// both the REST API's issue objects and the output of
+// `gh issue list --json number,title,body,labels,milestone,url`.
+type GitHubIssue struct {
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	Body    string `json:"body"`
+	URL     string `json:"url"`
+	HTMLURL string `json:"html_url"`
+	Labels  []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	Milestone *struct {
+		Title string `json:"title"`
+	} `json:"milestone"`
+
+	// PullRequest is set on pull requests, which the issues API also lists.
+	PullRequest json.RawMessage `json:"pull_request,omitempty"`
+}
+
+// link returns the issue's web address.
+func (i GitHubIssue) link() string {
+	if i.HTMLURL != "" {
+		return i.HTMLURL
+	}
+	if strings.Contains(i.URL, "api.github.com") {
+		return ""
+	}
+	return i.URL
+}
+
+var (
+	blockedByPattern = regexp.MustCompile(`(?i)\b(?:blocked by|depends on)\s*:?\s*((?:#\d+(?:\s*,\s*|\s+and\s+|\s+)?)+)`)
+	issueRefPattern  = regexp.MustCompile(`#(\d+)`)
+	checkboxPattern  = regexp.MustCompile(`^\[[ xX]\]\s+`)
+	boldLinePattern  = regexp.MustCompile(`^\*\*(.+?):?\*\*:?\s*$`)
+)
+
+// issueSections maps body section headings, lowercased, to the task field
+// they fill.
+var issueSections = map[string]string{
+	"goal": "goal", "summary": "goal", "description": "goal", "objective": "goal",
+	"acceptance": "acceptance", "acceptance criteria": "acceptance", "definition of done": "acceptance", "done when": "acceptance",
+	"constraints": "constraints",
+	"non-goals":   "non_goals", "non goals": "non_goals", "out of scope": "non_goals",
+	"files": "files_scope", "files scope": "files_scope", "files_scope": "files_scope",
+}
+
+// FromGitHubIssues converts issues into a draft task graph, in issue
+// number order. Titles become task names. Body sections named Goal,
+// Acceptance criteria, Constraints, Non-goals, and Files fill those fields;
+// other text goes to notes. "Blocked by #12" or "depends on #12, #13"
+// becomes depends_on when the issue is among those imported. Fields the
+// issue does not supply are TODO values, as in FromMarkdown. A non-empty
+// milestone keeps only issues in that milestone and groups them under it.
+func FromGitHubIssues(issues []GitHubIssue, milestone string) (*validator.TaskGraph, error) {
+	var selected []GitHubIssue
+	for _, is := range issues {
+		if len(is.PullRequest) > 0 && string(is.PullRequest) != "null" {
+			continue
+		}
+		if milestone != "" && (is.Milestone == nil || is.Milestone.Title != milestone) {
+			continue
+		}
+		selected = append(selected, is)
+	}
+	if len(selected) == 0 {
+		if milestone != "" {
+			return nil, fmt.Errorf("no issues in milestone '%s'", milestone)
+		}
+		return nil, fmt.Errorf("no issues to import")
+	}
+	slices.SortFunc(selected, func(a, b GitHubIssue) int { return a.Number - b.Number })
+
+	usedIDs := make(map[string]bool)
+	idOf := make(map[int]string, len(selected))
+	for _, is := range selected {
+		idOf[is.Number] = uniqueID(slugify(is.Title), usedIDs)
+	}
+
+	graph := &validator.TaskGraph{Version: "0.1.0"}
+	for _, is := range selected {
+		graph.Tasks = append(graph.Tasks, issueTask(is, idOf))
+	}
+	if milestone != "" {
+		m := validator.Milestone{Name: milestone}
+		for _, t := range graph.Tasks {
+			m.TaskIDs = append(m.TaskIDs, t.TaskID)
+		}
+		graph.Milestones = []validator.Milestone{m}
+	}
+	return graph, nil
+}
+
+// issueTask drafts the task for one issue; idOf maps the imported issue
+// numbers to task_ids.
+func issueTask(is GitHubIssue, idOf map[int]string) validator.TaskNode {
+	t := *draftTask(idOf[is.Number], strings.TrimSpace(is.Title))
+	sections, rest := splitIssueBody(is.Body)
+
+	// Without a goal section, an opening paragraph of prose is the goal.
+	goal := firstParagraph(sections["goal"])
+	if goal == "" {
+		first, remainder, _ := strings.Cut(strings.TrimSpace(rest), "\n\n")
+		if p := firstParagraph(first); p != "" && !bulletPattern.MatchString(p) && !blockedByPattern.MatchString(p) {
+			goal, rest = p, remainder
+		}
+	}
+	if goal != "" {
+		t.Goal = goal
+	}
+	for _, a := range listItems(sections["acceptance"]) {
+		t.Acceptance = append(t.Acceptance, padCriterion(a))
+	}
+	if len(t.Acceptance) == 0 {
+		t.Acceptance = []string{"TODO: verifiable assertion that proves this task is complete"}
+	}
+	if items := listItems(sections["constraints"]); len(items) > 0 {
+		t.Constraints, _ = json.Marshal(items)
+	}
+	if items := listItems(sections["files_scope"]); len(items) > 0 {
+		t.FilesScope, _ = json.Marshal(items)
+	}
+	t.NonGoals = listItems(sections["non_goals"])
+
+	var deps []string
+	var unresolved []string
+	for _, m := range blockedByPattern.FindAllStringSubmatch(is.Body, -1) {
+		for _, ref := range issueRefPattern.FindAllStringSubmatch(m[1], -1) {
+			n, _ := strconv.Atoi(ref[1])
+			switch id, ok := idOf[n]; {
+			case !ok:
+				unresolved = append(unresolved, "#"+ref[1])
+			case n != is.Number && !slices.Contains(deps, id):
+				deps = append(deps, id)
+			}
+		}
+	}
+	if len(deps) > 0 {
+		t.DependsOn, _ = json.Marshal(deps)
+	}
+
+	for _, l := range is.Labels {
+		if label := slugify(l.Name); !slices.Contains(t.Labels, label) && len(t.Labels) < 10 {
+			t.Labels = append(t.Labels, label)
+		}
+	}
+
+	notes := []string{fmt.Sprintf("Imported from GitHub issue #%d.", is.Number)}
+	if link := is.link(); link != "" {
+		notes[0] = fmt.Sprintf("Imported from GitHub issue #%d (%s).", is.Number, link)
+	}
+	if len(unresolved) > 0 {
+		notes = append(notes, fmt.Sprintf("Blocked by issues that were not imported: %s.", strings.Join(unresolved, ", ")))
+	}
+	if rest = strings.TrimSpace(rest); rest != "" {
+		notes = append(notes, rest)
+	}
+	t.Notes = strings.Join(notes, "\n\n")
+	return t
+}
+
+// splitIssueBody splits a markdown issue body into its known sections,
+// keyed by task field, and the remaining text. A section starts at a
+// heading or a line that is only bold text, such as "**Acceptance:**".
+func splitIssueBody(body string) (map[string]string, string) {
+	sections := make(map[string]string)
+	var rest strings.Builder
+	field := ""
+	for _, line := range strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n") {
+		title := ""
+		if m := headingPattern.FindStringSubmatch(line); m != nil {
+			title = m[2]
+		} else if m := boldLinePattern.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+			title = m[1]
+		}
+		if title != "" {
+			field = issueSections[strings.ToLower(strings.TrimSuffix(strings.TrimSpace(title), ":"))]
+			if field == "" {
+				rest.WriteString(line + "\n")
+			}
+			continue
+		}
+		if field == "" {
+			rest.WriteString(line + "\n")
+		} else {
+			sections[field] += line + "\n"
+		}
+	}
+	return sections, rest.String()
+}
+
+// firstParagraph returns the first paragraph of text on one line.
+func firstParagraph(text string) string {
+	para, _, _ := strings.Cut(strings.TrimSpace(text), "\n\n")
+	return strings.Join(strings.Fields(para), " ")
+}
+
+// listItems returns the bullet items of a section, without checkboxes, or
+// its non-empty lines when it has no bullets.
+func listItems(text string) []string {
+	var bullets, lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if m := bulletPattern.FindStringSubmatch(line); m != nil {
+			bullets = append(bullets, checkboxPattern.ReplaceAllString(m[2], ""))
+		} else if l := strings.TrimSpace(line); l != "" {
+			lines = append(lines, l)
+		}
+	}
+	if len(bullets) > 0 {
+		return bullets
+	}
+	return lines
+}
+
+// FetchGitHubIssues reads the issues of repo ("owner/name") from the
+// GitHub REST API at base: those in the named milestone in any state, or
+// all open issues when milestone is empty. token, if set, authenticates
+// the requests, which private repositories need.
+func FetchGitHubIssues(ctx context.Context, base, repo, milestone, token string) ([]GitHubIssue, error) {
+	if owner, name, ok := strings.Cut(repo, "/"); !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return nil, fmt.Errorf("invalid repository '%s': use owner/name", repo)
+	}
+	gh := githubClient{client: &http.Client{Timeout: 30 * time.Second}, base: base + "/repos/" + repo, token: token}
+
+	query := url.Values{"state": {"open"}, "per_page": {"100"}}
+	if milestone != "" {
+		var milestones []githubMilestone
+		if err := gh.get(ctx, "/milestones", url.Values{"state": {"all"}, "per_page": {"100"}}, &milestones); err != nil {
+			return nil, err
+		}
+		i := slices.IndexFunc(milestones, func(m githubMilestone) bool { return m.Title == milestone })
+		if i < 0 {
+			return nil, fmt.Errorf("repository '%s' has no milestone '%s'", repo, milestone)
+		}
+		query.Set("milestone", strconv.Itoa(milestones[i].Number))
+		query.Set("state", "all")
+	}
+
+	var issues []GitHubIssue
+	for page := 1; ; page++ {
+		query.Set("page", strconv.Itoa(page))
+		var batch []GitHubIssue
+		if err := gh.get(ctx, "/issues", query, &batch); err != nil {
+			return nil, err
+		}
+		issues = append(issues, batch...)
+		if len(batch) < 100 {
+			return issues, nil
+		}
+	}
+}
+
+type githubMilestone struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+}
+
+// githubClient reads JSON from one repository's REST endpoints.
+type githubClient struct {
+	client *http.Client
+	base   string
+	token  string
+}
+
+// get decodes the JSON response of a GET request into v.
+func (gh githubClient) get(ctx context.Context, path string, query url.Values, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, gh.base+path+"?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if gh.token != "" {
+		req.Header.Set("Authorization", "Bearer "+gh.token)
+	}
+	resp, err := gh.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("reading GitHub issues: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("reading GitHub issues: %s returned %s: %s", req.URL.Path, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("reading GitHub issues: decoding %s: %w", req.URL.Path, err)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package scaffold
+
+import (
+	"fmt"
+	"go/format"
+	"path"
+	"strings"
+	"unicode"
+
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// maxTestNameLen bounds generated test function names so they stay readable.
+const maxTestNameLen = 60
+
+// GoTestStub generates a _test.go skeleton for task with one test function
+// per acceptance criterion. Each test is skipped until implemented; the
+// task's inputs and outputs are carried in doc comments. When pkg is empty
+// the package name is derived from the first .go file in files_scope.
+func GoTestStub(task *validator.TaskNode, pkg string) ([]byte, error) {
+	if pkg == "" {
+		pkg = goPackageName(task)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "package %s\n\n", pkg)
+	sb.WriteString("import \"testing\"\n\n")
+
+	fmt.Fprintf(&sb, "// Tests for task %s: %s\n", task.TaskID, task.TaskName)
+	sb.WriteString("//\n")
+	writeCommentLines(&sb, "Goal: "+task.Goal)
+	if len(task.Inputs) > 0 {
+		sb.WriteString("//\n// Inputs:\n")
+		for _, in := range task.Inputs {
+			writeCommentLines(&sb, fmt.Sprintf("  - %s (%s): %s; source: %s", in.Name, in.Type, in.Constraints, in.Source))
+		}
+	}
+	if len(task.Outputs) > 0 {
+		sb.WriteString("//\n// Outputs:\n")
+		for _, out := range task.Outputs {
+			writeCommentLines(&sb, fmt.Sprintf("  - %s (%s): %s; destination: %s", out.Name, out.Type, out.Constraints, out.Destination))
+		}
+	}
+
+	used := make(map[string]bool)
+	for _, criterion := range task.Acceptance {
+		name := uniqueTestName(testName(criterion), used)
+		sb.WriteString("\n")
+		fmt.Fprintf(&sb, "// %s verifies the acceptance criterion:\n", name)
+		writeCommentLines(&sb, "  "+criterion)
+		fmt.Fprintf(&sb, "func %s(t *testing.T) {\n", name)
+		sb.WriteString("\tt.Skip(\"not implemented\")\n")
+		sb.WriteString("}\n")
+	}
+
+	src, err := format.Source([]byte(sb.String()))
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated test file: %w", err)
+	}
+	return src, nil
+}
+
+// testName converts a criterion into a Go test function name.
+func testName(criterion string) string {
+	var sb strings.Builder
+	sb.WriteString("Test")
+	upper := true
+	for _, r := range criterion {
+		if !(unicode.IsLetter(r) || unicode.IsDigit(r)) || r > unicode.MaxASCII {
+			upper = true
+			continue
+		}
+		if sb.Len() >= maxTestNameLen {
+			break
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		sb.WriteRune(r)
+	}
+	if sb.Len() == len("Test") {
+		sb.WriteString("Criterion")
+	}
+	return sb.String()
+}
+
+// uniqueTestName appends a numeric suffix until name is unused.
+func uniqueTestName(name string, used map[string]bool) string {
+	candidate := name
+	for n := 2; used[candidate]; n++ {
+		candidate = fmt.Sprintf("%s%d", name, n)
+	}
+	used[candidate] = true
+	return candidate
+}
+
+// goPackageName derives a package name from the directory of the first Go
+// file in files_scope, falling back to "main".
+func goPackageName(task *validator.TaskNode) string {
+	files, _, err := task.ParseFilesScope()
+	if err != nil {
+		return "main"
+	}
+	for _, f := range files {
+		if !strings.HasSuffix(f, ".go") {
+			continue
+		}
+		dir := path.Base(path.Dir(validator.NormalizeScopePath(f)))
+		var sb strings.Builder
+		for _, r := range strings.ToLower(dir) {
+			if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9' && sb.Len() > 0) {
+				sb.WriteRune(r)
+			}
+		}
+		if sb.Len() > 0 {
+			return sb.String()
+		}
+	}
+	return "main"
+}
+
+// writeCommentLines writes text as one or more // comment lines.
+func writeCommentLines(sb *strings.Builder, text string) {
+	for _, line := range strings.Split(text, "\n") {
+		sb.WriteString("// " + line + "\n")
+	}
+}
@@ -0,0 +1,147 @@
+package scaffold
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+var (
+	headingPattern = regexp.MustCompile(`^(#{1,6})\s+(.+?)\s*#*\s*$`)
+	bulletPattern  = regexp.MustCompile(`^(\s*)(?:[-*+]|\d+[.)])\s+(?:\[[ xX]\]\s+)?(.+?)\s*$`)
+	slugStrip      = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// maxTaskIDLen and maxTaskNameLen mirror the schema limits on task_id and task_name.
+const (
+	maxTaskIDLen   = 60
+	maxTaskNameLen = 80
+)
+
+// FromMarkdown converts a markdown plan into a draft task graph. Headings
+// become milestones, top-level bullets become tasks, and indented bullets
+// under a task become its draft acceptance criteria. Goals, inputs, and
+// outputs are TODO values that the author refines before validation.
+func FromMarkdown(r io.Reader) (*validator.TaskGraph, error) {
+	graph := &validator.TaskGraph{Version: "0.1.0"}
+	usedIDs := make(map[string]bool)
+
+	var milestone *validator.Milestone
+	var task *validator.TaskNode
+
+	flushTask := func() {
+		if task == nil {
+			return
+		}
+		if len(task.Acceptance) == 0 {
+			task.Acceptance = []string{"TODO: verifiable assertion that proves this task is complete"}
+		}
+		graph.Tasks = append(graph.Tasks, *task)
+		if milestone != nil {
+			milestone.TaskIDs = append(milestone.TaskIDs, task.TaskID)
+		}
+		task = nil
+	}
+	flushMilestone := func() {
+		flushTask()
+		// The schema requires at least one task per milestone.
+		if milestone != nil && len(milestone.TaskIDs) > 0 {
+			graph.Milestones = append(graph.Milestones, *milestone)
+		}
+		milestone = nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if m := headingPattern.FindStringSubmatch(line); m != nil {
+			flushMilestone()
+			milestone = &validator.Milestone{Name: m[2]}
+			continue
+		}
+
+		m := bulletPattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		indent, text := m[1], m[2]
+
+		if len(indent) > 0 && task != nil {
+			task.Acceptance = append(task.Acceptance, padCriterion(text))
+			continue
+		}
+
+		flushTask()
+		task = draftTask(uniqueID(slugify(text), usedIDs), text)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading markdown: %w", err)
+	}
+	flushMilestone()
+
+	if len(graph.Tasks) == 0 {
+		return nil, fmt.Errorf("no bullet items found; list each task as a '- ' bullet under a heading")
+	}
+	return graph, nil
+}
+
+// draftTask builds a schema-shaped task from a single bullet line.
+func draftTask(id, text string) *validator.TaskNode {
+	name := text
+	if len(name) < 5 {
+		name = "Implement " + name
+	}
+	if len(name) > maxTaskNameLen {
+		name = strings.TrimSpace(name[:maxTaskNameLen])
+	}
+
+	return &validator.TaskNode{
+		TaskID:   id,
+		TaskName: name,
+		Goal:     "TODO: state the observable outcome of '" + text + "' in one sentence.",
+		Inputs: []validator.InputSpec{
+			{Name: "TODO", Type: "TODO", Constraints: "none", Source: "TODO"},
+		},
+		Outputs: []validator.OutputSpec{
+			{Name: "TODO", Type: "TODO", Constraints: "none", Destination: "TODO"},
+		},
+		DependsOn:   naJSON("TODO: list prerequisite task_ids or justify N/A"),
+		Constraints: naJSON("TODO: list constraints or justify N/A"),
+		FilesScope:  naJSON("TODO: list files or justify N/A"),
+	}
+}
+
+// padCriterion keeps short sub-bullets above the schema's minimum criterion length.
+func padCriterion(text string) string {
+	if len(text) >= 10 {
+		return text
+	}
+	return "TODO: " + text
+}
+
+// slugify converts free text into a kebab-case task_id candidate.
+func slugify(text string) string {
+	slug := strings.Trim(slugStrip.ReplaceAllString(strings.ToLower(text), "-"), "-")
+	if len(slug) > maxTaskIDLen-4 {
+		slug = strings.Trim(slug[:maxTaskIDLen-4], "-")
+	}
+	if slug == "" {
+		slug = "task"
+	}
+	return slug
+}
+
+// uniqueID appends a numeric suffix until id is not yet in used.
+func uniqueID(id string, used map[string]bool) string {
+	candidate := id
+	for n := 2; used[candidate]; n++ {
+		candidate = fmt.Sprintf("%s-%d", id, n)
+	}
+	used[candidate] = true
+	return candidate
+}
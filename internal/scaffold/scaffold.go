@@ -0,0 +1,127 @@
+// Package scaffold generates starter documents that conform to the
+// Structured Task Template Spec, so authors begin from a valid shape
+// instead of reverse-engineering it from validation errors.
+package scaffold
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// TaskSkeleton returns a single task node with every required field filled
+// with guidance text, plus examples of the contextual and optional fields.
+func TaskSkeleton() validator.TaskNode {
+	return validator.TaskNode{
+		TaskID:   "first-task",
+		TaskName: "Implement the first component (imperative phrase, starts with a verb)",
+		Goal:     "TODO: state the observable outcome in one sentence, e.g. 'Parse() returns a Config for every valid input file.'",
+		Inputs: []validator.InputSpec{
+			{
+				Name:        "input_name",
+				Type:        "string",
+				Constraints: "TODO: constraint expression such as len > 0, or 'none'",
+				Source:      "TODO: where the value comes from (CLI argument, database record, config file)",
+			},
+		},
+		Outputs: []validator.OutputSpec{
+			{
+				Name:        "output_name",
+				Type:        "string",
+				Constraints: "TODO: constraint expression such as len > 0, or 'none'",
+				Destination: "TODO: where the value goes (return value, stdout, database table)",
+			},
+		},
+		Acceptance: []string{
+			"TODO: Given input 'x', the function returns 'y' (one verifiable assertion per entry)",
+		},
+		DependsOn:   naJSON("Standalone task with no prerequisite tasks"),
+		Constraints: json.RawMessage(`["TODO: non-negotiable rule, e.g. 'No new third-party dependencies'"]`),
+		FilesScope:  json.RawMessage(`["internal/example/example.go", "internal/example/example_test.go"]`),
+		NonGoals:    []string{"TODO: behavior explicitly excluded from this task"},
+		Effects:     json.RawMessage(`"None"`),
+		ErrorCases: []validator.ErrorSpec{
+			{
+				Condition: "TODO: when this failure occurs",
+				Behavior:  "TODO: what the code does in response",
+				Output:    "TODO: what the caller sees",
+			},
+		},
+		Priority: "medium",
+		Estimate: "small",
+		Notes:    "Replace every TODO value, then run taskval to check the result. Contextual fields (depends_on, constraints, files_scope) take either a list or {\"status\": \"N/A\", \"reason\": \"...\"}.",
+	}
+}
+
+// GraphSkeleton returns a two-task graph with one sample milestone, showing
+// both an N/A dependency and a real depends_on reference.
+func GraphSkeleton() validator.TaskGraph {
+	first := TaskSkeleton()
+
+	second := TaskSkeleton()
+	second.TaskID = "second-task"
+	second.TaskName = "Add the second component that consumes the first"
+	second.Inputs[0].Name = "output_name"
+	second.Inputs[0].Source = "output_name from first-task"
+	second.Outputs[0].Name = "second_output"
+	second.DependsOn = json.RawMessage(`["first-task"]`)
+	second.FilesScope = naJSON("Scope is decided once first-task lands")
+	second.ErrorCases = nil
+	second.Notes = ""
+
+	return validator.TaskGraph{
+		Version: "0.1.0",
+		Defaults: &validator.Defaults{
+			Constraints: []string{"TODO: rule inherited by every task"},
+			Acceptance:  []string{"TODO: criterion inherited by every task, e.g. 'go test ./... passes'"},
+		},
+		Milestones: []validator.Milestone{
+			{
+				Name:    "M1 - First milestone",
+				TaskIDs: []string{first.TaskID, second.TaskID},
+			},
+		},
+		Tasks: []validator.TaskNode{first, second},
+	}
+}
+
+// Render returns the skeleton for the given mode as indented JSON.
+func Render(mode validator.Mode) ([]byte, error) {
+	var v any
+	switch mode {
+	case validator.ModeSingleTask:
+		v = TaskSkeleton()
+	case validator.ModeTaskGraph:
+		v = GraphSkeleton()
+	default:
+		return nil, fmt.Errorf("unknown mode: %d", mode)
+	}
+
+	return marshal(v)
+}
+
+// RenderGraph marshals a graph as indented JSON with a trailing newline.
+func RenderGraph(graph *validator.TaskGraph) ([]byte, error) {
+	return marshal(graph)
+}
+
+// marshal encodes v as indented JSON without HTML escaping, so constraint
+// expressions like "x > 0" stay readable in generated files.
+func marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return nil, fmt.Errorf("marshaling document: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// naJSON builds an explicit N/A object for a contextual field.
+func naJSON(reason string) json.RawMessage {
+	data, _ := json.Marshal(validator.NotApplicable{Status: "N/A", Reason: reason})
+	return data
+}
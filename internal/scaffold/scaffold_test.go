@@ -0,0 +1,367 @@
+package scaffold
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+func TestRenderTaskPassesValidation(t *testing.T) {
+	data, err := Render(validator.ModeSingleTask)
+	if err != nil {
+		t.Fatalf("Render error: %v", err)
+	}
+
+	result, err := validator.Validate(data, validator.ModeSingleTask)
+	if err != nil {
+		t.Fatalf("Validate error: %v", err)
+	}
+	if !result.Valid {
+		for _, e := range result.Errors {
+			t.Errorf("unexpected finding: %s", e.Error())
+		}
+	}
+}
+
+func TestRenderGraphPassesValidation(t *testing.T) {
+	data, err := Render(validator.ModeTaskGraph)
+	if err != nil {
+		t.Fatalf("Render error: %v", err)
+	}
+
+	result, err := validator.Validate(data, validator.ModeTaskGraph)
+	if err != nil {
+		t.Fatalf("Validate error: %v", err)
+	}
+	if !result.Valid {
+		for _, e := range result.Errors {
+			t.Errorf("unexpected finding: %s", e.Error())
+		}
+	}
+	if result.Graph == nil || len(result.Graph.Milestones) != 1 {
+		t.Error("expected graph skeleton with one milestone")
+	}
+}
+
+func TestFromMarkdown(t *testing.T) {
+	plan := `# Plan
+
+## Phase 1
+- Parse config file
+  - Returns error on missing file
+- [ ] Parse config file
+* Add CLI flag
+
+## Empty phase
+
+## Phase 2
+1. Wire everything together
+`
+	graph, err := FromMarkdown(strings.NewReader(plan))
+	if err != nil {
+		t.Fatalf("FromMarkdown error: %v", err)
+	}
+
+	if len(graph.Tasks) != 4 {
+		t.Fatalf("expected 4 tasks, got %d", len(graph.Tasks))
+	}
+	if graph.Tasks[0].TaskID != "parse-config-file" || graph.Tasks[1].TaskID != "parse-config-file-2" {
+		t.Errorf("unexpected task IDs: %s, %s", graph.Tasks[0].TaskID, graph.Tasks[1].TaskID)
+	}
+	if len(graph.Tasks[0].Acceptance) != 1 || graph.Tasks[0].Acceptance[0] != "Returns error on missing file" {
+		t.Errorf("sub-bullet should become acceptance, got %v", graph.Tasks[0].Acceptance)
+	}
+
+	// "Plan" and "Empty phase" have no tasks and are dropped.
+	if len(graph.Milestones) != 2 {
+		t.Fatalf("expected 2 milestones, got %d", len(graph.Milestones))
+	}
+	if graph.Milestones[0].Name != "Phase 1" || len(graph.Milestones[0].TaskIDs) != 3 {
+		t.Errorf("unexpected first milestone: %+v", graph.Milestones[0])
+	}
+
+	data, err := RenderGraph(graph)
+	if err != nil {
+		t.Fatalf("RenderGraph error: %v", err)
+	}
+	result, err := validator.Validate(data, validator.ModeTaskGraph)
+	if err != nil {
+		t.Fatalf("Validate error: %v", err)
+	}
+	for _, e := range result.Errors {
+		if e.Rule == "SCHEMA" {
+			t.Errorf("draft graph should be schema-valid: %s", e.Error())
+		}
+	}
+}
+
+func TestFromMarkdownNoBullets(t *testing.T) {
+	if _, err := FromMarkdown(strings.NewReader("# Only a heading\n\nSome prose.\n")); err == nil {
+		t.Error("expected error for markdown without bullets")
+	}
+}
+
+func TestGoTestStub(t *testing.T) {
+	task := &validator.TaskNode{
+		TaskID:     "calc-total",
+		TaskName:   "Implement total calculation",
+		Goal:       "CalculateTotal returns the discounted total.",
+		Inputs:     []validator.InputSpec{{Name: "price", Type: "f64", Constraints: "price > 0", Source: "order"}},
+		Outputs:    []validator.OutputSpec{{Name: "total", Type: "f64", Constraints: "total >= 0", Destination: "return"}},
+		Acceptance: []string{"CalculateTotal(100.0, Fixed(10.0)) == 90.0", "Returns error on zero price", "Returns error on zero price"},
+		FilesScope: json.RawMessage(`["internal/pricing/discount.go"]`),
+	}
+
+	src, err := GoTestStub(task, "")
+	if err != nil {
+		t.Fatalf("GoTestStub error: %v", err)
+	}
+	out := string(src)
+
+	for _, want := range []string{
+		"package pricing",
+		"//   - price (f64): price > 0; source: order",
+		"//   - total (f64): total >= 0; destination: return",
+		"func TestCalculateTotal1000Fixed100900(t *testing.T) {",
+		"func TestReturnsErrorOnZeroPrice(t *testing.T) {",
+		"func TestReturnsErrorOnZeroPrice2(t *testing.T) {",
+		`t.Skip("not implemented")`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("stub missing %q\n%s", want, out)
+		}
+	}
+}
+
+func TestFromGitHubIssues(t *testing.T) {
+	var issues []GitHubIssue
+	if err := json.Unmarshal([]byte(`[
+		{"number": 12, "title": "Add retry middleware", "html_url": "https://github.com/o/r/issues/12",
+		 "labels": [{"name": "Backend"}], "milestone": {"title": "v1"},
+		 "body": "Blocked by #10 and #99\n\n## Goal\nRetry() retries idempotent requests up to 3 times.\n\n## Acceptance criteria\n- [ ] Given a 503, Retry sends the request again\n- [x] Given a 400, Retry returns the error at once\n\n## Context\nSee the outage report."},
+		{"number": 10, "title": "Build HTTP client", "milestone": {"title": "v1"},
+		 "body": "Client.Do returns the response for a 2xx status.\r\n\r\n**Files:**\r\n- internal/http/client.go\r\n"},
+		{"number": 11, "title": "Later work", "milestone": {"title": "v2"}, "body": ""},
+		{"number": 13, "title": "A pull request", "milestone": {"title": "v1"}, "pull_request": {"url": "x"}}
+	]`), &issues); err != nil {
+		t.Fatal(err)
+	}
+
+	graph, err := FromGitHubIssues(issues, "v1")
+	if err != nil {
+		t.Fatalf("FromGitHubIssues error: %v", err)
+	}
+	if len(graph.Tasks) != 2 || graph.Tasks[0].TaskID != "build-http-client" || graph.Tasks[1].TaskID != "add-retry-middleware" {
+		t.Fatalf("tasks = %+v, want the two v1 issues in number order", graph.Tasks)
+	}
+	if len(graph.Milestones) != 1 || graph.Milestones[0].Name != "v1" || len(graph.Milestones[0].TaskIDs) != 2 {
+		t.Errorf("milestones = %+v, want v1 with both tasks", graph.Milestones)
+	}
+
+	client := graph.Tasks[0]
+	if client.Goal != "Client.Do returns the response for a 2xx status." {
+		t.Errorf("goal from the opening paragraph = %q", client.Goal)
+	}
+	if string(client.FilesScope) != `["internal/http/client.go"]` {
+		t.Errorf("files_scope = %s", client.FilesScope)
+	}
+
+	retry := graph.Tasks[1]
+	if retry.Goal != "Retry() retries idempotent requests up to 3 times." {
+		t.Errorf("goal = %q", retry.Goal)
+	}
+	if len(retry.Acceptance) != 2 || retry.Acceptance[1] != "Given a 400, Retry returns the error at once" {
+		t.Errorf("acceptance = %q", retry.Acceptance)
+	}
+	if string(retry.DependsOn) != `["build-http-client"]` {
+		t.Errorf("depends_on = %s, want the imported blocker only", retry.DependsOn)
+	}
+	if len(retry.Labels) != 1 || retry.Labels[0] != "backend" {
+		t.Errorf("labels = %q", retry.Labels)
+	}
+	for _, want := range []string{"issue #12 (https://github.com/o/r/issues/12)", "not imported: #99", "## Context\nSee the outage report."} {
+		if !strings.Contains(retry.Notes, want) {
+			t.Errorf("notes missing %q:\n%s", want, retry.Notes)
+		}
+	}
+
+	// The draft passes the schema once TODO values are its only problem.
+	data, _ := RenderGraph(graph)
+	result, err := validator.ValidateWithOptions(data, validator.ModeTaskGraph, validator.Options{Tiers: validator.SchemaTier})
+	if err != nil || !result.Valid {
+		t.Errorf("draft graph fails the schema: %v %+v", err, result)
+	}
+
+	if _, err := FromGitHubIssues(issues, "v3"); err == nil {
+		t.Error("expected an error for a milestone without issues")
+	}
+}
+
+func TestFetchGitHubIssues(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer tok" {
+			t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+		}
+		switch r.URL.Path {
+		case "/repos/o/r/milestones":
+			fmt.Fprint(w, `[{"number": 4, "title": "v1"}]`)
+		case "/repos/o/r/issues":
+			if q := r.URL.Query(); q.Get("milestone") != "4" || q.Get("state") != "all" {
+				t.Errorf("issues query = %s", r.URL.RawQuery)
+			}
+			fmt.Fprint(w, `[{"number": 1, "title": "First"}]`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	issues, err := FetchGitHubIssues(context.Background(), srv.URL, "o/r", "v1", "tok")
+	if err != nil || len(issues) != 1 || issues[0].Title != "First" {
+		t.Fatalf("FetchGitHubIssues = %+v, %v", issues, err)
+	}
+	if _, err := FetchGitHubIssues(context.Background(), srv.URL, "o/r", "v9", "tok"); err == nil || !strings.Contains(err.Error(), "no milestone 'v9'") {
+		t.Errorf("unknown milestone error = %v", err)
+	}
+	if _, err := FetchGitHubIssues(context.Background(), srv.URL, "o", "", ""); err == nil {
+		t.Error("expected an error for a repository without owner")
+	}
+}
+
+func TestFromCSV(t *testing.T) {
+	plan := "\ufeffID,Name,Goal,Depends_On,Priority,Estimate,Milestone,Owner\n" +
+		"parser,Write parser,Parse returns a Config for valid input.,,High,small,M1,ana\n" +
+		",Write loader,,parser; cache,,120,M1,\n" +
+		",,,,,,,\n" +
+		"docs,Document the loader,,\"parser, write-loader\",low,,M2,\n"
+	graph, err := FromCSV(strings.NewReader(plan))
+	if err != nil {
+		t.Fatalf("FromCSV error: %v", err)
+	}
+	if len(graph.Tasks) != 3 {
+		t.Fatalf("got %d tasks, want 3 (blank row skipped)", len(graph.Tasks))
+	}
+	parser, loader, docs := graph.Tasks[0], graph.Tasks[1], graph.Tasks[2]
+	if parser.TaskID != "parser" || parser.Goal != "Parse returns a Config for valid input." || parser.Priority != "high" || parser.Estimate != "small" {
+		t.Errorf("parser = %+v", parser)
+	}
+	if loader.TaskID != "write-loader" || !strings.HasPrefix(loader.Goal, "TODO:") || loader.Estimate != "120" {
+		t.Errorf("loader = %+v, want a derived id, TODO goal, and raw estimate", loader)
+	}
+	if string(loader.DependsOn) != `["parser","cache"]` || string(docs.DependsOn) != `["parser","write-loader"]` {
+		t.Errorf("depends_on = %s, %s", loader.DependsOn, docs.DependsOn)
+	}
+	if len(graph.Milestones) != 2 || graph.Milestones[0].Name != "M1" || len(graph.Milestones[0].TaskIDs) != 2 || graph.Milestones[1].TaskIDs[0] != "docs" {
+		t.Errorf("milestones = %+v", graph.Milestones)
+	}
+
+	data, _ := RenderGraph(graph)
+	result, err := validator.ValidateWithOptions(data, validator.ModeTaskGraph, validator.Options{Tiers: validator.SchemaTier})
+	if err != nil || !result.Valid {
+		t.Errorf("draft graph fails the schema: %v %+v", err, result)
+	}
+
+	for plan, want := range map[string]string{
+		"":                        "CSV is empty",
+		"id,goal\na,b\n":          "no 'name' column",
+		"name\n":                  "no task rows",
+		"id,name\na,One\na,Two\n": "line 3: duplicate id 'a'",
+		"id,name\na,One\nb,\n":    "line 3: the task has no name",
+	} {
+		if _, err := FromCSV(strings.NewReader(plan)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("FromCSV(%q) error = %v, want %q", plan, err, want)
+		}
+	}
+}
+
+const testTemplate = `{
+  "name": "feature",
+  "variables": [{"name": "resource"}, {"name": "pkg", "default": "internal/api"}],
+  "graph": {"version": "0.1.0", "tasks": [{
+    "task_id": "${resource}-store",
+    "task_name": "Add ${resource} storage",
+    "goal": "Persist ${resource} records in ${pkg}/store.go.",
+    "inputs": [],
+    "outputs": [{"name": "store", "type": "file", "constraints": "N/A", "destination": "${pkg}/store.go"}],
+    "acceptance": ["go test ./${pkg}/... passes with GOFLAGS=${GOFLAGS}"]
+  }]}
+}`
+
+func TestTemplate(t *testing.T) {
+	tmpl, err := ParseTemplate([]byte(testTemplate))
+	if err != nil {
+		t.Fatalf("ParseTemplate: %v", err)
+	}
+	plan, err := tmpl.Instantiate(map[string]string{"resource": `in"voice`})
+	if err != nil {
+		t.Fatalf("Instantiate: %v", err)
+	}
+	var graph validator.TaskGraph
+	if err := json.Unmarshal(plan, &graph); err != nil {
+		t.Fatalf("instantiated plan is not a graph: %v\n%s", err, plan)
+	}
+	task := graph.Tasks[0]
+	if task.TaskID != `in"voice-store` || task.Goal != `Persist in"voice records in internal/api/store.go.` {
+		t.Errorf("task = %q, %q", task.TaskID, task.Goal)
+	}
+	// Undeclared references, like shell variables, are kept.
+	if task.Acceptance[0] != "go test ./internal/api/... passes with GOFLAGS=${GOFLAGS}" {
+		t.Errorf("acceptance = %q", task.Acceptance[0])
+	}
+
+	if _, err := tmpl.Instantiate(nil); err == nil || !strings.Contains(err.Error(), "needs a value for resource") {
+		t.Errorf("missing variable error = %v", err)
+	}
+	if _, err := tmpl.Instantiate(map[string]string{"resource": "x", "other": "y"}); err == nil || !strings.Contains(err.Error(), "no variable 'other'") {
+		t.Errorf("unknown variable error = %v", err)
+	}
+	for _, bad := range []string{
+		`{"name": "t"}`,
+		`{"name": "t", "graph": {}, "variables": [{"name": "1x"}]}`,
+		`{"name": "t", "graph": {}, "variables": [{"name": "x"}, {"name": "x"}]}`,
+		`{"name": "t", "graph": {}, "extra": true}`,
+		`{"name": "t", "graph": {}} {}`,
+	} {
+		if _, err := ParseTemplate([]byte(bad)); err == nil {
+			t.Errorf("ParseTemplate(%s) should fail", bad)
+		}
+	}
+}
+
+func TestFetchTemplate(t *testing.T) {
+	for source, want := range map[string][2]string{
+		"git@github.com:org/templates:backend-feature": {"git@github.com:org/templates", "backend-feature"},
+		"https://example.com/templates.git:api/crud":   {"https://example.com/templates.git", "api/crud"},
+		"https://example.com:8443/backend.json":        {},
+		"plans/backend.json":                           {},
+	} {
+		repo, name, _ := splitGitSource(source)
+		if [2]string{repo, name} != want {
+			t.Errorf("splitGitSource(%q) = %q, %q; want %q", source, repo, name, want)
+		}
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/feature.json" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, testTemplate)
+	}))
+	defer srv.Close()
+	if data, err := FetchTemplate(context.Background(), srv.URL+"/feature.json"); err != nil || string(data) != testTemplate {
+		t.Errorf("FetchTemplate = %q, %v", data, err)
+	}
+	if _, err := FetchTemplate(context.Background(), srv.URL+"/missing.json"); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("missing template error = %v", err)
+	}
+	if _, err := FetchTemplate(context.Background(), "git@example.com:org/t:../secret"); err == nil || !strings.Contains(err.Error(), "invalid template name") {
+		t.Errorf("path traversal error = %v", err)
+	}
+}
@@ -0,0 +1,217 @@
+package scaffold
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// Template is a reusable plan blueprint: a task graph whose strings may
+// contain ${name} references to the variables it declares.
+//
+//	{
+//	  "name": "backend-feature",
+//	  "description": "Endpoint, storage, and tests for one resource",
+//	  "variables": [{"name": "resource", "description": "Resource noun, e.g. invoice"}],
+//	  "graph": {"version": "0.1.0", "tasks": [{"task_id": "${resource}-store", ...}]}
+//	}
+//
+// References to names the template does not declare, such as shell
+// variables in verification commands, are left as they are.
+type Template struct {
+	Name        string             `json:"name"`
+	Description string             `json:"description,omitempty"`
+	Variables   []TemplateVariable `json:"variables,omitempty"`
+	Graph       json.RawMessage    `json:"graph"`
+}
+
+// TemplateVariable is a value the user supplies when instantiating a
+// Template. A variable without a default is required.
+type TemplateVariable struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description,omitempty"`
+	Default     *string `json:"default,omitempty"`
+}
+
+var (
+	variableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	variableRefPattern  = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+	templateNamePattern = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]*(/[A-Za-z0-9_][A-Za-z0-9_.-]*)*$`)
+)
+
+// ParseTemplate decodes a template document and checks its declarations.
+func ParseTemplate(data []byte) (*Template, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var t Template
+	if err := dec.Decode(&t); err != nil {
+		return nil, fmt.Errorf("parsing template: %w", err)
+	}
+	if err := validator.EndOfInput(dec); err != nil {
+		return nil, fmt.Errorf("parsing template: the template is complete, but %w", err)
+	}
+	if len(bytes.TrimSpace(t.Graph)) == 0 || bytes.Equal(bytes.TrimSpace(t.Graph), []byte("null")) {
+		return nil, errors.New("template has no 'graph'")
+	}
+	seen := make(map[string]bool)
+	for _, v := range t.Variables {
+		if !variableNamePattern.MatchString(v.Name) {
+			return nil, fmt.Errorf("template variable '%s': names are letters, digits, and underscores, not starting with a digit", v.Name)
+		}
+		if seen[v.Name] {
+			return nil, fmt.Errorf("template declares variable '%s' twice", v.Name)
+		}
+		seen[v.Name] = true
+	}
+	return &t, nil
+}
+
+// Instantiate returns the template's graph with each declared variable
+// replaced by its value in values, or its default when values has none,
+// as indented JSON. Values need no JSON escaping. It fails when a required
+// variable has no value or values names an undeclared one.
+func (t *Template) Instantiate(values map[string]string) ([]byte, error) {
+	resolved := make(map[string]string, len(t.Variables))
+	var missing []string
+	for _, v := range t.Variables {
+		switch value, ok := values[v.Name]; {
+		case ok:
+			resolved[v.Name] = value
+		case v.Default != nil:
+			resolved[v.Name] = *v.Default
+		default:
+			missing = append(missing, v.Name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("template '%s' needs a value for %s", t.Name, strings.Join(missing, ", "))
+	}
+	for name := range values {
+		if _, ok := resolved[name]; !ok {
+			return nil, fmt.Errorf("template '%s' declares no variable '%s'", t.Name, name)
+		}
+	}
+
+	// A ${ can only occur inside a JSON string, so each reference is
+	// replaced with its value escaped for one.
+	expanded := variableRefPattern.ReplaceAllFunc(t.Graph, func(ref []byte) []byte {
+		value, ok := resolved[string(ref[2:len(ref)-1])]
+		if !ok {
+			return ref
+		}
+		quoted, _ := json.Marshal(value)
+		return quoted[1 : len(quoted)-1]
+	})
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, expanded, "", "  "); err != nil {
+		return nil, fmt.Errorf("template '%s' graph: %w", t.Name, err)
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+// maxTemplateSize bounds a template read over HTTP.
+const maxTemplateSize = 10 << 20
+
+// FetchTemplate reads the template document at source, which is one of
+//
+//	REPO:NAME      NAME.json or templates/NAME.json in a git repository,
+//	               where REPO is git@host:path, ssh://, git://, or any
+//	               address ending in .git (cloned with the git CLI)
+//	http(s)://URL  the document at URL
+//	PATH           a local file
+func FetchTemplate(ctx context.Context, source string) ([]byte, error) {
+	if repo, name, ok := splitGitSource(source); ok {
+		return fetchGitTemplate(ctx, repo, name)
+	}
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		return fetchURLTemplate(ctx, source)
+	}
+	data, err := os.ReadFile(source)
+	if err != nil {
+		return nil, fmt.Errorf("reading template: %w", err)
+	}
+	return data, nil
+}
+
+// splitGitSource splits a REPO:NAME template source. The name follows the
+// last colon, so scp-style addresses like git@host:org/repo keep theirs.
+func splitGitSource(source string) (repo, name string, ok bool) {
+	i := strings.LastIndex(source, ":")
+	if i < 0 {
+		return "", "", false
+	}
+	repo, name = source[:i], source[i+1:]
+	gitLike := strings.HasSuffix(repo, ".git") ||
+		strings.HasPrefix(repo, "git@") || strings.HasPrefix(repo, "ssh://") || strings.HasPrefix(repo, "git://")
+	if !gitLike || repo == "" || name == "" {
+		return "", "", false
+	}
+	return repo, name, true
+}
+
+// fetchGitTemplate shallow-clones repo into a temp directory and reads the
+// named template from it.
+func fetchGitTemplate(ctx context.Context, repo, name string) ([]byte, error) {
+	if !templateNamePattern.MatchString(name) || slices.Contains(strings.Split(name, "/"), "..") {
+		return nil, fmt.Errorf("invalid template name '%s'", name)
+	}
+	dir, err := os.MkdirTemp("", "taskval-template-*")
+	if err != nil {
+		return nil, fmt.Errorf("fetching template: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	cmd := exec.CommandContext(ctx, "git", "clone", "--depth", "1", "--quiet", "--", repo, dir)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("cloning '%s': %w\n%s", repo, err, strings.TrimSpace(string(out)))
+	}
+	candidates := []string{name + ".json", "templates/" + name + ".json"}
+	for _, c := range candidates {
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(c)))
+		if err == nil {
+			return data, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("reading template: %w", err)
+		}
+	}
+	return nil, fmt.Errorf("repository '%s' has no template '%s' (looked for %s)", repo, name, strings.Join(candidates, " and "))
+}
+
+// fetchURLTemplate reads the template document at url.
+func fetchURLTemplate(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := (&http.Client{Timeout: 30 * time.Second}).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching template: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching template '%s': %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxTemplateSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("fetching template: %w", err)
+	}
+	if len(data) > maxTemplateSize {
+		return nil, fmt.Errorf("template '%s' is larger than %d MB", url, maxTemplateSize>>20)
+	}
+	return data, nil
+}
@@ -0,0 +1,40 @@
+package schedule
+
+import "time"
+
+// Calendar maps working minutes onto calendar dates. Work happens on
+// weekdays only, HoursPerDay hours per day.
+type Calendar struct {
+	Start       time.Time
+	HoursPerDay int
+}
+
+// Date returns the calendar day on which the given working minute falls.
+// Minute offsets that land exactly on a day boundary belong to the earlier
+// day when end is true, so a task ending at 8h on day one ends on day one.
+func (c Calendar) Date(minute int, end bool) time.Time {
+	perDay := c.HoursPerDay * 60
+	if perDay <= 0 {
+		perDay = 8 * 60
+	}
+	days := minute / perDay
+	if end && minute > 0 && minute%perDay == 0 {
+		days--
+	}
+
+	d := time.Date(c.Start.Year(), c.Start.Month(), c.Start.Day(), 0, 0, 0, 0, time.UTC)
+	for isWeekend(d) {
+		d = d.AddDate(0, 0, 1)
+	}
+	for days > 0 {
+		d = d.AddDate(0, 0, 1)
+		if !isWeekend(d) {
+			days--
+		}
+	}
+	return d
+}
+
+func isWeekend(d time.Time) bool {
+	return d.Weekday() == time.Saturday || d.Weekday() == time.Sunday
+}
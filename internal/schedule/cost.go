@@ -0,0 +1,98 @@
+package schedule
+
+import (
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// NoMilestone names the group of tasks that belong to no milestone.
+const NoMilestone = "(no milestone)"
+
+// CostOptions controls a cost estimate.
+type CostOptions struct {
+	// Rate is the spend per agent-hour, applied to tasks without a cost hint.
+	Rate float64
+
+	// Minutes and UnknownMinutes convert estimates as in Options.
+	Minutes        func(estimate string) int
+	UnknownMinutes int
+}
+
+// TaskCost is the estimated spend of one task.
+type TaskCost struct {
+	TaskID    string
+	Milestone string
+	Minutes   int
+	Cost      float64
+
+	// Hint is true when Cost is the task's own cost field rather than
+	// its estimate times the rate.
+	Hint bool
+}
+
+// CostGroup sums the tasks of one milestone, or of the whole graph.
+type CostGroup struct {
+	Name    string
+	Tasks   int
+	Minutes int
+	Cost    float64
+}
+
+// CostReport is the estimated spend of a graph.
+type CostReport struct {
+	// Tasks lists every task in graph order.
+	Tasks []TaskCost
+
+	// Milestones lists the graph's milestones in order, then NoMilestone
+	// if any task is outside all of them. Empty milestones are kept.
+	Milestones []CostGroup
+
+	Total CostGroup
+
+	// Unpriced counts tasks with no cost hint, which are priced at Rate.
+	Unpriced int
+}
+
+// EstimateCost prices each task as its cost hint or, failing that, its
+// estimated agent-hours times the rate, and sums them per milestone. A
+// task counts toward the first milestone that lists it.
+func EstimateCost(graph *validator.TaskGraph, opts CostOptions) *CostReport {
+	r := &CostReport{Total: CostGroup{Name: "Total"}}
+	index := make(map[string]int, len(graph.Milestones)+1)
+	for _, m := range graph.Milestones {
+		if _, dup := index[m.Name]; !dup {
+			index[m.Name] = len(r.Milestones)
+			r.Milestones = append(r.Milestones, CostGroup{Name: m.Name})
+		}
+	}
+
+	for _, t := range graph.Tasks {
+		minutes := opts.Minutes(string(t.Estimate))
+		if minutes <= 0 {
+			minutes = opts.UnknownMinutes
+		}
+		tc := TaskCost{TaskID: t.TaskID, Milestone: graph.MilestoneOf(t.TaskID), Minutes: minutes}
+		if tc.Milestone == "" {
+			tc.Milestone = NoMilestone
+		}
+		if t.Cost != nil {
+			tc.Cost, tc.Hint = *t.Cost, true
+		} else {
+			tc.Cost = float64(minutes) / 60 * opts.Rate
+			r.Unpriced++
+		}
+		r.Tasks = append(r.Tasks, tc)
+
+		i, ok := index[tc.Milestone]
+		if !ok {
+			i = len(r.Milestones)
+			index[tc.Milestone] = i
+			r.Milestones = append(r.Milestones, CostGroup{Name: tc.Milestone})
+		}
+		for _, g := range []*CostGroup{&r.Milestones[i], &r.Total} {
+			g.Tasks++
+			g.Minutes += tc.Minutes
+			g.Cost += tc.Cost
+		}
+	}
+	return r
+}
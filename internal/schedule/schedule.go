@@ -0,0 +1,276 @@
+// Package schedule estimates when each task in a graph can run, given a
+// number of parallel workers and a duration per estimate bucket.
+package schedule
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// Options controls schedule computation.
+type Options struct {
+	// Workers is the number of tasks that may run in parallel. Values below
+	// one are treated as one.
+	Workers int
+
+	// Minutes converts a task estimate into working minutes. Required.
+	Minutes func(estimate string) int
+
+	// UnknownMinutes is used for tasks whose estimate converts to zero
+	// (missing or "unknown").
+	UnknownMinutes int
+
+	// Adjust, when set, replaces each task's duration after conversion.
+	// Simulate uses it to draw random durations around the estimate.
+	Adjust func(taskID string, minutes int) int
+}
+
+// Slot is the planned execution window of one task, in working minutes
+// from the start of the plan.
+type Slot struct {
+	TaskID   string
+	Start    int
+	End      int
+	Worker   int
+	Critical bool
+}
+
+// Schedule is the computed plan for a graph.
+type Schedule struct {
+	// Slots lists every task in start order.
+	Slots []Slot
+
+	// Makespan is the total working minutes until the last task ends.
+	Makespan int
+
+	// CriticalPath is the longest dependency chain by duration, in order.
+	CriticalPath []string
+
+	// CriticalMinutes is the duration of the critical path, which is the
+	// lower bound on Makespan with unlimited workers.
+	CriticalMinutes int
+}
+
+// Compute builds a schedule using list scheduling: whenever a worker is
+// free, the ready task with the longest remaining chain runs next, ties
+// broken by priority and then graph order. The graph must be acyclic.
+func Compute(graph *validator.TaskGraph, opts Options) (*Schedule, error) {
+	if opts.Minutes == nil {
+		return nil, fmt.Errorf("schedule: Minutes conversion is required")
+	}
+	workers := opts.Workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	n := len(graph.Tasks)
+	index := make(map[string]int, n)
+	for i, t := range graph.Tasks {
+		index[t.TaskID] = i
+	}
+
+	dur := make([]int, n)
+	deps := make([][]int, n)
+	dependents := make([][]int, n)
+	for i, t := range graph.Tasks {
+		dur[i] = opts.Minutes(string(t.Estimate))
+		if dur[i] == 0 {
+			dur[i] = opts.UnknownMinutes
+		}
+		if opts.Adjust != nil {
+			dur[i] = opts.Adjust(t.TaskID, dur[i])
+		}
+		ids, _, err := t.ParseDependsOn()
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			j, ok := index[id]
+			if !ok {
+				continue // External or dangling references do not constrain the schedule.
+			}
+			deps[i] = append(deps[i], j)
+			dependents[j] = append(dependents[j], i)
+		}
+	}
+
+	order, err := topoOrder(n, deps, dependents)
+	if err != nil {
+		return nil, err
+	}
+
+	// tail[i] is the longest duration chain starting at task i.
+	tail := make([]int, n)
+	next := make([]int, n)
+	for k := n - 1; k >= 0; k-- {
+		i := order[k]
+		next[i] = -1
+		best := 0
+		for _, d := range dependents[i] {
+			if tail[d] > best {
+				best, next[i] = tail[d], d
+			}
+		}
+		tail[i] = dur[i] + best
+	}
+
+	// Critical path: start from the root with the longest tail.
+	sched := &Schedule{}
+	start := -1
+	for _, i := range order {
+		if len(deps[i]) == 0 && (start < 0 || tail[i] > tail[start]) {
+			start = i
+		}
+	}
+	critical := make([]bool, n)
+	for i := start; i >= 0; i = next[i] {
+		critical[i] = true
+		sched.CriticalPath = append(sched.CriticalPath, graph.Tasks[i].TaskID)
+	}
+	if start >= 0 {
+		sched.CriticalMinutes = tail[start]
+	}
+
+	// List scheduling simulation.
+	remaining := make([]int, n)
+	ready := make([]int, n) // earliest start imposed by dependencies
+	for i := range graph.Tasks {
+		remaining[i] = len(deps[i])
+	}
+	free := make([]int, workers) // time each worker becomes free
+	var queue []int
+	for i := range graph.Tasks {
+		if remaining[i] == 0 {
+			queue = append(queue, i)
+		}
+	}
+
+	for len(queue) > 0 {
+		sort.SliceStable(queue, func(a, b int) bool {
+			x, y := queue[a], queue[b]
+			if tail[x] != tail[y] {
+				return tail[x] > tail[y]
+			}
+			px, py := priorityRank(string(graph.Tasks[x].Priority)), priorityRank(string(graph.Tasks[y].Priority))
+			if px != py {
+				return px < py
+			}
+			return x < y
+		})
+
+		// Pick the worker that frees up first.
+		w := 0
+		for k := range free {
+			if free[k] < free[w] {
+				w = k
+			}
+		}
+
+		// Among queued tasks, take the best one that can start earliest on w.
+		pick := 0
+		bestStart := max(free[w], ready[queue[0]])
+		for k, i := range queue {
+			if s := max(free[w], ready[i]); s < bestStart {
+				pick, bestStart = k, s
+			}
+		}
+		i := queue[pick]
+		queue = append(queue[:pick], queue[pick+1:]...)
+
+		slot := Slot{
+			TaskID:   graph.Tasks[i].TaskID,
+			Start:    bestStart,
+			End:      bestStart + dur[i],
+			Worker:   w + 1,
+			Critical: critical[i],
+		}
+		free[w] = slot.End
+		sched.Slots = append(sched.Slots, slot)
+		if slot.End > sched.Makespan {
+			sched.Makespan = slot.End
+		}
+
+		for _, d := range dependents[i] {
+			if slot.End > ready[d] {
+				ready[d] = slot.End
+			}
+			remaining[d]--
+			if remaining[d] == 0 {
+				queue = append(queue, d)
+			}
+		}
+	}
+
+	sort.SliceStable(sched.Slots, func(a, b int) bool { return sched.Slots[a].Start < sched.Slots[b].Start })
+	return sched, nil
+}
+
+// Slot returns the slot for taskID, or nil.
+func (s *Schedule) Slot(taskID string) *Slot {
+	for i := range s.Slots {
+		if s.Slots[i].TaskID == taskID {
+			return &s.Slots[i]
+		}
+	}
+	return nil
+}
+
+// CriticalRisks returns the high-risk tasks on the critical path, in path
+// order. These are the tasks most likely to delay the whole plan.
+func (s *Schedule) CriticalRisks(graph *validator.TaskGraph) []*validator.TaskNode {
+	var risky []*validator.TaskNode
+	for _, id := range s.CriticalPath {
+		if t := graph.FindTask(id); t != nil && t.IsHighRisk() {
+			risky = append(risky, t)
+		}
+	}
+	return risky
+}
+
+// topoOrder returns task indexes with dependencies before dependents.
+func topoOrder(n int, deps, dependents [][]int) ([]int, error) {
+	inDegree := make([]int, n)
+	var queue []int
+	for i := 0; i < n; i++ {
+		inDegree[i] = len(deps[i])
+		if inDegree[i] == 0 {
+			queue = append(queue, i)
+		}
+	}
+	var order []int
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+		order = append(order, i)
+		for _, d := range dependents[i] {
+			inDegree[d]--
+			if inDegree[d] == 0 {
+				queue = append(queue, d)
+			}
+		}
+	}
+	if len(order) != n {
+		return nil, fmt.Errorf("schedule: dependency graph contains a cycle")
+	}
+	return order, nil
+}
+
+// priorityRank orders priorities from most to least urgent.
+func priorityRank(p string) int {
+	if n, err := strconv.Atoi(p); err == nil {
+		return n
+	}
+	switch p {
+	case "critical":
+		return 0
+	case "high":
+		return 1
+	case "low":
+		return 3
+	default:
+		return 2
+	}
+}
@@ -0,0 +1,187 @@
+package schedule
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+func minutes(estimate string) int {
+	switch estimate {
+	case "small":
+		return 60
+	case "medium":
+		return 240
+	case "large":
+		return 480
+	}
+	return 0
+}
+
+func testGraph() *validator.TaskGraph {
+	return &validator.TaskGraph{
+		Version: "0.1.0",
+		Tasks: []validator.TaskNode{
+			{TaskID: "a", Estimate: "small"},
+			{TaskID: "b", Estimate: "large", DependsOn: json.RawMessage(`["a"]`)},
+			{TaskID: "c", Estimate: "small", DependsOn: json.RawMessage(`["a"]`)},
+			{TaskID: "d", Estimate: "medium", DependsOn: json.RawMessage(`["b", "c"]`)},
+			{TaskID: "e", Estimate: "unknown"},
+		},
+	}
+}
+
+func TestComputeSingleWorker(t *testing.T) {
+	s, err := Compute(testGraph(), Options{Workers: 1, Minutes: minutes, UnknownMinutes: 30})
+	if err != nil {
+		t.Fatalf("Compute error: %v", err)
+	}
+	// 60 + 480 + 60 + 240 + 30 with no parallelism.
+	if s.Makespan != 870 {
+		t.Errorf("Makespan = %d, want 870", s.Makespan)
+	}
+	if got := strings.Join(s.CriticalPath, ","); got != "a,b,d" {
+		t.Errorf("CriticalPath = %s, want a,b,d", got)
+	}
+	if s.CriticalMinutes != 780 {
+		t.Errorf("CriticalMinutes = %d, want 780", s.CriticalMinutes)
+	}
+	for _, sl := range s.Slots {
+		if sl.TaskID == "d" && sl.Start < s.Slot("b").End {
+			t.Error("d must not start before b ends")
+		}
+	}
+}
+
+func TestComputeParallelWorkers(t *testing.T) {
+	s, err := Compute(testGraph(), Options{Workers: 3, Minutes: minutes, UnknownMinutes: 30})
+	if err != nil {
+		t.Fatalf("Compute error: %v", err)
+	}
+	if s.Makespan != s.CriticalMinutes {
+		t.Errorf("with enough workers Makespan = %d, want critical path %d", s.Makespan, s.CriticalMinutes)
+	}
+	if !s.Slot("b").Critical || s.Slot("c").Critical {
+		t.Error("b should be critical and c should not")
+	}
+}
+
+func TestCriticalRisks(t *testing.T) {
+	g := testGraph()
+	g.Tasks[1].Risk = &validator.Risk{Level: validator.RiskHigh, Mitigation: "Spike first"}
+	g.Tasks[2].Risk = &validator.Risk{Level: validator.RiskHigh}
+	s, err := Compute(g, Options{Workers: 1, Minutes: minutes})
+	if err != nil {
+		t.Fatalf("Compute error: %v", err)
+	}
+	// c is high risk but off the critical path a -> b -> d.
+	risky := s.CriticalRisks(g)
+	if len(risky) != 1 || risky[0].TaskID != "b" {
+		t.Errorf("CriticalRisks = %v, want [b]", risky)
+	}
+}
+
+func TestComputeCycle(t *testing.T) {
+	g := &validator.TaskGraph{Tasks: []validator.TaskNode{
+		{TaskID: "a", DependsOn: json.RawMessage(`["b"]`)},
+		{TaskID: "b", DependsOn: json.RawMessage(`["a"]`)},
+	}}
+	if _, err := Compute(g, Options{Minutes: minutes}); err == nil {
+		t.Error("expected cycle error")
+	}
+}
+
+func TestCalendarDate(t *testing.T) {
+	// Friday 2026-01-02.
+	c := Calendar{Start: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC), HoursPerDay: 8}
+	tests := []struct {
+		minute int
+		end    bool
+		want   string
+	}{
+		{0, false, "2026-01-02"},
+		{480, true, "2026-01-02"},
+		{480, false, "2026-01-05"}, // skips the weekend
+		{960, true, "2026-01-05"},
+	}
+	for _, tt := range tests {
+		if got := c.Date(tt.minute, tt.end).Format("2006-01-02"); got != tt.want {
+			t.Errorf("Date(%d, %v) = %s, want %s", tt.minute, tt.end, got, tt.want)
+		}
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	g := testGraph()
+	g.Milestones = []validator.Milestone{{Name: "M1", TaskIDs: []string{"a", "b"}}, {Name: "Empty"}}
+	opts := Options{Workers: 1, Minutes: minutes, UnknownMinutes: 30}
+
+	for _, dist := range Distributions {
+		f, err := Simulate(g, opts, SimulateOptions{Iterations: 500, Distribution: dist, Seed: 7})
+		if err != nil {
+			t.Fatalf("Simulate(%s) error: %v", dist, err)
+		}
+		if p := f.Graph; p.P50 > p.P80 || p.P80 > p.P95 {
+			t.Errorf("%s: percentiles out of order: %+v", dist, p)
+		}
+		if len(f.Milestones) != 1 || f.Milestones[0].P95 > f.Graph.P95 {
+			t.Errorf("%s: milestones = %+v", dist, f.Milestones)
+		}
+		if dist == Triangular && (f.Graph.P50 < 870/2 || f.Graph.P95 > 870*2) {
+			t.Errorf("triangular forecast %+v outside [e/2, 2e] of 870", f.Graph)
+		}
+	}
+
+	a, _ := Simulate(g, opts, SimulateOptions{Iterations: 100, Seed: 3})
+	b, _ := Simulate(g, opts, SimulateOptions{Iterations: 100, Seed: 3})
+	if a.Graph != b.Graph {
+		t.Errorf("same seed gave %+v and %+v", a.Graph, b.Graph)
+	}
+	if _, err := Simulate(g, opts, SimulateOptions{Distribution: "normal"}); err == nil {
+		t.Error("expected error for unknown distribution")
+	}
+}
+
+func TestPercentiles(t *testing.T) {
+	values := []int{10, 1, 9, 2, 8, 3, 7, 4, 6, 5}
+	if got := percentiles(values); got != (Percentiles{P50: 5, P80: 8, P95: 10}) {
+		t.Errorf("percentiles = %+v", got)
+	}
+}
+
+func TestEstimateCost(t *testing.T) {
+	graph := testGraph()
+	graph.Milestones = []validator.Milestone{
+		{Name: "M1", TaskIDs: []string{"a", "b"}},
+		{Name: "M2", TaskIDs: []string{"c", "a"}},
+		{Name: "Later", TaskIDs: []string{}},
+	}
+	hint := 1000.0
+	graph.Tasks[1].Cost = &hint
+
+	r := EstimateCost(graph, CostOptions{Rate: 60, Minutes: minutes, UnknownMinutes: 30})
+	want := []CostGroup{
+		// a at the rate (60 min), b by its hint; a counts toward M1 only.
+		{Name: "M1", Tasks: 2, Minutes: 540, Cost: 1060},
+		{Name: "M2", Tasks: 1, Minutes: 60, Cost: 60},
+		{Name: "Later"},
+		{Name: NoMilestone, Tasks: 2, Minutes: 270, Cost: 270},
+	}
+	if len(r.Milestones) != len(want) {
+		t.Fatalf("Milestones = %+v, want %+v", r.Milestones, want)
+	}
+	for i := range want {
+		if r.Milestones[i] != want[i] {
+			t.Errorf("Milestones[%d] = %+v, want %+v", i, r.Milestones[i], want[i])
+		}
+	}
+	if r.Total != (CostGroup{Name: "Total", Tasks: 5, Minutes: 870, Cost: 1390}) {
+		t.Errorf("Total = %+v", r.Total)
+	}
+	if r.Unpriced != 4 || !r.Tasks[1].Hint || r.Tasks[0].Hint {
+		t.Errorf("Unpriced = %d, tasks = %+v", r.Unpriced, r.Tasks)
+	}
+}
@@ -0,0 +1,174 @@
+package schedule
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// Distribution names accepted by SimulateOptions.
+const (
+	Triangular = "triangular"
+	PERT       = "pert"
+	LogNormal  = "lognormal"
+)
+
+// Distributions lists the supported duration distributions.
+var Distributions = []string{Triangular, PERT, LogNormal}
+
+// SimulateOptions controls a Monte Carlo run.
+type SimulateOptions struct {
+	// Iterations is the number of sampled schedules. Values below one are
+	// treated as one.
+	Iterations int
+
+	// Distribution shapes each task's sampled duration around its estimate
+	// e: triangular and pert range from e/2 to 2e with e most likely;
+	// lognormal has median e and a long right tail. Default triangular.
+	Distribution string
+
+	// Seed makes runs reproducible.
+	Seed uint64
+}
+
+// Percentiles are completion times in working minutes from the start of
+// the plan.
+type Percentiles struct {
+	P50, P80, P95 int
+}
+
+// MilestoneForecast is the completion forecast of one milestone: the time
+// its last task ends.
+type MilestoneForecast struct {
+	Name string
+	Percentiles
+}
+
+// Forecast is the result of Simulate.
+type Forecast struct {
+	Iterations int
+
+	// Graph is the forecast for the whole graph (the makespan).
+	Graph Percentiles
+
+	// Milestones follow graph order; milestones without tasks are omitted.
+	Milestones []MilestoneForecast
+}
+
+// Simulate runs Compute repeatedly with task durations sampled from the
+// chosen distribution and reports percentile completion times for the
+// graph and each milestone.
+func Simulate(graph *validator.TaskGraph, opts Options, sim SimulateOptions) (*Forecast, error) {
+	sample, err := sampler(sim.Distribution)
+	if err != nil {
+		return nil, err
+	}
+	iterations := max(sim.Iterations, 1)
+	rng := rand.New(rand.NewPCG(sim.Seed, sim.Seed))
+	opts.Adjust = func(_ string, minutes int) int {
+		return max(int(math.Round(sample(rng, float64(minutes)))), 1)
+	}
+
+	var milestones []validator.Milestone
+	for _, m := range graph.Milestones {
+		if len(m.TaskIDs) > 0 {
+			milestones = append(milestones, m)
+		}
+	}
+	makespans := make([]int, iterations)
+	ends := make([][]int, len(milestones))
+	for n := range iterations {
+		s, err := Compute(graph, opts)
+		if err != nil {
+			return nil, err
+		}
+		makespans[n] = s.Makespan
+		for k, m := range milestones {
+			end := 0
+			for _, id := range m.TaskIDs {
+				if slot := s.Slot(id); slot != nil {
+					end = max(end, slot.End)
+				}
+			}
+			ends[k] = append(ends[k], end)
+		}
+	}
+
+	f := &Forecast{Iterations: iterations, Graph: percentiles(makespans)}
+	for k, m := range milestones {
+		f.Milestones = append(f.Milestones, MilestoneForecast{Name: m.Name, Percentiles: percentiles(ends[k])})
+	}
+	return f, nil
+}
+
+// sampler returns a function drawing a duration around the estimate e.
+func sampler(name string) (func(rng *rand.Rand, e float64) float64, error) {
+	switch name {
+	case Triangular, "":
+		return func(rng *rand.Rand, e float64) float64 {
+			return triangular(rng.Float64(), e/2, e, 2*e)
+		}, nil
+	case PERT:
+		return func(rng *rand.Rand, e float64) float64 {
+			// Beta-PERT on [e/2, 2e] with mode e: alpha = 1 + 4(mode-min)/(max-min).
+			lo, hi := e/2, 2*e
+			a := 1 + 4*(e-lo)/(hi-lo)
+			b := 1 + 4*(hi-e)/(hi-lo)
+			return lo + beta(rng, a, b)*(hi-lo)
+		}, nil
+	case LogNormal:
+		return func(rng *rand.Rand, e float64) float64 {
+			return e * math.Exp(0.5*rng.NormFloat64())
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown distribution '%s'. Must be one of: %s, %s, %s", name, Triangular, PERT, LogNormal)
+	}
+}
+
+// triangular maps a uniform u in [0,1) onto the triangular distribution
+// with the given minimum, mode, and maximum.
+func triangular(u, lo, mode, hi float64) float64 {
+	if hi == lo {
+		return lo
+	}
+	if c := (mode - lo) / (hi - lo); u < c {
+		return lo + math.Sqrt(u*(hi-lo)*(mode-lo))
+	}
+	return hi - math.Sqrt((1-u)*(hi-lo)*(hi-mode))
+}
+
+// beta draws from Beta(a, b) as X/(X+Y) with X, Y gamma distributed.
+func beta(rng *rand.Rand, a, b float64) float64 {
+	x, y := gamma(rng, a), gamma(rng, b)
+	return x / (x + y)
+}
+
+// gamma draws from Gamma(k, 1) for k >= 1 (Marsaglia and Tsang).
+func gamma(rng *rand.Rand, k float64) float64 {
+	d := k - 1.0/3
+	c := 1 / math.Sqrt(9*d)
+	for {
+		x := rng.NormFloat64()
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+		if u := rng.Float64(); math.Log(u) < 0.5*x*x+d-d*v+d*math.Log(v) {
+			return d * v
+		}
+	}
+}
+
+// percentiles returns nearest-rank percentiles of values, which it sorts.
+func percentiles(values []int) Percentiles {
+	slices.Sort(values)
+	rank := func(p float64) int {
+		i := int(math.Ceil(p*float64(len(values)))) - 1
+		return values[max(i, 0)]
+	}
+	return Percentiles{P50: rank(0.50), P80: rank(0.80), P95: rank(0.95)}
+}
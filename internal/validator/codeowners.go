@@ -0,0 +1,161 @@
+package validator
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// CodeOwners is a parsed CODEOWNERS file. As on GitHub, the last rule that
+// matches a path decides its owners, and a rule without owners leaves the
+// path unowned.
+type CodeOwners struct {
+	rules []ownerRule
+}
+
+type ownerRule struct {
+	pattern string
+	re      *regexp.Regexp
+	owners  []string
+}
+
+// LoadCodeOwners reads and parses a CODEOWNERS file.
+func LoadCodeOwners(path string) (*CodeOwners, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading CODEOWNERS: %w", err)
+	}
+	defer f.Close()
+
+	co := &CodeOwners{}
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		re, err := ownerPattern(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("CODEOWNERS line %d: %w", n, err)
+		}
+		co.rules = append(co.rules, ownerRule{pattern: fields[0], re: re, owners: fields[1:]})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading CODEOWNERS: %w", err)
+	}
+	return co, nil
+}
+
+// Owners returns the owners of a repository-relative path, or nil if no
+// rule assigns any. Backslash separators are accepted.
+func (co *CodeOwners) Owners(path string) []string {
+	path = strings.TrimSuffix(NormalizeScopePath(path), "/")
+	for i := len(co.rules) - 1; i >= 0; i-- {
+		if co.rules[i].re.MatchString(path) {
+			return co.rules[i].owners
+		}
+	}
+	return nil
+}
+
+// ownerPattern compiles a CODEOWNERS pattern. Patterns use gitignore
+// syntax: a leading or inner '/' anchors the pattern at the repository
+// root, a trailing '/' matches only directory contents, '*' and '?' stay
+// within one path segment, and '**' spans segments. A pattern naming a
+// directory also matches everything below it.
+func ownerPattern(p string) (*regexp.Regexp, error) {
+	anchored := strings.Contains(strings.TrimSuffix(p, "/"), "/")
+	p = strings.TrimPrefix(p, "/")
+	dirOnly := strings.HasSuffix(p, "/")
+	p = strings.TrimSuffix(p, "/")
+
+	var sb strings.Builder
+	sb.WriteString("^")
+	if !anchored {
+		sb.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(p); i++ {
+		switch {
+		case strings.HasPrefix(p[i:], "**/"):
+			sb.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(p[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case p[i] == '*':
+			sb.WriteString("[^/]*")
+		case p[i] == '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(p[i : i+1]))
+		}
+	}
+	if dirOnly {
+		sb.WriteString("/.*$")
+	} else {
+		sb.WriteString("(?:/.*)?$")
+	}
+	return regexp.Compile(sb.String())
+}
+
+// checkCodeOwners resolves each task's files_scope against
+// Options.CodeOwners (OWNERS). It reports the owning teams of every task as
+// an INFO and warns when a task spans files of several teams without notes
+// explaining the coordination.
+func (sv *SemanticValidator) checkCodeOwners(graph *TaskGraph, result *ValidationResult) {
+	co := sv.opts.CodeOwners
+	if co == nil {
+		return
+	}
+
+	for i, t := range graph.Tasks {
+		files, _, err := t.ParseFilesScope()
+		if err != nil || len(files) == 0 {
+			continue
+		}
+		var teams []string // distinct owner sets, e.g. "@org/api" or "@a @b"
+		var unowned []string
+		for _, f := range files {
+			owners := co.Owners(f)
+			if len(owners) == 0 {
+				unowned = append(unowned, f)
+				continue
+			}
+			if team := strings.Join(owners, " "); !slices.Contains(teams, team) {
+				teams = append(teams, team)
+			}
+		}
+
+		path := fmt.Sprintf("tasks[%d].files_scope", i)
+		msg := fmt.Sprintf("Task '%s' is owned by %s.", t.TaskID, strings.Join(teams, ", "))
+		if len(teams) == 0 {
+			msg = fmt.Sprintf("Task '%s' touches no files with a CODEOWNERS owner.", t.TaskID)
+		} else if len(unowned) > 0 {
+			msg += fmt.Sprintf(" No owner for: %s.", strings.Join(unowned, ", "))
+		}
+		result.AddError(ValidationError{
+			Rule:     "OWNERS",
+			Severity: SeverityInfo,
+			Path:     path,
+			Message:  msg,
+			Context:  strings.Join(teams, ", "),
+		})
+
+		if len(teams) > 1 && strings.TrimSpace(t.Notes) == "" {
+			result.AddError(ValidationError{
+				Rule:       "OWNERS",
+				Severity:   SeverityWarning,
+				Path:       path,
+				Message:    fmt.Sprintf("Task '%s' spans files owned by %d teams (%s) and has no notes.", t.TaskID, len(teams), strings.Join(teams, ", ")),
+				Suggestion: "Split the task by owner, or use notes to say who reviews which part.",
+			})
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package validator
+
+import (
+	"fmt"
+	"time"
+)
+
+// ParseDate parses a due or not_before value: an RFC 3339 full date
+// ("2026-03-01", taken as midnight UTC) or a full timestamp.
+func ParseDate(s string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+// dated holds the parsed dates of one task or milestone; zero means unset
+// or unparseable (already reported).
+type dated struct {
+	due, notBefore time.Time
+}
+
+// checkDates validates due and not_before on tasks and milestones (DATES):
+// values must parse, not_before may not follow due, a task may not be due
+// before a dependency is due or may start, and a task due after its
+// milestone's deadline is flagged.
+func (sv *SemanticValidator) checkDates(graph *TaskGraph, result *ValidationResult) {
+	tasks := make(map[string]dated, len(graph.Tasks))
+	for i, t := range graph.Tasks {
+		d := sv.parseDates(fmt.Sprintf("tasks[%d]", i), fmt.Sprintf("task '%s'", t.TaskID), t.Due, t.NotBefore, result)
+		if _, dup := tasks[t.TaskID]; !dup {
+			tasks[t.TaskID] = d
+		}
+	}
+
+	for i, t := range graph.Tasks {
+		own := tasks[t.TaskID]
+		if own.due.IsZero() {
+			continue
+		}
+		deps, _, err := t.ParseDependsOn()
+		if err != nil {
+			continue
+		}
+		for _, dep := range deps {
+			d, ok := tasks[dep]
+			if !ok {
+				continue
+			}
+			if !d.due.IsZero() && d.due.After(own.due) {
+				result.AddError(ValidationError{
+					Rule:     "DATES",
+					Severity: SeverityError,
+					Path:     fmt.Sprintf("tasks[%d].due", i),
+					Message: fmt.Sprintf(
+						"Task '%s' is due %s, before its dependency '%s' is due (%s).",
+						t.TaskID, t.Due, dep, graph.FindTask(dep).Due,
+					),
+					Suggestion: "Move this task's due date later, or the dependency's earlier.",
+					Context:    t.Due,
+				})
+			} else if !d.notBefore.IsZero() && d.notBefore.After(own.due) {
+				result.AddError(ValidationError{
+					Rule:     "DATES",
+					Severity: SeverityError,
+					Path:     fmt.Sprintf("tasks[%d].due", i),
+					Message: fmt.Sprintf(
+						"Task '%s' is due %s, before its dependency '%s' may start (not_before %s).",
+						t.TaskID, t.Due, dep, graph.FindTask(dep).NotBefore,
+					),
+					Suggestion: "Move this task's due date later, or let the dependency start earlier.",
+					Context:    t.Due,
+				})
+			}
+		}
+	}
+
+	for i, m := range graph.Milestones {
+		md := sv.parseDates(fmt.Sprintf("milestones[%d]", i), fmt.Sprintf("milestone '%s'", m.Name), m.Due, m.NotBefore, result)
+		if md.due.IsZero() {
+			continue
+		}
+		for _, id := range m.TaskIDs {
+			if d := tasks[id]; !d.due.IsZero() && d.due.After(md.due) {
+				result.AddError(ValidationError{
+					Rule:     "DATES",
+					Severity: SeverityWarning,
+					Path:     fmt.Sprintf("milestones[%d].due", i),
+					Message: fmt.Sprintf(
+						"Task '%s' is due %s, after the deadline of its milestone '%s' (%s).",
+						id, graph.FindTask(id).Due, m.Name, m.Due,
+					),
+					Suggestion: "Bring the task's due date within the milestone, or move the milestone deadline.",
+					Context:    m.Due,
+				})
+			}
+		}
+	}
+}
+
+// parseDates parses one element's due and not_before, reporting values
+// that do not parse and a not_before later than due.
+func (sv *SemanticValidator) parseDates(path, what, due, notBefore string, result *ValidationResult) dated {
+	var d dated
+	for _, f := range []struct {
+		name, value string
+		dst         *time.Time
+	}{{"due", due, &d.due}, {"not_before", notBefore, &d.notBefore}} {
+		if f.value == "" {
+			continue
+		}
+		t, err := ParseDate(f.value)
+		if err != nil {
+			result.AddError(ValidationError{
+				Rule:       "DATES",
+				Severity:   SeverityError,
+				Path:       path + "." + f.name,
+				Message:    fmt.Sprintf("%s of %s is not an RFC 3339 date or timestamp: '%s'.", f.name, what, f.value),
+				Suggestion: "Use a date like '2026-03-01' or a timestamp like '2026-03-01T17:00:00Z'.",
+				Context:    f.value,
+			})
+			continue
+		}
+		*f.dst = t
+	}
+	if !d.due.IsZero() && !d.notBefore.IsZero() && d.notBefore.After(d.due) {
+		result.AddError(ValidationError{
+			Rule:       "DATES",
+			Severity:   SeverityError,
+			Path:       path + ".not_before",
+			Message:    fmt.Sprintf("not_before (%s) of %s is later than its due date (%s).", notBefore, what, due),
+			Suggestion: "Swap the dates or correct one of them.",
+			Context:    notBefore,
+		})
+	}
+	return d
+}
@@ -0,0 +1,57 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// DesignVersion is the version of the _template metadata format that
+// taskval writes to a Beads issue's design field.
+const DesignVersion = "0.2.0"
+
+// ValidateDesign checks a design field blob ({"_template": {...}}) against
+// the design metadata schema, then checks that its version is one this
+// build writes (DESIGN) and that its dates parse (DATES). Tools that read
+// the metadata back from bd use it before trusting the fields.
+func ValidateDesign(data []byte) (*ValidationResult, error) {
+	result := &ValidationResult{Valid: true}
+	if !checkNesting(data, result) || !checkTrailing(data, result) {
+		return result, nil
+	}
+	checkDuplicateKeys(data, result)
+
+	sv, err := NewSchemaValidator()
+	if err != nil {
+		return nil, fmt.Errorf("initializing schema validator: %w", err)
+	}
+	sv.ValidateDesign(data, result)
+	if !result.Valid {
+		return result, nil
+	}
+
+	var meta struct {
+		Template struct {
+			Version   string `json:"version"`
+			TaskID    string `json:"task_id"`
+			Due       string `json:"due"`
+			NotBefore string `json:"not_before"`
+		} `json:"_template"`
+	}
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, fmt.Errorf("parsing design metadata: %w", err)
+	}
+	result.Stats.TotalTasks = 1
+
+	if v := meta.Template.Version; v != DesignVersion {
+		result.AddError(ValidationError{
+			Rule:       "DESIGN",
+			Severity:   SeverityError,
+			Path:       "_template.version",
+			Message:    fmt.Sprintf("Design metadata version '%s' is not supported; this taskval reads and writes version %s.", v, DesignVersion),
+			Suggestion: "Re-create or update the issue with this taskval version (--on-duplicate=update) to rewrite its design metadata.",
+			Context:    v,
+		})
+	}
+	NewSemanticValidator().parseDates("_template", fmt.Sprintf("task '%s'", meta.Template.TaskID), meta.Template.Due, meta.Template.NotBefore, result)
+	return result, nil
+}
@@ -0,0 +1,98 @@
+package validator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// checkDuplicateKeys reports each object key that appears twice in the
+// same object (DUPKEY). encoding/json keeps the last value without a
+// word, so a second "goal" would silently replace the first. Malformed
+// JSON is left to the schema check.
+func checkDuplicateKeys(data []byte, result *ValidationResult) {
+	for _, d := range duplicateKeys(data) {
+		key, path := d[0], d[1]
+		result.AddError(ValidationError{
+			Rule:       "DUPKEY",
+			Severity:   SeverityError,
+			Path:       path,
+			Message:    fmt.Sprintf("Key '%s' appears more than once in the same object. Only the last value is kept; the earlier ones are ignored.", key),
+			Suggestion: fmt.Sprintf("Keep one '%s' key at %s and merge the values into it.", key, path),
+		})
+	}
+}
+
+// keyFrame is an object or array being walked by duplicateKeys.
+type keyFrame struct {
+	path    string
+	obj     bool
+	keys    map[string]bool
+	key     string // the object key whose value comes next
+	wantKey bool   // the next token in the object is a key
+	index   int    // the array index of the next element
+}
+
+// duplicateKeys returns each repeated object key in data with its path,
+// such as {"goal", "tasks[0].goal"}, in document order. The walk is
+// iterative, so nesting depth costs no stack.
+func duplicateKeys(data []byte) [][2]string {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	var stack []*keyFrame
+	var dups [][2]string
+
+	// valuePath returns the path of the value about to be read.
+	valuePath := func() string {
+		if len(stack) == 0 {
+			return ""
+		}
+		top := stack[len(stack)-1]
+		if !top.obj {
+			return fmt.Sprintf("%s[%d]", top.path, top.index)
+		}
+		if top.path == "" {
+			return top.key
+		}
+		return top.path + "." + top.key
+	}
+	// valueDone advances the enclosing object or array past a value.
+	valueDone := func() {
+		if len(stack) == 0 {
+			return
+		}
+		top := stack[len(stack)-1]
+		if top.obj {
+			top.wantKey = true
+		} else {
+			top.index++
+		}
+	}
+
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return dups
+		}
+		if len(stack) > 0 {
+			top := stack[len(stack)-1]
+			if key, ok := tok.(string); ok && top.obj && top.wantKey {
+				top.key, top.wantKey = key, false
+				if top.keys[key] {
+					dups = append(dups, [2]string{key, valuePath()})
+				}
+				top.keys[key] = true
+				continue
+			}
+		}
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			obj := tok == json.Delim('{')
+			stack = append(stack, &keyFrame{path: valuePath(), obj: obj, wantKey: obj, keys: make(map[string]bool)})
+		case json.Delim('}'), json.Delim(']'):
+			stack = stack[:len(stack)-1]
+			valueDone()
+		default:
+			valueDone()
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package validator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Config is a validation config file: rule adjustments (the RuleConfig
+// fields, at the top level) plus custom rules.
+type Config struct {
+	RuleConfig
+
+	// CustomRules are house rules run after the built-in semantic checks.
+	CustomRules []ExternalRule `json:"custom_rules,omitempty"`
+
+	// TaskIDs is the house task_id naming convention (IDS rule).
+	TaskIDs *IDConvention `json:"task_ids,omitempty"`
+}
+
+// ExternalRule is a custom rule implemented outside taskval. A command
+// rule is run with the parsed graph as JSON on stdin and prints a JSON
+// array of findings (ValidationError objects) on stdout; findings without
+// a rule ID get the rule's ID, and without a severity become warnings.
+type ExternalRule struct {
+	// ID names the rule in findings and in RuleConfig.
+	ID string `json:"id"`
+
+	// Command is the program and its arguments. A relative program path
+	// containing a separator is resolved against the config file's directory.
+	Command []string `json:"command,omitempty"`
+
+	// Timeout bounds one run, as a Go duration ("10s"). Default 30s.
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// defaultRuleTimeout bounds a custom rule without its own timeout.
+const defaultRuleTimeout = 30 * time.Second
+
+// LoadConfig reads a validation config file and checks its custom rules.
+func LoadConfig(path string) (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, fmt.Errorf("reading config: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return cfg, fmt.Errorf("parsing config '%s': %w", path, err)
+	}
+	if err := EndOfInput(dec); err != nil {
+		return cfg, fmt.Errorf("parsing config '%s': the config %w", path, err)
+	}
+	if err := cfg.checkLimits(); err != nil {
+		return cfg, fmt.Errorf("config '%s': %w", path, err)
+	}
+	if cfg.TaskIDs != nil {
+		if err := cfg.TaskIDs.check(); err != nil {
+			return cfg, fmt.Errorf("config '%s': %w", path, err)
+		}
+	}
+
+	dir := filepath.Dir(path)
+	for i := range cfg.CustomRules {
+		r := &cfg.CustomRules[i]
+		switch {
+		case r.ID == "":
+			return cfg, fmt.Errorf("config '%s': custom_rules[%d] has no id", path, i)
+		case len(r.Command) == 0:
+			return cfg, fmt.Errorf("config '%s': rule '%s' needs a command", path, r.ID)
+		}
+		if r.Timeout != "" {
+			if _, err := time.ParseDuration(r.Timeout); err != nil {
+				return cfg, fmt.Errorf("config '%s': rule '%s': invalid timeout: %w", path, r.ID, err)
+			}
+		}
+		if prog := r.Command[0]; !filepath.IsAbs(prog) && strings.ContainsRune(prog, filepath.Separator) {
+			r.Command[0] = filepath.Join(dir, prog)
+		}
+	}
+	return cfg, nil
+}
+
+// runExternalRules runs Options.CustomRules against the graph. A rule that
+// fails to run or prints something other than findings is reported as an
+// ERROR under its own ID, so a broken house rule cannot pass silently.
+func (sv *SemanticValidator) runExternalRules(graph *TaskGraph, result *ValidationResult) {
+	if len(sv.opts.CustomRules) == 0 {
+		return
+	}
+	input, err := json.Marshal(graph)
+	if err != nil {
+		return
+	}
+	for _, r := range sv.opts.CustomRules {
+		sv.timed(r.ID, result, func() { runExternalRule(r, input, result) })
+	}
+}
+
+// runExternalRule runs one custom rule on the JSON-encoded graph and adds
+// its findings, or an ERROR if the command failed.
+func runExternalRule(r ExternalRule, input []byte, result *ValidationResult) {
+	findings, err := r.run(input)
+	if err != nil {
+		result.AddError(ValidationError{
+			Rule:       r.ID,
+			Severity:   SeverityError,
+			Message:    fmt.Sprintf("Custom rule '%s' failed: %s", r.ID, err),
+			Suggestion: "Fix the rule command, or remove it from the config.",
+		})
+		return
+	}
+	for _, f := range findings {
+		if f.Rule == "" {
+			f.Rule = r.ID
+		}
+		if f.Severity != SeverityError && f.Severity != SeverityInfo {
+			f.Severity = SeverityWarning
+		}
+		result.AddError(f)
+	}
+}
@@ -0,0 +1,52 @@
+//go:build !js
+
+package validator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// run executes the rule command with input on stdin and decodes its
+// findings.
+func (r ExternalRule) run(input []byte) ([]ValidationError, error) {
+	if len(r.Command) == 0 {
+		return nil, fmt.Errorf("no command")
+	}
+	timeout := defaultRuleTimeout
+	if d, err := time.ParseDuration(r.Timeout); err == nil && d > 0 {
+		timeout = d
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, r.Command[0], r.Command[1:]...)
+	cmd.Stdin = bytes.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("timed out after %s", timeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+
+	out := bytes.TrimSpace(stdout.Bytes())
+	if len(out) == 0 {
+		return nil, nil
+	}
+	var findings []ValidationError
+	if err := json.Unmarshal(out, &findings); err != nil {
+		return nil, fmt.Errorf("output is not a JSON array of findings: %w", err)
+	}
+	return findings, nil
+}
@@ -0,0 +1,11 @@
+//go:build js
+
+package validator
+
+import "errors"
+
+// run reports that command rules are unavailable: a js/wasm build, such as
+// the browser build in cmd/taskval-wasm, cannot start processes.
+func (r ExternalRule) run(input []byte) ([]ValidationError, error) {
+	return nil, errors.New("custom rule commands cannot run in a js/wasm build")
+}
@@ -0,0 +1,159 @@
+package validator
+
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// ExtensionPrefix starts the names of custom fields the schema accepts on
+// graphs and tasks ("x_team", "x_jira_key"), so organizations can carry
+// their own data without forking the spec.
+const ExtensionPrefix = "x_"
+
+// Extensions returns the graph's x_ fields, or nil if it has none.
+func (g *TaskGraph) Extensions() map[string]json.RawMessage {
+	return extensions(g.Extra)
+}
+
+// Extensions returns the task's x_ fields, or nil if it has none.
+func (t *TaskNode) Extensions() map[string]json.RawMessage {
+	return extensions(t.Extra)
+}
+
+func extensions(extra map[string]json.RawMessage) map[string]json.RawMessage {
+	var out map[string]json.RawMessage
+	for k, v := range extra {
+		if !strings.HasPrefix(k, ExtensionPrefix) {
+			continue
+		}
+		if out == nil {
+			out = make(map[string]json.RawMessage)
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// Local copies of the model types without their JSON methods, so the
+// methods below can use the standard encoding for the known fields.
+type (
+	taskGraphFields TaskGraph
+	taskNodeFields  TaskNode
+)
+
+// UnmarshalJSON decodes the graph's fields and keeps any the model does
+// not define in Extra.
+func (g *TaskGraph) UnmarshalJSON(data []byte) error {
+	extra, err := unmarshalWithExtra(data, (*taskGraphFields)(g))
+	if err != nil {
+		return err
+	}
+	g.Extra = extra
+	return nil
+}
+
+// MarshalJSON encodes the graph's fields followed by its Extra fields.
+func (g TaskGraph) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(taskGraphFields(g), g.Extra)
+}
+
+// UnmarshalJSON decodes the task's fields and keeps any the model does
+// not define in Extra.
+func (t *TaskNode) UnmarshalJSON(data []byte) error {
+	extra, err := unmarshalWithExtra(data, (*taskNodeFields)(t))
+	if err != nil {
+		return err
+	}
+	t.Extra = extra
+	return nil
+}
+
+// MarshalJSON encodes the task's fields followed by its Extra fields.
+func (t TaskNode) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(taskNodeFields(t), t.Extra)
+}
+
+// unmarshalWithExtra decodes data into v, a pointer to a struct, and
+// returns the object's keys that match none of its fields. Keys are
+// matched the way encoding/json matches them (case-insensitively), so a
+// key that was decoded into a field is never also kept as extra.
+func unmarshalWithExtra(data []byte, v any) (map[string]json.RawMessage, error) {
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+	known := jsonFieldNames(reflect.TypeOf(v).Elem())
+	var extra map[string]json.RawMessage
+	for k, raw := range obj {
+		if slices.ContainsFunc(known, func(name string) bool { return strings.EqualFold(name, k) }) {
+			continue
+		}
+		if extra == nil {
+			extra = make(map[string]json.RawMessage)
+		}
+		extra[k] = raw
+	}
+	return extra, nil
+}
+
+// marshalWithExtra encodes v, a struct, and appends the extra fields in
+// key order. Extras that collide with a field v already wrote are skipped.
+func marshalWithExtra(v any, extra map[string]json.RawMessage) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false) // The caller's encoder decides on escaping.
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	buf.Truncate(buf.Len() - 1) // The encoder's newline.
+	if len(extra) == 0 {
+		return buf.Bytes(), nil
+	}
+
+	known := jsonFieldNames(reflect.TypeOf(v))
+	buf.Truncate(buf.Len() - 1) // Reopen the object.
+	empty := buf.Len() == 1
+	for _, k := range slices.Sorted(maps.Keys(extra)) {
+		if len(extra[k]) == 0 || slices.Contains(known, k) {
+			continue
+		}
+		key, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+		if !empty {
+			buf.WriteByte(',')
+		}
+		empty = false
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(extra[k])
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// jsonFieldNames lists the JSON names of a struct type's encoded fields.
+func jsonFieldNames(t reflect.Type) []string {
+	var names []string
+	for i := range t.NumField() {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" || !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = f.Name
+		}
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,94 @@
+package validator
+
+import (
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+)
+
+// IDConvention is a house naming convention for task_ids, checked by the
+// IDS rule on top of the schema's kebab-case pattern. Patterns use
+// path.Match syntax: "auth-*", "task-[0-9]*".
+type IDConvention struct {
+	// MaxLength caps task_id length below the schema's 60 characters.
+	MaxLength int `json:"max_length,omitempty"`
+
+	// Milestones maps a milestone name to the pattern the task_ids of its
+	// tasks must match.
+	Milestones map[string]string `json:"milestones,omitempty"`
+
+	// Forbidden lists patterns too generic to name a task ("misc",
+	// "task-[0-9]*").
+	Forbidden []string `json:"forbidden,omitempty"`
+}
+
+// check reports a malformed pattern or limit.
+func (c *IDConvention) check() error {
+	if c.MaxLength < 0 {
+		return fmt.Errorf("task_ids.max_length must not be negative, got %d", c.MaxLength)
+	}
+	for _, name := range slices.Sorted(maps.Keys(c.Milestones)) {
+		if _, err := path.Match(c.Milestones[name], ""); err != nil {
+			return fmt.Errorf("task_ids.milestones['%s']: invalid pattern '%s'", name, c.Milestones[name])
+		}
+	}
+	for _, p := range c.Forbidden {
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("task_ids.forbidden: invalid pattern '%s'", p)
+		}
+	}
+	return nil
+}
+
+// checkIDConvention applies Options.IDConvention to every task_id (IDS).
+func (sv *SemanticValidator) checkIDConvention(graph *TaskGraph, result *ValidationResult) {
+	if sv.opts.IDConvention == nil {
+		return
+	}
+	for _, e := range LintIDs(graph, *sv.opts.IDConvention) {
+		result.AddError(e)
+	}
+}
+
+// LintIDs returns the IDS findings for graph under convention c. A task
+// in several milestones must match the pattern of each.
+func LintIDs(graph *TaskGraph, c IDConvention) []ValidationError {
+	var out []ValidationError
+	add := func(i int, msg, suggestion string) {
+		out = append(out, ValidationError{
+			Rule:       "IDS",
+			Severity:   SeverityError,
+			Path:       fmt.Sprintf("tasks[%d].task_id", i),
+			Message:    msg,
+			Suggestion: suggestion,
+			Context:    graph.Tasks[i].TaskID,
+		})
+	}
+
+	for i, t := range graph.Tasks {
+		id := t.TaskID
+		if c.MaxLength > 0 && len(id) > c.MaxLength {
+			add(i, fmt.Sprintf("Task '%s' has a task_id of %d characters; the limit is %d.", id, len(id), c.MaxLength),
+				"Shorten the task_id, keeping the words that tell tasks apart.")
+		}
+		for _, p := range c.Forbidden {
+			if ok, _ := path.Match(p, id); ok {
+				add(i, fmt.Sprintf("Task '%s' has a generic task_id (matches forbidden pattern '%s').", id, p),
+					"Name the task_id after what the task delivers, e.g. 'auth-token-refresh'.")
+				break
+			}
+		}
+		for _, m := range graph.Milestones {
+			p, ok := c.Milestones[m.Name]
+			if !ok || !slices.Contains(m.TaskIDs, id) {
+				continue
+			}
+			if match, _ := path.Match(p, id); !match {
+				add(i, fmt.Sprintf("Task '%s' is in milestone '%s', whose task_ids must match '%s'.", id, m.Name, p),
+					fmt.Sprintf("Rename the task to match '%s', or move it to another milestone.", p))
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,88 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Task kinds accepted in TaskNode.Kind.
+const (
+	KindImplementation = "implementation"
+	KindResearch       = "research"
+	KindDoc            = "doc"
+	KindSpike          = "spike"
+)
+
+// Words in a task name that suggest its kind when kind is not set. Research
+// and doc words win over implementation words, so "Write the API guide" is
+// a doc task and "Evaluate cache libraries" a research task.
+var (
+	researchWords = []string{"research", "evaluate", "investigate", "explore", "assess", "survey", "spike"}
+	docWords      = []string{"doc", "docs", "document", "documentation", "readme", "changelog", "guide", "runbook", "adr"}
+	implWords     = []string{
+		// Verbs.
+		"implement", "add", "fix", "create", "build", "write", "wire", "refactor", "migrate", "integrate", "port", "replace", "remove",
+		// Noun phrases naming code: "API client implementation", "Retry middleware".
+		"implementation", "client", "handler", "endpoint", "middleware", "parser", "service", "cache", "command", "flag", "function", "module", "schema", "migration",
+	}
+)
+
+var nameWordPattern = regexp.MustCompile(`[a-z0-9]+`)
+
+// ClassifyKind returns the task's kind: its kind field if set, otherwise a
+// guess from the words of its task_name, or "" when the name gives no
+// hint. explicit reports whether the kind came from the field.
+func (t *TaskNode) ClassifyKind() (kind string, explicit bool) {
+	if t.Kind != "" {
+		return t.Kind, true
+	}
+	words := nameWordPattern.FindAllString(strings.ToLower(t.TaskName), -1)
+	has := func(list []string) bool {
+		return slices.ContainsFunc(words, func(w string) bool { return slices.Contains(list, w) })
+	}
+	switch {
+	case has(researchWords):
+		return KindResearch, false
+	case has(docWords):
+		return KindDoc, false
+	case has(implWords):
+		return KindImplementation, false
+	}
+	return "", false
+}
+
+// decisionPattern matches an acceptance criterion that states the decision
+// a spike produces.
+var decisionPattern = regexp.MustCompile(`(?i)\b(decision|decides?|decided|recommend(s|ed|ation)?|chooses?|chosen|go/no-go)\b`)
+
+// checkSpikes requires a spike to be timeboxed by its estimate and to end
+// in a decision stated by one of its acceptance criteria (SPIKE). Its goal
+// may describe exploration, so V6 does not check it for activity words.
+func (sv *SemanticValidator) checkSpikes(graph *TaskGraph, result *ValidationResult) {
+	for i, t := range graph.Tasks {
+		if t.Kind != KindSpike {
+			continue
+		}
+		if t.Estimate == "" || t.Estimate == "unknown" {
+			result.AddError(ValidationError{
+				Rule:       "SPIKE",
+				Severity:   SeverityError,
+				Path:       fmt.Sprintf("tasks[%d].estimate", i),
+				Message:    fmt.Sprintf("Spike '%s' has no timebox.", t.TaskID),
+				Suggestion: "Set estimate to the time the investigation may take, e.g. \"small\" or 240 (minutes). A spike stops when its timebox runs out.",
+				Context:    string(t.Estimate),
+			})
+		}
+		if !slices.ContainsFunc(t.Acceptance, decisionPattern.MatchString) {
+			result.AddError(ValidationError{
+				Rule:       "SPIKE",
+				Severity:   SeverityError,
+				Path:       fmt.Sprintf("tasks[%d].acceptance", i),
+				Message:    fmt.Sprintf("Spike '%s' has no acceptance criterion stating the decision it produces.", t.TaskID),
+				Suggestion: "Add a criterion such as 'A decision record names the chosen library and the rejected alternatives.'",
+			})
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package validator
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// MaxNestingDepth is the deepest nesting of objects and arrays a document
+// may have. Task documents need a handful of levels; the limit leaves x_
+// extension fields plenty of room while keeping pathological input, such
+// as thousands of nested arrays, away from the recursive decoders.
+const MaxNestingDepth = 100
+
+// checkNesting reports a SCHEMA error and returns false when data nests
+// objects and arrays deeper than MaxNestingDepth.
+func checkNesting(data []byte, result *ValidationResult) bool {
+	depth, at := nestingDepth(data, MaxNestingDepth)
+	if depth <= MaxNestingDepth {
+		return true
+	}
+	result.AddError(ValidationError{
+		Rule:       "SCHEMA",
+		Severity:   SeverityError,
+		Path:       "$",
+		Message:    fmt.Sprintf("Document nests objects and arrays more than %d levels deep (at byte %d); no task document needs that many.", MaxNestingDepth, at),
+		Suggestion: "Flatten the deeply nested value, most likely in an x_ extension field, or check that the input is the intended file.",
+	})
+	return false
+}
+
+// nestingDepth returns the deepest nesting of objects and arrays in data,
+// stopping at the first byte offset where it exceeds limit. Brackets
+// inside strings are skipped; malformed JSON is left to the decoder.
+func nestingDepth(data []byte, limit int) (maxDepth, at int) {
+	depth := 0
+	inString, escaped := false, false
+	for i, c := range data {
+		switch {
+		case inString:
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+		case c == '"':
+			inString = true
+		case c == '{' || c == '[':
+			depth++
+			if depth > maxDepth {
+				maxDepth = depth
+			}
+			if depth > limit {
+				return maxDepth, i
+			}
+		case c == '}' || c == ']':
+			depth--
+		}
+	}
+	return maxDepth, -1
+}
+
+// checkTrailing reports a SCHEMA error and returns false when data holds
+// more than one JSON value, such as a document followed by stray text or
+// two documents run together. Malformed JSON is left to the schema check.
+func checkTrailing(data []byte, result *ValidationResult) bool {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	var doc json.RawMessage
+	if dec.Decode(&doc) != nil {
+		return true
+	}
+	if err := EndOfInput(dec); err != nil {
+		result.AddError(ValidationError{
+			Rule:       "SCHEMA",
+			Severity:   SeverityError,
+			Path:       "$",
+			Message:    fmt.Sprintf("The document is complete, but %s.", err),
+			Suggestion: "Remove everything after the document's closing brace. To validate several documents, put each in its own file.",
+		})
+		return false
+	}
+	return true
+}
+
+// EndOfInput returns an error unless only whitespace is left in dec after
+// the value just decoded. Decoders read one value and stop, so without
+// this check anything after it would be ignored.
+func EndOfInput(dec *json.Decoder) error {
+	offset := dec.InputOffset()
+	var extra json.RawMessage
+	switch err := dec.Decode(&extra); {
+	case errors.Is(err, io.EOF):
+		return nil
+	case err == nil:
+		return fmt.Errorf("another JSON value follows it at byte %d; only one document is read", offset)
+	default:
+		return fmt.Errorf("text that is not JSON follows it after byte %d", offset)
+	}
+}
@@ -3,15 +3,40 @@ package validator
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
 )
 
+// Spec versions a task graph may declare, oldest first.
+const (
+	SpecVersion010 = "0.1.0"
+
+	// SpecVersion020 makes the contextual fields (depends_on, constraints,
+	// files_scope) required: each must hold a value or an explicit N/A.
+	SpecVersion020 = "0.2.0"
+)
+
+// SpecVersions lists every supported spec version, oldest first.
+var SpecVersions = []string{SpecVersion010, SpecVersion020}
+
 // TaskGraph represents the top-level task graph document.
 type TaskGraph struct {
-	Version    string                       `json:"version"`
-	Types      map[string]map[string]string `json:"types,omitempty"`
-	Defaults   *Defaults                    `json:"defaults,omitempty"`
-	Milestones []Milestone                  `json:"milestones,omitempty"`
-	Tasks      []TaskNode                   `json:"tasks"`
+	Version       string                       `json:"version"`
+	GeneratedBy   string                       `json:"generated_by,omitempty"`
+	GeneratedAt   string                       `json:"generated_at,omitempty"`
+	GraphRevision string                       `json:"graph_revision,omitempty"`
+	Seal          *GraphSeal                   `json:"seal,omitempty"`
+	Types         map[string]map[string]string `json:"types,omitempty"`
+	Defaults      *Defaults                    `json:"defaults,omitempty"`
+	ExternalTasks []string                     `json:"external_tasks,omitempty"`
+	Milestones    []Milestone                  `json:"milestones,omitempty"`
+	Tasks         []TaskNode                   `json:"tasks"`
+
+	// Extra holds top-level fields this version of the spec does not
+	// define, so rewriting a graph written for a newer spec keeps them.
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 // Defaults represents inheritable default field values.
@@ -26,25 +51,101 @@ type Milestone struct {
 	Name                string   `json:"name"`
 	DependsOnMilestones []string `json:"depends_on_milestones,omitempty"`
 	TaskIDs             []string `json:"task_ids"`
+	Due                 string   `json:"due,omitempty"`
+	NotBefore           string   `json:"not_before,omitempty"`
 }
 
 // TaskNode represents a single task in the graph.
 type TaskNode struct {
-	TaskID      string          `json:"task_id"`
-	TaskName    string          `json:"task_name"`
-	Goal        string          `json:"goal"`
-	Inputs      []InputSpec     `json:"inputs"`
-	Outputs     []OutputSpec    `json:"outputs"`
-	Acceptance  []string        `json:"acceptance"`
-	DependsOn   json.RawMessage `json:"depends_on,omitempty"`
-	Constraints json.RawMessage `json:"constraints,omitempty"`
-	FilesScope  json.RawMessage `json:"files_scope,omitempty"`
-	NonGoals    []string        `json:"non_goals,omitempty"`
-	Effects     json.RawMessage `json:"effects,omitempty"`
-	ErrorCases  []ErrorSpec     `json:"error_cases,omitempty"`
-	Priority    string          `json:"priority,omitempty"`
-	Estimate    string          `json:"estimate,omitempty"`
-	Notes       string          `json:"notes,omitempty"`
+	TaskID       string             `json:"task_id"`
+	TaskName     string             `json:"task_name"`
+	Kind         string             `json:"kind,omitempty"`
+	Goal         string             `json:"goal"`
+	GoalAllow    []string           `json:"goal_allow,omitempty"`
+	Inputs       []InputSpec        `json:"inputs"`
+	Outputs      []OutputSpec       `json:"outputs"`
+	Acceptance   []string           `json:"acceptance"`
+	DependsOn    json.RawMessage    `json:"depends_on,omitempty"`
+	Constraints  json.RawMessage    `json:"constraints,omitempty"`
+	FilesScope   json.RawMessage    `json:"files_scope,omitempty"`
+	NonGoals     []string           `json:"non_goals,omitempty"`
+	Effects      json.RawMessage    `json:"effects,omitempty"`
+	ErrorCases   []ErrorSpec        `json:"error_cases,omitempty"`
+	Verification []VerificationSpec `json:"verification,omitempty"`
+	Priority     Level              `json:"priority,omitempty"`
+	Estimate     Level              `json:"estimate,omitempty"`
+	Cost         *float64           `json:"cost,omitempty"`
+	Due          string             `json:"due,omitempty"`
+	NotBefore    string             `json:"not_before,omitempty"`
+	Labels       []string           `json:"labels,omitempty"`
+	Risk         *Risk              `json:"risk,omitempty"`
+	Notes        string             `json:"notes,omitempty"`
+
+	// Extra holds task fields this version of the spec does not define.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// Level is a priority or estimate: either one of the spec's named buckets
+// ("high", "small") or a raw number for finer control (a bd priority 0-4,
+// an estimate in minutes). Numbers are kept as their decimal text and
+// encoded back as JSON numbers.
+type Level string
+
+// Number returns the level's numeric value, if it is a raw number.
+func (l Level) Number() (int, bool) {
+	n, err := strconv.Atoi(string(l))
+	return n, err == nil
+}
+
+// UnmarshalJSON accepts a string or an integer.
+func (l *Level) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*l = Level(s)
+		return nil
+	}
+	var n int
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("must be a string or an integer, got %s", string(data))
+	}
+	*l = Level(strconv.Itoa(n))
+	return nil
+}
+
+// MarshalJSON writes raw numbers as JSON numbers and names as strings.
+func (l Level) MarshalJSON() ([]byte, error) {
+	if n, ok := l.Number(); ok {
+		return []byte(strconv.Itoa(n)), nil
+	}
+	return json.Marshal(string(l))
+}
+
+// Risk levels accepted in TaskNode.Risk.
+const (
+	RiskLow    = "low"
+	RiskMedium = "medium"
+	RiskHigh   = "high"
+)
+
+// Risk records how likely a task is to slip or fail, and the plan for it.
+type Risk struct {
+	Level      string `json:"level"`
+	Mitigation string `json:"mitigation,omitempty"`
+}
+
+// IsUrgent reports whether the task's priority is critical or high, by
+// name or as bd priority 0 or 1.
+func (t *TaskNode) IsUrgent() bool {
+	if n, ok := t.Priority.Number(); ok {
+		return n <= 1
+	}
+	p := strings.ToLower(string(t.Priority))
+	return p == "critical" || p == "high"
+}
+
+// IsHighRisk reports whether the task is marked high risk.
+func (t *TaskNode) IsHighRisk() bool {
+	return t.Risk != nil && t.Risk.Level == RiskHigh
 }
 
 // InputSpec represents a single input the task requires.
@@ -63,6 +164,16 @@ type OutputSpec struct {
 	Destination string `json:"destination"`
 }
 
+// Effect types accepted in EffectSpec.Type.
+const (
+	EffectDBRead          = "DB.Read"
+	EffectDBWrite         = "DB.Write"
+	EffectNetworkOut      = "Network.Out"
+	EffectFilesystemWrite = "Filesystem.Write"
+	EffectSubprocess      = "Subprocess"
+	EffectNone            = "None"
+)
+
 // EffectSpec represents a declared side effect.
 type EffectSpec struct {
 	Type   string `json:"type"`
@@ -76,12 +187,167 @@ type ErrorSpec struct {
 	Output    string `json:"output"`
 }
 
+// VerificationSpec says how one acceptance criterion is checked.
+type VerificationSpec struct {
+	// Criterion is the index of the criterion in Acceptance.
+	Criterion int    `json:"criterion"`
+	Command   string `json:"command,omitempty"`
+	TestFile  string `json:"test_file,omitempty"`
+}
+
 // NotApplicable represents an explicit N/A for contextual fields.
 type NotApplicable struct {
 	Status string `json:"status"`
 	Reason string `json:"reason"`
 }
 
+// WrapTask returns a one-task graph containing task, at the current spec version.
+func WrapTask(task TaskNode) *TaskGraph {
+	return &TaskGraph{
+		Version: "0.1.0",
+		Tasks:   []TaskNode{task},
+	}
+}
+
+// FindTask returns the task with the given task_id, or nil if absent.
+func (g *TaskGraph) FindTask(taskID string) *TaskNode {
+	for i := range g.Tasks {
+		if g.Tasks[i].TaskID == taskID {
+			return &g.Tasks[i]
+		}
+	}
+	return nil
+}
+
+// MilestoneOf returns the name of the first milestone listing taskID, or ""
+// if the task belongs to no milestone.
+func (g *TaskGraph) MilestoneOf(taskID string) string {
+	for _, m := range g.Milestones {
+		for _, id := range m.TaskIDs {
+			if id == taskID {
+				return m.Name
+			}
+		}
+	}
+	return ""
+}
+
+// ResolvedTask returns a copy of the named task with the graph defaults
+// applied. Default constraints, acceptance criteria, and non-goals come
+// first and task-level values are appended, per the spec's inheritance rule.
+// A task whose constraints are N/A inherits the default constraints alone.
+func (g *TaskGraph) ResolvedTask(taskID string) (*TaskNode, error) {
+	t := g.FindTask(taskID)
+	if t == nil {
+		return nil, fmt.Errorf("no task with task_id '%s' exists in the graph", taskID)
+	}
+	resolved := *t
+	if g.Defaults == nil {
+		return &resolved, nil
+	}
+
+	resolved.Acceptance = appendStrings(g.Defaults.Acceptance, t.Acceptance)
+	if len(g.Defaults.NonGoals) > 0 {
+		resolved.NonGoals = appendStrings(g.Defaults.NonGoals, t.NonGoals)
+	}
+	if len(g.Defaults.Constraints) > 0 {
+		var own []string
+		_ = json.Unmarshal(t.Constraints, &own) // N/A or absent leaves own empty.
+		data, err := json.Marshal(appendStrings(g.Defaults.Constraints, own))
+		if err != nil {
+			return nil, fmt.Errorf("marshaling resolved constraints: %w", err)
+		}
+		resolved.Constraints = data
+	}
+	return &resolved, nil
+}
+
+// Revision parts accepted by BumpRevision.
+const (
+	RevisionMinor = iota + 1
+	RevisionPatch
+)
+
+// BumpRevision increments graph_revision and returns the new value. Bumping
+// the minor part resets patch; any pre-release suffix is dropped. A graph
+// without a revision is left unchanged and "" is returned.
+func (g *TaskGraph) BumpRevision(part int) (string, error) {
+	if g.GraphRevision == "" {
+		return "", nil
+	}
+	core, _, _ := strings.Cut(g.GraphRevision, "-")
+	fields := strings.Split(core, ".")
+	if len(fields) != 3 {
+		return "", fmt.Errorf("graph_revision '%s' is not a semantic version", g.GraphRevision)
+	}
+	nums := make([]int, 3)
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil {
+			return "", fmt.Errorf("graph_revision '%s' is not a semantic version", g.GraphRevision)
+		}
+		nums[i] = n
+	}
+	switch part {
+	case RevisionMinor:
+		nums[1]++
+		nums[2] = 0
+	case RevisionPatch:
+		nums[2]++
+	default:
+		return "", fmt.Errorf("unknown revision part %d", part)
+	}
+	g.GraphRevision = fmt.Sprintf("%d.%d.%d", nums[0], nums[1], nums[2])
+	return g.GraphRevision, nil
+}
+
+// appendStrings concatenates two slices into a new slice.
+func appendStrings(a, b []string) []string {
+	out := make([]string, 0, len(a)+len(b))
+	out = append(out, a...)
+	return append(out, b...)
+}
+
+// parseNotApplicable decodes raw as an N/A object. It returns nil, nil
+// when raw is not a JSON object, so the caller can report its own
+// shape error. An object must be exactly {"status": "N/A", "reason": ...}
+// with a non-empty reason; near-misses such as {"status": "na"} or an
+// extra key get an error naming the problem.
+func parseNotApplicable(field string, raw json.RawMessage) (*NotApplicable, error) {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return nil, nil
+	}
+
+	for _, k := range slices.Sorted(maps.Keys(obj)) {
+		if k != "status" && k != "reason" {
+			return nil, fmt.Errorf("%s N/A object has unknown key %q; only \"status\" and \"reason\" are allowed", field, k)
+		}
+	}
+
+	var na NotApplicable
+	statusRaw, ok := obj["status"]
+	if !ok {
+		return nil, fmt.Errorf("%s object is missing \"status\"; write {\"status\": \"N/A\", \"reason\": \"...\"}", field)
+	}
+	if err := json.Unmarshal(statusRaw, &na.Status); err != nil {
+		return nil, fmt.Errorf("%s status must be the string \"N/A\", got: %s", field, string(statusRaw))
+	}
+	if na.Status != "N/A" {
+		return nil, fmt.Errorf("%s status must be exactly \"N/A\", got %q", field, na.Status)
+	}
+
+	if reasonRaw, ok := obj["reason"]; ok {
+		if err := json.Unmarshal(reasonRaw, &na.Reason); err != nil {
+			return nil, fmt.Errorf("%s reason must be a string, got: %s", field, string(reasonRaw))
+		}
+	}
+	if strings.TrimSpace(na.Reason) == "" {
+		return nil, fmt.Errorf("%s is N/A without a reason; add a non-empty \"reason\" saying why it does not apply", field)
+	}
+	return &na, nil
+}
+
 // ParseDependsOn extracts the depends_on field which can be either
 // a list of task IDs or a NotApplicable object.
 func (t *TaskNode) ParseDependsOn() (taskIDs []string, na *NotApplicable, err error) {
@@ -96,11 +362,8 @@ func (t *TaskNode) ParseDependsOn() (taskIDs []string, na *NotApplicable, err er
 	}
 
 	// Try as NotApplicable object.
-	var notAppl NotApplicable
-	if err := json.Unmarshal(t.DependsOn, &notAppl); err == nil {
-		if notAppl.Status == "N/A" {
-			return nil, &notAppl, nil
-		}
+	if na, err := parseNotApplicable("depends_on", t.DependsOn); na != nil || err != nil {
+		return nil, na, err
 	}
 
 	return nil, nil, fmt.Errorf("depends_on must be either an array of task IDs or {\"status\": \"N/A\", \"reason\": \"...\"}, got: %s", string(t.DependsOn))
@@ -118,12 +381,50 @@ func (t *TaskNode) ParseFilesScope() (files []string, na *NotApplicable, err err
 		return paths, nil, nil
 	}
 
-	var notAppl NotApplicable
-	if err := json.Unmarshal(t.FilesScope, &notAppl); err == nil {
-		if notAppl.Status == "N/A" {
-			return nil, &notAppl, nil
-		}
+	if na, err := parseNotApplicable("files_scope", t.FilesScope); na != nil || err != nil {
+		return nil, na, err
 	}
 
 	return nil, nil, fmt.Errorf("files_scope must be either an array of file paths or {\"status\": \"N/A\", \"reason\": \"...\"}, got: %s", string(t.FilesScope))
 }
+
+// ParseConstraints extracts the constraints field which can be either
+// a list of constraint strings or a NotApplicable object.
+func (t *TaskNode) ParseConstraints() (constraints []string, na *NotApplicable, err error) {
+	if t.Constraints == nil {
+		return nil, nil, nil
+	}
+
+	var items []string
+	if err := json.Unmarshal(t.Constraints, &items); err == nil {
+		return items, nil, nil
+	}
+
+	if na, err := parseNotApplicable("constraints", t.Constraints); na != nil || err != nil {
+		return nil, na, err
+	}
+
+	return nil, nil, fmt.Errorf("constraints must be either an array of strings or {\"status\": \"N/A\", \"reason\": \"...\"}, got: %s", string(t.Constraints))
+}
+
+// ParseEffects extracts the effects field which can be either the string
+// "None" (reported as none=true) or a list of EffectSpec objects.
+func (t *TaskNode) ParseEffects() (effects []EffectSpec, none bool, err error) {
+	if t.Effects == nil {
+		return nil, false, nil
+	}
+
+	var s string
+	if err := json.Unmarshal(t.Effects, &s); err == nil {
+		if strings.EqualFold(s, "none") {
+			return nil, true, nil
+		}
+	}
+
+	var specs []EffectSpec
+	if err := json.Unmarshal(t.Effects, &specs); err == nil {
+		return specs, false, nil
+	}
+
+	return nil, false, fmt.Errorf("effects must be either \"None\" or an array of {\"type\", \"target\"} objects, got: %s", string(t.Effects))
+}
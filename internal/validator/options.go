@@ -0,0 +1,76 @@
+package validator
+
+// Validator validates documents with a fixed mode and Options, for Go
+// callers validating many documents the same way. Build one with New.
+type Validator struct {
+	mode Mode
+	opts Options
+}
+
+// Option configures a Validator.
+type Option func(*Validator) error
+
+// New returns a Validator in graph mode with no options, adjusted by opts
+// in order. It returns the first error an option reports.
+func New(opts ...Option) (*Validator, error) {
+	v := &Validator{mode: ModeTaskGraph}
+	for _, opt := range opts {
+		if err := opt(v); err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+// WithMode validates single tasks or task graphs.
+func WithMode(mode Mode) Option {
+	return func(v *Validator) error {
+		v.mode = mode
+		return nil
+	}
+}
+
+// WithProfile merges the named profile into the rule config; see Profile.
+// Later profiles and rule configs are layered on top of earlier ones.
+func WithProfile(name string) Option {
+	return func(v *Validator) error {
+		rules, err := Profile(name)
+		if err != nil {
+			return err
+		}
+		v.opts.Rules = v.opts.Rules.Merge(rules)
+		return nil
+	}
+}
+
+// WithRules merges rules into the rule config, as a --config file's rules
+// are layered over the profile.
+func WithRules(rules RuleConfig) Option {
+	return func(v *Validator) error {
+		v.opts.Rules = v.opts.Rules.Merge(rules)
+		return nil
+	}
+}
+
+// WithRepoRoot enables the REPO checks against the repository checked
+// out at dir.
+func WithRepoRoot(dir string) Option {
+	return func(v *Validator) error {
+		v.opts.RepoRoot = dir
+		return nil
+	}
+}
+
+// WithOptions replaces the Options wholesale, for settings without an
+// Option of their own. Options given after it still apply.
+func WithOptions(opts Options) Option {
+	return func(v *Validator) error {
+		v.opts = opts
+		return nil
+	}
+}
+
+// Validate validates data; see ValidateWithOptions.
+func (v *Validator) Validate(data []byte) (*ValidationResult, error) {
+	return ValidateWithOptions(data, v.mode, v.opts)
+}
@@ -0,0 +1,56 @@
+package validator
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// NormalizeScopePath returns a files_scope entry in the form used to
+// compare entries: forward slashes, no "./" prefix or repeated slashes,
+// and the trailing slash of a directory entry kept. Graphs written on
+// Windows may use backslashes; agents on other systems would otherwise
+// treat "internal\api.go" and "internal/api.go" as different files.
+func NormalizeScopePath(p string) string {
+	s := strings.ReplaceAll(p, `\`, "/")
+	dir := strings.HasSuffix(s, "/")
+	s = path.Clean(s)
+	if dir && s != "/" && s != "." {
+		s += "/"
+	}
+	return s
+}
+
+// isAbsScopePath reports whether a normalized entry is absolute on any
+// platform: rooted ("/etc") or with a drive letter ("C:/src").
+func isAbsScopePath(p string) bool {
+	if strings.HasPrefix(p, "/") {
+		return true
+	}
+	return len(p) >= 2 && p[1] == ':' && (p[0] >= 'a' && p[0] <= 'z' || p[0] >= 'A' && p[0] <= 'Z')
+}
+
+// checkScopePaths flags files_scope entries written with backslashes
+// (PATHS). The spec uses forward slashes on every platform.
+func (sv *SemanticValidator) checkScopePaths(graph *TaskGraph, result *ValidationResult) {
+	for i, t := range graph.Tasks {
+		files, _, err := t.ParseFilesScope()
+		if err != nil {
+			continue // Already reported elsewhere.
+		}
+		for j, f := range files {
+			if !strings.Contains(f, `\`) {
+				continue
+			}
+			fixed := NormalizeScopePath(f)
+			result.AddError(ValidationError{
+				Rule:       "PATHS",
+				Severity:   SeverityWarning,
+				Path:       fmt.Sprintf("tasks[%d].files_scope[%d]", i, j),
+				Message:    fmt.Sprintf("files_scope entry '%s' of task '%s' uses backslashes.", f, t.TaskID),
+				Suggestion: fmt.Sprintf("Use forward slashes on every platform: '%s'.", fixed),
+				Context:    f,
+			})
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package validator
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// RuleFunc is a Go-native semantic check. It inspects a parsed graph and
+// reports findings with result.AddError, like the built-in rules.
+type RuleFunc func(graph *TaskGraph, result *ValidationResult)
+
+type registeredRule struct {
+	id string
+	fn RuleFunc
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   []registeredRule
+)
+
+// RegisterRule adds a custom semantic check that runs on every graph after
+// the built-in rules, in registration order. Findings it reports without a
+// rule ID get id, so RuleConfig can disable it or change its severity like
+// any built-in rule. It is meant to be called from an init function;
+// RegisterRule panics if id is empty or already registered, or fn is nil.
+func RegisterRule(id string, fn RuleFunc) {
+	if id == "" || fn == nil {
+		panic("validator: RegisterRule needs an id and a function")
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	for _, r := range registry {
+		if r.id == id {
+			panic(fmt.Sprintf("validator: rule '%s' is already registered", id))
+		}
+	}
+	registry = append(registry, registeredRule{id: id, fn: fn})
+}
+
+// RegisteredRules returns the IDs of the rules added with RegisterRule,
+// in the order they run.
+func RegisteredRules() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	ids := make([]string, len(registry))
+	for i, r := range registry {
+		ids[i] = r.id
+	}
+	return ids
+}
+
+// runRegisteredRules runs every registered rule against the graph.
+func (sv *SemanticValidator) runRegisteredRules(graph *TaskGraph, result *ValidationResult) {
+	registryMu.RLock()
+	rules := slices.Clone(registry)
+	registryMu.RUnlock()
+
+	for _, r := range rules {
+		start := len(result.Errors)
+		sv.timed(r.id, result, func() { r.fn(graph, result) })
+		for i := start; i < len(result.Errors); i++ {
+			if result.Errors[i].Rule == "" {
+				result.Errors[i].Rule = r.id
+			}
+		}
+	}
+}
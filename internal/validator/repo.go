@@ -0,0 +1,71 @@
+package validator
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// checkRepoPaths compares files_scope entries with the repository at
+// Options.RepoRoot (REPO). Files may not exist yet, since tasks create
+// them, but their directory should, and no entry may leave the repository.
+func (sv *SemanticValidator) checkRepoPaths(graph *TaskGraph, result *ValidationResult) {
+	root := sv.opts.RepoRoot
+	if root == "" {
+		return
+	}
+
+	for i, t := range graph.Tasks {
+		files, _, err := t.ParseFilesScope()
+		if err != nil {
+			continue // Already reported elsewhere.
+		}
+		for j, f := range files {
+			path := fmt.Sprintf("tasks[%d].files_scope[%d]", i, j)
+			norm := NormalizeScopePath(f)
+			if isAbsScopePath(norm) || norm == ".." || strings.HasPrefix(norm, "../") {
+				result.AddError(ValidationError{
+					Rule:       "REPO",
+					Severity:   SeverityWarning,
+					Path:       path,
+					Message:    fmt.Sprintf("files_scope entry '%s' of task '%s' points outside the repository.", f, t.TaskID),
+					Suggestion: "Use a path relative to the repository root.",
+					Context:    f,
+				})
+				continue
+			}
+
+			dir := staticDir(norm)
+			if dir == "." {
+				continue
+			}
+			if info, err := os.Stat(filepath.Join(root, filepath.FromSlash(dir))); err != nil || !info.IsDir() {
+				result.AddError(ValidationError{
+					Rule:     "REPO",
+					Severity: SeverityWarning,
+					Path:     path,
+					Message: fmt.Sprintf(
+						"files_scope entry '%s' of task '%s' is in directory '%s', which does not exist in the repository.",
+						f, t.TaskID, dir,
+					),
+					Suggestion: "Check the path for typos. If the task creates the directory, say so in the goal or notes.",
+					Context:    f,
+				})
+			}
+		}
+	}
+}
+
+// staticDir returns the directory part of a normalized files_scope entry,
+// stopping before the first segment that contains a glob metacharacter.
+func staticDir(p string) string {
+	segments := strings.Split(p, "/")
+	for k, seg := range segments {
+		if strings.ContainsAny(seg, "*?[") {
+			return path.Join(append([]string{"."}, segments[:k]...)...)
+		}
+	}
+	return path.Dir(strings.TrimSuffix(p, "/"))
+}
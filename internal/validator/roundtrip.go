@@ -0,0 +1,166 @@
+package validator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+)
+
+// Kinds of Difference.
+const (
+	DiffDropped = "dropped" // In the document, missing from the re-encoding.
+	DiffAdded   = "added"   // Only in the re-encoding.
+	DiffChanged = "changed" // In both, with different values.
+)
+
+// Difference is one place where a document and its re-encoding disagree.
+type Difference struct {
+	Kind string `json:"kind"`
+	Path string `json:"path"`
+
+	// Before and After are the compact JSON values; empty when absent.
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+}
+
+// RoundTrip decodes a document into the model for mode, encodes it again,
+// and compares the two as JSON values: fields the model dropped, fields
+// it added, and values it reinterpreted (such as a numeric string read as
+// a number). Key order, whitespace, and number formatting are not
+// differences. A document that does not decode is an error.
+func RoundTrip(data []byte, mode Mode) ([]Difference, error) {
+	var model any = &TaskGraph{}
+	if mode == ModeSingleTask {
+		model = &TaskNode{}
+	}
+	if err := json.Unmarshal(data, model); err != nil {
+		return nil, fmt.Errorf("decoding document: %w", err)
+	}
+	encoded, err := json.Marshal(model)
+	if err != nil {
+		return nil, fmt.Errorf("encoding document: %w", err)
+	}
+
+	before, err := decodeGeneric(data)
+	if err != nil {
+		return nil, err
+	}
+	after, err := decodeGeneric(encoded)
+	if err != nil {
+		return nil, err
+	}
+	var diffs []Difference
+	diffValues("", before, after, &diffs)
+	return diffs, nil
+}
+
+// DroppedFields lists the paths of fields that decoding data into the
+// model for mode would lose. Fields the spec does not define are kept in
+// Extra on graphs and tasks, so these are unknown fields nested deeper
+// (in a milestone or an input, say). Empty values the model omits, such
+// as "labels": [], are not counted.
+func DroppedFields(data []byte, mode Mode) ([]string, error) {
+	diffs, err := RoundTrip(data, mode)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, d := range diffs {
+		if d.Kind != DiffDropped {
+			continue
+		}
+		switch d.Before {
+		case "null", `""`, "[]", "{}", "false", "0":
+			continue
+		}
+		paths = append(paths, d.Path)
+	}
+	return paths, nil
+}
+
+// decodeGeneric decodes JSON into maps, slices, and json.Numbers.
+func decodeGeneric(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("decoding document: %w", err)
+	}
+	if err := EndOfInput(dec); err != nil {
+		return nil, fmt.Errorf("decoding document: the document is complete, but %w", err)
+	}
+	return v, nil
+}
+
+// diffValues appends the differences between a and b, found at path.
+func diffValues(path string, a, b any, diffs *[]Difference) {
+	switch av := a.(type) {
+	case map[string]any:
+		if bv, ok := b.(map[string]any); ok {
+			keys := slices.Collect(maps.Keys(av))
+			for k := range bv {
+				if _, ok := av[k]; !ok {
+					keys = append(keys, k)
+				}
+			}
+			slices.Sort(keys)
+			for _, k := range keys {
+				p := k
+				if path != "" {
+					p = path + "." + k
+				}
+				x, inA := av[k]
+				y, inB := bv[k]
+				switch {
+				case !inB:
+					*diffs = append(*diffs, Difference{Kind: DiffDropped, Path: p, Before: compactJSON(x)})
+				case !inA:
+					*diffs = append(*diffs, Difference{Kind: DiffAdded, Path: p, After: compactJSON(y)})
+				default:
+					diffValues(p, x, y, diffs)
+				}
+			}
+			return
+		}
+	case []any:
+		if bv, ok := b.([]any); ok {
+			for i := range max(len(av), len(bv)) {
+				p := fmt.Sprintf("%s[%d]", path, i)
+				switch {
+				case i >= len(bv):
+					*diffs = append(*diffs, Difference{Kind: DiffDropped, Path: p, Before: compactJSON(av[i])})
+				case i >= len(av):
+					*diffs = append(*diffs, Difference{Kind: DiffAdded, Path: p, After: compactJSON(bv[i])})
+				default:
+					diffValues(p, av[i], bv[i], diffs)
+				}
+			}
+			return
+		}
+	case json.Number:
+		if bv, ok := b.(json.Number); ok {
+			x, errA := strconv.ParseFloat(string(av), 64)
+			y, errB := strconv.ParseFloat(string(bv), 64)
+			if errA == nil && errB == nil && x == y {
+				return
+			}
+		}
+	default:
+		if a == b {
+			return
+		}
+	}
+	*diffs = append(*diffs, Difference{Kind: DiffChanged, Path: path, Before: compactJSON(a), After: compactJSON(b)})
+}
+
+// compactJSON renders a decoded value for a Difference.
+func compactJSON(v any) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(v)
+	return string(bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+}
@@ -0,0 +1,165 @@
+package validator
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// RuleConfig adjusts which findings a validation run reports and how
+// severe they are. The zero value reports every finding unchanged.
+type RuleConfig struct {
+	// Disabled lists rule IDs (e.g. "V11") whose findings are dropped.
+	Disabled []string `json:"disabled,omitempty"`
+
+	// Severity overrides the severity of every finding of a rule.
+	Severity map[string]Severity `json:"severity,omitempty"`
+
+	// WarningsAsErrors promotes any warning left after overrides to an error.
+	WarningsAsErrors bool `json:"warnings_as_errors,omitempty"`
+
+	// Enabled turns on rules that are off by default (see optInRules).
+	Enabled []string `json:"enabled,omitempty"`
+
+	// Limits overrides the thresholds of rules that have one, by name
+	// (see defaultLimits).
+	Limits map[string]int `json:"limits,omitempty"`
+}
+
+// Profile names accepted by Profile, in increasing order of strictness.
+const (
+	ProfileMinimal  = "minimal"
+	ProfileStandard = "standard"
+	ProfileStrict   = "strict"
+)
+
+// heuristicRules are the content-quality and advisory rules; the rest
+// check structure, dates, and referential integrity. A new rule belongs
+// here unless a document that breaks it is malformed.
+var heuristicRules = []string{"V6", "V7", "V9", "V10", "V11", "V12", "V13", "V14", "RISK", "OUTPUTS", "FAN", "DEPTH", "SPIKE", "PATHS", "OWNERS", "LLM"}
+
+// optInRules run only when a RuleConfig enables them. ESTIMATE matters to
+// teams that schedule from the graph and is noise for the rest; STYLE is
+// writing advice some authors want and others do not.
+var optInRules = []string{"ESTIMATE", "STYLE", "NONGOALS"}
+
+// defaultLimits are the thresholds RuleConfig.Limits may override.
+var defaultLimits = map[string]int{
+	// NONGOALS: acceptance criteria that make a task large.
+	"non_goals_acceptance": 6,
+	// FAN: direct dependencies and direct dependents of one task.
+	"max_depends_on": 6,
+	"max_dependents": 6,
+	// DEPTH: tasks in the longest dependency chain.
+	"max_depth": 10,
+}
+
+// Profile returns the named RuleConfig preset:
+//
+//   - minimal: structure and referential integrity only (SCHEMA, DUPKEY,
+//     TEMPLATE, V2, V4, V5, MILESTONE, META, DATES, VERIFY, and the REPO,
+//     IDS, and SEAL checks when configured); every heuristic rule is
+//     disabled.
+//   - standard: every rule at its default severity.
+//   - strict: every rule, including the opt-in ESTIMATE, STYLE, and
+//     NONGOALS, with warnings promoted to errors (STYLE stays INFO). Combine with
+//     Options.RepoRoot to add the REPO checks.
+func Profile(name string) (RuleConfig, error) {
+	switch name {
+	case ProfileMinimal:
+		return RuleConfig{Disabled: slices.Clone(heuristicRules)}, nil
+	case ProfileStandard, "":
+		return RuleConfig{}, nil
+	case ProfileStrict:
+		return RuleConfig{WarningsAsErrors: true, Enabled: slices.Clone(optInRules)}, nil
+	default:
+		return RuleConfig{}, fmt.Errorf("unknown profile '%s'. Must be '%s', '%s', or '%s'", name, ProfileMinimal, ProfileStandard, ProfileStrict)
+	}
+}
+
+// IsZero reports whether the config leaves findings unchanged.
+func (rc RuleConfig) IsZero() bool {
+	return len(rc.Disabled) == 0 && len(rc.Severity) == 0 && !rc.WarningsAsErrors && len(rc.Enabled) == 0 && len(rc.Limits) == 0
+}
+
+// enables reports whether an opt-in rule should run.
+func (rc RuleConfig) enables(rule string) bool {
+	return slices.Contains(rc.Enabled, rule)
+}
+
+// limit returns the named threshold: the override in Limits, or the
+// default.
+func (rc RuleConfig) limit(name string) int {
+	if n, ok := rc.Limits[name]; ok {
+		return n
+	}
+	return defaultLimits[name]
+}
+
+// checkLimits reports a Limits entry that names no threshold or is not
+// positive.
+func (rc RuleConfig) checkLimits() error {
+	for _, name := range slices.Sorted(maps.Keys(rc.Limits)) {
+		if _, ok := defaultLimits[name]; !ok {
+			return fmt.Errorf("unknown limit '%s'; known limits: %s", name, strings.Join(slices.Sorted(maps.Keys(defaultLimits)), ", "))
+		}
+		if rc.Limits[name] < 1 {
+			return fmt.Errorf("limit '%s' must be at least 1, got %d", name, rc.Limits[name])
+		}
+	}
+	return nil
+}
+
+// Apply returns a result with disabled findings removed and severities
+// adjusted, with Valid and the counts recomputed. The input is not modified.
+func (rc RuleConfig) Apply(result *ValidationResult) *ValidationResult {
+	if rc.IsZero() {
+		return result
+	}
+
+	out := &ValidationResult{
+		Valid:   true,
+		Partial: result.Partial,
+		Stats:   ValidationStats{TotalTasks: result.Stats.TotalTasks},
+		Graph:   result.Graph,
+	}
+	for _, e := range result.Errors {
+		if slices.Contains(rc.Disabled, e.Rule) {
+			continue
+		}
+		if sev, ok := rc.Severity[e.Rule]; ok {
+			e.Severity = sev
+		}
+		if rc.WarningsAsErrors && e.Severity == SeverityWarning {
+			e.Severity = SeverityError
+		}
+		out.AddError(e)
+	}
+	if !out.Valid {
+		out.Graph = nil
+	}
+	return out
+}
+
+// Merge returns rc with other layered on top: disabled and enabled rules
+// are combined, other's severity overrides and limits win, and warnings
+// are promoted if either asks.
+func (rc RuleConfig) Merge(other RuleConfig) RuleConfig {
+	out := RuleConfig{
+		Disabled:         append(slices.Clone(rc.Disabled), other.Disabled...),
+		WarningsAsErrors: rc.WarningsAsErrors || other.WarningsAsErrors,
+		Enabled:          append(slices.Clone(rc.Enabled), other.Enabled...),
+	}
+	if len(rc.Severity)+len(other.Severity) > 0 {
+		out.Severity = make(map[string]Severity, len(rc.Severity)+len(other.Severity))
+		maps.Copy(out.Severity, rc.Severity)
+		maps.Copy(out.Severity, other.Severity)
+	}
+	if len(rc.Limits)+len(other.Limits) > 0 {
+		out.Limits = make(map[string]int, len(rc.Limits)+len(other.Limits))
+		maps.Copy(out.Limits, rc.Limits)
+		maps.Copy(out.Limits, other.Limits)
+	}
+	return out
+}
@@ -3,6 +3,9 @@ package validator
 import (
 	"embed"
 	"fmt"
+	"maps"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/kaptinlin/jsonschema"
@@ -15,6 +18,7 @@ var embeddedSchemas embed.FS
 type SchemaValidator struct {
 	taskNodeSchema  *jsonschema.Schema
 	taskGraphSchema *jsonschema.Schema
+	designSchema    *jsonschema.Schema
 }
 
 // NewSchemaValidator creates a validator with the embedded JSON schemas.
@@ -43,9 +47,21 @@ func NewSchemaValidator() (*SchemaValidator, error) {
 		return nil, fmt.Errorf("compiling task_graph schema: %w", err)
 	}
 
+	// Load and compile the design metadata schema.
+	designData, err := embeddedSchemas.ReadFile("schemas/design_metadata.schema.json")
+	if err != nil {
+		return nil, fmt.Errorf("reading embedded design_metadata schema: %w", err)
+	}
+
+	designSchema, err := c.Compile(designData)
+	if err != nil {
+		return nil, fmt.Errorf("compiling design_metadata schema: %w", err)
+	}
+
 	return &SchemaValidator{
 		taskNodeSchema:  nodeSchema,
 		taskGraphSchema: graphSchema,
+		designSchema:    designSchema,
 	}, nil
 }
 
@@ -53,7 +69,7 @@ func NewSchemaValidator() (*SchemaValidator, error) {
 func (sv *SchemaValidator) ValidateTaskNode(data []byte, result *ValidationResult) {
 	schemaResult := sv.taskNodeSchema.Validate(data)
 	if !schemaResult.IsValid() {
-		convertSchemaErrors(schemaResult, result)
+		convertSchemaErrors(sv.taskNodeSchema, schemaResult, result)
 	}
 }
 
@@ -61,59 +77,242 @@ func (sv *SchemaValidator) ValidateTaskNode(data []byte, result *ValidationResul
 func (sv *SchemaValidator) ValidateTaskGraph(data []byte, result *ValidationResult) {
 	schemaResult := sv.taskGraphSchema.Validate(data)
 	if !schemaResult.IsValid() {
-		convertSchemaErrors(schemaResult, result)
+		convertSchemaErrors(sv.taskGraphSchema, schemaResult, result)
+	}
+}
+
+// ValidateDesign validates design metadata JSON against the schema.
+func (sv *SchemaValidator) ValidateDesign(data []byte, result *ValidationResult) {
+	schemaResult := sv.designSchema.Validate(data)
+	if !schemaResult.IsValid() {
+		convertSchemaErrors(sv.designSchema, schemaResult, result)
 	}
 }
 
 // convertSchemaErrors translates kaptinlin/jsonschema validation results
 // into our LLM-friendly ValidationError format.
-func convertSchemaErrors(schemaResult *jsonschema.EvaluationResult, result *ValidationResult) {
-	// GetDetailedErrors returns map[fieldPath]errorMessage with all leaf errors.
-	errors := schemaResult.GetDetailedErrors()
-	for path, msg := range errors {
-		if path == "" {
-			path = "$"
-		}
-
-		suggestion := generateSchemaSuggestion(path, msg)
-
+func convertSchemaErrors(schema *jsonschema.Schema, schemaResult *jsonschema.EvaluationResult, result *ValidationResult) {
+	for _, f := range collectSchemaErrors(schema, schemaResult) {
 		result.AddError(ValidationError{
 			Rule:       "SCHEMA",
 			Severity:   SeverityError,
-			Path:       path,
-			Message:    msg,
-			Suggestion: suggestion,
+			Path:       f.path,
+			Message:    f.err.Error(),
+			Suggestion: generateSchemaSuggestion(f),
 		})
 	}
 }
 
-// generateSchemaSuggestion produces actionable fix advice based on the
-// JSON path and error message.
-func generateSchemaSuggestion(path, msg string) string {
-	lowerMsg := strings.ToLower(msg)
+// schemaFailure is one leaf error of a schema evaluation: the reported
+// path, the instance location it applies to, the subschema that failed
+// (nil if it could not be located), and the error.
+type schemaFailure struct {
+	path   string
+	at     string
+	schema *jsonschema.Schema
+	err    *jsonschema.EvaluationError
+}
+
+// collectSchemaErrors walks the evaluation tree for leaf errors, keyed the
+// way GetDetailedErrors keys them (instance location, then keyword) but
+// keeping the structured error and the subschema that produced it, so
+// suggestions can quote the allowed values. A later error at the same
+// path replaces an earlier one.
+func collectSchemaErrors(root *jsonschema.Schema, r *jsonschema.EvaluationResult) []schemaFailure {
+	var out []schemaFailure
+	seen := make(map[string]int)
+	var walk func(r *jsonschema.EvaluationResult, base string, schema *jsonschema.Schema)
+	walk = func(r *jsonschema.EvaluationResult, base string, schema *jsonschema.Schema) {
+		current := base + r.InstanceLocation
+		at := current
+		if at == "" {
+			at = "$"
+		}
+		for _, key := range slices.Sorted(maps.Keys(r.Errors)) {
+			path := current
+			if path != "" && key != "" {
+				path += "/" + key
+			} else if key != "" {
+				path = key
+			}
+			if path == "" {
+				path = "$"
+			}
+			f := schemaFailure{path: path, at: at, schema: schema, err: r.Errors[key]}
+			if i, ok := seen[path]; ok {
+				out[i] = f
+				continue
+			}
+			seen[path] = len(out)
+			out = append(out, f)
+		}
+		for _, d := range r.Details {
+			walk(d, current, subschema(schema, d.EvaluationPath))
+		}
+	}
+	walk(r, "", root)
+	return out
+}
 
-	switch {
-	case strings.Contains(lowerMsg, "required"):
-		return fmt.Sprintf("Add the missing required field at '%s'. Check the spec's Quick Reference (Appendix A) for the expected format.", path)
-	case strings.Contains(lowerMsg, "pattern"):
-		if strings.Contains(path, "task_id") {
+// subschema follows a detail's evaluation path (relative to its parent,
+// e.g. "/properties/goal" or "/oneOf/1") from s. An empty path steps into
+// s's resolved $ref. It returns nil for paths it does not understand.
+func subschema(s *jsonschema.Schema, evalPath string) *jsonschema.Schema {
+	if s == nil {
+		return nil
+	}
+	if evalPath == "" {
+		if s.ResolvedRef != nil {
+			return s.ResolvedRef
+		}
+		return s
+	}
+	parts := strings.Split(strings.TrimPrefix(evalPath, "/"), "/")
+	for i := 0; i < len(parts) && s != nil; i++ {
+		switch parts[i] {
+		case "properties":
+			if s.Properties == nil || i+1 >= len(parts) {
+				return nil
+			}
+			i++
+			s = (*s.Properties)[parts[i]]
+		case "items":
+			s = s.Items
+			i++ // Skip the item index.
+		case "additionalProperties":
+			s = s.AdditionalProperties
+			i++ // Skip the property name.
+		case "oneOf", "anyOf":
+			list := s.OneOf
+			if parts[i] == "anyOf" {
+				list = s.AnyOf
+			}
+			if i+1 >= len(parts) {
+				return nil
+			}
+			i++
+			n, err := strconv.Atoi(parts[i])
+			if err != nil || n >= len(list) {
+				return nil
+			}
+			s = list[n]
+		default:
+			return nil
+		}
+	}
+	return s
+}
+
+// allowedValues renders enum values as "critical|high|medium|low".
+func allowedValues(values []any) string {
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = fmt.Sprint(v)
+	}
+	return strings.Join(out, "|")
+}
+
+// describeShape summarizes a subschema for a oneOf suggestion, e.g.
+// "array of string", "object {reason, status}", or "trivial|small".
+func describeShape(s *jsonschema.Schema) string {
+	if s.ResolvedRef != nil {
+		s = s.ResolvedRef
+	}
+	if len(s.Enum) > 0 {
+		return allowedValues(s.Enum)
+	}
+	shape := strings.Join(s.Type, "/")
+	if shape == "" {
+		shape = "value"
+	}
+	if s.Items != nil && len(s.Items.Type) > 0 {
+		shape += " of " + strings.Join(s.Items.Type, "/")
+	}
+	if s.Properties != nil {
+		shape += " {" + strings.Join(slices.Sorted(maps.Keys(*s.Properties)), ", ") + "}"
+	}
+	return shape
+}
+
+// generateSchemaSuggestion produces actionable fix advice from the failed
+// schema keyword, its parameters, and the subschema that failed: it quotes
+// the allowed values, the pattern, the limit, or the valid field names, so
+// the fix does not need a trip to the schema and does not depend on the
+// library's wording.
+func generateSchemaSuggestion(f schemaFailure) string {
+	e, at, s := f.err, f.at, f.schema
+	param := func(name string) string {
+		return strings.Trim(fmt.Sprint(e.Params[name]), "'")
+	}
+
+	switch e.Keyword {
+	case "required":
+		missing := param("property")
+		if _, ok := e.Params["properties"]; ok {
+			missing = strings.ReplaceAll(param("properties"), "'", "")
+		}
+		return fmt.Sprintf("Add the missing required field(s) %s at '%s'. Check the spec's Quick Reference (Appendix A) for the expected format.", missing, at)
+	case "pattern":
+		if strings.Contains(f.path, "task_id") || strings.Contains(f.path, "depends_on") {
 			return "task_id must be kebab-case (lowercase letters, numbers, hyphens). Example: 'my-task-name'. Pattern: ^[a-z0-9]+(-[a-z0-9]+)*$"
 		}
-		return fmt.Sprintf("The value at '%s' does not match the required pattern. Check the schema for the expected format.", path)
-	case strings.Contains(lowerMsg, "enum") || strings.Contains(lowerMsg, "const"):
-		return fmt.Sprintf("The value at '%s' must be one of the allowed values. Check the schema definition for valid options.", path)
-	case strings.Contains(lowerMsg, "maxlength") || strings.Contains(lowerMsg, "maximum"):
-		return fmt.Sprintf("The value at '%s' exceeds the maximum length. Shorten it.", path)
-	case strings.Contains(lowerMsg, "minlength") || strings.Contains(lowerMsg, "minimum"):
-		return fmt.Sprintf("The value at '%s' is too short or empty. Provide a meaningful value.", path)
-	case strings.Contains(lowerMsg, "minitems"):
-		return fmt.Sprintf("The array at '%s' must have at least one item. Add the required elements.", path)
-	case strings.Contains(lowerMsg, "additional"):
-		return fmt.Sprintf("The field at '%s' is not recognized. Remove it or check for typos. Valid fields are listed in the schema.", path)
-	case strings.Contains(lowerMsg, "type"):
-		return fmt.Sprintf("The value at '%s' has the wrong type. Check the schema for the expected type (string, array, object, etc.).", path)
-	case strings.Contains(lowerMsg, "oneof"):
-		return fmt.Sprintf("The value at '%s' must match exactly one of the allowed schemas. Check the spec for valid formats.", path)
+		return fmt.Sprintf("The value at '%s' must match the pattern %s.", at, param("pattern"))
+	case "enum":
+		allowed := param("expected")
+		if s != nil && len(s.Enum) > 0 {
+			allowed = allowedValues(s.Enum)
+		}
+		return fmt.Sprintf("Use one of the allowed values at '%s'. Allowed: %s.", at, allowed)
+	case "const":
+		if s != nil && s.Const != nil && s.Const.IsSet {
+			return fmt.Sprintf("The value at '%s' must be exactly %q.", at, fmt.Sprint(s.Const.Value))
+		}
+		return fmt.Sprintf("The value at '%s' must be exactly the value the schema requires.", at)
+	case "maxLength":
+		return fmt.Sprintf("The value at '%s' has %s characters; shorten it to at most %s.", at, param("length"), param("max_length"))
+	case "minLength":
+		return fmt.Sprintf("The value at '%s' has %s characters; provide a meaningful value of at least %s.", at, param("length"), param("min_length"))
+	case "maximum":
+		return fmt.Sprintf("The value at '%s' is %s; use at most %s.", at, param("value"), param("maximum"))
+	case "minimum":
+		return fmt.Sprintf("The value at '%s' is %s; use at least %s.", at, param("value"), param("minimum"))
+	case "minItems":
+		return fmt.Sprintf("The array at '%s' has %s item(s); add items until it has at least %s.", at, param("count"), param("min_items"))
+	case "maxItems":
+		return fmt.Sprintf("The array at '%s' has %s items; remove items until it has at most %s.", at, param("count"), param("max_items"))
+	case "uniqueItems":
+		return fmt.Sprintf("The array at '%s' repeats items (indexes %s). Remove the duplicates.", at, param("duplicates"))
+	case "additionalProperties":
+		field := param("property")
+		if _, ok := e.Params["properties"]; ok {
+			field = strings.ReplaceAll(param("properties"), "'", "")
+		}
+		msg := fmt.Sprintf("The field(s) %s at '%s' are not recognized. Remove them or check for typos.", field, at)
+		if s != nil && s.Properties != nil {
+			msg += " Allowed: " + strings.Join(slices.Sorted(maps.Keys(*s.Properties)), ", ") + "."
+		}
+		return msg
+	case "schema":
+		// A false subschema: the field itself is not allowed.
+		return fmt.Sprintf("The field at '%s' is not recognized. Remove it or check for typos.", at)
+	case "type":
+		return fmt.Sprintf("The value at '%s' is %s; use %s.", at, param("received"), param("expected"))
+	case "oneOf", "anyOf":
+		list := []*jsonschema.Schema(nil)
+		if s != nil {
+			list = s.OneOf
+			if e.Keyword == "anyOf" {
+				list = s.AnyOf
+			}
+		}
+		if len(list) == 0 {
+			return fmt.Sprintf("The value at '%s' must match exactly one of the allowed shapes. Check the spec for valid formats.", at)
+		}
+		shapes := make([]string, len(list))
+		for i, option := range list {
+			shapes[i] = describeShape(option)
+		}
+		return fmt.Sprintf("The value at '%s' must match exactly one of the allowed shapes. Allowed: %s.", at, strings.Join(shapes, " | "))
 	default:
 		return ""
 	}
@@ -0,0 +1,193 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	schemaTaskPath   = regexp.MustCompile(`^/tasks/(\d+)(?:/|$)`)
+	semanticTaskPath = regexp.MustCompile(`^tasks\[(\d+)\]`)
+	taskRef          = regexp.MustCompile(`\btasks\[\d+\]`)
+)
+
+// validateScoped validates only the tasks named in opts.Tasks. The graph
+// envelope is kept, tasks are decoded individually so unrelated broken
+// tasks do not block the run, and findings are reported against the
+// original task indices. source is the document as written, before its
+// task templates were expanded into data.
+func validateScoped(source, data []byte, sv *SchemaValidator, opts Options, result *ValidationResult) (*ValidationResult, error) {
+	result.Partial = true
+
+	// taskGraphFields, not TaskGraph: the model's UnmarshalJSON would
+	// take over and decode the tasks itself.
+	var envelope struct {
+		taskGraphFields
+		Tasks []json.RawMessage `json:"tasks"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		// Let the schema validator describe the malformed document.
+		sv.ValidateTaskGraph(data, result)
+		if result.Valid {
+			return nil, fmt.Errorf("parsing task graph: %w", err)
+		}
+		return result, nil
+	}
+
+	index := make(map[string]int, len(envelope.Tasks))
+	ids := make([]string, len(envelope.Tasks))
+	for i, raw := range envelope.Tasks {
+		var head struct {
+			TaskID string `json:"task_id"`
+		}
+		_ = json.Unmarshal(raw, &head)
+		ids[i] = head.TaskID
+		if _, exists := index[head.TaskID]; !exists && head.TaskID != "" {
+			index[head.TaskID] = i
+		}
+	}
+
+	inScope := make(map[int]bool)
+	for _, id := range opts.Tasks {
+		i, ok := index[id]
+		if !ok {
+			result.AddError(ValidationError{
+				Rule:       "SCOPE",
+				Severity:   SeverityError,
+				Path:       "tasks",
+				Message:    fmt.Sprintf("Task '%s' was requested for validation, but no task with that task_id exists in the graph.", id),
+				Suggestion: "Check the task_id spelling, or drop it from the task list.",
+				Context:    id,
+			})
+			continue
+		}
+		inScope[i] = true
+	}
+
+	// Tier 1: schema findings are kept for the envelope and scoped tasks.
+	schemaResult := &ValidationResult{Valid: true}
+	if opts.Tiers != SemanticTier {
+		timeRule(opts.RuleDone, "SCHEMA", schemaResult, func() { sv.ValidateTaskGraph(data, schemaResult) })
+		timeRule(opts.RuleDone, "DUPKEY", schemaResult, func() { checkDuplicateKeys(source, schemaResult) })
+	}
+	schemaValid := true
+	for _, e := range schemaResult.Errors {
+		m := schemaTaskPath.FindStringSubmatch(e.Path)
+		if m == nil {
+			m = semanticTaskPath.FindStringSubmatch(e.Path)
+		}
+		if m != nil {
+			i, _ := strconv.Atoi(m[1])
+			if !inScope[i] {
+				continue
+			}
+		}
+		if e.Severity == SeverityError {
+			schemaValid = false
+		}
+		result.AddError(e)
+	}
+	if opts.Tiers == SchemaTier {
+		result.Stats.TotalTasks = len(inScope)
+		return result, nil
+	}
+	// As in a full run, schema errors leave the semantic findings provisional.
+	provisional := !schemaValid
+
+	// Scoped tasks plus their direct dependencies form the subgraph.
+	graph := TaskGraph(envelope.taskGraphFields)
+	graph.Tasks = nil
+	var origIndex []int
+	include := make(map[int]bool)
+	for i := range inScope {
+		include[i] = true
+		var task TaskNode
+		if err := json.Unmarshal(envelope.Tasks[i], &task); err != nil {
+			if provisional {
+				result.Stats.TotalTasks = len(inScope)
+				return result, nil
+			}
+			return nil, fmt.Errorf("parsing task '%s': %w", ids[i], err)
+		}
+		deps, _, _ := task.ParseDependsOn()
+		for _, d := range deps {
+			if j, ok := index[d]; ok {
+				include[j] = true
+			}
+		}
+	}
+	order := make([]int, 0, len(include))
+	for i := range include {
+		order = append(order, i)
+	}
+	sort.Ints(order)
+	for _, i := range order {
+		var task TaskNode
+		if err := json.Unmarshal(envelope.Tasks[i], &task); err != nil {
+			continue // A broken dependency shows up as a dangling reference.
+		}
+		graph.Tasks = append(graph.Tasks, task)
+		origIndex = append(origIndex, i)
+	}
+
+	// Milestones keep their positions; task_ids outside the subgraph are
+	// dropped so they are not reported as missing.
+	graph.Milestones = make([]Milestone, len(envelope.Milestones))
+	for i, m := range envelope.Milestones {
+		m.TaskIDs = nil
+		for _, id := range envelope.Milestones[i].TaskIDs {
+			if j, ok := index[id]; ok && include[j] {
+				m.TaskIDs = append(m.TaskIDs, id)
+			}
+		}
+		graph.Milestones[i] = m
+	}
+
+	// Tier 2: keep findings for scoped tasks and graph-level findings.
+	remapRef := func(ref string) string {
+		k, _ := strconv.Atoi(ref[len("tasks[") : len(ref)-1])
+		if k >= len(origIndex) {
+			return ref
+		}
+		return fmt.Sprintf("tasks[%d]", origIndex[k])
+	}
+	semResult := &ValidationResult{Valid: true}
+	NewSemanticValidatorWithOptions(opts).ValidateTaskGraph(&graph, semResult)
+	for _, e := range semResult.Errors {
+		if m := semanticTaskPath.FindStringSubmatchIndex(e.Path); m != nil {
+			k, _ := strconv.Atoi(e.Path[m[2]:m[3]])
+			orig := origIndex[k]
+			if !inScope[orig] {
+				continue
+			}
+			e.Path = fmt.Sprintf("tasks[%d]%s", orig, e.Path[m[1]:])
+		}
+		e.Message = taskRef.ReplaceAllStringFunc(e.Message, remapRef)
+		e.Suggestion = taskRef.ReplaceAllStringFunc(e.Suggestion, remapRef)
+		e.Provisional = provisional
+		result.AddError(e)
+	}
+
+	scoped := make([]string, 0, len(inScope))
+	for _, i := range order {
+		if inScope[i] {
+			scoped = append(scoped, ids[i])
+		}
+	}
+	result.AddError(ValidationError{
+		Rule:     "SCOPE",
+		Severity: SeverityInfo,
+		Path:     "tasks",
+		Message: fmt.Sprintf(
+			"Partial validation: checked %d of %d task(s) [%s] with %d direct dependency task(s) as context. Graph-wide checks (V2 uniqueness, V5 cycles, milestones, V13 graph size, V14 hidden links) only saw this subgraph.",
+			len(inScope), len(envelope.Tasks), strings.Join(scoped, ", "), len(graph.Tasks)-len(inScope),
+		),
+		Suggestion: "Run without a task filter before handing the graph off.",
+	})
+	result.Stats.TotalTasks = len(inScope)
+	return result, nil
+}
@@ -0,0 +1,89 @@
+package validator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// SealAlgorithm is the digest algorithm ApplySeal uses and checkSeal accepts.
+const SealAlgorithm = "sha256"
+
+// GraphSeal records a digest of a graph's canonical form, so consumers can
+// detect edits made after the graph was validated or approved.
+type GraphSeal struct {
+	Algorithm string `json:"algorithm"`
+	Digest    string `json:"digest"`
+}
+
+// ContentDigest returns the hex SHA-256 of the graph's canonical form: the
+// compact JSON encoding of the graph with its seal removed. Field order is
+// fixed by the model, object keys are sorted, and whitespace is ignored, so
+// reformatting a file does not change its digest.
+func (g *TaskGraph) ContentDigest() (string, error) {
+	unsealed := *g
+	unsealed.Seal = nil
+	data, err := json.Marshal(&unsealed)
+	if err != nil {
+		return "", fmt.Errorf("encoding canonical form: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// ApplySeal sets the graph's seal to its current content digest.
+func (g *TaskGraph) ApplySeal() error {
+	digest, err := g.ContentDigest()
+	if err != nil {
+		return err
+	}
+	g.Seal = &GraphSeal{Algorithm: SealAlgorithm, Digest: digest}
+	return nil
+}
+
+// checkSeal verifies the graph against its seal (SEAL). Only run when
+// Options.VerifySeal is set; an unsealed graph is then an error too.
+func checkSeal(graph *TaskGraph, result *ValidationResult) {
+	if graph.Seal == nil {
+		result.AddError(ValidationError{
+			Rule:       "SEAL",
+			Severity:   SeverityError,
+			Path:       "seal",
+			Message:    "The graph has no seal, so it cannot be verified.",
+			Suggestion: "Seal the approved graph with 'taskval seal <file.json>'.",
+		})
+		return
+	}
+	if graph.Seal.Algorithm != SealAlgorithm {
+		result.AddError(ValidationError{
+			Rule:       "SEAL",
+			Severity:   SeverityError,
+			Path:       "seal.algorithm",
+			Message:    fmt.Sprintf("Seal algorithm '%s' is not supported; expected '%s'.", graph.Seal.Algorithm, SealAlgorithm),
+			Suggestion: "Re-seal the graph with 'taskval seal'.",
+			Context:    graph.Seal.Algorithm,
+		})
+		return
+	}
+	digest, err := graph.ContentDigest()
+	if err != nil {
+		result.AddError(ValidationError{
+			Rule:     "SEAL",
+			Severity: SeverityError,
+			Path:     "seal",
+			Message:  fmt.Sprintf("Could not compute the graph digest: %s.", err),
+		})
+		return
+	}
+	if digest != graph.Seal.Digest {
+		result.AddError(ValidationError{
+			Rule:       "SEAL",
+			Severity:   SeverityError,
+			Path:       "seal.digest",
+			Message:    "The graph content does not match its seal; it was modified after it was sealed.",
+			Suggestion: "Review the changes. If they are approved, re-seal the graph with 'taskval seal'.",
+			Context:    fmt.Sprintf("sealed %s, content %s", graph.Seal.Digest, digest),
+		})
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"slices"
 	"strings"
+	"time"
 )
 
 // Forbidden words in GOAL field per spec Section 3.1.
@@ -13,6 +15,12 @@ var goalForbiddenWords = []string{"try", "explore", "investigate", "look into"}
 // goalForbiddenPattern matches forbidden words as whole words (case-insensitive).
 var goalForbiddenPatterns []*regexp.Regexp
 
+// goalLiteralPattern matches the parts of a goal that name things rather
+// than describe work: `code spans`, "double-quoted" text, and
+// 'single-quoted' identifiers. V6 ignores forbidden words inside them, so
+// a goal may mention a function called `Try` or an "Explore" menu.
+var goalLiteralPattern = regexp.MustCompile("`[^`]*`" + `|"[^"]*"|\B'[A-Za-z_][\w.]*'\B`)
+
 // Weasel words/phrases that signal deferred or vague scope (V11).
 var weaselWords = []string{
 	"v1",
@@ -53,13 +61,20 @@ func init() {
 
 // SemanticValidator performs Tier 2 validation: checks that require
 // cross-node analysis or semantic understanding beyond JSON Schema.
-type SemanticValidator struct{}
+type SemanticValidator struct {
+	opts Options
+}
 
 // NewSemanticValidator creates a new semantic validator.
 func NewSemanticValidator() *SemanticValidator {
 	return &SemanticValidator{}
 }
 
+// NewSemanticValidatorWithOptions creates a semantic validator that applies opts.
+func NewSemanticValidatorWithOptions(opts Options) *SemanticValidator {
+	return &SemanticValidator{opts: opts}
+}
+
 // ValidateTaskGraph performs all semantic checks on a parsed task graph.
 func (sv *SemanticValidator) ValidateTaskGraph(graph *TaskGraph, result *ValidationResult) {
 	result.Stats.TotalTasks = len(graph.Tasks)
@@ -70,41 +85,283 @@ func (sv *SemanticValidator) ValidateTaskGraph(graph *TaskGraph, result *Validat
 		taskIndex[t.TaskID] = i
 	}
 
+	// META: Provenance metadata.
+	sv.timed("META", result, func() { sv.checkProvenance(graph, result) })
+
 	// V2: Unique TASK_IDs.
-	sv.checkUniqueTaskIDs(graph, result)
+	sv.timed("V2", result, func() { sv.checkUniqueTaskIDs(graph, result) })
 
 	// V4: DEPENDS_ON reference integrity.
-	sv.checkDependencyReferences(graph, taskIndex, result)
+	sv.timed("V4", result, func() { sv.checkDependencyReferences(graph, taskIndex, sv.externalTasks(graph), result) })
 
 	// V5: DAG acyclicity.
-	sv.checkDAGAcyclicity(graph, taskIndex, result)
+	sv.timed("V5", result, func() { sv.checkDAGAcyclicity(graph, taskIndex, result) })
+
+	// FAN: no task joins or feeds too many others directly.
+	sv.timed("FAN", result, func() { sv.checkFan(graph, taskIndex, result) })
+
+	// DEPTH: dependency chains stay short enough to parallelize.
+	sv.timed("DEPTH", result, func() { sv.checkDepth(graph, taskIndex, result) })
 
 	// V6: GOAL quality.
-	sv.checkGoalQuality(graph, result)
+	sv.timed("V6", result, func() { sv.checkGoalQuality(graph, result) })
 
 	// V7: ACCEPTANCE quality.
-	sv.checkAcceptanceQuality(graph, result)
+	sv.timed("V7", result, func() { sv.checkAcceptanceQuality(graph, result) })
 
 	// V9: Contextual fields are present or N/A.
-	sv.checkContextualFields(graph, result)
+	sv.timed("V9", result, func() { sv.checkContextualFields(graph, result) })
 
 	// V10: FILES_SCOPE non-empty for implementation tasks.
-	sv.checkFilesScope(graph, result)
+	sv.timed("V10", result, func() { sv.checkFilesScope(graph, result) })
+
+	// PATHS: files_scope entries use forward slashes.
+	sv.timed("PATHS", result, func() { sv.checkScopePaths(graph, result) })
 
 	// Milestone checks.
-	sv.checkMilestones(graph, taskIndex, result)
+	sv.timed("MILESTONE", result, func() { sv.checkMilestones(graph, taskIndex, result) })
+
+	// DATES: due / not_before parse and respect dependency order.
+	sv.timed("DATES", result, func() { sv.checkDates(graph, result) })
+
+	// VERIFY: verification entries point at real criteria.
+	sv.timed("VERIFY", result, func() { sv.checkVerification(graph, result) })
+
+	// RISK: high-risk tasks must say how the risk is mitigated.
+	sv.timed("RISK", result, func() { sv.checkRisk(graph, result) })
+
+	// SPIKE: spikes are timeboxed and end in a decision.
+	sv.timed("SPIKE", result, func() { sv.checkSpikes(graph, result) })
+
+	// ESTIMATE: urgent tasks carry an estimate (opt-in).
+	sv.timed("ESTIMATE", result, func() { sv.checkEstimates(graph, result) })
+
+	// STYLE: INFO-level writing guidance (opt-in).
+	sv.timed("STYLE", result, func() { sv.checkStyle(graph, result) })
+
+	// NONGOALS: large tasks fence their scope (opt-in).
+	sv.timed("NONGOALS", result, func() { sv.checkNonGoals(graph, result) })
+
+	// OUTPUTS: acceptance criteria assert on the declared outputs.
+	sv.timed("OUTPUTS", result, func() { sv.checkOutputCoverage(graph, result) })
 
 	// V11: Weasel words.
-	sv.checkWeaselWords(graph, result)
+	sv.timed("V11", result, func() { sv.checkWeaselWords(graph, result) })
 
 	// V12: Cross-task contracts.
-	sv.checkCrossTaskContracts(graph, result)
+	sv.timed("V12", result, func() { sv.checkCrossTaskContracts(graph, result) })
 
 	// V13: Granularity heuristics.
-	sv.checkGranularity(graph, result)
+	sv.timed("V13", result, func() { sv.checkGranularity(graph, result) })
 
 	// V14: Missing dependency links.
-	sv.checkMissingDependencyLinks(graph, taskIndex, result)
+	sv.timed("V14", result, func() { sv.checkMissingDependencyLinks(graph, taskIndex, result) })
+
+	// REPO: files_scope against the checked-out repository (opt-in).
+	sv.timed("REPO", result, func() { sv.checkRepoPaths(graph, result) })
+
+	// OWNERS: files_scope owners from CODEOWNERS (only with Options.CodeOwners).
+	sv.timed("OWNERS", result, func() { sv.checkCodeOwners(graph, result) })
+
+	// IDS: house task_id conventions (only with Options.IDConvention).
+	sv.timed("IDS", result, func() { sv.checkIDConvention(graph, result) })
+
+	// Rules added by library users with RegisterRule.
+	sv.runRegisteredRules(graph, result)
+
+	// Custom rules from the config file.
+	sv.runExternalRules(graph, result)
+}
+
+// timed runs one check and reports it to Options.RuleDone; see timeRule.
+func (sv *SemanticValidator) timed(rule string, result *ValidationResult, check func()) {
+	timeRule(sv.opts.RuleDone, rule, result, check)
+}
+
+// timeRule runs check and, if done is set, reports the findings it added
+// to result and how long it took.
+func timeRule(done func(rule string, findings int, elapsed time.Duration), rule string, result *ValidationResult, check func()) {
+	if done == nil {
+		check()
+		return
+	}
+	start, n := time.Now(), len(result.Errors)
+	check()
+	done(rule, len(result.Errors)-n, time.Since(start))
+}
+
+// checkProvenance ensures generated_at is an RFC 3339 timestamp (META). The
+// schema's date-time format is an annotation only, so it is checked here.
+func (sv *SemanticValidator) checkProvenance(graph *TaskGraph, result *ValidationResult) {
+	if graph.GeneratedAt == "" {
+		return
+	}
+	if _, err := time.Parse(time.RFC3339, graph.GeneratedAt); err != nil {
+		result.AddError(ValidationError{
+			Rule:       "META",
+			Severity:   SeverityError,
+			Path:       "generated_at",
+			Message:    fmt.Sprintf("generated_at '%s' is not an RFC 3339 timestamp.", graph.GeneratedAt),
+			Suggestion: "Use a full timestamp with a zone offset, e.g. '2026-01-31T14:05:00Z'.",
+			Context:    graph.GeneratedAt,
+		})
+	}
+}
+
+// checkVerification checks that verification entries reference existing
+// acceptance criteria and say how to check them, and with
+// RequireVerification, that every criterion is covered (VERIFY).
+func (sv *SemanticValidator) checkVerification(graph *TaskGraph, result *ValidationResult) {
+	for i, t := range graph.Tasks {
+		covered := make([]bool, len(t.Acceptance))
+		for j, v := range t.Verification {
+			path := fmt.Sprintf("tasks[%d].verification[%d]", i, j)
+			if v.Criterion < 0 || v.Criterion >= len(t.Acceptance) {
+				result.AddError(ValidationError{
+					Rule:       "VERIFY",
+					Severity:   SeverityError,
+					Path:       path + ".criterion",
+					Message:    fmt.Sprintf("Task '%s' verifies acceptance criterion %d, but it has only %d.", t.TaskID, v.Criterion, len(t.Acceptance)),
+					Suggestion: "Criteria are numbered from 0 in the order they appear in acceptance.",
+				})
+			} else {
+				covered[v.Criterion] = true
+			}
+			if v.Command == "" && v.TestFile == "" {
+				result.AddError(ValidationError{
+					Rule:       "VERIFY",
+					Severity:   SeverityError,
+					Path:       path,
+					Message:    fmt.Sprintf("Verification entry for task '%s' gives neither a command nor a test_file.", t.TaskID),
+					Suggestion: "Add the command that checks the criterion, the test file that covers it, or both.",
+				})
+			}
+		}
+		if !sv.opts.RequireVerification {
+			continue
+		}
+		for k, ok := range covered {
+			if ok {
+				continue
+			}
+			result.AddError(ValidationError{
+				Rule:       "VERIFY",
+				Severity:   SeverityError,
+				Path:       fmt.Sprintf("tasks[%d].acceptance[%d]", i, k),
+				Message:    fmt.Sprintf("Acceptance criterion %d of task '%s' has no verification.", k, t.TaskID),
+				Suggestion: fmt.Sprintf("Add {\"criterion\": %d, \"command\": \"...\"} to verification.", k),
+				Context:    t.Acceptance[k],
+			})
+		}
+	}
+}
+
+// checkEstimates warns when a critical or high priority task has no
+// estimate (ESTIMATE). schedule and bd count such a task as zero work, so
+// the plan looks shorter than it is. The rule is opt-in: it runs only when
+// Options.Rules enables it.
+func (sv *SemanticValidator) checkEstimates(graph *TaskGraph, result *ValidationResult) {
+	if !sv.opts.Rules.enables("ESTIMATE") {
+		return
+	}
+	for i, t := range graph.Tasks {
+		if !t.IsUrgent() || (t.Estimate != "" && t.Estimate != "unknown") {
+			continue
+		}
+		result.AddError(ValidationError{
+			Rule:       "ESTIMATE",
+			Severity:   SeverityWarning,
+			Path:       fmt.Sprintf("tasks[%d].estimate", i),
+			Message:    fmt.Sprintf("Task '%s' has priority '%s' but no estimate; schedules and bd count it as zero work.", t.TaskID, t.Priority),
+			Suggestion: "Set estimate to a size (trivial, small, medium, large) or a number of minutes.",
+			Context:    string(t.Estimate),
+		})
+	}
+}
+
+// checkNonGoals warns when a large task, by estimate or by its number of
+// acceptance criteria (limit non_goals_acceptance), declares no non_goals
+// (NONGOALS). Scope creep is how big tasks fail. The rule is opt-in: it
+// runs only when Options.Rules enables it.
+func (sv *SemanticValidator) checkNonGoals(graph *TaskGraph, result *ValidationResult) {
+	if !sv.opts.Rules.enables("NONGOALS") {
+		return
+	}
+	maxAcceptance := sv.opts.Rules.limit("non_goals_acceptance")
+	for i, t := range graph.Tasks {
+		if len(t.NonGoals) > 0 {
+			continue
+		}
+		var why string
+		switch {
+		case isLargeEstimate(t.Estimate):
+			why = fmt.Sprintf("estimate '%s'", t.Estimate)
+		case len(t.Acceptance) >= maxAcceptance:
+			why = fmt.Sprintf("%d acceptance criteria", len(t.Acceptance))
+		default:
+			continue
+		}
+		result.AddError(ValidationError{
+			Rule:       "NONGOALS",
+			Severity:   SeverityWarning,
+			Path:       fmt.Sprintf("tasks[%d].non_goals", i),
+			Message:    fmt.Sprintf("Task '%s' is large (%s) but declares no non_goals.", t.TaskID, why),
+			Suggestion: "List what the task deliberately leaves out, e.g. \"No retry logic; handled by retry-middleware.\" An agent working a big task otherwise drifts into neighbouring work.",
+		})
+	}
+}
+
+// checkOutputCoverage warns on an output that no acceptance criterion
+// mentions by name or by destination (OUTPUTS): the criteria and the
+// interface have drifted apart, or the output is never checked. Text is
+// compared as words, so "ranked_results" matches "returns the ranked
+// results" and "total" matches "CalculateTotal(100) == 90".
+func (sv *SemanticValidator) checkOutputCoverage(graph *TaskGraph, result *ValidationResult) {
+	for i, t := range graph.Tasks {
+		if len(t.Acceptance) == 0 {
+			continue // Reported by the schema.
+		}
+		criteria := make([]string, len(t.Acceptance))
+		for j, a := range t.Acceptance {
+			criteria[j] = " " + normalizeProse(camelWordPattern.ReplaceAllString(a, "$1 $2")) + " "
+		}
+		mentioned := func(phrase string) bool {
+			p := normalizeProse(camelWordPattern.ReplaceAllString(phrase, "$1 $2"))
+			return p != "" && slices.ContainsFunc(criteria, func(c string) bool { return strings.Contains(c, " "+p+" ") })
+		}
+		for j, out := range t.Outputs {
+			if strings.TrimSpace(out.Name) == "" || mentioned(out.Name) || mentioned(out.Destination) {
+				continue
+			}
+			result.AddError(ValidationError{
+				Rule:       "OUTPUTS",
+				Severity:   SeverityWarning,
+				Path:       fmt.Sprintf("tasks[%d].outputs[%d]", i, j),
+				Message:    fmt.Sprintf("Output '%s' of task '%s' is not mentioned in any acceptance criterion.", out.Name, t.TaskID),
+				Suggestion: fmt.Sprintf("Add a criterion that asserts on '%s', e.g. 'Given <input>, %s is <expected value>.' If the task no longer produces it, remove it from outputs.", out.Name, out.Name),
+				Context:    out.Name,
+			})
+		}
+	}
+}
+
+// camelWordPattern finds the boundary inside a camelCase identifier.
+var camelWordPattern = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+
+// checkRisk warns on high-risk tasks without a mitigation (RISK).
+func (sv *SemanticValidator) checkRisk(graph *TaskGraph, result *ValidationResult) {
+	for i, t := range graph.Tasks {
+		if !t.IsHighRisk() || strings.TrimSpace(t.Risk.Mitigation) != "" {
+			continue
+		}
+		result.AddError(ValidationError{
+			Rule:       "RISK",
+			Severity:   SeverityWarning,
+			Path:       fmt.Sprintf("tasks[%d].risk.mitigation", i),
+			Message:    fmt.Sprintf("Task '%s' is high risk but has no mitigation.", t.TaskID),
+			Suggestion: "Describe how the risk is reduced, e.g. a spike first, a feature flag, or a rollback plan.",
+		})
+	}
 }
 
 // checkUniqueTaskIDs ensures no duplicate TASK_IDs exist (V2).
@@ -125,8 +382,35 @@ func (sv *SemanticValidator) checkUniqueTaskIDs(graph *TaskGraph, result *Valida
 	}
 }
 
-// checkDependencyReferences ensures all DEPENDS_ON references resolve (V4).
-func (sv *SemanticValidator) checkDependencyReferences(graph *TaskGraph, taskIndex map[string]int, result *ValidationResult) {
+// externalTasks returns the task_ids that may be referenced without being
+// defined in the graph: the graph's external_tasks plus Options.ExternalTasks.
+func (sv *SemanticValidator) externalTasks(graph *TaskGraph) map[string]bool {
+	external := make(map[string]bool, len(graph.ExternalTasks)+len(sv.opts.ExternalTasks))
+	for _, id := range graph.ExternalTasks {
+		external[id] = true
+	}
+	for _, id := range sv.opts.ExternalTasks {
+		external[id] = true
+	}
+	return external
+}
+
+// checkDependencyReferences ensures all DEPENDS_ON references resolve (V4),
+// either to a task in the graph or to a declared external task.
+func (sv *SemanticValidator) checkDependencyReferences(graph *TaskGraph, taskIndex map[string]int, external map[string]bool, result *ValidationResult) {
+	for i, id := range graph.ExternalTasks {
+		if _, exists := taskIndex[id]; exists {
+			result.AddError(ValidationError{
+				Rule:       "V4",
+				Severity:   SeverityWarning,
+				Path:       fmt.Sprintf("external_tasks[%d]", i),
+				Message:    fmt.Sprintf("'%s' is listed in external_tasks but is also defined in this graph.", id),
+				Suggestion: fmt.Sprintf("Remove '%s' from external_tasks; references to it already resolve.", id),
+				Context:    id,
+			})
+		}
+	}
+
 	for i, t := range graph.Tasks {
 		deps, _, err := t.ParseDependsOn()
 		if err != nil {
@@ -141,7 +425,7 @@ func (sv *SemanticValidator) checkDependencyReferences(graph *TaskGraph, taskInd
 		}
 
 		for _, dep := range deps {
-			if _, exists := taskIndex[dep]; !exists {
+			if _, exists := taskIndex[dep]; !exists && !external[dep] {
 				result.AddError(ValidationError{
 					Rule:     "V4",
 					Severity: SeverityError,
@@ -151,7 +435,7 @@ func (sv *SemanticValidator) checkDependencyReferences(graph *TaskGraph, taskInd
 						t.TaskID, dep,
 					),
 					Suggestion: fmt.Sprintf(
-						"Either add a task with task_id '%s' to the graph, or remove '%s' from the depends_on list of task '%s'.",
+						"Either add a task with task_id '%s' to the graph, list it in external_tasks if it is defined in another graph, or remove '%s' from the depends_on list of task '%s'.",
 						dep, dep, t.TaskID,
 					),
 					Context: dep,
@@ -251,19 +535,34 @@ func (sv *SemanticValidator) checkDAGAcyclicity(graph *TaskGraph, taskIndex map[
 // checkGoalQuality ensures GOAL fields meet spec requirements (V6).
 func (sv *SemanticValidator) checkGoalQuality(graph *TaskGraph, result *ValidationResult) {
 	for i, t := range graph.Tasks {
+		if t.Kind == KindSpike {
+			continue // Exploration is the point; SPIKE checks the timebox and decision.
+		}
+		goal := goalLiteralPattern.ReplaceAllStringFunc(t.Goal, func(m string) string {
+			return strings.Repeat(" ", len(m))
+		})
+		// A task declared as research may name its activity; it still
+		// gets a warning, since the goal should state what it delivers.
+		severity := SeverityError
+		if t.Kind == KindResearch {
+			severity = SeverityWarning
+		}
 		for j, pattern := range goalForbiddenPatterns {
-			if pattern.MatchString(t.Goal) {
+			if slices.ContainsFunc(t.GoalAllow, func(w string) bool { return strings.EqualFold(w, goalForbiddenWords[j]) }) {
+				continue
+			}
+			if pattern.MatchString(goal) {
 				result.AddError(ValidationError{
 					Rule:     "V6",
-					Severity: SeverityError,
+					Severity: severity,
 					Path:     fmt.Sprintf("tasks[%d].goal", i),
 					Message: fmt.Sprintf(
 						"Goal contains the forbidden word/phrase '%s'. Goals must describe testable outcomes, not activities or explorations.",
 						goalForbiddenWords[j],
 					),
 					Suggestion: fmt.Sprintf(
-						"Rewrite the goal as a concrete, testable outcome. Instead of '%s ...', describe what the system does when the task is complete. Example: 'The function returns X when given Y.'",
-						goalForbiddenWords[j],
+						"Rewrite the goal as a concrete, testable outcome. Instead of '%s ...', describe what the system does when the task is complete. Example: 'The function returns X when given Y.' If '%s' names something, such as a function, put it in backticks or list it in goal_allow.",
+						goalForbiddenWords[j], goalForbiddenWords[j],
 					),
 					Context: t.Goal,
 				})
@@ -325,9 +624,15 @@ func (sv *SemanticValidator) checkAcceptanceQuality(graph *TaskGraph, result *Va
 }
 
 // checkContextualFields ensures contextual fields are present or explicitly N/A (V9).
+// Missing fields are warnings in spec 0.1.0 documents and errors from 0.2.0 on.
 func (sv *SemanticValidator) checkContextualFields(graph *TaskGraph, result *ValidationResult) {
 	contextualFields := []string{"depends_on", "constraints", "files_scope"}
 
+	severity, requirement := SeverityWarning, "should be"
+	if graph.Version == SpecVersion020 {
+		severity, requirement = SeverityError, "must be (spec 0.2.0)"
+	}
+
 	for i, t := range graph.Tasks {
 		for _, field := range contextualFields {
 			var raw json.RawMessage
@@ -343,17 +648,36 @@ func (sv *SemanticValidator) checkContextualFields(graph *TaskGraph, result *Val
 			if raw == nil {
 				result.AddError(ValidationError{
 					Rule:     "V9",
-					Severity: SeverityWarning,
+					Severity: severity,
 					Path:     fmt.Sprintf("tasks[%d].%s", i, field),
 					Message: fmt.Sprintf(
-						"Contextual field '%s' is missing from task '%s'. Contextual fields should be explicitly present or set to {\"status\": \"N/A\", \"reason\": \"...\"}.",
-						field, t.TaskID,
+						"Contextual field '%s' is missing from task '%s'. Contextual fields %s explicitly present or set to {\"status\": \"N/A\", \"reason\": \"...\"}.",
+						field, t.TaskID, requirement,
 					),
 					Suggestion: fmt.Sprintf(
 						"Either provide a value for '%s' or explicitly mark it as not applicable: {\"status\": \"N/A\", \"reason\": \"your justification here\"}.",
 						field,
 					),
 				})
+				continue
+			}
+
+			// A present value must parse; depends_on is reported by V4.
+			var err error
+			switch field {
+			case "constraints":
+				_, _, err = t.ParseConstraints()
+			case "files_scope":
+				_, _, err = t.ParseFilesScope()
+			}
+			if err != nil {
+				result.AddError(ValidationError{
+					Rule:       "V9",
+					Severity:   SeverityError,
+					Path:       fmt.Sprintf("tasks[%d].%s", i, field),
+					Message:    err.Error(),
+					Suggestion: fmt.Sprintf("%s must be an array of strings or {\"status\": \"N/A\", \"reason\": \"...\"}.", field),
+				})
 			}
 		}
 	}
@@ -361,23 +685,15 @@ func (sv *SemanticValidator) checkContextualFields(graph *TaskGraph, result *Val
 
 // checkFilesScope warns if FILES_SCOPE is empty for implementation tasks (V10).
 func (sv *SemanticValidator) checkFilesScope(graph *TaskGraph, result *ValidationResult) {
-	// Heuristic: tasks with verbs like "Implement", "Add", "Fix" in task_name
-	// are likely implementation tasks.
-	implVerbs := []string{"implement", "add", "fix", "create", "build", "write"}
-
 	for i, t := range graph.Tasks {
-		nameLower := strings.ToLower(t.TaskName)
-		isImplTask := false
-		for _, verb := range implVerbs {
-			if strings.HasPrefix(nameLower, verb) {
-				isImplTask = true
-				break
-			}
-		}
-
-		if !isImplTask {
+		kind, explicit := t.ClassifyKind()
+		if kind != KindImplementation {
 			continue
 		}
+		why := "its task_name reads like an implementation task"
+		if explicit {
+			why = "kind is 'implementation'"
+		}
 
 		files, na, err := t.ParseFilesScope()
 		if err != nil {
@@ -389,8 +705,8 @@ func (sv *SemanticValidator) checkFilesScope(graph *TaskGraph, result *Validatio
 				Severity: SeverityWarning,
 				Path:     fmt.Sprintf("tasks[%d].files_scope", i),
 				Message: fmt.Sprintf(
-					"Task '%s' appears to be an implementation task (name starts with an implementation verb) but has no files_scope defined.",
-					t.TaskID,
+					"Task '%s' appears to be an implementation task (%s) but has no files_scope defined.",
+					t.TaskID, why,
 				),
 				Suggestion: "Add a files_scope listing the files the agent should create or modify. This prevents unintended changes to other parts of the codebase.",
 			})
@@ -438,6 +754,16 @@ func (sv *SemanticValidator) checkMilestones(graph *TaskGraph, taskIndex map[str
 	// Check milestone dependency references.
 	for i, m := range graph.Milestones {
 		for _, dep := range m.DependsOnMilestones {
+			if dep == m.Name {
+				result.AddError(ValidationError{
+					Rule:       "MILESTONE",
+					Severity:   SeverityError,
+					Path:       fmt.Sprintf("milestones[%d].depends_on_milestones", i),
+					Message:    fmt.Sprintf("Milestone '%s' lists itself in depends_on_milestones.", m.Name),
+					Suggestion: "Remove the milestone's own name from its depends_on_milestones.",
+				})
+				continue
+			}
 			if _, exists := milestoneIndex[dep]; !exists {
 				result.AddError(ValidationError{
 					Rule:     "MILESTONE",
@@ -452,6 +778,116 @@ func (sv *SemanticValidator) checkMilestones(graph *TaskGraph, taskIndex map[str
 			}
 		}
 	}
+
+	sv.checkEmptyMilestones(graph, result)
+	sv.checkMilestoneOrder(graph, milestoneIndex, result)
+}
+
+// checkEmptyMilestones warns about milestones without tasks (MILESTONE):
+// an empty milestones block, which the schema allows, or milestones with
+// no task_ids, which fail the schema and so only reach here with the
+// schema tier skipped or in a provisional run. Either is an authoring
+// mistake; when no milestone lists a task, one warning covers them all.
+func (sv *SemanticValidator) checkEmptyMilestones(graph *TaskGraph, result *ValidationResult) {
+	if len(graph.Milestones) == 0 {
+		result.AddError(ValidationError{
+			Rule:       "MILESTONE",
+			Severity:   SeverityWarning,
+			Path:       "milestones",
+			Message:    "The milestones block is empty, so no task belongs to a milestone.",
+			Suggestion: "Group the tasks into milestones, or remove the empty milestones block.",
+		})
+		return
+	}
+
+	var empty []int
+	for i, m := range graph.Milestones {
+		if len(m.TaskIDs) == 0 {
+			empty = append(empty, i)
+		}
+	}
+	if len(empty) == 0 {
+		return
+	}
+	if len(empty) == len(graph.Milestones) {
+		result.AddError(ValidationError{
+			Rule:       "MILESTONE",
+			Severity:   SeverityWarning,
+			Path:       "milestones",
+			Message:    fmt.Sprintf("None of the %d milestone(s) lists any task.", len(graph.Milestones)),
+			Suggestion: "Fill each milestone's task_ids, or remove the milestones block if the graph has no phases.",
+		})
+		return
+	}
+	for _, i := range empty {
+		m := graph.Milestones[i]
+		result.AddError(ValidationError{
+			Rule:       "MILESTONE",
+			Severity:   SeverityWarning,
+			Path:       fmt.Sprintf("milestones[%d].task_ids", i),
+			Message:    fmt.Sprintf("Milestone '%s' lists no tasks.", m.Name),
+			Suggestion: "Add the tasks that make up the milestone, or remove it (and any depends_on_milestones references to it).",
+		})
+	}
+}
+
+// checkMilestoneOrder reports task dependencies that run against the
+// milestone order (MILESTONE): a task in milestone A depending on a task
+// in milestone B, when B (directly or transitively) depends on A. B cannot
+// finish before A starts if A's work is needed for B.
+func (sv *SemanticValidator) checkMilestoneOrder(graph *TaskGraph, milestoneIndex map[string]int, result *ValidationResult) {
+	// after[i] holds every milestone that milestone i waits for.
+	after := make([]map[int]bool, len(graph.Milestones))
+	var visit func(i int, seen map[int]bool)
+	visit = func(i int, seen map[int]bool) {
+		for _, dep := range graph.Milestones[i].DependsOnMilestones {
+			j, ok := milestoneIndex[dep]
+			if !ok || seen[j] {
+				continue
+			}
+			seen[j] = true
+			visit(j, seen)
+		}
+	}
+	for i := range graph.Milestones {
+		after[i] = map[int]bool{}
+		visit(i, after[i])
+	}
+
+	inMilestone := make(map[string][]int)
+	for i, m := range graph.Milestones {
+		for _, id := range m.TaskIDs {
+			inMilestone[id] = append(inMilestone[id], i)
+		}
+	}
+
+	for i, t := range graph.Tasks {
+		deps, _, err := t.ParseDependsOn()
+		if err != nil {
+			continue // Already reported elsewhere.
+		}
+		for _, dep := range deps {
+			for _, a := range inMilestone[t.TaskID] {
+				for _, b := range inMilestone[dep] {
+					if a == b || !after[b][a] {
+						continue
+					}
+					ma, mb := graph.Milestones[a].Name, graph.Milestones[b].Name
+					result.AddError(ValidationError{
+						Rule:     "MILESTONE",
+						Severity: SeverityError,
+						Path:     fmt.Sprintf("tasks[%d].depends_on", i),
+						Message: fmt.Sprintf(
+							"Task '%s' in milestone '%s' depends on task '%s' in milestone '%s', but milestone '%s' depends on '%s'.",
+							t.TaskID, ma, dep, mb, mb, ma,
+						),
+						Suggestion: fmt.Sprintf("Move '%s' into '%s' or an earlier milestone, move '%s' to a later milestone, or drop the dependency.", dep, ma, t.TaskID),
+						Context:    dep,
+					})
+				}
+			}
+		}
+	}
 }
 
 // checkWeaselWords flags deferral / vague-scope language in goals and acceptance criteria (V11).
@@ -572,22 +1008,35 @@ func typesCompatible(inputType, outputType string) bool {
 	return false
 }
 
+// largeEstimateMinutes is the raw estimate treated like the 'large' bucket:
+// a full working day.
+const largeEstimateMinutes = 480
+
+// isLargeEstimate reports whether an estimate is 'large' or a raw minute
+// count of at least a working day.
+func isLargeEstimate(e Level) bool {
+	if n, ok := e.Number(); ok {
+		return n >= largeEstimateMinutes
+	}
+	return strings.EqualFold(strings.TrimSpace(string(e)), "large")
+}
+
 // checkGranularity applies Nyquist Compliance heuristics for task granularity (V13).
 // Flags potentially over-large tasks, over-large graphs, and overloaded milestones
 // as INFO findings (advisory, not blocking).
 func (sv *SemanticValidator) checkGranularity(graph *TaskGraph, result *ValidationResult) {
 	for i, t := range graph.Tasks {
-		if strings.EqualFold(strings.TrimSpace(t.Estimate), "large") {
+		if isLargeEstimate(t.Estimate) {
 			result.AddError(ValidationError{
 				Rule:     "V13",
 				Severity: SeverityInfo,
 				Path:     fmt.Sprintf("tasks[%d].estimate", i),
 				Message: fmt.Sprintf(
-					"Task '%s' has estimate 'large'. Large tasks tend to violate Nyquist Compliance — they bundle too many concerns to verify atomically.",
-					t.TaskID,
+					"Task '%s' has estimate '%s'. Large tasks tend to violate Nyquist Compliance — they bundle too many concerns to verify atomically.",
+					t.TaskID, t.Estimate,
 				),
 				Suggestion: "Decompose this task into 2-4 smaller tasks, each with its own goal and acceptance criteria.",
-				Context:    t.Estimate,
+				Context:    string(t.Estimate),
 			})
 		}
 	}
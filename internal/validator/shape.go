@@ -0,0 +1,135 @@
+package validator
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// checkFan warns on a task with more direct dependencies than the limit
+// max_depends_on, or more direct dependents than max_dependents (FAN). A
+// very wide node usually means the decomposition is wrong: the work that
+// joins many branches, or that many branches wait on, deserves its own
+// task.
+func (sv *SemanticValidator) checkFan(graph *TaskGraph, taskIndex map[string]int, result *ValidationResult) {
+	maxIn := sv.opts.Rules.limit("max_depends_on")
+	maxOut := sv.opts.Rules.limit("max_dependents")
+
+	dependents := make([][]string, len(graph.Tasks))
+	for i, t := range graph.Tasks {
+		deps, _, err := t.ParseDependsOn()
+		if err != nil {
+			continue // Already reported by V9.
+		}
+		deps = slices.Compact(slices.Sorted(slices.Values(deps)))
+		for _, d := range deps {
+			if j, ok := taskIndex[d]; ok {
+				dependents[j] = append(dependents[j], t.TaskID)
+			}
+		}
+		if len(deps) > maxIn {
+			result.AddError(ValidationError{
+				Rule:       "FAN",
+				Severity:   SeverityWarning,
+				Path:       fmt.Sprintf("tasks[%d].depends_on", i),
+				Message:    fmt.Sprintf("Task '%s' depends directly on %d tasks (more than %d).", t.TaskID, len(deps), maxIn),
+				Suggestion: "Add an intermediate integration task that depends on a related group of these tasks, and depend on it instead, or split this task by the inputs it joins.",
+				Context:    strings.Join(deps, ", "),
+			})
+		}
+	}
+
+	for i, ds := range dependents {
+		if len(ds) <= maxOut {
+			continue
+		}
+		result.AddError(ValidationError{
+			Rule:       "FAN",
+			Severity:   SeverityWarning,
+			Path:       fmt.Sprintf("tasks[%d]", i),
+			Message:    fmt.Sprintf("%d tasks depend directly on task '%s' (more than %d).", len(ds), graph.Tasks[i].TaskID, maxOut),
+			Suggestion: "Add an intermediate integration task that builds on this one for a related group of dependents, or split this task so each dependent waits only on the part it needs.",
+			Context:    strings.Join(ds, ", "),
+		})
+	}
+}
+
+// checkDepth warns when the longest dependency chain has more tasks than
+// the limit max_depth, reporting the chain (DEPTH). A deep chain runs one
+// task at a time, and its links are often sequencing that the work does
+// not need. Graphs with a cycle are left to V5.
+func (sv *SemanticValidator) checkDepth(graph *TaskGraph, taskIndex map[string]int, result *ValidationResult) {
+	maxDepth := sv.opts.Rules.limit("max_depth")
+
+	deps := make([][]int, len(graph.Tasks))
+	for i, t := range graph.Tasks {
+		ids, _, err := t.ParseDependsOn()
+		if err != nil {
+			continue
+		}
+		for _, d := range ids {
+			if j, ok := taskIndex[d]; ok {
+				deps[i] = append(deps[i], j)
+			}
+		}
+	}
+
+	// depth[i] is the number of tasks in the longest chain ending at i;
+	// next[i] is the dependency that chain continues through, or -1.
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make([]int, len(graph.Tasks))
+	depth := make([]int, len(graph.Tasks))
+	next := make([]int, len(graph.Tasks))
+	var visit func(i int) bool
+	visit = func(i int) bool {
+		switch state[i] {
+		case visiting:
+			return false
+		case done:
+			return true
+		}
+		state[i] = visiting
+		depth[i], next[i] = 1, -1
+		for _, j := range deps[i] {
+			if !visit(j) {
+				return false
+			}
+			if depth[j]+1 > depth[i] {
+				depth[i], next[i] = depth[j]+1, j
+			}
+		}
+		state[i] = done
+		return true
+	}
+	deepest := -1
+	for i := range graph.Tasks {
+		if !visit(i) {
+			return // Cycle; reported by V5.
+		}
+		if deepest < 0 || depth[i] > depth[deepest] {
+			deepest = i
+		}
+	}
+	if deepest < 0 || depth[deepest] <= maxDepth {
+		return
+	}
+
+	// Walk back from the deepest task and list the chain first to last.
+	var chain []string
+	for i := deepest; i >= 0; i = next[i] {
+		chain = append(chain, graph.Tasks[i].TaskID)
+	}
+	slices.Reverse(chain)
+	result.AddError(ValidationError{
+		Rule:       "DEPTH",
+		Severity:   SeverityWarning,
+		Path:       "tasks",
+		Message:    fmt.Sprintf("The longest dependency chain has %d tasks (more than %d): %s.", len(chain), maxDepth, strings.Join(chain, " -> ")),
+		Suggestion: "Check each link in the chain: drop depends_on entries that only order the work rather than feed it, so independent tasks can run in parallel.",
+		Context:    strings.Join(chain, " -> "),
+	})
+}
@@ -0,0 +1,67 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// maxAcceptanceLength is the length in characters past which an acceptance
+// criterion likely bundles several checks.
+const maxAcceptanceLength = 200
+
+// passivePattern matches a form of "to be" followed by a past participle,
+// as in "the cache is invalidated" or "errors were logged".
+var passivePattern = regexp.MustCompile(`(?i)\b(is|are|was|were|be|been|being)\s+(\w+ed|built|done|made|kept|sent|set|shown|written|given|taken|run|found|held|known|seen|thrown|chosen|drawn|driven|hidden|broken|spoken|bound)\b`)
+
+// checkStyle gives INFO-level writing guidance (STYLE): passive goals,
+// long acceptance criteria, and notes that repeat the goal. It never
+// changes whether a graph passes. The rule is opt-in: it runs only when
+// Options.Rules enables it.
+func (sv *SemanticValidator) checkStyle(graph *TaskGraph, result *ValidationResult) {
+	if !sv.opts.Rules.enables("STYLE") {
+		return
+	}
+	for i, t := range graph.Tasks {
+		if m := passivePattern.FindString(t.Goal); m != "" {
+			result.AddError(ValidationError{
+				Rule:       "STYLE",
+				Severity:   SeverityInfo,
+				Path:       fmt.Sprintf("tasks[%d].goal", i),
+				Message:    fmt.Sprintf("Goal of task '%s' is in the passive voice ('%s').", t.TaskID, m),
+				Suggestion: "Name what acts: instead of 'The cache is invalidated on write', write 'Store.Put invalidates the cache entry for the key it writes.'",
+				Context:    t.Goal,
+			})
+		}
+		for j, a := range t.Acceptance {
+			if n := len([]rune(a)); n > maxAcceptanceLength {
+				result.AddError(ValidationError{
+					Rule:       "STYLE",
+					Severity:   SeverityInfo,
+					Path:       fmt.Sprintf("tasks[%d].acceptance[%d]", i, j),
+					Message:    fmt.Sprintf("Acceptance criterion of task '%s' is %d characters long (over %d).", t.TaskID, n, maxAcceptanceLength),
+					Suggestion: "Split it into one criterion per observable check.",
+					Context:    a,
+				})
+			}
+		}
+		if goal := normalizeProse(t.Goal); goal != "" && strings.Contains(normalizeProse(t.Notes), goal) {
+			result.AddError(ValidationError{
+				Rule:       "STYLE",
+				Severity:   SeverityInfo,
+				Path:       fmt.Sprintf("tasks[%d].notes", i),
+				Message:    fmt.Sprintf("Notes of task '%s' repeat its goal.", t.TaskID),
+				Suggestion: "Use notes for context the other fields do not hold, such as background or pitfalls, or remove them.",
+				Context:    t.Notes,
+			})
+		}
+	}
+}
+
+// normalizeProse lowercases s and reduces it to its words separated by
+// single spaces, so punctuation and spacing do not hide a repeat.
+func normalizeProse(s string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	}), " ")
+}
@@ -0,0 +1,256 @@
+package validator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// paramRefPattern matches a {{name}} parameter reference in a task template.
+var paramRefPattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// UsesTaskTemplates reports whether the graph in data defines
+// task_templates or has a task instantiating one. Such a graph cannot be
+// passed through the TaskGraph model unexpanded: the template-provided
+// fields would be written out empty and then override the template.
+func UsesTaskTemplates(data []byte) bool {
+	var doc struct {
+		TaskTemplates json.RawMessage              `json:"task_templates"`
+		Tasks         []map[string]json.RawMessage `json:"tasks"`
+	}
+	if json.Unmarshal(data, &doc) != nil {
+		return false
+	}
+	for _, task := range doc.Tasks {
+		if _, ok := task["from_template"]; ok {
+			return true
+		}
+	}
+	return doc.TaskTemplates != nil
+}
+
+// ExpandTaskTemplates returns the graph in data with its task templates
+// expanded, as validation sees it, for commands that read the graph
+// without validating it. The error describes the first TEMPLATE finding.
+func ExpandTaskTemplates(data []byte) ([]byte, error) {
+	result := &ValidationResult{Valid: true}
+	expanded := expandTaskTemplates(data, result)
+	if len(result.Errors) > 0 {
+		e := result.Errors[0]
+		return nil, fmt.Errorf("%s: %s", e.Path, e.Message)
+	}
+	return expanded, nil
+}
+
+// expandTaskTemplates returns data with the graph's task_templates section
+// removed and each task naming one in from_template replaced by that
+// template's body, with {{name}} references filled from the task's params
+// and the task's own fields layered on top. Documents that use neither
+// key, or that do not decode, are returned unchanged. Problems are
+// reported as TEMPLATE errors; the task then keeps only its own fields.
+func expandTaskTemplates(data []byte, result *ValidationResult) []byte {
+	if !bytes.Contains(data, []byte(`"task_templates"`)) && !bytes.Contains(data, []byte(`"from_template"`)) {
+		return data
+	}
+	var doc map[string]json.RawMessage
+	if json.Unmarshal(data, &doc) != nil {
+		return data
+	}
+	var tasks []json.RawMessage
+	if json.Unmarshal(doc["tasks"], &tasks) != nil {
+		return data
+	}
+
+	var templates map[string]map[string]any
+	if raw, ok := doc["task_templates"]; ok {
+		if templates, ok = taskTemplates(raw); !ok {
+			result.AddError(ValidationError{
+				Rule:       "TEMPLATE",
+				Severity:   SeverityError,
+				Path:       "task_templates",
+				Message:    "task_templates must be an object mapping each template name to a task body object.",
+				Suggestion: `Write it as {"name": {"goal": "...", ...}}.`,
+			})
+		}
+		delete(doc, "task_templates")
+	}
+
+	for i, raw := range tasks {
+		v, _ := decodeGeneric(raw)
+		task, ok := v.(map[string]any)
+		if !ok {
+			continue
+		}
+		name, ok := task["from_template"]
+		if !ok {
+			continue
+		}
+		params := task["params"]
+		delete(task, "from_template")
+		delete(task, "params")
+		if body, ok := instantiateTaskTemplate(templates, name, params, fmt.Sprintf("tasks[%d]", i), result); ok {
+			for key, value := range task {
+				body[key] = value
+			}
+			task = body
+		}
+		tasks[i], _ = json.Marshal(task)
+	}
+	doc["tasks"], _ = json.Marshal(tasks)
+	expanded, _ := json.Marshal(doc)
+	return expanded
+}
+
+// instantiateTaskTemplate returns a copy of the named template with its
+// parameter references replaced, or reports why it cannot at path.
+func instantiateTaskTemplate(templates map[string]map[string]any, name, params any, path string, result *ValidationResult) (map[string]any, bool) {
+	s, isString := name.(string)
+	tmpl, ok := templates[s]
+	if !isString || !ok {
+		known := slices.Sorted(maps.Keys(templates))
+		suggestion := "Define the template under task_templates, or fix the name."
+		if len(known) > 0 {
+			suggestion = "Use one of the defined templates: " + strings.Join(known, ", ") + "."
+		}
+		result.AddError(ValidationError{
+			Rule:       "TEMPLATE",
+			Severity:   SeverityError,
+			Path:       path + ".from_template",
+			Message:    fmt.Sprintf("Task template %s is not defined in task_templates.", templateName(name)),
+			Suggestion: suggestion,
+		})
+		return nil, false
+	}
+
+	values := make(map[string]string)
+	if params != nil {
+		object, ok := params.(map[string]any)
+		if !ok {
+			result.AddError(ValidationError{
+				Rule:       "TEMPLATE",
+				Severity:   SeverityError,
+				Path:       path + ".params",
+				Message:    "params must be an object mapping parameter names to values.",
+				Suggestion: `Write it as {"resource": "users"}.`,
+			})
+			return nil, false
+		}
+		for _, key := range slices.Sorted(maps.Keys(object)) {
+			switch value := object[key]; value.(type) {
+			case string, json.Number, bool:
+				values[key] = fmt.Sprint(value)
+			default:
+				result.AddError(ValidationError{
+					Rule:       "TEMPLATE",
+					Severity:   SeverityError,
+					Path:       path + ".params." + key,
+					Message:    fmt.Sprintf("Parameter '%s' must be a string, number, or boolean; it is substituted into text.", key),
+					Suggestion: "Pass the value as a string.",
+				})
+				return nil, false
+			}
+		}
+	}
+
+	used := make(map[string]bool)
+	body := substituteParams(tmpl, values, used).(map[string]any)
+	var missing, unused []string
+	for key := range used {
+		if _, ok := values[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	for key := range values {
+		if !used[key] {
+			unused = append(unused, key)
+		}
+	}
+	slices.Sort(missing)
+	slices.Sort(unused)
+	if len(missing) > 0 {
+		result.AddError(ValidationError{
+			Rule:       "TEMPLATE",
+			Severity:   SeverityError,
+			Path:       path + ".params",
+			Message:    fmt.Sprintf("Task template '%s' needs params %s, which the task does not give.", s, strings.Join(missing, ", ")),
+			Suggestion: "Add the missing params to the task.",
+		})
+		return nil, false
+	}
+	if len(unused) > 0 {
+		result.AddError(ValidationError{
+			Rule:       "TEMPLATE",
+			Severity:   SeverityError,
+			Path:       path + ".params",
+			Message:    fmt.Sprintf("Task template '%s' has no {{%s}} reference, so these params would be ignored.", s, strings.Join(unused, "}}, {{")),
+			Suggestion: "Remove the params, or check their spelling against the template.",
+		})
+		return nil, false
+	}
+	return body, true
+}
+
+// substituteParams returns a copy of v with the {{name}} references in its
+// strings and object keys replaced from values. Every name referenced is
+// recorded in used, whether or not values has it.
+func substituteParams(v any, values map[string]string, used map[string]bool) any {
+	expand := func(s string) string {
+		return paramRefPattern.ReplaceAllStringFunc(s, func(ref string) string {
+			name := paramRefPattern.FindStringSubmatch(ref)[1]
+			used[name] = true
+			if value, ok := values[name]; ok {
+				return value
+			}
+			return ref
+		})
+	}
+	switch v := v.(type) {
+	case string:
+		return expand(v)
+	case []any:
+		out := make([]any, len(v))
+		for i := range v {
+			out[i] = substituteParams(v[i], values, used)
+		}
+		return out
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, value := range v {
+			out[expand(key)] = substituteParams(value, values, used)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// taskTemplates decodes the task_templates section, reporting false when
+// it is not an object of objects.
+func taskTemplates(raw json.RawMessage) (map[string]map[string]any, bool) {
+	v, _ := decodeGeneric(raw)
+	object, ok := v.(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	templates := make(map[string]map[string]any, len(object))
+	for name, body := range object {
+		if templates[name], ok = body.(map[string]any); !ok {
+			return nil, false
+		}
+	}
+	return templates, true
+}
+
+// templateName renders a from_template value for a message: quoted when
+// it is a string, as JSON otherwise.
+func templateName(v any) string {
+	if s, ok := v.(string); ok {
+		return "'" + s + "'"
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
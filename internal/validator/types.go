@@ -2,7 +2,12 @@
 // for task nodes and task graphs conforming to the Structured Task Template Spec.
 package validator
 
-import "fmt"
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
 
 // Severity classifies how critical a validation finding is.
 type Severity string
@@ -33,11 +38,19 @@ type ValidationError struct {
 
 	// Context provides the actual value that caused the error, if applicable.
 	Context string `json:"context,omitempty"`
+
+	// Provisional marks a semantic finding made on a document that failed
+	// schema validation. It may change or go away once the schema errors
+	// are fixed.
+	Provisional bool `json:"provisional,omitempty"`
 }
 
 // Error implements the error interface.
 func (ve ValidationError) Error() string {
 	s := fmt.Sprintf("[%s] %s at '%s': %s", ve.Severity, ve.Rule, ve.Path, ve.Message)
+	if ve.Provisional {
+		s = fmt.Sprintf("[%s] %s (provisional) at '%s': %s", ve.Severity, ve.Rule, ve.Path, ve.Message)
+	}
 	if ve.Suggestion != "" {
 		s += fmt.Sprintf(" -> Fix: %s", ve.Suggestion)
 	}
@@ -46,10 +59,11 @@ func (ve ValidationError) Error() string {
 
 // ValidationResult aggregates all findings from a validation run.
 type ValidationResult struct {
-	Valid  bool              `json:"valid"`
-	Errors []ValidationError `json:"errors,omitempty"`
-	Stats  ValidationStats   `json:"stats"`
-	Graph  *TaskGraph        `json:"-"` // Parsed graph, not included in JSON output
+	Valid   bool              `json:"valid"`
+	Partial bool              `json:"partial,omitempty"` // Only a subset of tasks was validated (Options.Tasks)
+	Errors  []ValidationError `json:"errors,omitempty"`
+	Stats   ValidationStats   `json:"stats"`
+	Graph   *TaskGraph        `json:"-"` // Parsed graph, not included in JSON output; nil when Partial, and on failure unless Options.ParseAlways
 }
 
 // ValidationStats provides summary counts.
@@ -73,3 +87,62 @@ func (vr *ValidationResult) AddError(ve ValidationError) {
 		vr.Stats.InfoCount++
 	}
 }
+
+// ForTask returns the findings whose path points into tasks[index].
+func (vr *ValidationResult) ForTask(index int) []ValidationError {
+	prefix := fmt.Sprintf("tasks[%d]", index)
+	var out []ValidationError
+	for _, e := range vr.Errors {
+		if e.Path == prefix || strings.HasPrefix(e.Path, prefix+".") || strings.HasPrefix(e.Path, prefix+"[") {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// FilterBySeverity returns the findings with any of the given severities,
+// in report order.
+func (vr *ValidationResult) FilterBySeverity(severities ...Severity) []ValidationError {
+	var out []ValidationError
+	for _, e := range vr.Errors {
+		if slices.Contains(severities, e.Severity) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// ByTask groups the findings whose path points into a task by the task's
+// index in tasks, as ForTask does for one task. Graph-level findings, such
+// as a cycle reported at "tasks", are left out.
+func (vr *ValidationResult) ByTask() map[int][]ValidationError {
+	out := make(map[int][]ValidationError)
+	for _, e := range vr.Errors {
+		if i, ok := taskIndex(e.Path); ok {
+			out[i] = append(out[i], e)
+		}
+	}
+	return out
+}
+
+// HasRule reports whether any finding comes from rule.
+func (vr *ValidationResult) HasRule(rule string) bool {
+	return slices.ContainsFunc(vr.Errors, func(e ValidationError) bool { return e.Rule == rule })
+}
+
+// taskIndex returns N for a path starting with "tasks[N]".
+func taskIndex(path string) (int, bool) {
+	rest, ok := strings.CutPrefix(path, "tasks[")
+	if !ok {
+		return 0, false
+	}
+	end := strings.IndexByte(rest, ']')
+	if end < 0 {
+		return 0, false
+	}
+	i, err := strconv.Atoi(rest[:end])
+	if err != nil || i < 0 {
+		return 0, false
+	}
+	return i, true
+}
@@ -3,6 +3,7 @@ package validator
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 )
 
 // Mode indicates whether we're validating a single task or a full graph.
@@ -13,52 +14,145 @@ const (
 	ModeTaskGraph
 )
 
+// Tiers selects which validation tiers a run performs.
+type Tiers int
+
+const (
+	// AllTiers runs Tier 1, then Tier 2. If Tier 1 failed, Tier 2 still
+	// runs and its findings are marked provisional.
+	AllTiers Tiers = iota
+
+	// SchemaTier runs only the Tier 1 JSON Schema checks.
+	SchemaTier
+
+	// SemanticTier runs only the Tier 2 checks. The document is assumed
+	// to be schema-valid; if it cannot be decoded, Validate returns an error.
+	SemanticTier
+)
+
+// Options adjusts validation beyond what the document itself declares.
+type Options struct {
+	// ExternalTasks lists task_ids defined outside the document. V4 accepts
+	// depends_on references to them, in addition to the graph's own
+	// external_tasks field.
+	ExternalTasks []string
+
+	// Tasks limits graph validation to the named task_ids. Their direct
+	// dependencies are loaded as context but not reported on, and the
+	// result is marked Partial. Ignored in single task mode.
+	Tasks []string
+
+	// Rules filters and re-grades findings; see Profile for presets.
+	Rules RuleConfig
+
+	// RepoRoot enables the REPO checks, which compare files_scope entries
+	// against the repository checked out at this directory.
+	RepoRoot string
+
+	// CodeOwners enables the OWNERS checks, which report the teams owning
+	// each task's files_scope; see LoadCodeOwners.
+	CodeOwners *CodeOwners
+
+	// Tiers selects the validation tiers to run. The default runs both.
+	Tiers Tiers
+
+	// VerifySeal checks the graph against its seal (SEAL); a graph without
+	// a seal fails. Ignored in single task mode and with Tasks set.
+	VerifySeal bool
+
+	// RequireVerification reports acceptance criteria that no verification
+	// entry covers (VERIFY). Entries are checked for consistency either way.
+	RequireVerification bool
+
+	// CustomRules run after the built-in semantic checks; see LoadConfig.
+	CustomRules []ExternalRule
+
+	// IDConvention enables the IDS checks of task_id naming.
+	IDConvention *IDConvention
+
+	// RuleDone, when set, is called after each check with the rule it
+	// implements (SCHEMA for Tier 1, a custom rule's ID for custom rules),
+	// the number of findings it added before Rules re-grades them, and how
+	// long it ran. It is meant for progress reporting.
+	RuleDone func(rule string, findings int, elapsed time.Duration)
+
+	// ParseAlways sets the result's Graph whenever the document decodes,
+	// even if validation fails (Valid stays false), for tools that want
+	// the structure regardless. Scoped results still have no Graph.
+	ParseAlways bool
+}
+
 // Validate performs full validation (Tier 1 + Tier 2) on input JSON data.
 // Returns a ValidationResult with all findings.
 func Validate(data []byte, mode Mode) (*ValidationResult, error) {
+	return ValidateWithOptions(data, mode, Options{})
+}
+
+// ValidateWithOptions is Validate with caller-supplied options. A document
+// nested deeper than MaxNestingDepth, or followed by more content, gets a
+// single SCHEMA error and is not decoded.
+func ValidateWithOptions(data []byte, mode Mode, opts Options) (*ValidationResult, error) {
 	result := &ValidationResult{Valid: true}
+	if !checkNesting(data, result) || !checkTrailing(data, result) {
+		return result, nil
+	}
+	source := data
+	if mode == ModeTaskGraph {
+		data = expandTaskTemplates(data, result)
+	}
 
-	// Tier 1: JSON Schema validation.
 	sv, err := NewSchemaValidator()
 	if err != nil {
 		return nil, fmt.Errorf("initializing schema validator: %w", err)
 	}
 
+	// When the schema rejects the document but it still decodes, Tier 2
+	// runs anyway and its findings are marked provisional, so one run
+	// reports everything that can be checked.
+	var parsed *TaskGraph
+	provisional := false
 	switch mode {
 	case ModeSingleTask:
-		sv.ValidateTaskNode(data, result)
-
-		// If schema validation passed, proceed to Tier 2.
-		if result.Valid {
-			// Wrap single task in a graph for semantic validation.
-			var task TaskNode
-			if err := json.Unmarshal(data, &task); err != nil {
-				return nil, fmt.Errorf("parsing task node: %w", err)
-			}
-			graph := &TaskGraph{
-				Version: "0.1.0",
-				Tasks:   []TaskNode{task},
-			}
-			sem := NewSemanticValidator()
-			sem.ValidateTaskGraph(graph, result)
+		// Tier 1: JSON Schema validation.
+		if opts.Tiers != SemanticTier {
+			timeRule(opts.RuleDone, "SCHEMA", result, func() { sv.ValidateTaskNode(data, result) })
+			timeRule(opts.RuleDone, "DUPKEY", result, func() { checkDuplicateKeys(data, result) })
+		}
+		// Wrap single task in a graph for semantic validation.
+		var task TaskNode
+		if err := json.Unmarshal(data, &task); err != nil {
 			if result.Valid {
-				result.Graph = graph
+				return nil, fmt.Errorf("parsing task node: %w", err)
 			}
+		} else {
+			parsed = WrapTask(task)
+			provisional = !result.Valid
 		}
 
 	case ModeTaskGraph:
-		sv.ValidateTaskGraph(data, result)
+		if len(opts.Tasks) > 0 {
+			scoped, err := validateScoped(source, data, sv, opts, result)
+			if err != nil {
+				return nil, err
+			}
+			return opts.Rules.Apply(scoped), nil
+		}
 
-		// If schema validation passed, proceed to Tier 2.
-		if result.Valid {
-			var graph TaskGraph
-			if err := json.Unmarshal(data, &graph); err != nil {
+		// Tier 1: JSON Schema validation.
+		if opts.Tiers != SemanticTier {
+			timeRule(opts.RuleDone, "SCHEMA", result, func() { sv.ValidateTaskGraph(data, result) })
+			timeRule(opts.RuleDone, "DUPKEY", result, func() { checkDuplicateKeys(source, result) })
+		}
+		var graph TaskGraph
+		if err := json.Unmarshal(data, &graph); err != nil {
+			if result.Valid {
 				return nil, fmt.Errorf("parsing task graph: %w", err)
 			}
-			sem := NewSemanticValidator()
-			sem.ValidateTaskGraph(&graph, result)
-			if result.Valid {
-				result.Graph = &graph
+		} else {
+			parsed = &graph
+			provisional = !result.Valid
+			if opts.VerifySeal && !provisional {
+				timeRule(opts.RuleDone, "SEAL", result, func() { checkSeal(parsed, result) })
 			}
 		}
 
@@ -66,5 +160,21 @@ func Validate(data []byte, mode Mode) (*ValidationResult, error) {
 		return nil, fmt.Errorf("unknown validation mode: %d", mode)
 	}
 
+	// Tier 2: semantic validation, provisional if Tier 1 failed.
+	if parsed != nil {
+		result.Stats.TotalTasks = len(parsed.Tasks)
+		if opts.Tiers != SchemaTier {
+			start := len(result.Errors)
+			NewSemanticValidatorWithOptions(opts).ValidateTaskGraph(parsed, result)
+			for i := start; i < len(result.Errors); i++ {
+				result.Errors[i].Provisional = provisional
+			}
+		}
+	}
+
+	result = opts.Rules.Apply(result)
+	if result.Valid || opts.ParseAlways {
+		result.Graph = parsed
+	}
 	return result, nil
 }
@@ -3,8 +3,13 @@ package validator
 import (
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 )
 
 func hasFinding(r *ValidationResult, rule string, sev Severity) bool {
@@ -248,6 +253,59 @@ func TestDanglingDependencyReference(t *testing.T) {
 	}
 }
 
+func TestExternalTaskReferences(t *testing.T) {
+	graph := map[string]any{
+		"version":        "0.1.0",
+		"external_tasks": []string{"platform-auth", "task-a"},
+		"tasks": []map[string]any{
+			{
+				"task_id":     "task-a",
+				"task_name":   "Implement task A",
+				"goal":        "Task A produces output X.",
+				"inputs":      []map[string]string{{"name": "in", "type": "string", "constraints": "none", "source": "caller"}},
+				"outputs":     []map[string]string{{"name": "out", "type": "string", "constraints": "none", "destination": "return"}},
+				"acceptance":  []string{"Output X is produced"},
+				"depends_on":  []string{"platform-auth", "billing-api"},
+				"constraints": []string{"No new dependencies"},
+				"files_scope": []string{"a.go"},
+			},
+		},
+	}
+
+	data, err := json.Marshal(graph)
+	if err != nil {
+		t.Fatalf("marshaling: %v", err)
+	}
+
+	// billing-api is neither in the graph nor in external_tasks.
+	result, err := Validate(data, ModeTaskGraph)
+	if err != nil {
+		t.Fatalf("validation error: %v", err)
+	}
+	if result.Valid {
+		t.Error("expected V4 error for billing-api")
+	}
+	for _, e := range result.Errors {
+		if e.Rule == "V4" && e.Severity == SeverityError && e.Context == "platform-auth" {
+			t.Error("platform-auth is listed in external_tasks and should not be flagged")
+		}
+	}
+	if !hasFindingAt(result, "V4", SeverityWarning, "external_tasks[1]") {
+		t.Error("expected warning for external_tasks entry defined in the graph")
+	}
+
+	// Options.ExternalTasks supplies the rest.
+	result, err = ValidateWithOptions(data, ModeTaskGraph, Options{ExternalTasks: []string{"billing-api"}})
+	if err != nil {
+		t.Fatalf("validation error: %v", err)
+	}
+	if !result.Valid {
+		for _, e := range result.Errors {
+			t.Errorf("unexpected finding: %s", e.Error())
+		}
+	}
+}
+
 func TestGraphFieldPopulatedOnSuccess(t *testing.T) {
 	graph := map[string]any{
 		"version": "0.1.0",
@@ -977,3 +1035,2272 @@ func TestContainsWord(t *testing.T) {
 		})
 	}
 }
+
+func TestResolvedTaskAppliesDefaults(t *testing.T) {
+	graph := &TaskGraph{
+		Version: "0.1.0",
+		Defaults: &Defaults{
+			Constraints: []string{"Default constraint"},
+			Acceptance:  []string{"go test ./... passes"},
+			NonGoals:    []string{"Default non-goal"},
+		},
+		Tasks: []TaskNode{
+			{
+				TaskID:      "task-a",
+				Acceptance:  []string{"Task criterion holds"},
+				Constraints: json.RawMessage(`["Own constraint"]`),
+			},
+			{
+				TaskID:      "task-b",
+				Acceptance:  []string{"Task criterion holds"},
+				Constraints: json.RawMessage(`{"status": "N/A", "reason": "nothing extra"}`),
+			},
+		},
+	}
+
+	a, err := graph.ResolvedTask("task-a")
+	if err != nil {
+		t.Fatalf("ResolvedTask error: %v", err)
+	}
+	if strings.Join(a.Acceptance, "|") != "go test ./... passes|Task criterion holds" {
+		t.Errorf("acceptance = %v, want defaults first", a.Acceptance)
+	}
+	if string(a.Constraints) != `["Default constraint","Own constraint"]` {
+		t.Errorf("constraints = %s", a.Constraints)
+	}
+	if len(a.NonGoals) != 1 {
+		t.Errorf("non_goals = %v, want inherited default", a.NonGoals)
+	}
+
+	b, err := graph.ResolvedTask("task-b")
+	if err != nil {
+		t.Fatalf("ResolvedTask error: %v", err)
+	}
+	if string(b.Constraints) != `["Default constraint"]` {
+		t.Errorf("N/A constraints should resolve to defaults only, got %s", b.Constraints)
+	}
+
+	// The graph itself is not modified.
+	if len(graph.Tasks[0].Acceptance) != 1 {
+		t.Error("ResolvedTask must not mutate the graph")
+	}
+
+	if _, err := graph.ResolvedTask("missing"); err == nil {
+		t.Error("expected error for unknown task_id")
+	}
+}
+
+func TestScopedValidation(t *testing.T) {
+	task := func(id, goal string, deps ...string) map[string]any {
+		var dependsOn any = map[string]string{"status": "N/A", "reason": "First task"}
+		if len(deps) > 0 {
+			dependsOn = deps
+		}
+		return map[string]any{
+			"task_id":     id,
+			"task_name":   "Implement " + id,
+			"goal":        goal,
+			"inputs":      []map[string]string{{"name": "in", "type": "string", "constraints": "none", "source": "caller"}},
+			"outputs":     []map[string]string{{"name": "out", "type": "string", "constraints": "none", "destination": "return"}},
+			"acceptance":  []string{"Output X is produced"},
+			"depends_on":  dependsOn,
+			"constraints": []string{"No new dependencies"},
+			"files_scope": []string{id + ".go"},
+		}
+	}
+	broken := task("task-broken", "Broken task has an invalid estimate.")
+	broken["estimate"] = "enormous"
+
+	graph := map[string]any{
+		"version": "0.1.0",
+		"tasks": []map[string]any{
+			broken,
+			task("task-a", "Task A produces output X."),
+			task("task-b", "Try to produce output Y.", "task-a"),
+			task("task-c", "Task C produces output Z.", "task-b", "task-missing"),
+		},
+	}
+	data, err := json.Marshal(graph)
+	if err != nil {
+		t.Fatalf("marshaling: %v", err)
+	}
+
+	result, err := ValidateWithOptions(data, ModeTaskGraph, Options{Tasks: []string{"task-c"}})
+	if err != nil {
+		t.Fatalf("validation error: %v", err)
+	}
+	if !result.Partial || result.Graph != nil {
+		t.Error("scoped result should be Partial with no Graph")
+	}
+	if result.Stats.TotalTasks != 1 {
+		t.Errorf("TotalTasks = %d, want 1", result.Stats.TotalTasks)
+	}
+	// task-c's dangling reference is reported at its original index.
+	if !hasFindingAt(result, "V4", SeverityError, "tasks[3].depends_on") {
+		t.Error("expected V4 for task-c at tasks[3]")
+	}
+	// task-b is context only: its V6 goal error is not reported, nor is the
+	// schema error in the unrelated broken task.
+	if hasFinding(result, "V6", SeverityError) || hasFinding(result, "SCHEMA", SeverityError) {
+		for _, e := range result.Errors {
+			t.Logf("finding: %s", e.Error())
+		}
+		t.Error("findings outside the scope should be dropped")
+	}
+	if !hasFinding(result, "SCOPE", SeverityInfo) {
+		t.Error("expected partial-mode note")
+	}
+
+	result, err = ValidateWithOptions(data, ModeTaskGraph, Options{Tasks: []string{"task-b", "nope"}})
+	if err != nil {
+		t.Fatalf("validation error: %v", err)
+	}
+	if !hasFinding(result, "SCOPE", SeverityError) {
+		t.Error("expected SCOPE error for unknown task_id")
+	}
+	if !hasFindingAt(result, "V6", SeverityError, "tasks[2].goal") {
+		t.Error("expected V6 for task-b at tasks[2]")
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	// A schema-valid task whose goal contains a weasel word (V11 warning)
+	// and whose files_scope uses a backslash (PATHS warning).
+	task := map[string]any{
+		"task_id":     "task-a",
+		"task_name":   "Implement task A",
+		"goal":        "Task A produces a placeholder output X.",
+		"inputs":      []map[string]string{{"name": "in", "type": "string", "constraints": "none", "source": "caller"}},
+		"outputs":     []map[string]string{{"name": "out", "type": "string", "constraints": "none", "destination": "return"}},
+		"acceptance":  []string{"Output X is produced"},
+		"depends_on":  map[string]string{"status": "N/A", "reason": "First task"},
+		"constraints": []string{"No new dependencies"},
+		"files_scope": []string{`pkg\a.go`},
+	}
+	data, err := json.Marshal(task)
+	if err != nil {
+		t.Fatalf("marshaling: %v", err)
+	}
+
+	run := func(profile string) *ValidationResult {
+		t.Helper()
+		rules, err := Profile(profile)
+		if err != nil {
+			t.Fatalf("Profile(%q) error: %v", profile, err)
+		}
+		result, err := ValidateWithOptions(data, ModeSingleTask, Options{Rules: rules})
+		if err != nil {
+			t.Fatalf("validation error: %v", err)
+		}
+		return result
+	}
+
+	if r := run(ProfileStandard); !r.Valid || !hasFinding(r, "V11", SeverityWarning) || !hasFinding(r, "PATHS", SeverityWarning) || r.Graph == nil {
+		t.Error("standard: expected valid result with V11 and PATHS warnings")
+	}
+	if r := run(ProfileMinimal); !r.Valid || hasFinding(r, "V11", SeverityWarning) || r.Stats.WarningCount != 0 {
+		t.Error("minimal: heuristic findings should be dropped")
+	}
+	if r := run(ProfileStrict); r.Valid || !hasFinding(r, "V11", SeverityError) || r.Graph != nil {
+		t.Error("strict: V11 should be promoted to an error")
+	}
+	if _, err := Profile("lenient"); err == nil {
+		t.Error("expected error for unknown profile")
+	}
+
+	// A spike without a timebox fails the SPIKE rule, a heuristic the
+	// minimal profile drops.
+	task["kind"] = "spike"
+	if data, err = json.Marshal(task); err != nil {
+		t.Fatalf("marshaling: %v", err)
+	}
+	if r := run(ProfileStandard); r.Valid || !hasFinding(r, "SPIKE", SeverityError) {
+		t.Error("standard: expected a SPIKE error for a spike without a timebox")
+	}
+	if r := run(ProfileMinimal); !r.Valid || hasFinding(r, "SPIKE", SeverityError) {
+		t.Errorf("minimal: SPIKE should be dropped, got %v", r.Errors)
+	}
+	delete(task, "kind")
+	if data, err = json.Marshal(task); err != nil {
+		t.Fatalf("marshaling: %v", err)
+	}
+
+	rules := RuleConfig{Severity: map[string]Severity{"V11": SeverityInfo}}
+	result, err := ValidateWithOptions(data, ModeSingleTask, Options{Rules: rules})
+	if err != nil {
+		t.Fatalf("validation error: %v", err)
+	}
+	if !hasFinding(result, "V11", SeverityInfo) || result.Stats.InfoCount == 0 {
+		t.Error("severity override should re-grade V11 to INFO")
+	}
+}
+
+func TestRepoChecks(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "internal", "pricing"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	task := map[string]any{
+		"task_id":     "task-a",
+		"task_name":   "Implement task A",
+		"goal":        "Task A produces output X.",
+		"inputs":      []map[string]string{{"name": "in", "type": "string", "constraints": "none", "source": "caller"}},
+		"outputs":     []map[string]string{{"name": "out", "type": "string", "constraints": "none", "destination": "return"}},
+		"acceptance":  []string{"Given in, out is X"},
+		"depends_on":  map[string]string{"status": "N/A", "reason": "First task"},
+		"constraints": []string{"No new dependencies"},
+		"files_scope": []string{"internal/pricing/new.go", "internal/pricng/typo.go", "internal/**/*.go", "../outside.go"},
+	}
+	data, err := json.Marshal(task)
+	if err != nil {
+		t.Fatalf("marshaling: %v", err)
+	}
+
+	result, err := ValidateWithOptions(data, ModeSingleTask, Options{RepoRoot: root})
+	if err != nil {
+		t.Fatalf("validation error: %v", err)
+	}
+	for _, want := range []string{"files_scope[1]", "files_scope[3]"} {
+		if !hasFindingAt(result, "REPO", SeverityWarning, want) {
+			t.Errorf("expected REPO warning at %s", want)
+		}
+	}
+	if result.Stats.WarningCount != 2 {
+		t.Errorf("expected exactly 2 REPO warnings, got %d warnings", result.Stats.WarningCount)
+	}
+
+	result, err = Validate(data, ModeSingleTask)
+	if err != nil {
+		t.Fatalf("validation error: %v", err)
+	}
+	if hasFinding(result, "REPO", SeverityWarning) {
+		t.Error("REPO checks should only run with RepoRoot set")
+	}
+}
+
+func TestScopePaths(t *testing.T) {
+	for in, want := range map[string]string{
+		`internal\pricing\calc.go`: "internal/pricing/calc.go",
+		"./internal//api/":         "internal/api/",
+		`.\docs\`:                  "docs/",
+		"internal/**/*.go":         "internal/**/*.go",
+		`C:\src\main.go`:           "C:/src/main.go",
+	} {
+		if got := NormalizeScopePath(in); got != want {
+			t.Errorf("NormalizeScopePath(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "internal", "pricing"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	task := map[string]any{
+		"task_id":     "task-a",
+		"task_name":   "Implement task A",
+		"goal":        "Task A produces output X.",
+		"inputs":      []map[string]string{{"name": "in", "type": "string", "constraints": "none", "source": "caller"}},
+		"outputs":     []map[string]string{{"name": "out", "type": "string", "constraints": "none", "destination": "return"}},
+		"acceptance":  []string{"Output X is produced"},
+		"depends_on":  map[string]string{"status": "N/A", "reason": "First task"},
+		"constraints": []string{"No new dependencies"},
+		"files_scope": []string{`internal\pricing\new.go`, "C:/work/outside.go", "/etc/outside.conf"},
+	}
+	data, err := json.Marshal(task)
+	if err != nil {
+		t.Fatalf("marshaling: %v", err)
+	}
+	result, err := ValidateWithOptions(data, ModeSingleTask, Options{RepoRoot: root})
+	if err != nil {
+		t.Fatalf("validation error: %v", err)
+	}
+	if !hasFindingAt(result, "PATHS", SeverityWarning, "files_scope[0]") {
+		t.Errorf("expected PATHS warning for the backslash entry, got: %+v", result.Errors)
+	}
+	if hasFindingAt(result, "REPO", SeverityWarning, "files_scope[0]") {
+		t.Errorf("backslash entry in an existing directory should pass REPO, got: %+v", result.Errors)
+	}
+	for _, want := range []string{"files_scope[1]", "files_scope[2]"} {
+		if !hasFindingAt(result, "REPO", SeverityWarning, want) {
+			t.Errorf("expected REPO warning for absolute entry at %s", want)
+		}
+	}
+}
+
+func TestTierSelection(t *testing.T) {
+	// "None" is too short for the schema; "placeholder" is a V11 weasel word.
+	task := map[string]any{
+		"task_id":     "task-a",
+		"task_name":   "Implement task A",
+		"goal":        "Task A produces a placeholder output X.",
+		"inputs":      []map[string]string{{"name": "in", "type": "string", "constraints": "none", "source": "caller"}},
+		"outputs":     []map[string]string{{"name": "out", "type": "string", "constraints": "none", "destination": "return"}},
+		"acceptance":  []string{"Output X is produced"},
+		"depends_on":  map[string]string{"status": "N/A", "reason": "First task"},
+		"constraints": []string{"None"},
+		"files_scope": []string{`pkg\a.go`},
+	}
+	data, err := json.Marshal(task)
+	if err != nil {
+		t.Fatalf("marshaling: %v", err)
+	}
+
+	result, err := ValidateWithOptions(data, ModeSingleTask, Options{Tiers: SchemaTier})
+	if err != nil {
+		t.Fatalf("validation error: %v", err)
+	}
+	if result.Valid || !hasFinding(result, "SCHEMA", SeverityError) || hasFinding(result, "V11", SeverityWarning) {
+		t.Error("schema tier: expected SCHEMA errors only")
+	}
+
+	result, err = ValidateWithOptions(data, ModeSingleTask, Options{Tiers: SemanticTier})
+	if err != nil {
+		t.Fatalf("validation error: %v", err)
+	}
+	if !result.Valid || hasFinding(result, "SCHEMA", SeverityError) || !hasFinding(result, "V11", SeverityWarning) {
+		t.Error("semantic tier: expected V11 warning and no SCHEMA errors")
+	}
+	if result.Stats.TotalTasks != 1 || result.Graph == nil {
+		t.Error("semantic tier: expected task count and parsed graph")
+	}
+
+	if _, err := ValidateWithOptions([]byte(`{"goal": 5}`), ModeSingleTask, Options{Tiers: SemanticTier}); err == nil {
+		t.Error("semantic tier: expected error for undecodable input")
+	}
+}
+
+func TestContextualFieldsRequiredInSpec020(t *testing.T) {
+	graph := map[string]any{
+		"version": "0.2.0",
+		"tasks": []map[string]any{
+			{
+				"task_id":    "task-a",
+				"task_name":  "Implement task A",
+				"goal":       "Task A produces output X.",
+				"inputs":     []map[string]string{{"name": "in", "type": "string", "constraints": "none", "source": "caller"}},
+				"outputs":    []map[string]string{{"name": "out", "type": "string", "constraints": "none", "destination": "return"}},
+				"acceptance": []string{"Output X is produced"},
+				"depends_on": map[string]string{"status": "N/A", "reason": "First task"},
+			},
+		},
+	}
+	data, err := json.Marshal(graph)
+	if err != nil {
+		t.Fatalf("marshaling: %v", err)
+	}
+
+	result, err := Validate(data, ModeTaskGraph)
+	if err != nil {
+		t.Fatalf("validation error: %v", err)
+	}
+	if result.Valid || !hasFindingAt(result, "V9", SeverityError, "tasks[0].constraints") {
+		t.Error("expected V9 error for missing constraints in a 0.2.0 graph")
+	}
+}
+
+func TestProvenanceMetadata(t *testing.T) {
+	base := map[string]any{
+		"version":        "0.1.0",
+		"generated_by":   "planner-agent/1.4",
+		"generated_at":   "2026-01-31T14:05:00Z",
+		"graph_revision": "1.2.0",
+		"tasks": []map[string]any{
+			{
+				"task_id":     "task-a",
+				"task_name":   "Implement task A",
+				"goal":        "Task A produces output X.",
+				"inputs":      []map[string]string{{"name": "in", "type": "string", "constraints": "none", "source": "caller"}},
+				"outputs":     []map[string]string{{"name": "out", "type": "string", "constraints": "none", "destination": "return"}},
+				"acceptance":  []string{"Output X is produced"},
+				"depends_on":  map[string]string{"status": "N/A", "reason": "First task"},
+				"constraints": []string{"No new dependencies"},
+				"files_scope": []string{"a.go"},
+			},
+		},
+	}
+	validate := func(field, value string) *ValidationResult {
+		t.Helper()
+		doc := make(map[string]any, len(base))
+		for k, v := range base {
+			doc[k] = v
+		}
+		if field != "" {
+			doc[field] = value
+		}
+		data, err := json.Marshal(doc)
+		if err != nil {
+			t.Fatalf("marshaling: %v", err)
+		}
+		result, err := Validate(data, ModeTaskGraph)
+		if err != nil {
+			t.Fatalf("validation error: %v", err)
+		}
+		return result
+	}
+
+	result := validate("", "")
+	if !result.Valid || result.Graph.GeneratedBy != "planner-agent/1.4" {
+		for _, e := range result.Errors {
+			t.Logf("finding: %s", e.Error())
+		}
+		t.Error("expected valid graph with provenance fields")
+	}
+	if result := validate("generated_at", "31/01/2026"); !hasFinding(result, "META", SeverityError) {
+		t.Error("expected META error for non-RFC 3339 timestamp")
+	}
+	if result := validate("graph_revision", "v2"); !hasFinding(result, "SCHEMA", SeverityError) {
+		t.Error("expected SCHEMA error for non-semver revision")
+	}
+}
+
+func TestBumpRevision(t *testing.T) {
+	tests := []struct {
+		rev  string
+		part int
+		want string
+	}{
+		{"1.2.3", RevisionPatch, "1.2.4"},
+		{"1.2.3", RevisionMinor, "1.3.0"},
+		{"1.2.3-rc.1", RevisionPatch, "1.2.4"},
+		{"", RevisionPatch, ""},
+	}
+	for _, tt := range tests {
+		g := &TaskGraph{GraphRevision: tt.rev}
+		got, err := g.BumpRevision(tt.part)
+		if err != nil || got != tt.want || g.GraphRevision != tt.want {
+			t.Errorf("BumpRevision(%q, %d) = %q, %v; want %q", tt.rev, tt.part, got, err, tt.want)
+		}
+	}
+	if _, err := (&TaskGraph{GraphRevision: "1.x.0"}).BumpRevision(RevisionPatch); err == nil {
+		t.Error("expected error for malformed revision")
+	}
+}
+
+func TestSealVerification(t *testing.T) {
+	graph := TaskGraph{
+		Version: SpecVersion010,
+		Tasks: []TaskNode{{
+			TaskID:      "task-a",
+			TaskName:    "Implement task A",
+			Goal:        "Task A produces output X.",
+			Inputs:      []InputSpec{{Name: "in", Type: "string", Constraints: "none", Source: "caller"}},
+			Outputs:     []OutputSpec{{Name: "out", Type: "string", Constraints: "none", Destination: "return"}},
+			Acceptance:  []string{"Output X is produced"},
+			DependsOn:   json.RawMessage(`{"status": "N/A", "reason": "First task"}`),
+			Constraints: json.RawMessage(`["No new dependencies"]`),
+			FilesScope:  json.RawMessage(`["a.go"]`),
+		}},
+	}
+	verify := func(g TaskGraph, indent bool) *ValidationResult {
+		t.Helper()
+		var data []byte
+		var err error
+		if indent {
+			data, err = json.MarshalIndent(&g, "", "    ")
+		} else {
+			data, err = json.Marshal(&g)
+		}
+		if err != nil {
+			t.Fatalf("marshaling: %v", err)
+		}
+		result, err := ValidateWithOptions(data, ModeTaskGraph, Options{VerifySeal: true})
+		if err != nil {
+			t.Fatalf("validation error: %v", err)
+		}
+		return result
+	}
+
+	if result := verify(graph, false); !hasFinding(result, "SEAL", SeverityError) {
+		t.Error("expected SEAL error for an unsealed graph")
+	}
+
+	if err := graph.ApplySeal(); err != nil {
+		t.Fatalf("ApplySeal: %v", err)
+	}
+	if len(graph.Seal.Digest) != 64 {
+		t.Fatalf("digest = %q, want 64 hex characters", graph.Seal.Digest)
+	}
+	if result := verify(graph, true); hasFinding(result, "SEAL", SeverityError) {
+		t.Errorf("reformatted sealed graph should verify, got: %+v", result.Errors)
+	}
+
+	tampered := graph
+	tampered.Tasks = []TaskNode{graph.Tasks[0]}
+	tampered.Tasks[0].Goal = "Task A produces output Y."
+	if result := verify(tampered, false); !hasFinding(result, "SEAL", SeverityError) || result.Valid {
+		t.Error("expected SEAL error for a graph modified after sealing")
+	}
+}
+
+func TestNumericPriorityAndEstimate(t *testing.T) {
+	validate := func(priority, estimate any) *ValidationResult {
+		t.Helper()
+		task := map[string]any{
+			"task_id":     "numeric-levels",
+			"task_name":   "Implement numeric levels",
+			"goal":        "The task carries raw numeric priority and estimate values.",
+			"inputs":      []map[string]string{{"name": "in", "type": "string", "constraints": "none", "source": "caller"}},
+			"outputs":     []map[string]string{{"name": "out", "type": "string", "constraints": "none", "destination": "return"}},
+			"acceptance":  []string{"Output is produced for every input"},
+			"depends_on":  map[string]string{"status": "N/A", "reason": "Standalone"},
+			"constraints": []string{"No new dependencies"},
+			"files_scope": []string{"a.go"},
+			"priority":    priority,
+			"estimate":    estimate,
+		}
+		data, err := json.Marshal(task)
+		if err != nil {
+			t.Fatalf("marshaling: %v", err)
+		}
+		result, err := Validate(data, ModeSingleTask)
+		if err != nil {
+			t.Fatalf("validation error: %v", err)
+		}
+		return result
+	}
+
+	result := validate(1, 90)
+	if !result.Valid {
+		t.Fatalf("numeric levels should validate, got: %+v", result.Errors)
+	}
+	task := result.Graph.Tasks[0]
+	if task.Priority != "1" || task.Estimate != "90" {
+		t.Errorf("decoded levels = %q, %q", task.Priority, task.Estimate)
+	}
+	if data, _ := json.Marshal(task); !strings.Contains(string(data), `"priority":1,"estimate":90`) {
+		t.Errorf("numeric levels should encode as numbers: %s", data)
+	}
+
+	if result := validate(7, 90); result.Valid || !hasFinding(result, "SCHEMA", SeverityError) {
+		t.Error("expected SCHEMA error for priority 7")
+	}
+	if result := validate("high", 0); result.Valid {
+		t.Error("expected SCHEMA error for estimate 0")
+	}
+	// A misspelled size names the allowed sizes, not a pattern.
+	result = validate("high", "medum")
+	found := false
+	for _, e := range result.Errors {
+		found = found || strings.Contains(e.Suggestion, "trivial|small|medium|large|unknown")
+	}
+	if result.Valid || !found {
+		t.Errorf("expected the allowed sizes for estimate 'medum', got %+v", result.Errors)
+	}
+	if result := validate(1, 600); !hasFindingAt(result, "V13", SeverityInfo, "estimate") {
+		t.Error("expected V13 info for a raw estimate of a working day or more")
+	}
+}
+
+func TestDueDates(t *testing.T) {
+	task := func(id, due, notBefore, deps string) TaskNode {
+		return TaskNode{
+			TaskID:      id,
+			TaskName:    "Implement " + id,
+			Goal:        "The task produces output X.",
+			Inputs:      []InputSpec{{Name: "in", Type: "string", Constraints: "none", Source: "caller"}},
+			Outputs:     []OutputSpec{{Name: "out", Type: "string", Constraints: "none", Destination: "return"}},
+			Acceptance:  []string{"Output X is produced"},
+			DependsOn:   json.RawMessage(deps),
+			Constraints: json.RawMessage(`["No new dependencies"]`),
+			FilesScope:  json.RawMessage(`["` + id + `.go"]`),
+			Due:         due,
+			NotBefore:   notBefore,
+		}
+	}
+	first := `{"status": "N/A", "reason": "First task"}`
+	validate := func(milestoneDue string, tasks ...TaskNode) *ValidationResult {
+		t.Helper()
+		graph := TaskGraph{
+			Version:    SpecVersion010,
+			Milestones: []Milestone{{Name: "M1", TaskIDs: []string{"task-a", "task-b"}, Due: milestoneDue}},
+			Tasks:      tasks,
+		}
+		data, err := json.Marshal(&graph)
+		if err != nil {
+			t.Fatalf("marshaling: %v", err)
+		}
+		result, err := Validate(data, ModeTaskGraph)
+		if err != nil {
+			t.Fatalf("validation error: %v", err)
+		}
+		return result
+	}
+
+	result := validate("2026-03-31",
+		task("task-a", "2026-03-05", "2026-03-01", first),
+		task("task-b", "2026-03-10T17:00:00Z", "", `["task-a"]`))
+	if hasFinding(result, "DATES", SeverityError) || hasFinding(result, "DATES", SeverityWarning) {
+		t.Errorf("consistent dates should pass, got: %+v", result.Errors)
+	}
+
+	result = validate("", task("task-a", "2026-02-30", "", first), task("task-b", "", "", `["task-a"]`))
+	if !hasFindingAt(result, "DATES", SeverityError, "tasks[0].due") {
+		t.Error("expected DATES error for an impossible date")
+	}
+
+	result = validate("", task("task-a", "2026-03-01", "2026-03-05", first), task("task-b", "", "", `["task-a"]`))
+	if !hasFindingAt(result, "DATES", SeverityError, "tasks[0].not_before") {
+		t.Error("expected DATES error for not_before after due")
+	}
+
+	result = validate("", task("task-a", "2026-03-10", "", first), task("task-b", "2026-03-05", "", `["task-a"]`))
+	if !hasFindingAt(result, "DATES", SeverityError, "tasks[1].due") {
+		t.Error("expected DATES error for a task due before its dependency")
+	}
+
+	result = validate("", task("task-a", "", "2026-03-10", first), task("task-b", "2026-03-05", "", `["task-a"]`))
+	if !hasFindingAt(result, "DATES", SeverityError, "tasks[1].due") {
+		t.Error("expected DATES error for a task due before its dependency may start")
+	}
+
+	result = validate("2026-03-01", task("task-a", "2026-03-05", "", first), task("task-b", "", "", `["task-a"]`))
+	if !hasFindingAt(result, "DATES", SeverityWarning, "milestones[0].due") {
+		t.Error("expected DATES warning for a task due after its milestone")
+	}
+}
+
+func TestRiskMitigation(t *testing.T) {
+	validate := func(risk *Risk) *ValidationResult {
+		t.Helper()
+		task := TaskNode{
+			TaskID:      "risky-task",
+			TaskName:    "Migrate the orders table",
+			Goal:        "The orders table uses the new schema.",
+			Inputs:      []InputSpec{{Name: "in", Type: "string", Constraints: "none", Source: "caller"}},
+			Outputs:     []OutputSpec{{Name: "out", Type: "string", Constraints: "none", Destination: "return"}},
+			Acceptance:  []string{"Every row is readable after migration"},
+			DependsOn:   json.RawMessage(`{"status": "N/A", "reason": "First task"}`),
+			Constraints: json.RawMessage(`["No downtime"]`),
+			FilesScope:  json.RawMessage(`["migrations/"]`),
+			Risk:        risk,
+		}
+		data, err := json.Marshal(&task)
+		if err != nil {
+			t.Fatalf("marshaling: %v", err)
+		}
+		result, err := Validate(data, ModeSingleTask)
+		if err != nil {
+			t.Fatalf("validation error: %v", err)
+		}
+		return result
+	}
+
+	if result := validate(&Risk{Level: RiskHigh}); !hasFindingAt(result, "RISK", SeverityWarning, "risk.mitigation") {
+		t.Error("expected RISK warning for high risk without mitigation")
+	}
+	if result := validate(&Risk{Level: RiskHigh, Mitigation: "Rehearse on a copy"}); hasFinding(result, "RISK", SeverityWarning) {
+		t.Error("mitigated high risk should not warn")
+	}
+	if result := validate(&Risk{Level: RiskMedium}); hasFinding(result, "RISK", SeverityWarning) {
+		t.Error("medium risk without mitigation should not warn")
+	}
+	if result := validate(&Risk{Level: "extreme"}); result.Valid || !hasFinding(result, "SCHEMA", SeverityError) {
+		t.Error("expected SCHEMA error for an unknown risk level")
+	}
+}
+
+func TestLabels(t *testing.T) {
+	validate := func(labels []string) *ValidationResult {
+		t.Helper()
+		task := TaskNode{
+			TaskID:      "labelled-task",
+			TaskName:    "Implement labelled task",
+			Goal:        "The task produces output X.",
+			Inputs:      []InputSpec{{Name: "in", Type: "string", Constraints: "none", Source: "caller"}},
+			Outputs:     []OutputSpec{{Name: "out", Type: "string", Constraints: "none", Destination: "return"}},
+			Acceptance:  []string{"Output X is produced"},
+			DependsOn:   json.RawMessage(`{"status": "N/A", "reason": "First task"}`),
+			Constraints: json.RawMessage(`["No new dependencies"]`),
+			FilesScope:  json.RawMessage(`["a.go"]`),
+			Labels:      labels,
+		}
+		data, err := json.Marshal(&task)
+		if err != nil {
+			t.Fatalf("marshaling: %v", err)
+		}
+		result, err := Validate(data, ModeSingleTask)
+		if err != nil {
+			t.Fatalf("validation error: %v", err)
+		}
+		return result
+	}
+
+	if result := validate([]string{"backend", "security"}); !result.Valid {
+		t.Errorf("kebab-case labels should validate, got: %+v", result.Errors)
+	}
+	if result := validate([]string{"Backend"}); result.Valid {
+		t.Error("expected SCHEMA error for a label that is not kebab-case")
+	}
+	if result := validate([]string{"a", "a"}); result.Valid {
+		t.Error("expected SCHEMA error for duplicate labels")
+	}
+	many := []string{"l0", "l1", "l2", "l3", "l4", "l5", "l6", "l7", "l8", "l9", "l10"}
+	if result := validate(many); result.Valid {
+		t.Error("expected SCHEMA error for more than 10 labels")
+	}
+}
+
+func TestVerification(t *testing.T) {
+	validate := func(verification []VerificationSpec, require bool) *ValidationResult {
+		t.Helper()
+		task := TaskNode{
+			TaskID:       "verified-task",
+			TaskName:     "Implement verified task",
+			Goal:         "The task produces output X.",
+			Inputs:       []InputSpec{{Name: "in", Type: "string", Constraints: "none", Source: "caller"}},
+			Outputs:      []OutputSpec{{Name: "out", Type: "string", Constraints: "none", Destination: "return"}},
+			Acceptance:   []string{"Output X is produced", "Input Y is rejected"},
+			DependsOn:    json.RawMessage(`{"status": "N/A", "reason": "First task"}`),
+			Constraints:  json.RawMessage(`["No new dependencies"]`),
+			FilesScope:   json.RawMessage(`["a.go"]`),
+			Verification: verification,
+		}
+		data, err := json.Marshal(&task)
+		if err != nil {
+			t.Fatalf("marshaling: %v", err)
+		}
+		result, err := ValidateWithOptions(data, ModeSingleTask, Options{RequireVerification: require})
+		if err != nil {
+			t.Fatalf("validation error: %v", err)
+		}
+		return result
+	}
+
+	partial := []VerificationSpec{{Criterion: 0, Command: "go test ./... -run TestX"}}
+	if result := validate(partial, false); hasFinding(result, "VERIFY", SeverityError) {
+		t.Errorf("partial verification is fine unless required, got: %+v", result.Errors)
+	}
+	if result := validate(partial, true); !hasFindingAt(result, "VERIFY", SeverityError, "acceptance[1]") {
+		t.Error("expected VERIFY error for an uncovered criterion when verification is required")
+	}
+	full := append(partial, VerificationSpec{Criterion: 1, TestFile: "a_test.go"})
+	if result := validate(full, true); hasFinding(result, "VERIFY", SeverityError) {
+		t.Errorf("fully verified task should pass, got: %+v", result.Errors)
+	}
+	if result := validate([]VerificationSpec{{Criterion: 2, Command: "make check"}}, false); !hasFindingAt(result, "VERIFY", SeverityError, "verification[0].criterion") {
+		t.Error("expected VERIFY error for a criterion index out of range")
+	}
+	// A negative index fails the schema, but semantic checks still run on
+	// the decoded document and must report it rather than panic.
+	if result := validate([]VerificationSpec{{Criterion: -1, Command: "make check"}}, false); !hasFindingAt(result, "VERIFY", SeverityError, "verification[0].criterion") {
+		t.Error("expected VERIFY error for a negative criterion index")
+	}
+	if result := validate([]VerificationSpec{{Criterion: 0}}, false); !hasFindingAt(result, "VERIFY", SeverityError, "verification[0]") {
+		t.Error("expected VERIFY error for an entry without command or test_file")
+	}
+}
+
+func TestCodeOwners(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "CODEOWNERS")
+	rules := "# owners\n*        @org/core\n/api/    @org/api\n*.md     @org/docs\ndocs/**/generated\n"
+	if err := os.WriteFile(path, []byte(rules), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	co, err := LoadCodeOwners(path)
+	if err != nil {
+		t.Fatalf("LoadCodeOwners: %v", err)
+	}
+	for file, want := range map[string]string{
+		"main.go":                 "@org/core",
+		"api/handler.go":          "@org/api",
+		"api/README.md":           "@org/docs",
+		"docs/a/generated/x.json": "",
+		"src/api/x.go":            "@org/core",
+	} {
+		if got := strings.Join(co.Owners(file), " "); got != want {
+			t.Errorf("Owners(%q) = %q, want %q", file, got, want)
+		}
+	}
+
+	validate := func(notes string) *ValidationResult {
+		t.Helper()
+		task := TaskNode{
+			TaskID:      "cross-team",
+			TaskName:    "Implement cross-team change",
+			Goal:        "The handler and the core loader agree on the config format.",
+			Inputs:      []InputSpec{{Name: "in", Type: "string", Constraints: "none", Source: "caller"}},
+			Outputs:     []OutputSpec{{Name: "out", Type: "string", Constraints: "none", Destination: "return"}},
+			Acceptance:  []string{"Both packages parse the same file"},
+			DependsOn:   json.RawMessage(`{"status": "N/A", "reason": "First task"}`),
+			Constraints: json.RawMessage(`["No new dependencies"]`),
+			FilesScope:  json.RawMessage(`["api/handler.go", "internal/config.go"]`),
+			Notes:       notes,
+		}
+		data, err := json.Marshal(&task)
+		if err != nil {
+			t.Fatalf("marshaling: %v", err)
+		}
+		result, err := ValidateWithOptions(data, ModeSingleTask, Options{CodeOwners: co})
+		if err != nil {
+			t.Fatalf("validation error: %v", err)
+		}
+		return result
+	}
+
+	result := validate("")
+	if !hasFinding(result, "OWNERS", SeverityInfo) || !hasFinding(result, "OWNERS", SeverityWarning) {
+		t.Errorf("expected OWNERS info and warning, got: %+v", result.Errors)
+	}
+	if result := validate("@org/api reviews the handler; @org/core the loader."); hasFinding(result, "OWNERS", SeverityWarning) {
+		t.Error("notes should silence the multi-team warning")
+	}
+}
+
+func TestCustomRules(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "house.sh")
+	findings := `[{"message": "Task names must start with a ticket key", "path": "tasks[0].task_name"}, {"rule": "HOUSE-2", "severity": "INFO", "message": "ok"}]`
+	if err := os.WriteFile(script, []byte("#!/bin/sh\ncat >/dev/null\necho '"+findings+"'\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	configPath := filepath.Join(dir, "taskval.json")
+	config := `{"disabled": ["V7"], "custom_rules": [{"id": "HOUSE", "command": ["./house.sh"]}, {"id": "BROKEN", "command": ["false"]}]}`
+	if err := os.WriteFile(configPath, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.CustomRules[0].Command[0] != script {
+		t.Errorf("command = %q, want it resolved to %q", cfg.CustomRules[0].Command[0], script)
+	}
+	if strings.Join(cfg.Disabled, ",") != "V7" {
+		t.Errorf("Disabled = %v, want [V7]", cfg.Disabled)
+	}
+
+	task := TaskNode{
+		TaskID:     "house-style",
+		TaskName:   "Implement config loader",
+		Goal:       "Load() returns the parsed config for a valid file.",
+		Inputs:     []InputSpec{{Name: "path", Type: "string", Constraints: "non-empty", Source: "caller"}},
+		Outputs:    []OutputSpec{{Name: "config", Type: "Config", Constraints: "none", Destination: "return"}},
+		Acceptance: []string{"Given a valid file, Load returns its config"},
+		DependsOn:  json.RawMessage(`{"status": "N/A", "reason": "First task"}`),
+	}
+	data, err := json.Marshal(&task)
+	if err != nil {
+		t.Fatalf("marshaling: %v", err)
+	}
+	result, err := ValidateWithOptions(data, ModeSingleTask, Options{CustomRules: cfg.CustomRules})
+	if err != nil {
+		t.Fatalf("validation error: %v", err)
+	}
+	if !hasFindingAt(result, "HOUSE", SeverityWarning, "task_name") {
+		t.Errorf("expected HOUSE warning defaulted from the rule, got: %+v", result.Errors)
+	}
+	if !hasFinding(result, "HOUSE-2", SeverityInfo) {
+		t.Errorf("expected HOUSE-2 info kept as reported, got: %+v", result.Errors)
+	}
+	if !hasFinding(result, "BROKEN", SeverityError) {
+		t.Errorf("expected failing rule to be an error, got: %+v", result.Errors)
+	}
+
+	for name, bad := range map[string]string{
+		"wasm":    `{"custom_rules": [{"id": "W", "wasm": "rule.wasm"}]}`,
+		"no id":   `{"custom_rules": [{"command": ["true"]}]}`,
+		"unknown": `{"custom_rulez": []}`,
+		"limit":   `{"limits": {"max_everything": 3}}`,
+		"zero":    `{"limits": {"non_goals_acceptance": 0}}`,
+	} {
+		if err := os.WriteFile(configPath, []byte(bad), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadConfig(configPath); err == nil {
+			t.Errorf("%s: expected LoadConfig error", name)
+		}
+	}
+}
+
+func TestRegisterRule(t *testing.T) {
+	RegisterRule("TEST-TICKET", func(graph *TaskGraph, result *ValidationResult) {
+		for i, task := range graph.Tasks {
+			if !strings.HasPrefix(task.TaskName, "PROJ-") {
+				result.AddError(ValidationError{
+					Severity: SeverityWarning,
+					Path:     fmt.Sprintf("tasks[%d].task_name", i),
+					Message:  "task_name should start with a ticket key",
+				})
+			}
+		}
+	})
+	t.Cleanup(func() {
+		registryMu.Lock()
+		registry = slices.DeleteFunc(registry, func(r registeredRule) bool { return r.id == "TEST-TICKET" })
+		registryMu.Unlock()
+	})
+
+	if !slices.Contains(RegisteredRules(), "TEST-TICKET") {
+		t.Fatalf("RegisteredRules() = %v, want TEST-TICKET", RegisteredRules())
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected panic registering a duplicate rule id")
+			}
+		}()
+		RegisterRule("TEST-TICKET", func(*TaskGraph, *ValidationResult) {})
+	}()
+
+	task := TaskNode{
+		TaskID:     "no-ticket",
+		TaskName:   "Implement config loader",
+		Goal:       "Load() returns the parsed config for a valid file.",
+		Inputs:     []InputSpec{{Name: "path", Type: "string", Constraints: "non-empty", Source: "caller"}},
+		Outputs:    []OutputSpec{{Name: "config", Type: "Config", Constraints: "none", Destination: "return"}},
+		Acceptance: []string{"Given a valid file, Load returns its config"},
+		DependsOn:  json.RawMessage(`{"status": "N/A", "reason": "First task"}`),
+	}
+	data, err := json.Marshal(&task)
+	if err != nil {
+		t.Fatalf("marshaling: %v", err)
+	}
+	result, err := Validate(data, ModeSingleTask)
+	if err != nil {
+		t.Fatalf("validation error: %v", err)
+	}
+	if !hasFindingAt(result, "TEST-TICKET", SeverityWarning, "task_name") {
+		t.Errorf("expected registered rule finding, got: %+v", result.Errors)
+	}
+
+	opts := Options{Rules: RuleConfig{Severity: map[string]Severity{"TEST-TICKET": SeverityError}}}
+	result, err = ValidateWithOptions(data, ModeSingleTask, opts)
+	if err != nil {
+		t.Fatalf("validation error: %v", err)
+	}
+	if result.Valid || !hasFinding(result, "TEST-TICKET", SeverityError) {
+		t.Errorf("RuleConfig should raise the registered rule to an error, got: %+v", result.Errors)
+	}
+}
+
+func TestValidateDesign(t *testing.T) {
+	tests := []struct {
+		name string
+		blob string
+		rule string
+	}{
+		{"valid", `{"_template": {"version": "0.2.0", "task_id": "a", "files_scope": [], "effects": "", "inputs": [], "outputs": [], "due": "2026-03-01"}}`, ""},
+		{"old version", `{"_template": {"version": "0.1.0", "task_id": "a", "files_scope": [], "effects": "", "inputs": [], "outputs": []}}`, "DESIGN"},
+		{"bad date", `{"_template": {"version": "0.2.0", "task_id": "a", "files_scope": [], "effects": "", "inputs": [], "outputs": [], "due": "2026-02-30"}}`, "DATES"},
+		{"bad task_id", `{"_template": {"version": "0.2.0", "task_id": "A_b", "files_scope": [], "effects": "", "inputs": [], "outputs": []}}`, "SCHEMA"},
+		{"unknown field", `{"_template": {"version": "0.2.0", "task_id": "a", "files_scope": [], "effects": "", "inputs": [], "outputs": [], "extra": 1}}`, "SCHEMA"},
+		{"extensions", `{"_template": {"version": "0.2.0", "task_id": "a", "files_scope": [], "effects": "", "inputs": [], "outputs": [], "extensions": {"x_team": "payments"}}}`, ""},
+		{"bad extension name", `{"_template": {"version": "0.2.0", "task_id": "a", "files_scope": [], "effects": "", "inputs": [], "outputs": [], "extensions": {"team": "payments"}}}`, "SCHEMA"},
+		{"no wrapper", `{"version": "0.2.0"}`, "SCHEMA"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ValidateDesign([]byte(tt.blob))
+			if err != nil {
+				t.Fatalf("ValidateDesign error: %v", err)
+			}
+			if tt.rule == "" {
+				if !result.Valid {
+					t.Errorf("expected valid, got: %+v", result.Errors)
+				}
+				return
+			}
+			if result.Valid || !hasFinding(result, tt.rule, SeverityError) {
+				t.Errorf("expected %s error, got: %+v", tt.rule, result.Errors)
+			}
+		})
+	}
+}
+
+func TestMilestoneOrder(t *testing.T) {
+	task := func(id, deps string) TaskNode {
+		return TaskNode{
+			TaskID:      id,
+			TaskName:    "Implement " + id,
+			Goal:        "The task produces output X.",
+			Inputs:      []InputSpec{{Name: "in", Type: "string", Constraints: "none", Source: "caller"}},
+			Outputs:     []OutputSpec{{Name: "out", Type: "string", Constraints: "none", Destination: "return"}},
+			Acceptance:  []string{"Output X is produced"},
+			DependsOn:   json.RawMessage(deps),
+			Constraints: json.RawMessage(`["No new dependencies"]`),
+			FilesScope:  json.RawMessage(`["` + id + `.go"]`),
+		}
+	}
+	validate := func(milestones []Milestone, tasks ...TaskNode) *ValidationResult {
+		t.Helper()
+		data, err := json.Marshal(&TaskGraph{Version: SpecVersion010, Milestones: milestones, Tasks: tasks})
+		if err != nil {
+			t.Fatalf("marshaling: %v", err)
+		}
+		result, err := Validate(data, ModeTaskGraph)
+		if err != nil {
+			t.Fatalf("validation error: %v", err)
+		}
+		return result
+	}
+	first := `{"status": "N/A", "reason": "First task"}`
+
+	result := validate([]Milestone{{Name: "M1", TaskIDs: []string{"task-a"}, DependsOnMilestones: []string{"M1"}}}, task("task-a", first))
+	if !hasFindingAt(result, "MILESTONE", SeverityError, "milestones[0].depends_on_milestones") {
+		t.Errorf("expected MILESTONE error for a self-dependency, got: %+v", result.Errors)
+	}
+
+	// M3 waits for M1 through M2, yet task-a in M1 needs task-c from M3.
+	milestones := []Milestone{
+		{Name: "M1", TaskIDs: []string{"task-a"}},
+		{Name: "M2", TaskIDs: []string{"task-b"}, DependsOnMilestones: []string{"M1"}},
+		{Name: "M3", TaskIDs: []string{"task-c"}, DependsOnMilestones: []string{"M2"}},
+	}
+	result = validate(milestones, task("task-a", `["task-c"]`), task("task-b", first), task("task-c", first))
+	if !hasFindingAt(result, "MILESTONE", SeverityError, "tasks[0].depends_on") {
+		t.Errorf("expected MILESTONE error for a dependency against milestone order, got: %+v", result.Errors)
+	}
+
+	result = validate(milestones, task("task-a", first), task("task-b", `["task-a"]`), task("task-c", `["task-a"]`))
+	if hasFinding(result, "MILESTONE", SeverityError) {
+		t.Errorf("dependencies along milestone order should pass, got: %+v", result.Errors)
+	}
+}
+
+func TestEmptyMilestones(t *testing.T) {
+	task := TaskNode{
+		TaskID:      "task-a",
+		TaskName:    "Implement task-a",
+		Goal:        "The task produces output X.",
+		Inputs:      []InputSpec{{Name: "in", Type: "string", Constraints: "none", Source: "caller"}},
+		Outputs:     []OutputSpec{{Name: "out", Type: "string", Constraints: "none", Destination: "return"}},
+		Acceptance:  []string{"Given in, out is X"},
+		DependsOn:   json.RawMessage(`{"status": "N/A", "reason": "First task"}`),
+		Constraints: json.RawMessage(`["No new dependencies"]`),
+		FilesScope:  json.RawMessage(`["a.go"]`),
+	}
+	// Empty task_ids fail the schema, so the warning is checked with the
+	// semantic tier alone.
+	validate := func(milestones ...Milestone) *ValidationResult {
+		t.Helper()
+		data, err := json.Marshal(&TaskGraph{Version: SpecVersion010, Milestones: milestones, Tasks: []TaskNode{task}})
+		if err != nil {
+			t.Fatalf("marshaling: %v", err)
+		}
+		result, err := ValidateWithOptions(data, ModeTaskGraph, Options{Tiers: SemanticTier})
+		if err != nil {
+			t.Fatalf("validation error: %v", err)
+		}
+		return result
+	}
+
+	result := validate(Milestone{Name: "M1", TaskIDs: []string{"task-a"}}, Milestone{Name: "M2", TaskIDs: []string{}})
+	if !hasFindingAt(result, "MILESTONE", SeverityWarning, "milestones[1].task_ids") {
+		t.Errorf("expected MILESTONE warning for the empty milestone, got: %+v", result.Errors)
+	}
+
+	// A full run keeps the schema error.
+	data, err := json.Marshal(&TaskGraph{Version: SpecVersion010, Milestones: []Milestone{{Name: "M1", TaskIDs: []string{}}}, Tasks: []TaskNode{task}})
+	if err != nil {
+		t.Fatalf("marshaling: %v", err)
+	}
+	if result, err := Validate(data, ModeTaskGraph); err != nil || result.Valid || !hasFinding(result, "SCHEMA", SeverityError) {
+		t.Errorf("expected a SCHEMA error for empty task_ids, got: %v, %+v", err, result)
+	}
+
+	result = validate(Milestone{Name: "M1", TaskIDs: []string{}}, Milestone{Name: "M2", TaskIDs: []string{}})
+	if result.Stats.WarningCount != 1 || !hasFindingAt(result, "MILESTONE", SeverityWarning, "milestones") {
+		t.Errorf("expected a single MILESTONE warning when no milestone lists tasks, got: %+v", result.Errors)
+	}
+
+	// An empty milestones block passes the schema.
+	taskJSON, err := json.Marshal(&task)
+	if err != nil {
+		t.Fatalf("marshaling: %v", err)
+	}
+	data = []byte(`{"version": "0.1.0", "milestones": [], "tasks": [` + string(taskJSON) + `]}`)
+	result, err = Validate(data, ModeTaskGraph)
+	if err != nil {
+		t.Fatalf("validation error: %v", err)
+	}
+	if !hasFindingAt(result, "MILESTONE", SeverityWarning, "milestones") {
+		t.Errorf("expected MILESTONE warning for an empty milestones block, got: %+v", result.Errors)
+	}
+}
+
+func TestContextualFieldParseErrors(t *testing.T) {
+	task := TaskNode{
+		TaskID:      "task-a",
+		TaskName:    "Implement task-a",
+		Goal:        "The task produces output X.",
+		Inputs:      []InputSpec{{Name: "in", Type: "string", Constraints: "none", Source: "caller"}},
+		Outputs:     []OutputSpec{{Name: "out", Type: "string", Constraints: "none", Destination: "return"}},
+		Acceptance:  []string{"Output X is produced"},
+		DependsOn:   json.RawMessage(`{"status": "N/A", "reason": "First task"}`),
+		Constraints: json.RawMessage(`"No new dependencies"`),
+		FilesScope:  json.RawMessage(`{"status": "later", "reason": "Unknown yet"}`),
+	}
+	data, err := json.Marshal(&task)
+	if err != nil {
+		t.Fatalf("marshaling: %v", err)
+	}
+
+	// The schema rejects both shapes, so check the semantic tier on its own.
+	result, err := ValidateWithOptions(data, ModeSingleTask, Options{Tiers: SemanticTier})
+	if err != nil {
+		t.Fatalf("validation error: %v", err)
+	}
+	for _, field := range []string{"constraints", "files_scope"} {
+		if !hasFindingAt(result, "V9", SeverityError, field) {
+			t.Errorf("expected V9 error for malformed %s, got: %+v", field, result.Errors)
+		}
+	}
+}
+
+func TestStrictNotApplicable(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string // error substring; empty means the object is accepted
+	}{
+		{`{"status": "N/A", "reason": "First task"}`, ""},
+		{`{"status": "na", "reason": "First task"}`, `status must be exactly "N/A", got "na"`},
+		{`{"status": "n/a", "reason": "First task"}`, `status must be exactly "N/A", got "n/a"`},
+		{`{"status": "N/A"}`, "without a reason"},
+		{`{"status": "N/A", "reason": "  "}`, "without a reason"},
+		{`{"status": "N/A", "reason": "First task", "note": "x"}`, `unknown key "note"`},
+		{`{"reason": "First task"}`, `missing "status"`},
+		{`{"status": true, "reason": "First task"}`, `status must be the string "N/A"`},
+		{`"N/A"`, "must be either an array of task IDs"},
+	}
+	for _, tt := range tests {
+		task := TaskNode{TaskID: "task-a", DependsOn: json.RawMessage(tt.raw)}
+		_, na, err := task.ParseDependsOn()
+		if tt.want == "" {
+			if err != nil || na == nil {
+				t.Errorf("%s: expected N/A to parse, got na=%v err=%v", tt.raw, na, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.raw, tt.want, err)
+		}
+		if err != nil && !strings.HasPrefix(err.Error(), "depends_on") {
+			t.Errorf("%s: error should name the field, got %v", tt.raw, err)
+		}
+	}
+
+	// The same check applies to files_scope and constraints, reported as V9.
+	task := TaskNode{
+		TaskID:      "task-a",
+		TaskName:    "Implement task-a",
+		Goal:        "The task produces output X.",
+		Inputs:      []InputSpec{{Name: "in", Type: "string", Constraints: "none", Source: "caller"}},
+		Outputs:     []OutputSpec{{Name: "out", Type: "string", Constraints: "none", Destination: "return"}},
+		Acceptance:  []string{"Output X is produced"},
+		DependsOn:   json.RawMessage(`{"status": "N/A", "reason": "First task"}`),
+		Constraints: json.RawMessage(`{"status": "N/A"}`),
+		FilesScope:  json.RawMessage(`{"status": "NA", "reason": "Unknown yet"}`),
+	}
+	data, err := json.Marshal(&task)
+	if err != nil {
+		t.Fatalf("marshaling: %v", err)
+	}
+	result, err := ValidateWithOptions(data, ModeSingleTask, Options{Tiers: SemanticTier})
+	if err != nil {
+		t.Fatalf("validation error: %v", err)
+	}
+	for _, field := range []string{"constraints", "files_scope"} {
+		if !hasFindingAt(result, "V9", SeverityError, field) {
+			t.Errorf("expected V9 error for strict N/A %s, got: %+v", field, result.Errors)
+		}
+	}
+}
+
+func TestGoalForbiddenWordLiterals(t *testing.T) {
+	tests := []struct {
+		goal  string
+		allow []string
+		want  bool // expect a V6 error
+	}{
+		{"The cache retries the lookup once; we try a fallback after that.", nil, true},
+		{"The cache calls `Try` once and returns its error unchanged.", nil, false},
+		{"The wrapper exposes TryLock and never blocks the caller.", nil, false},
+		{`The "Explore" menu lists every saved query in creation order.`, nil, false},
+		{"The helper 'try' returns false when the lock is held.", nil, false},
+		{"The client does not try to reconnect after a 4xx response.", []string{"try"}, false},
+		{"The client does not try to reconnect; we investigate later.", []string{"try"}, true},
+	}
+	for _, tt := range tests {
+		task := TaskNode{
+			TaskID:     "task-a",
+			TaskName:   "Implement task-a",
+			Goal:       tt.goal,
+			GoalAllow:  tt.allow,
+			Inputs:     []InputSpec{{Name: "in", Type: "string", Constraints: "none", Source: "caller"}},
+			Outputs:    []OutputSpec{{Name: "out", Type: "string", Constraints: "none", Destination: "return"}},
+			Acceptance: []string{"Output X is produced"},
+			DependsOn:  json.RawMessage(`{"status": "N/A", "reason": "First task"}`),
+		}
+		data, err := json.Marshal(&task)
+		if err != nil {
+			t.Fatalf("marshaling: %v", err)
+		}
+		result, err := Validate(data, ModeSingleTask)
+		if err != nil {
+			t.Fatalf("validation error: %v", err)
+		}
+		if hasFinding(result, "SCHEMA", SeverityError) {
+			t.Fatalf("%q: unexpected schema error: %+v", tt.goal, result.Errors)
+		}
+		if got := hasFinding(result, "V6", SeverityError); got != tt.want {
+			t.Errorf("%q (allow %v): V6 error = %v, want %v", tt.goal, tt.allow, got, tt.want)
+		}
+	}
+}
+
+func TestClassifyKind(t *testing.T) {
+	tests := []struct {
+		name, kind   string
+		want         string
+		wantExplicit bool
+	}{
+		{"Implement retry middleware", "", KindImplementation, false},
+		{"API client implementation", "", KindImplementation, false},
+		{"Build and wire the cache", "", KindImplementation, false},
+		{"Write the API guide", "", KindDoc, false},
+		{"Evaluate cache libraries", "", KindResearch, false},
+		{"Quarterly planning sync", "", "", false},
+		{"Add retry middleware", KindDoc, KindDoc, true},
+	}
+	for _, tt := range tests {
+		task := TaskNode{TaskName: tt.name, Kind: tt.kind}
+		got, explicit := task.ClassifyKind()
+		if got != tt.want || explicit != tt.wantExplicit {
+			t.Errorf("ClassifyKind(%q, kind=%q) = %q, %v; want %q, %v", tt.name, tt.kind, got, explicit, tt.want, tt.wantExplicit)
+		}
+	}
+
+	// V10 follows the kind; V6 relaxes to a warning only for declared
+	// research, not for a name that merely suggests it.
+	task := func(id, name, kind, goal string) TaskNode {
+		return TaskNode{
+			TaskID:     id,
+			TaskName:   name,
+			Kind:       kind,
+			Goal:       goal,
+			Inputs:     []InputSpec{{Name: "in", Type: "string", Constraints: "none", Source: "caller"}},
+			Outputs:    []OutputSpec{{Name: "out", Type: "string", Constraints: "none", Destination: "return"}},
+			Acceptance: []string{"Output X is produced"},
+			DependsOn:  json.RawMessage(`{"status": "N/A", "reason": "First task"}`),
+		}
+	}
+	graph := TaskGraph{
+		Version: SpecVersion010,
+		Tasks: []TaskNode{
+			task("api-client", "API client implementation", "", "The client returns decoded responses for every endpoint."),
+			task("write-notes", "Write release notes", KindDoc, "The release notes list every user-facing change."),
+			task("cache-spike", "Cache library spike", KindResearch, "We explore three cache libraries and record the winner."),
+			task("flaky-login", "Investigate the flaky login test", "", "Investigate why the login test fails and try a few fixes."),
+		},
+	}
+	data, err := json.Marshal(&graph)
+	if err != nil {
+		t.Fatalf("marshaling: %v", err)
+	}
+	result, err := Validate(data, ModeTaskGraph)
+	if err != nil {
+		t.Fatalf("validation error: %v", err)
+	}
+	if !hasFindingAt(result, "V10", SeverityWarning, "tasks[0].files_scope") {
+		t.Errorf("expected V10 for an implementation noun phrase, got: %+v", result.Errors)
+	}
+	if hasFindingAt(result, "V10", SeverityWarning, "tasks[1]") {
+		t.Error("a task declared as doc should not need files_scope")
+	}
+	if !hasFindingAt(result, "V6", SeverityWarning, "tasks[2].goal") || hasFindingAt(result, "V6", SeverityError, "tasks[2].goal") {
+		t.Errorf("expected only a V6 warning for the declared research task, got: %+v", result.Errors)
+	}
+	if !hasFindingAt(result, "V6", SeverityError, "tasks[3].goal") {
+		t.Errorf("expected a V6 error for a task whose name alone suggests research, got: %+v", result.Errors)
+	}
+}
+
+func TestSpikes(t *testing.T) {
+	spike := func(id string, estimate Level, acceptance ...string) TaskNode {
+		return TaskNode{
+			TaskID:     id,
+			TaskName:   "Cache library spike",
+			Kind:       KindSpike,
+			Goal:       "We explore three cache libraries and investigate their eviction behaviour.",
+			Inputs:     []InputSpec{{Name: "in", Type: "string", Constraints: "none", Source: "caller"}},
+			Outputs:    []OutputSpec{{Name: "out", Type: "string", Constraints: "none", Destination: "return"}},
+			Acceptance: acceptance,
+			DependsOn:  json.RawMessage(`{"status": "N/A", "reason": "First task"}`),
+			Estimate:   estimate,
+		}
+	}
+	graph := TaskGraph{
+		Version: SpecVersion010,
+		Tasks: []TaskNode{
+			spike("good", "small", "A decision record names the chosen library"),
+			spike("no-timebox", "unknown", "The team decides between the two libraries"),
+			spike("no-decision", "240", "Benchmarks for all three libraries are committed"),
+		},
+	}
+	data, err := json.Marshal(&graph)
+	if err != nil {
+		t.Fatalf("marshaling: %v", err)
+	}
+	result, err := Validate(data, ModeTaskGraph)
+	if err != nil {
+		t.Fatalf("validation error: %v", err)
+	}
+	if hasFinding(result, "V6", SeverityError) || hasFinding(result, "V6", SeverityWarning) {
+		t.Errorf("spike goals may explore, got: %+v", result.Errors)
+	}
+	if hasFindingAt(result, "SPIKE", SeverityError, "tasks[0]") {
+		t.Errorf("timeboxed spike with a decision should pass, got: %+v", result.Errors)
+	}
+	if !hasFindingAt(result, "SPIKE", SeverityError, "tasks[1].estimate") {
+		t.Error("expected SPIKE error for a spike without a timebox")
+	}
+	if !hasFindingAt(result, "SPIKE", SeverityError, "tasks[2].acceptance") {
+		t.Error("expected SPIKE error for a spike without a decision criterion")
+	}
+}
+
+func TestEstimateRule(t *testing.T) {
+	task := func(id string, priority, estimate Level) TaskNode {
+		return TaskNode{
+			TaskID:     id,
+			TaskName:   "Implement " + id,
+			Goal:       "The task produces output X.",
+			Inputs:     []InputSpec{{Name: "in", Type: "string", Constraints: "none", Source: "caller"}},
+			Outputs:    []OutputSpec{{Name: "out", Type: "string", Constraints: "none", Destination: "return"}},
+			Acceptance: []string{"Output X is produced"},
+			DependsOn:  json.RawMessage(`{"status": "N/A", "reason": "First task"}`),
+			FilesScope: json.RawMessage(`["x.go"]`),
+			Priority:   priority,
+			Estimate:   estimate,
+		}
+	}
+	graph := TaskGraph{
+		Version: SpecVersion010,
+		Tasks: []TaskNode{
+			task("critical-none", "critical", ""),
+			task("high-unknown", "high", "unknown"),
+			task("bd-one", "1", ""),
+			task("high-sized", "high", "small"),
+			task("low-none", "low", ""),
+		},
+	}
+	data, err := json.Marshal(&graph)
+	if err != nil {
+		t.Fatalf("marshaling: %v", err)
+	}
+
+	result, err := Validate(data, ModeTaskGraph)
+	if err != nil {
+		t.Fatalf("validation error: %v", err)
+	}
+	if hasFinding(result, "ESTIMATE", SeverityWarning) {
+		t.Error("ESTIMATE is opt-in and should not run by default")
+	}
+
+	result, err = ValidateWithOptions(data, ModeTaskGraph, Options{Rules: RuleConfig{Enabled: []string{"ESTIMATE"}}})
+	if err != nil {
+		t.Fatalf("validation error: %v", err)
+	}
+	for i, want := range []bool{true, true, true, false, false} {
+		if got := hasFindingAt(result, "ESTIMATE", SeverityWarning, fmt.Sprintf("tasks[%d].estimate", i)); got != want {
+			t.Errorf("tasks[%d]: ESTIMATE warning = %v, want %v", i, got, want)
+		}
+	}
+
+	strict, err := Profile(ProfileStrict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err = ValidateWithOptions(data, ModeTaskGraph, Options{Rules: strict})
+	if err != nil {
+		t.Fatalf("validation error: %v", err)
+	}
+	if !hasFindingAt(result, "ESTIMATE", SeverityError, "tasks[0].estimate") {
+		t.Error("the strict profile should enable ESTIMATE as an error")
+	}
+}
+
+func TestSchemaSuggestions(t *testing.T) {
+	data := []byte(`{
+		"task_id": "task-a",
+		"task_name": "Implement task-a",
+		"goal": "The task produces output X.",
+		"inputs": [{"name": "in", "type": "string", "constraints": "none", "source": "caller"}],
+		"outputs": [{"name": "out", "type": "string", "constraints": "none", "destination": "return"}],
+		"priority": "urgent",
+		"estimate": 99999,
+		"depends_on": {"status": "na", "reason": "First task"},
+		"kind": "other",
+		"bogus": true
+	}`)
+	result, err := Validate(data, ModeSingleTask)
+	if err != nil {
+		t.Fatalf("validation error: %v", err)
+	}
+	want := map[string]string{
+		"/priority/enum":           "Allowed: critical|high|medium|low|0|1|2|3|4",
+		"/estimate/maximum":        "at most 2400",
+		"required":                 "acceptance",
+		"/depends_on/status/const": `exactly "N/A"`,
+		"/depends_on/oneOf":        "Allowed: array of string | object {reason, status}",
+		"additionalProperties":     "Allowed: acceptance, constraints,",
+	}
+	for path, text := range want {
+		found := false
+		for _, e := range result.Errors {
+			if e.Rule == "SCHEMA" && e.Path == path {
+				found = true
+				if !strings.Contains(e.Suggestion, text) {
+					t.Errorf("%s: suggestion %q should contain %q", path, e.Suggestion, text)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("expected a SCHEMA error at %s, got: %+v", path, result.Errors)
+		}
+	}
+}
+
+func TestProvisionalSemanticFindings(t *testing.T) {
+	// One schema error used to hide every semantic finding.
+	data := []byte(`{
+		"version": "0.1.0",
+		"tasks": [{
+			"task_id": "task-a",
+			"task_name": "Implement task-a",
+			"goal": "We try to produce output X.",
+			"inputs": [{"name": "in", "type": "string", "constraints": "none", "source": "caller"}],
+			"outputs": [{"name": "out", "type": "string", "constraints": "none", "destination": "return"}],
+			"acceptance": ["Output X is produced"],
+			"depends_on": ["missing-task"]
+		}],
+		"bogus": 1
+	}`)
+	result, err := Validate(data, ModeTaskGraph)
+	if err != nil {
+		t.Fatalf("validation error: %v", err)
+	}
+	if !hasFinding(result, "SCHEMA", SeverityError) {
+		t.Fatalf("expected a schema error, got: %+v", result.Errors)
+	}
+	for _, rule := range []string{"V4", "V6"} {
+		if !hasFinding(result, rule, SeverityError) {
+			t.Errorf("expected %s to run despite the schema error", rule)
+		}
+	}
+	for _, e := range result.Errors {
+		if (e.Rule == "SCHEMA") == e.Provisional {
+			t.Errorf("%s finding has Provisional = %v", e.Rule, e.Provisional)
+		}
+	}
+	if result.Graph != nil {
+		t.Error("an invalid result should carry no Graph")
+	}
+
+	task := []byte(`{
+		"task_id": "task-a",
+		"task_name": "Implement task-a",
+		"goal": "We try to produce output X.",
+		"inputs": [{"name": "in", "type": "string", "constraints": "none", "source": "caller"}],
+		"outputs": [{"name": "out", "type": "string", "constraints": "none", "destination": "return"}],
+		"acceptance": ["Output X is produced"],
+		"priority": "urgent"
+	}`)
+	result, err = Validate(task, ModeSingleTask)
+	if err != nil {
+		t.Fatalf("validation error: %v", err)
+	}
+	if !hasFindingAt(result, "SCHEMA", SeverityError, "/priority") || !hasFinding(result, "V6", SeverityError) {
+		t.Errorf("expected the priority schema error and a provisional V6, got: %+v", result.Errors)
+	}
+
+	// A document that does not decode still stops after the schema tier.
+	result, err = Validate([]byte(`{"version": 7, "tasks": [{"task_id": "task-a"}]}`), ModeTaskGraph)
+	if err != nil {
+		t.Fatalf("validation error: %v", err)
+	}
+	for _, e := range result.Errors {
+		if e.Rule != "SCHEMA" {
+			t.Errorf("unexpected %s finding on an undecodable document", e.Rule)
+		}
+	}
+}
+
+func TestParseAlways(t *testing.T) {
+	data := []byte(`{
+		"version": "0.1.0",
+		"tasks": [{
+			"task_id": "task-a",
+			"task_name": "Implement task-a",
+			"goal": "Output X is produced.",
+			"inputs": [{"name": "in", "type": "string", "constraints": "none", "source": "caller"}],
+			"outputs": [{"name": "out", "type": "string", "constraints": "none", "destination": "return"}],
+			"acceptance": ["Given input A, the function returns X."],
+			"depends_on": ["missing-task"]
+		}]
+	}`)
+	result, err := ValidateWithOptions(data, ModeTaskGraph, Options{})
+	if err != nil {
+		t.Fatalf("validation error: %v", err)
+	}
+	if result.Valid || result.Graph != nil {
+		t.Fatalf("expected an invalid result without a Graph, got Valid=%v Graph=%v", result.Valid, result.Graph)
+	}
+
+	result, err = ValidateWithOptions(data, ModeTaskGraph, Options{ParseAlways: true})
+	if err != nil {
+		t.Fatalf("validation error: %v", err)
+	}
+	if result.Valid {
+		t.Error("ParseAlways must not make the result valid")
+	}
+	if result.Graph == nil || len(result.Graph.Tasks) != 1 || result.Graph.Tasks[0].TaskID != "task-a" {
+		t.Errorf("expected the parsed graph with ParseAlways, got %+v", result.Graph)
+	}
+
+	// Nothing to populate when the input does not decode.
+	result, err = ValidateWithOptions([]byte(`{"version": 7, "tasks": []}`), ModeTaskGraph, Options{ParseAlways: true})
+	if err != nil {
+		t.Fatalf("validation error: %v", err)
+	}
+	if result.Graph != nil {
+		t.Error("expected no Graph for an undecodable document")
+	}
+}
+
+func TestStyleRule(t *testing.T) {
+	task := func(id, goal, notes string, acceptance ...string) TaskNode {
+		return TaskNode{
+			TaskID:     id,
+			TaskName:   "Implement " + id,
+			Goal:       goal,
+			Inputs:     []InputSpec{{Name: "in", Type: "string", Constraints: "none", Source: "caller"}},
+			Outputs:    []OutputSpec{{Name: "out", Type: "string", Constraints: "none", Destination: "return"}},
+			Acceptance: acceptance,
+			DependsOn:  json.RawMessage(`{"status": "N/A", "reason": "First task"}`),
+			FilesScope: json.RawMessage(`["x.go"]`),
+			Notes:      notes,
+		}
+	}
+	graph := TaskGraph{
+		Version: SpecVersion010,
+		Tasks: []TaskNode{
+			task("passive", "The cache entry is invalidated on every write.", "", "Given a write to key K, Get(K) misses."),
+			task("long", "Store.Put writes the value.", "", "Given key K "+strings.Repeat("and value V ", 20)+"Put returns nil."),
+			task("repeat", "Store.Put writes the value.", "Background: store.put writes the value!", "Given key K, Put returns nil."),
+			task("clean", "Store.Get returns the stored value.", "Reads never block writers.", "Given key K holds V, Get(K) returns V."),
+		},
+	}
+	data, err := json.Marshal(&graph)
+	if err != nil {
+		t.Fatalf("marshaling: %v", err)
+	}
+
+	result, err := Validate(data, ModeTaskGraph)
+	if err != nil {
+		t.Fatalf("validation error: %v", err)
+	}
+	if hasFinding(result, "STYLE", SeverityInfo) {
+		t.Error("STYLE is opt-in and should not run by default")
+	}
+
+	result, err = ValidateWithOptions(data, ModeTaskGraph, Options{Rules: RuleConfig{Enabled: []string{"STYLE"}}})
+	if err != nil {
+		t.Fatalf("validation error: %v", err)
+	}
+	for _, path := range []string{"tasks[0].goal", "tasks[1].acceptance[0]", "tasks[2].notes"} {
+		if !hasFindingAt(result, "STYLE", SeverityInfo, path) {
+			t.Errorf("expected a STYLE info at %s, got: %+v", path, result.Errors)
+		}
+	}
+	if hasFindingAt(result, "STYLE", SeverityInfo, "tasks[3]") {
+		t.Errorf("unexpected STYLE finding on the clean task: %+v", result.Errors)
+	}
+
+	// Strict turns STYLE on but does not promote it.
+	strict, err := Profile(ProfileStrict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err = ValidateWithOptions(data, ModeTaskGraph, Options{Rules: strict})
+	if err != nil {
+		t.Fatalf("validation error: %v", err)
+	}
+	if !hasFindingAt(result, "STYLE", SeverityInfo, "tasks[0].goal") {
+		t.Errorf("expected STYLE to stay INFO under the strict profile, got: %+v", result.Errors)
+	}
+}
+
+func TestNonGoalsRule(t *testing.T) {
+	task := func(id string, estimate Level, criteria int, nonGoals ...string) TaskNode {
+		acceptance := make([]string, criteria)
+		for i := range acceptance {
+			acceptance[i] = fmt.Sprintf("Given input %d, the function returns %d.", i, i)
+		}
+		return TaskNode{
+			TaskID:     id,
+			TaskName:   "Implement " + id,
+			Goal:       "The task produces output X.",
+			Inputs:     []InputSpec{{Name: "in", Type: "string", Constraints: "none", Source: "caller"}},
+			Outputs:    []OutputSpec{{Name: "out", Type: "string", Constraints: "none", Destination: "return"}},
+			Acceptance: acceptance,
+			DependsOn:  json.RawMessage(`{"status": "N/A", "reason": "First task"}`),
+			FilesScope: json.RawMessage(`["x.go"]`),
+			Estimate:   estimate,
+			NonGoals:   nonGoals,
+		}
+	}
+	graph := TaskGraph{
+		Version: SpecVersion010,
+		Tasks: []TaskNode{
+			task("large", "large", 1),
+			task("long-minutes", "600", 1),
+			task("many-criteria", "small", 6),
+			task("fenced", "large", 6, "No caching."),
+			task("small", "small", 3),
+		},
+	}
+	data, err := json.Marshal(&graph)
+	if err != nil {
+		t.Fatalf("marshaling: %v", err)
+	}
+
+	result, err := Validate(data, ModeTaskGraph)
+	if err != nil {
+		t.Fatalf("validation error: %v", err)
+	}
+	if hasFinding(result, "NONGOALS", SeverityWarning) {
+		t.Error("NONGOALS is opt-in and should not run by default")
+	}
+
+	rules := RuleConfig{Enabled: []string{"NONGOALS"}}
+	result, err = ValidateWithOptions(data, ModeTaskGraph, Options{Rules: rules})
+	if err != nil {
+		t.Fatalf("validation error: %v", err)
+	}
+	for i, want := range []bool{true, true, true, false, false} {
+		if got := hasFindingAt(result, "NONGOALS", SeverityWarning, fmt.Sprintf("tasks[%d].non_goals", i)); got != want {
+			t.Errorf("tasks[%d]: NONGOALS warning = %v, want %v", i, got, want)
+		}
+	}
+
+	// A lower limit catches the task with three criteria.
+	rules = rules.Merge(RuleConfig{Limits: map[string]int{"non_goals_acceptance": 3}})
+	result, err = ValidateWithOptions(data, ModeTaskGraph, Options{Rules: rules})
+	if err != nil {
+		t.Fatalf("validation error: %v", err)
+	}
+	if !hasFindingAt(result, "NONGOALS", SeverityWarning, "tasks[4].non_goals") {
+		t.Errorf("expected NONGOALS on tasks[4] with limit 3, got: %+v", result.Errors)
+	}
+}
+
+func TestOutputCoverage(t *testing.T) {
+	task := TaskNode{
+		TaskID:   "task-a",
+		TaskName: "Implement task-a",
+		Goal:     "Search returns ranked results.",
+		Inputs:   []InputSpec{{Name: "query", Type: "string", Constraints: "non-empty", Source: "caller"}},
+		Outputs: []OutputSpec{
+			{Name: "ranked_results", Type: "list<Result>", Constraints: "none", Destination: "return"},
+			{Name: "total", Type: "int", Constraints: ">= 0", Destination: "return"},
+			{Name: "report", Type: "string", Constraints: "none", Destination: "stdout"},
+			{Name: "audit_entry", Type: "string", Constraints: "none", Destination: "audit log"},
+		},
+		Acceptance: []string{
+			"Search('go') returns the ranked results in descending score order",
+			"CountTotal('go') == 3",
+			"The summary is printed to stdout",
+		},
+		DependsOn:  json.RawMessage(`{"status": "N/A", "reason": "First task"}`),
+		FilesScope: json.RawMessage(`["search.go"]`),
+	}
+	data, err := json.Marshal(&task)
+	if err != nil {
+		t.Fatalf("marshaling: %v", err)
+	}
+	result, err := Validate(data, ModeSingleTask)
+	if err != nil {
+		t.Fatalf("validation error: %v", err)
+	}
+	for j, want := range []bool{false, false, false, true} {
+		if got := hasFindingAt(result, "OUTPUTS", SeverityWarning, fmt.Sprintf("outputs[%d]", j)); got != want {
+			t.Errorf("outputs[%d]: OUTPUTS warning = %v, want %v", j, got, want)
+		}
+	}
+}
+
+func TestFanLimits(t *testing.T) {
+	task := func(id string, deps ...string) TaskNode {
+		dependsOn := json.RawMessage(`{"status": "N/A", "reason": "First task"}`)
+		if len(deps) > 0 {
+			dependsOn, _ = json.Marshal(deps)
+		}
+		return TaskNode{
+			TaskID:     id,
+			TaskName:   "Implement " + id,
+			Goal:       "The task produces output X.",
+			Inputs:     []InputSpec{{Name: "in", Type: "string", Constraints: "none", Source: "caller"}},
+			Outputs:    []OutputSpec{{Name: "out", Type: "string", Constraints: "none", Destination: "return"}},
+			Acceptance: []string{"Given in, out is X"},
+			DependsOn:  dependsOn,
+			FilesScope: json.RawMessage(`["x.go"]`),
+		}
+	}
+	// One hub feeds seven branches, and one join waits on all of them.
+	tasks := []TaskNode{task("hub")}
+	var branches []string
+	for i := range 7 {
+		id := fmt.Sprintf("branch-%d", i)
+		branches = append(branches, id)
+		tasks = append(tasks, task(id, "hub"))
+	}
+	tasks = append(tasks, task("join", branches...))
+	data, err := json.Marshal(&TaskGraph{Version: SpecVersion010, Tasks: tasks})
+	if err != nil {
+		t.Fatalf("marshaling: %v", err)
+	}
+
+	result, err := Validate(data, ModeTaskGraph)
+	if err != nil {
+		t.Fatalf("validation error: %v", err)
+	}
+	if !hasFindingAt(result, "FAN", SeverityWarning, "tasks[0]") {
+		t.Errorf("expected a FAN warning for the hub's dependents, got: %+v", result.Errors)
+	}
+	if !hasFindingAt(result, "FAN", SeverityWarning, "tasks[8].depends_on") {
+		t.Errorf("expected a FAN warning for the join's dependencies, got: %+v", result.Errors)
+	}
+
+	rules := RuleConfig{Limits: map[string]int{"max_depends_on": 7, "max_dependents": 7}}
+	result, err = ValidateWithOptions(data, ModeTaskGraph, Options{Rules: rules})
+	if err != nil {
+		t.Fatalf("validation error: %v", err)
+	}
+	if hasFinding(result, "FAN", SeverityWarning) {
+		t.Errorf("expected no FAN warnings at limit 7, got: %+v", result.Errors)
+	}
+}
+
+func TestDepthLimit(t *testing.T) {
+	task := func(id string, deps ...string) TaskNode {
+		dependsOn := json.RawMessage(`{"status": "N/A", "reason": "First task"}`)
+		if len(deps) > 0 {
+			dependsOn, _ = json.Marshal(deps)
+		}
+		return TaskNode{
+			TaskID:     id,
+			TaskName:   "Implement " + id,
+			Goal:       "The task produces output X.",
+			Inputs:     []InputSpec{{Name: "in", Type: "string", Constraints: "none", Source: "caller"}},
+			Outputs:    []OutputSpec{{Name: "out", Type: "string", Constraints: "none", Destination: "return"}},
+			Acceptance: []string{"Given in, out is X"},
+			DependsOn:  dependsOn,
+			FilesScope: json.RawMessage(`["x.go"]`),
+		}
+	}
+	// A chain of 11 tasks, with a side task hanging off the third.
+	tasks := []TaskNode{task("step-0")}
+	for i := 1; i <= 10; i++ {
+		tasks = append(tasks, task(fmt.Sprintf("step-%d", i), fmt.Sprintf("step-%d", i-1)))
+	}
+	tasks = append(tasks, task("side", "step-2"))
+	data, err := json.Marshal(&TaskGraph{Version: SpecVersion010, Tasks: tasks})
+	if err != nil {
+		t.Fatalf("marshaling: %v", err)
+	}
+
+	result, err := Validate(data, ModeTaskGraph)
+	if err != nil {
+		t.Fatalf("validation error: %v", err)
+	}
+	var found bool
+	for _, e := range result.Errors {
+		if e.Rule == "DEPTH" {
+			found = true
+			if !strings.HasPrefix(e.Context, "step-0 -> step-1") || !strings.HasSuffix(e.Context, "-> step-10") {
+				t.Errorf("expected the chain step-0 ... step-10, got %q", e.Context)
+			}
+		}
+	}
+	if !found {
+		t.Fatalf("expected a DEPTH warning for 11 chained tasks, got: %+v", result.Errors)
+	}
+
+	result, err = ValidateWithOptions(data, ModeTaskGraph, Options{Rules: RuleConfig{Limits: map[string]int{"max_depth": 11}}})
+	if err != nil {
+		t.Fatalf("validation error: %v", err)
+	}
+	if hasFinding(result, "DEPTH", SeverityWarning) {
+		t.Errorf("expected no DEPTH warning at limit 11, got: %+v", result.Errors)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	lossless := `{
+		"tasks": [{"task_id": "a", "task_name": "Implement a", "goal": "A returns 1.",
+		  "inputs": [], "outputs": [], "acceptance": ["A() == 1.0"],
+		  "estimate": 30, "depends_on": {"reason": "First", "status": "N/A"}}],
+		"version": "0.1.0"
+	}`
+	diffs, err := RoundTrip([]byte(lossless), ModeTaskGraph)
+	if err != nil {
+		t.Fatalf("RoundTrip error: %v", err)
+	}
+	if len(diffs) != 0 {
+		t.Errorf("expected key order and layout to be ignored, got %+v", diffs)
+	}
+
+	lossy := `{"version": "0.1.0", "owner": "ana", "tasks": [{"task_id": "a", "task_name": "Implement a",
+		"goal": "A returns 1.", "inputs": [], "outputs": [], "acceptance": ["A() == 1"],
+		"priority": "3", "estimate": 30}], "milestones": [{"name": "m", "task_ids": ["a"], "owner": "ana"}]}`
+	diffs, err = RoundTrip([]byte(lossy), ModeTaskGraph)
+	if err != nil {
+		t.Fatalf("RoundTrip error: %v", err)
+	}
+	want := []Difference{
+		{Kind: DiffDropped, Path: "milestones[0].owner", Before: `"ana"`},
+		{Kind: DiffChanged, Path: "tasks[0].priority", Before: `"3"`, After: "3"},
+	}
+	if !reflect.DeepEqual(diffs, want) {
+		t.Errorf("diffs = %+v\nwant %+v", diffs, want)
+	}
+
+	if _, err := RoundTrip([]byte(`{"tasks": 5}`), ModeTaskGraph); err == nil {
+		t.Error("expected an error for a document that does not decode")
+	}
+}
+
+func TestExtraFields(t *testing.T) {
+	doc := `{"version": "0.1.0", "x_origin": {"tool": "planner", "rev": 7}, "tasks": [
+		{"task_id": "a", "task_name": "Implement a", "goal": "A returns 1.", "inputs": [],
+		 "outputs": [], "acceptance": ["A() < 2"], "owner": "ana", "Task_Name": "dup"}]}`
+	var graph TaskGraph
+	if err := json.Unmarshal([]byte(doc), &graph); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if len(graph.Extra) != 1 || string(graph.Extra["x_origin"]) != `{"tool": "planner", "rev": 7}` {
+		t.Errorf("graph Extra = %v", graph.Extra)
+	}
+	// Task_Name matches task_name the way encoding/json does, so it is
+	// not kept as a second field.
+	if task := graph.Tasks[0]; len(task.Extra) != 1 || string(task.Extra["owner"]) != `"ana"` {
+		t.Errorf("task Extra = %v", task.Extra)
+	}
+
+	out, err := json.Marshal(&graph)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	for _, want := range []string{`"x_origin":{"tool":"planner","rev":7}`, `"owner":"ana"`, `"A() \u003c 2"`} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("encoding %s lacks %s", out, want)
+		}
+	}
+	if strings.Contains(string(out), "Task_Name") {
+		t.Errorf("case-variant key was kept: %s", out)
+	}
+
+	dropped, err := DroppedFields([]byte(doc), ModeTaskGraph)
+	if err != nil || len(dropped) != 1 || dropped[0] != "tasks[0].Task_Name" {
+		t.Errorf("DroppedFields = %v, %v; want only the case-variant key", dropped, err)
+	}
+	if dropped, _ := DroppedFields([]byte(`{"version": "0.1.0", "tasks": [], "milestones": []}`), ModeTaskGraph); len(dropped) != 0 {
+		t.Errorf("empty values the model omits are not losses, got %v", dropped)
+	}
+}
+
+func TestExtensionFields(t *testing.T) {
+	graph := `{"version": "0.1.0", "x_team": "payments", "tasks": [
+		{"task_id": "a", "task_name": "Implement a", "goal": "A returns 1.", "inputs": [],
+		 "outputs": [], "acceptance": ["A() == 1"], "x_jira": {"key": "PAY-1"}}]}`
+	result, err := Validate([]byte(graph), ModeTaskGraph)
+	if err != nil {
+		t.Fatalf("Validate error: %v", err)
+	}
+	if !result.Valid {
+		t.Errorf("x_ fields should pass the schema, got: %+v", result.Errors)
+	}
+	if ext := result.Graph.Extensions(); string(ext["x_team"]) != `"payments"` {
+		t.Errorf("graph Extensions = %v", ext)
+	}
+	if ext := result.Graph.Tasks[0].Extensions(); string(ext["x_jira"]) != `{"key": "PAY-1"}` {
+		t.Errorf("task Extensions = %v", ext)
+	}
+
+	result, err = Validate([]byte(strings.Replace(graph, `"x_team"`, `"team"`, 1)), ModeTaskGraph)
+	if err != nil {
+		t.Fatalf("Validate error: %v", err)
+	}
+	if result.Valid || !hasFinding(result, "SCHEMA", SeverityError) {
+		t.Errorf("a field without the x_ prefix should fail the schema, got: %+v", result.Errors)
+	}
+
+	task := &TaskNode{Extra: map[string]json.RawMessage{"x_a": json.RawMessage(`1`), "b": json.RawMessage(`2`)}}
+	if ext := task.Extensions(); len(ext) != 1 || ext["x_a"] == nil {
+		t.Errorf("Extensions should return only x_ fields, got %v", ext)
+	}
+}
+
+func TestIDConvention(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "taskval.json")
+	config := `{"task_ids": {"max_length": 20, "forbidden": ["misc", "task-[0-9]*"], "milestones": {"Auth": "auth-*"}}}`
+	if err := os.WriteFile(configPath, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+
+	graph := &TaskGraph{
+		Milestones: []Milestone{{Name: "Auth", TaskIDs: []string{"auth-login", "session-store"}}},
+		Tasks: []TaskNode{
+			{TaskID: "auth-login"},
+			{TaskID: "session-store"},
+			{TaskID: "task-12"},
+			{TaskID: "misc"},
+			{TaskID: "billing-invoice-renderer"},
+		},
+	}
+	var got []string
+	for _, e := range LintIDs(graph, *cfg.TaskIDs) {
+		if e.Rule != "IDS" || e.Severity != SeverityError {
+			t.Errorf("unexpected finding: %+v", e)
+		}
+		got = append(got, e.Path)
+	}
+	want := []string{"tasks[1].task_id", "tasks[2].task_id", "tasks[3].task_id", "tasks[4].task_id"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("IDS paths = %v, want %v", got, want)
+	}
+
+	data, err := json.Marshal(&TaskGraph{Version: SpecVersion010, Tasks: []TaskNode{{
+		TaskID:     "misc",
+		TaskName:   "Implement misc fixes",
+		Goal:       "Fix() returns nil for every known input.",
+		Inputs:     []InputSpec{},
+		Outputs:    []OutputSpec{},
+		Acceptance: []string{"Fix() == nil"},
+	}}})
+	if err != nil {
+		t.Fatalf("marshaling: %v", err)
+	}
+	result, err := ValidateWithOptions(data, ModeTaskGraph, Options{IDConvention: cfg.TaskIDs})
+	if err != nil {
+		t.Fatalf("validation error: %v", err)
+	}
+	if !hasFindingAt(result, "IDS", SeverityError, "tasks[0].task_id") {
+		t.Errorf("expected IDS error from the semantic tier, got: %+v", result.Errors)
+	}
+	if result, _ := Validate(data, ModeTaskGraph); hasFinding(result, "IDS", SeverityError) {
+		t.Error("IDS should only run with a convention configured")
+	}
+
+	for name, bad := range map[string]string{
+		"pattern":   `{"task_ids": {"forbidden": ["task-["]}}`,
+		"milestone": `{"task_ids": {"milestones": {"Auth": "[auth"}}}`,
+		"length":    `{"task_ids": {"max_length": -1}}`,
+	} {
+		if err := os.WriteFile(configPath, []byte(bad), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadConfig(configPath); err == nil {
+			t.Errorf("%s: expected LoadConfig error", name)
+		}
+	}
+}
+
+func TestRuleDone(t *testing.T) {
+	data := []byte(`{
+		"version": "0.1.0",
+		"tasks": [
+			{"task_id": "a", "task_name": "Write the parser", "goal": "Parse the config file.", "inputs": [], "outputs": [], "acceptance": ["Parse returns the config"], "depends_on": ["b"]},
+			{"task_id": "b", "task_name": "Write the lexer", "goal": "Lex the config file.", "inputs": [], "outputs": [], "acceptance": ["Lex returns the tokens"], "depends_on": ["a"]}
+		]
+	}`)
+	findings := make(map[string]int)
+	var order []string
+	opts := Options{RuleDone: func(rule string, n int, elapsed time.Duration) {
+		if elapsed < 0 {
+			t.Errorf("%s: negative duration %v", rule, elapsed)
+		}
+		findings[rule] += n
+		order = append(order, rule)
+	}}
+	if _, err := ValidateWithOptions(data, ModeTaskGraph, opts); err != nil {
+		t.Fatalf("ValidateWithOptions: %v", err)
+	}
+	if len(order) == 0 || order[0] != "SCHEMA" {
+		t.Fatalf("rules reported %v, want SCHEMA first", order)
+	}
+	if !slices.Contains(order, "IDS") || !slices.Contains(order, "V14") {
+		t.Errorf("every built-in check should be reported, got %v", order)
+	}
+	if findings["V5"] == 0 {
+		t.Errorf("V5 should report the a <-> b cycle, got counts %v", findings)
+	}
+}
+
+func TestNewValidator(t *testing.T) {
+	// A schema-valid task whose goal contains a weasel word (V11 warning)
+	// and whose files_scope uses a backslash (PATHS warning).
+	data := []byte(`{
+		"task_id": "task-a", "task_name": "Implement task A",
+		"goal": "Task A produces a placeholder output X.",
+		"inputs": [{"name": "in", "type": "string", "constraints": "none", "source": "caller"}],
+		"outputs": [{"name": "out", "type": "string", "constraints": "none", "destination": "return"}],
+		"acceptance": ["Output X is produced"],
+		"depends_on": {"status": "N/A", "reason": "First task"},
+		"constraints": ["No new dependencies"],
+		"files_scope": ["a.go"]
+	}`)
+
+	v, err := New(WithMode(ModeSingleTask))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	result, err := v.Validate(data)
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if !result.Valid || !result.HasRule("V11") || result.HasRule("V5") {
+		t.Fatalf("standard: valid=%v findings=%v, want valid with a V11 warning", result.Valid, result.Errors)
+	}
+	warnings := result.FilterBySeverity(SeverityWarning)
+	if len(warnings) != result.Stats.WarningCount || !slices.ContainsFunc(warnings, func(e ValidationError) bool { return e.Rule == "V11" }) {
+		t.Errorf("FilterBySeverity(WARNING) = %v, want every warning including V11", warnings)
+	}
+	if got := result.FilterBySeverity(SeverityError); len(got) != 0 {
+		t.Errorf("FilterBySeverity(ERROR) = %v, want none", got)
+	}
+	if got := result.ByTask()[0]; len(got) != len(result.ForTask(0)) || len(got) == 0 {
+		t.Errorf("ByTask()[0] = %v, want the same findings as ForTask(0)", got)
+	}
+
+	// Profiles and rule configs are merged in order.
+	v, err = New(WithMode(ModeSingleTask), WithProfile(ProfileMinimal))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if result, _ := v.Validate(data); result.HasRule("V11") {
+		t.Error("minimal profile should drop the V11 heuristic")
+	}
+	v, err = New(WithMode(ModeSingleTask), WithProfile(ProfileStandard), WithRules(RuleConfig{Severity: map[string]Severity{"V11": SeverityError}}))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if result, _ := v.Validate(data); result.Valid {
+		t.Error("V11 raised to ERROR should fail validation")
+	}
+
+	if _, err := New(WithProfile("lenient")); err == nil {
+		t.Error("expected an error for an unknown profile")
+	}
+}
+
+func TestByTask(t *testing.T) {
+	result := &ValidationResult{Errors: []ValidationError{
+		{Rule: "V5", Path: "tasks"},
+		{Rule: "V2", Path: "tasks[1].task_id"},
+		{Rule: "V6", Path: "tasks[10].goal"},
+		{Rule: "V8", Path: "tasks[1]"},
+		{Rule: "V1", Path: "milestones[0]"},
+	}}
+	got := result.ByTask()
+	if len(got) != 2 || len(got[1]) != 2 || len(got[10]) != 1 {
+		t.Errorf("ByTask() = %v, want tasks 1 (two findings) and 10", got)
+	}
+}
+
+func TestNestingLimit(t *testing.T) {
+	nested := func(n int) []byte {
+		return []byte(`{"version": "0.1.0", "tasks": [], "x_deep": ` + strings.Repeat("[", n) + strings.Repeat("]", n) + `, "x_text": "[[[[{{{{"}`)
+	}
+	// The root object adds a level; brackets inside strings do not count.
+	if depth, _ := nestingDepth(nested(MaxNestingDepth-1), MaxNestingDepth); depth != MaxNestingDepth {
+		t.Errorf("nestingDepth = %d, want %d", depth, MaxNestingDepth)
+	}
+	for _, n := range []int{MaxNestingDepth, 100000} {
+		result, err := Validate(nested(n), ModeTaskGraph)
+		if err != nil {
+			t.Fatalf("depth %d: unexpected error: %v", n, err)
+		}
+		if result.Valid || len(result.Errors) != 1 || !strings.Contains(result.Errors[0].Message, "levels deep") {
+			t.Errorf("depth %d: findings = %v, want one nesting error", n, result.Errors)
+		}
+	}
+	if result, _ := ValidateDesign(nested(1000)); result == nil || result.Valid {
+		t.Error("ValidateDesign should reject deep nesting too")
+	}
+}
+
+func TestTrailingContent(t *testing.T) {
+	doc := `{"version": "0.1.0", "tasks": []}`
+	for _, tc := range []struct {
+		name, input, want string
+	}{
+		{"garbage", doc + "\ngarbage\n", "not JSON"},
+		{"second document", doc + doc, "another JSON value"},
+		{"stray brace", doc + "}", "not JSON"},
+	} {
+		result, err := Validate([]byte(tc.input), ModeTaskGraph)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if result.Valid || len(result.Errors) != 1 || !strings.Contains(result.Errors[0].Message, tc.want) {
+			t.Errorf("%s: findings = %v, want one error mentioning %q", tc.name, result.Errors, tc.want)
+		}
+	}
+	if result, _ := Validate([]byte(doc+"\n\t \n"), ModeTaskGraph); result == nil {
+		t.Error("trailing whitespace: no result")
+	} else {
+		for _, e := range result.Errors {
+			if e.Path == "$" {
+				t.Errorf("trailing whitespace should be accepted, got %v", e)
+			}
+		}
+	}
+	if result, _ := ValidateDesign([]byte(doc + "x")); result == nil || result.Valid {
+		t.Error("ValidateDesign should reject trailing content too")
+	}
+}
+
+func TestTaskTemplates(t *testing.T) {
+	doc := func(tasks string) []byte {
+		return []byte(`{"version": "0.1.0",
+		  "task_templates": {"crud-endpoint": {
+		    "task_name": "Add the {{resource}} endpoint",
+		    "goal": "Serve GET /{{resource}} from internal/api/{{resource}}.go returning a JSON list.",
+		    "inputs": [],
+		    "outputs": [{"name": "{{resource}} handler", "type": "file", "constraints": "N/A", "destination": "internal/api/{{resource}}.go"}],
+		    "acceptance": ["GET /{{resource}} returns 200 with a JSON array"]
+		  }},
+		  "tasks": [` + tasks + `]}`)
+	}
+
+	result, err := Validate(doc(`
+		{"task_id": "users", "from_template": "crud-endpoint", "params": {"resource": "users"}},
+		{"task_id": "orders", "from_template": "crud-endpoint", "params": {"resource": "orders"}, "depends_on": ["users"], "goal": "Serve GET /orders from internal/api/orders.go, newest first."}`), ModeTaskGraph)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Valid {
+		t.Fatalf("expanded graph should validate: %v", result.Errors)
+	}
+	users, orders := result.Graph.Tasks[0], result.Graph.Tasks[1]
+	if users.TaskName != "Add the users endpoint" || users.Acceptance[0] != "GET /users returns 200 with a JSON array" {
+		t.Errorf("users task = %q, %q", users.TaskName, users.Acceptance)
+	}
+	// The task's own fields override the template's.
+	if orders.Goal != "Serve GET /orders from internal/api/orders.go, newest first." || !strings.Contains(string(orders.DependsOn), "users") {
+		t.Errorf("orders task = %q, %s", orders.Goal, orders.DependsOn)
+	}
+
+	plain := []byte(`{"version": "0.1.0", "tasks": [{"task_id": "a"}]}`)
+	if UsesTaskTemplates(plain) || !UsesTaskTemplates(doc(`{"task_id": "a"}`)) {
+		t.Error("UsesTaskTemplates should detect the task_templates section only where present")
+	}
+	if expanded, err := ExpandTaskTemplates(plain); err != nil || string(expanded) != string(plain) {
+		t.Errorf("ExpandTaskTemplates should leave a plain graph as is, got %s, %v", expanded, err)
+	}
+	if _, err := ExpandTaskTemplates(doc(`{"task_id": "a", "from_template": "nope"}`)); err == nil || !strings.Contains(err.Error(), "tasks[0].from_template") {
+		t.Errorf("ExpandTaskTemplates error = %v", err)
+	}
+
+	for _, tc := range []struct {
+		name, task, path, want string
+	}{
+		{"unknown template", `{"task_id": "a", "from_template": "crud"}`, "tasks[0].from_template", "crud-endpoint"},
+		{"missing param", `{"task_id": "a", "from_template": "crud-endpoint"}`, "tasks[0].params", "needs params resource"},
+		{"unused param", `{"task_id": "a", "from_template": "crud-endpoint", "params": {"resource": "a", "resorce": "b"}}`, "tasks[0].params", "{{resorce}}"},
+		{"object param", `{"task_id": "a", "from_template": "crud-endpoint", "params": {"resource": {}}}`, "tasks[0].params.resource", "string, number, or boolean"},
+	} {
+		result, err := Validate(doc(tc.task), ModeTaskGraph)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		found := false
+		for _, e := range result.Errors {
+			if e.Rule == "TEMPLATE" && e.Path == tc.path {
+				found = strings.Contains(e.Message+e.Suggestion, tc.want)
+			}
+		}
+		if result.Valid || !found {
+			t.Errorf("%s: findings = %v, want a TEMPLATE error at %s mentioning %q", tc.name, result.Errors, tc.path, tc.want)
+		}
+	}
+}
+
+// FuzzValidate checks that no input makes validation panic, in either
+// mode, and that a valid result carries its graph.
+func FuzzValidate(f *testing.F) {
+	f.Add([]byte(`{"version": "0.1.0", "tasks": [{"task_id": "a", "task_name": "Write the parser", "goal": "Parse the config file.", "inputs": [], "outputs": [], "acceptance": ["Parse returns the config"], "depends_on": ["b"]}, {"task_id": "b", "task_name": "Lex", "goal": "Lex it.", "inputs": [], "outputs": [], "acceptance": ["Lex returns tokens"], "depends_on": ["a"]}]}`))
+	f.Add([]byte(`{"task_id": "a", "depends_on": {"status": "N/A"}, "files_scope": "x", "effects": "none", "due": "2026-13-45"}`))
+	f.Add([]byte(`{"version": "0.2.0", "milestones": [{"name": "m", "task_ids": ["a", "a"]}], "tasks": [{"task_id": "a", "priority": "urgent", "estimate": "huge"}]}`))
+	f.Add([]byte(`[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[`))
+	f.Add([]byte(`{"version": "0.1.0", "tasks": [{"task_id": "\u0000", "goal": "` + strings.Repeat("x", 5000) + `"}]}`))
+	f.Add([]byte(`{"version": "0.1.0", "tasks": [{"task_id": "a", "acceptance": ["x"], "verification": [{"criterion": -1, "command": "true"}]}]}`))
+	f.Add([]byte(``))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		for _, mode := range []Mode{ModeSingleTask, ModeTaskGraph} {
+			result, err := Validate(data, mode)
+			if err != nil {
+				continue
+			}
+			if result.Valid && result.Graph == nil {
+				t.Errorf("mode %d: valid result without a graph", mode)
+			}
+		}
+	})
+}
+
+// FuzzParseHelpers checks that the Parse* helpers fail cleanly on any
+// value a decoded task can hold.
+func FuzzParseHelpers(f *testing.F) {
+	f.Add([]byte(`{"depends_on": ["a", "b"], "files_scope": ["a.go"], "constraints": ["none"], "effects": [{"kind": "network", "target": "x"}], "due": "2026-01-02"}`))
+	f.Add([]byte(`{"depends_on": {"status": "N/A", "reason": "first"}, "files_scope": {"status": "N/A"}, "constraints": {}, "effects": "none", "not_before": "2026-01-02T15:04:05Z"}`))
+	f.Add([]byte(`{"depends_on": 7, "files_scope": null, "constraints": [1, 2], "effects": {"status": "N/A"}, "due": "yesterday"}`))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var task TaskNode
+		if json.Unmarshal(data, &task) != nil {
+			return
+		}
+		task.ParseDependsOn()
+		task.ParseFilesScope()
+		task.ParseConstraints()
+		task.ParseEffects()
+		ParseDate(task.Due)
+		ParseDate(task.NotBefore)
+		task.ClassifyKind()
+	})
+}
+
+func TestDuplicateKeys(t *testing.T) {
+	got := duplicateKeys([]byte(`{"a": 1, "b": {"": 1, "": [{"x": 1, "x": 2}]}, "c": [{"k": "k", "goal": 1}, {"goal": {"goal": 1}, "goal": 2}], "a": "again"}`))
+	want := [][2]string{{"", "b."}, {"x", "b.[0].x"}, {"goal", "c[1].goal"}, {"a", "a"}}
+	if !slices.Equal(got, want) {
+		t.Errorf("duplicateKeys = %v, want %v", got, want)
+	}
+	if got := duplicateKeys([]byte(`{"a": 1, "a"`)); len(got) != 1 {
+		t.Errorf("truncated input: duplicateKeys = %v, want the one duplicate before the cut", got)
+	}
+
+	data := []byte(`{"version": "0.1.0", "tasks": [
+		{"task_id": "a", "task_name": "Write the parser", "goal": "Parse the config file.", "goal": "Explore parsing.", "inputs": [], "outputs": [], "acceptance": ["Parse returns the config"]}
+	]}`)
+	result, err := Validate(data, ModeTaskGraph)
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if result.Valid || !result.HasRule("DUPKEY") {
+		t.Fatalf("findings = %v, want a DUPKEY error", result.Errors)
+	}
+	for _, e := range result.Errors {
+		if e.Rule == "DUPKEY" && e.Path != "tasks[0].goal" {
+			t.Errorf("DUPKEY path = %q, want tasks[0].goal", e.Path)
+		}
+	}
+}
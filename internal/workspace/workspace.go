@@ -0,0 +1,231 @@
+// Package workspace validates a directory tree of task graph files as one
+// project: task_ids must be unique across files and depends_on references
+// may point at tasks defined in sibling files.
+package workspace
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// Result is the outcome of validating a workspace.
+type Result struct {
+	// Files lists the graph files that were validated, relative to the root.
+	Files []string `json:"files"`
+
+	// Report holds every finding. Paths are prefixed with the file they
+	// belong to, e.g. "api.json:tasks[2].goal".
+	Report *validator.ValidationResult `json:"report"`
+
+	// Graph is the merged graph with each file's defaults applied to its
+	// own tasks. It is set only when the workspace is valid.
+	Graph *validator.TaskGraph `json:"-"`
+}
+
+// origin records where an element of the merged graph came from.
+type origin struct {
+	file  string
+	index int
+}
+
+// Validate loads every graph file under root, schema-validates each one,
+// then runs semantic validation over the merged graph so cross-file
+// references and duplicate task_ids are checked project-wide.
+func Validate(root string) (*Result, error) {
+	files, err := discover(root)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no task graph files found under '%s'", root)
+	}
+
+	sv, err := validator.NewSchemaValidator()
+	if err != nil {
+		return nil, fmt.Errorf("initializing schema validator: %w", err)
+	}
+
+	res := &Result{Report: &validator.ValidationResult{Valid: true}}
+	merged := &validator.TaskGraph{}
+	var taskOrigins, milestoneOrigins []origin
+	var externals []string
+
+	for _, f := range files {
+		rel, _ := filepath.Rel(root, f)
+		rel = filepath.ToSlash(rel)
+		res.Files = append(res.Files, rel)
+
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("reading file '%s': %w", f, err)
+		}
+
+		// Schema errors are per file; such files are left out of the merge.
+		fileResult := &validator.ValidationResult{Valid: true}
+		sv.ValidateTaskGraph(data, fileResult)
+		for _, e := range fileResult.Errors {
+			e.Path = rel + ":" + e.Path
+			res.Report.AddError(e)
+		}
+		if !fileResult.Valid {
+			continue
+		}
+
+		var graph validator.TaskGraph
+		if err := json.Unmarshal(data, &graph); err != nil {
+			return nil, fmt.Errorf("parsing task graph '%s': %w", rel, err)
+		}
+		if merged.Version == "" {
+			merged.Version = graph.Version
+		}
+		for name, def := range graph.Types {
+			if merged.Types == nil {
+				merged.Types = make(map[string]map[string]string)
+			}
+			if _, exists := merged.Types[name]; !exists {
+				merged.Types[name] = def
+			}
+		}
+		for k, v := range graph.Extra {
+			if merged.Extra == nil {
+				merged.Extra = make(map[string]json.RawMessage)
+			}
+			if _, exists := merged.Extra[k]; !exists {
+				merged.Extra[k] = v
+			}
+		}
+		for i, t := range graph.Tasks {
+			resolved, err := graph.ResolvedTask(t.TaskID)
+			if err != nil {
+				return nil, fmt.Errorf("resolving task '%s' in '%s': %w", t.TaskID, rel, err)
+			}
+			// ResolvedTask finds the first task with the id; keep
+			// duplicates distinct so V2 still sees both.
+			if graph.FindTask(t.TaskID) != &graph.Tasks[i] {
+				resolved = &graph.Tasks[i]
+			}
+			merged.Tasks = append(merged.Tasks, *resolved)
+			taskOrigins = append(taskOrigins, origin{rel, i})
+		}
+		for i, m := range graph.Milestones {
+			merged.Milestones = append(merged.Milestones, m)
+			milestoneOrigins = append(milestoneOrigins, origin{rel, i})
+		}
+		externals = append(externals, graph.ExternalTasks...)
+	}
+
+	// A file's external tasks may be defined by a sibling file; only the
+	// ones still missing from the workspace stay external.
+	seenExternal := make(map[string]bool)
+	for _, id := range externals {
+		if merged.FindTask(id) == nil && !seenExternal[id] {
+			seenExternal[id] = true
+			merged.ExternalTasks = append(merged.ExternalTasks, id)
+		}
+	}
+
+	semResult := &validator.ValidationResult{Valid: true}
+	validator.NewSemanticValidator().ValidateTaskGraph(merged, semResult)
+	for _, e := range semResult.Errors {
+		e.Path = remapPath(e.Path, taskOrigins, milestoneOrigins)
+		e.Message = remapRefs(e.Message, taskOrigins, milestoneOrigins)
+		res.Report.AddError(e)
+	}
+	res.Report.Stats.TotalTasks = semResult.Stats.TotalTasks
+
+	if res.Report.Valid {
+		res.Graph = merged
+	}
+	return res, nil
+}
+
+// discover returns the task graph files under root in lexical order. A
+// .json file counts as a graph file when its top-level object has a
+// "tasks" key, or when it does not parse, so syntax errors are reported
+// rather than silently skipped. Hidden directories are not searched.
+func discover(root string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.EqualFold(filepath.Ext(path), ".json") {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("reading file '%s': %w", path, err)
+		}
+		var top map[string]json.RawMessage
+		if json.Unmarshal(data, &top) == nil {
+			if _, ok := top["tasks"]; !ok {
+				return nil
+			}
+		}
+		files = append(files, path)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scanning '%s': %w", root, err)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+var refPattern = regexp.MustCompile(`\b(tasks|milestones)\[(\d+)\]`)
+
+// remapPath rewrites a merged-graph path such as "tasks[7].goal" into the
+// originating file and local index, e.g. "api.json:tasks[2].goal".
+func remapPath(path string, tasks, milestones []origin) string {
+	m := refPattern.FindStringSubmatchIndex(path)
+	if m == nil || m[0] != 0 {
+		return path
+	}
+	o, ok := lookupOrigin(path[m[2]:m[3]], path[m[4]:m[5]], tasks, milestones)
+	if !ok {
+		return path
+	}
+	return fmt.Sprintf("%s:%s[%d]%s", o.file, path[m[2]:m[3]], o.index, path[m[1]:])
+}
+
+// remapRefs rewrites merged-graph references inside a message.
+func remapRefs(msg string, tasks, milestones []origin) string {
+	return refPattern.ReplaceAllStringFunc(msg, func(ref string) string {
+		sub := refPattern.FindStringSubmatch(ref)
+		o, ok := lookupOrigin(sub[1], sub[2], tasks, milestones)
+		if !ok {
+			return ref
+		}
+		return fmt.Sprintf("%s:%s[%d]", o.file, sub[1], o.index)
+	})
+}
+
+func lookupOrigin(kind, index string, tasks, milestones []origin) (origin, bool) {
+	i, err := strconv.Atoi(index)
+	if err != nil {
+		return origin{}, false
+	}
+	list := tasks
+	if kind == "milestones" {
+		list = milestones
+	}
+	if i >= len(list) {
+		return origin{}, false
+	}
+	return list[i], true
+}
@@ -0,0 +1,104 @@
+package workspace
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nixlim/task_templating/internal/scaffold"
+	"github.com/nixlim/task_templating/internal/validator"
+)
+
+// writeGraph writes a one-task graph file whose task depends on deps.
+func writeGraph(t *testing.T, path, taskID string, deps ...string) {
+	t.Helper()
+	task := scaffold.TaskSkeleton()
+	task.TaskID = taskID
+	task.TaskName = "Implement " + taskID
+	task.Goal = "Run() for " + taskID + " returns nil on valid input."
+	task.Acceptance = []string{"Given valid input, Run returns nil"}
+	if len(deps) > 0 {
+		task.DependsOn, _ = json.Marshal(deps)
+	}
+	graph := validator.TaskGraph{
+		Version:  "0.1.0",
+		Defaults: &validator.Defaults{Acceptance: []string{"go test ./... passes"}},
+		Tasks:    []validator.TaskNode{task},
+	}
+	data, err := json.Marshal(graph)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidateResolvesCrossFileDependencies(t *testing.T) {
+	root := t.TempDir()
+	writeGraph(t, filepath.Join(root, "core.json"), "build-core")
+	writeGraph(t, filepath.Join(root, "api", "api.json"), "build-api", "build-core")
+	if err := os.WriteFile(filepath.Join(root, "config.json"), []byte(`{"unrelated": true}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := Validate(root)
+	if err != nil {
+		t.Fatalf("Validate error: %v", err)
+	}
+	if !res.Report.Valid {
+		for _, e := range res.Report.Errors {
+			t.Errorf("unexpected finding: %s", e.Error())
+		}
+	}
+	if got := strings.Join(res.Files, ","); got != "api/api.json,core.json" {
+		t.Errorf("Files = %s, want api/api.json,core.json", got)
+	}
+	if res.Graph == nil || len(res.Graph.Tasks) != 2 {
+		t.Fatal("expected merged graph with 2 tasks")
+	}
+	if acc := res.Graph.Tasks[0].Acceptance; acc[0] != "go test ./... passes" {
+		t.Errorf("file defaults should be applied in merged graph, got %v", acc)
+	}
+}
+
+func TestValidateReportsCrossFileDuplicates(t *testing.T) {
+	root := t.TempDir()
+	writeGraph(t, filepath.Join(root, "a.json"), "shared-task")
+	writeGraph(t, filepath.Join(root, "b.json"), "shared-task")
+
+	res, err := Validate(root)
+	if err != nil {
+		t.Fatalf("Validate error: %v", err)
+	}
+	if res.Report.Valid || res.Graph != nil {
+		t.Fatal("expected workspace with duplicate task_id to be invalid")
+	}
+
+	var found bool
+	for _, e := range res.Report.Errors {
+		if e.Rule == "V2" {
+			found = true
+			if e.Path != "b.json:tasks[0].task_id" {
+				t.Errorf("V2 path = %q, want b.json:tasks[0].task_id", e.Path)
+			}
+			if !strings.Contains(e.Message, "a.json:tasks[0]") {
+				t.Errorf("V2 message should name the first file: %s", e.Message)
+			}
+		}
+	}
+	if !found {
+		t.Error("expected V2 finding")
+	}
+}
+
+func TestValidateEmptyDirectory(t *testing.T) {
+	if _, err := Validate(t.TempDir()); err == nil {
+		t.Error("expected error for directory without graph files")
+	}
+}